@@ -0,0 +1,627 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: expense/v1/parties.proto
+
+package expensev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Party struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	HouseholdId   uint32                 `protobuf:"varint,5,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+	Aliases       []*PartyAlias          `protobuf:"bytes,6,rep,name=aliases,proto3" json:"aliases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Party) Reset() {
+	*x = Party{}
+	mi := &file_expense_v1_parties_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Party) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Party) ProtoMessage() {}
+
+func (x *Party) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Party.ProtoReflect.Descriptor instead.
+func (*Party) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Party) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Party) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Party) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Party) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Party) GetHouseholdId() uint32 {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return 0
+}
+
+func (x *Party) GetAliases() []*PartyAlias {
+	if x != nil {
+		return x.Aliases
+	}
+	return nil
+}
+
+type PartyAlias struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartyAlias) Reset() {
+	*x = PartyAlias{}
+	mi := &file_expense_v1_parties_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyAlias) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyAlias) ProtoMessage() {}
+
+func (x *PartyAlias) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyAlias.ProtoReflect.Descriptor instead.
+func (*PartyAlias) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PartyAlias) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PartyAlias) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type PartyInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	HouseholdId   *uint32                `protobuf:"varint,2,opt,name=household_id,json=householdId,proto3,oneof" json:"household_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PartyInput) Reset() {
+	*x = PartyInput{}
+	mi := &file_expense_v1_parties_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PartyInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PartyInput) ProtoMessage() {}
+
+func (x *PartyInput) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PartyInput.ProtoReflect.Descriptor instead.
+func (*PartyInput) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PartyInput) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *PartyInput) GetHouseholdId() uint32 {
+	if x != nil && x.HouseholdId != nil {
+		return *x.HouseholdId
+	}
+	return 0
+}
+
+type ListPartiesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPartiesRequest) Reset() {
+	*x = ListPartiesRequest{}
+	mi := &file_expense_v1_parties_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPartiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPartiesRequest) ProtoMessage() {}
+
+func (x *ListPartiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPartiesRequest.ProtoReflect.Descriptor instead.
+func (*ListPartiesRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{3}
+}
+
+type ListPartiesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Entries       []*Party               `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPartiesResponse) Reset() {
+	*x = ListPartiesResponse{}
+	mi := &file_expense_v1_parties_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPartiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPartiesResponse) ProtoMessage() {}
+
+func (x *ListPartiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPartiesResponse.ProtoReflect.Descriptor instead.
+func (*ListPartiesResponse) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListPartiesResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ListPartiesResponse) GetEntries() []*Party {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetPartyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPartyRequest) Reset() {
+	*x = GetPartyRequest{}
+	mi := &file_expense_v1_parties_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPartyRequest) ProtoMessage() {}
+
+func (x *GetPartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPartyRequest.ProtoReflect.Descriptor instead.
+func (*GetPartyRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPartyRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreatePartyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Party         *PartyInput            `protobuf:"bytes,1,opt,name=party,proto3" json:"party,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePartyRequest) Reset() {
+	*x = CreatePartyRequest{}
+	mi := &file_expense_v1_parties_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePartyRequest) ProtoMessage() {}
+
+func (x *CreatePartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePartyRequest.ProtoReflect.Descriptor instead.
+func (*CreatePartyRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreatePartyRequest) GetParty() *PartyInput {
+	if x != nil {
+		return x.Party
+	}
+	return nil
+}
+
+type UpdatePartyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Party         *PartyInput            `protobuf:"bytes,2,opt,name=party,proto3" json:"party,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePartyRequest) Reset() {
+	*x = UpdatePartyRequest{}
+	mi := &file_expense_v1_parties_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePartyRequest) ProtoMessage() {}
+
+func (x *UpdatePartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePartyRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePartyRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePartyRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdatePartyRequest) GetParty() *PartyInput {
+	if x != nil {
+		return x.Party
+	}
+	return nil
+}
+
+type DeletePartyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// cascade moves the transactions of the party to the trash as well
+	Cascade bool `protobuf:"varint,2,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	// reassign_to moves the transactions of the party to this party
+	ReassignTo    *uint32 `protobuf:"varint,3,opt,name=reassign_to,json=reassignTo,proto3,oneof" json:"reassign_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletePartyRequest) Reset() {
+	*x = DeletePartyRequest{}
+	mi := &file_expense_v1_parties_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletePartyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePartyRequest) ProtoMessage() {}
+
+func (x *DeletePartyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_parties_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePartyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePartyRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_parties_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeletePartyRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeletePartyRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+func (x *DeletePartyRequest) GetReassignTo() uint32 {
+	if x != nil && x.ReassignTo != nil {
+		return *x.ReassignTo
+	}
+	return 0
+}
+
+var File_expense_v1_parties_proto protoreflect.FileDescriptor
+
+const file_expense_v1_parties_proto_rawDesc = "" +
+	"\n" +
+	"\x18expense/v1/parties.proto\x12\n" +
+	"expense.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf6\x01\n" +
+	"\x05Party\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12!\n" +
+	"\fhousehold_id\x18\x05 \x01(\rR\vhouseholdId\x120\n" +
+	"\aaliases\x18\x06 \x03(\v2\x16.expense.v1.PartyAliasR\aaliases\"0\n" +
+	"\n" +
+	"PartyAlias\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"g\n" +
+	"\n" +
+	"PartyInput\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tH\x00R\x04name\x88\x01\x01\x12&\n" +
+	"\fhousehold_id\x18\x02 \x01(\rH\x01R\vhouseholdId\x88\x01\x01B\a\n" +
+	"\x05_nameB\x0f\n" +
+	"\r_household_id\"\x14\n" +
+	"\x12ListPartiesRequest\"X\n" +
+	"\x13ListPartiesResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\x12+\n" +
+	"\aentries\x18\x02 \x03(\v2\x11.expense.v1.PartyR\aentries\"!\n" +
+	"\x0fGetPartyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"B\n" +
+	"\x12CreatePartyRequest\x12,\n" +
+	"\x05party\x18\x01 \x01(\v2\x16.expense.v1.PartyInputR\x05party\"R\n" +
+	"\x12UpdatePartyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12,\n" +
+	"\x05party\x18\x02 \x01(\v2\x16.expense.v1.PartyInputR\x05party\"t\n" +
+	"\x12DeletePartyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x18\n" +
+	"\acascade\x18\x02 \x01(\bR\acascade\x12$\n" +
+	"\vreassign_to\x18\x03 \x01(\rH\x00R\n" +
+	"reassignTo\x88\x01\x01B\x0e\n" +
+	"\f_reassign_to2\xe5\x02\n" +
+	"\fPartyService\x12N\n" +
+	"\vListParties\x12\x1e.expense.v1.ListPartiesRequest\x1a\x1f.expense.v1.ListPartiesResponse\x12:\n" +
+	"\bGetParty\x12\x1b.expense.v1.GetPartyRequest\x1a\x11.expense.v1.Party\x12@\n" +
+	"\vCreateParty\x12\x1e.expense.v1.CreatePartyRequest\x1a\x11.expense.v1.Party\x12@\n" +
+	"\vUpdateParty\x12\x1e.expense.v1.UpdatePartyRequest\x1a\x11.expense.v1.Party\x12E\n" +
+	"\vDeleteParty\x12\x1e.expense.v1.DeletePartyRequest\x1a\x16.google.protobuf.EmptyB,Z*expense-api/api/proto/expense/v1;expensev1b\x06proto3"
+
+var (
+	file_expense_v1_parties_proto_rawDescOnce sync.Once
+	file_expense_v1_parties_proto_rawDescData []byte
+)
+
+func file_expense_v1_parties_proto_rawDescGZIP() []byte {
+	file_expense_v1_parties_proto_rawDescOnce.Do(func() {
+		file_expense_v1_parties_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_expense_v1_parties_proto_rawDesc), len(file_expense_v1_parties_proto_rawDesc)))
+	})
+	return file_expense_v1_parties_proto_rawDescData
+}
+
+var file_expense_v1_parties_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_expense_v1_parties_proto_goTypes = []any{
+	(*Party)(nil),                 // 0: expense.v1.Party
+	(*PartyAlias)(nil),            // 1: expense.v1.PartyAlias
+	(*PartyInput)(nil),            // 2: expense.v1.PartyInput
+	(*ListPartiesRequest)(nil),    // 3: expense.v1.ListPartiesRequest
+	(*ListPartiesResponse)(nil),   // 4: expense.v1.ListPartiesResponse
+	(*GetPartyRequest)(nil),       // 5: expense.v1.GetPartyRequest
+	(*CreatePartyRequest)(nil),    // 6: expense.v1.CreatePartyRequest
+	(*UpdatePartyRequest)(nil),    // 7: expense.v1.UpdatePartyRequest
+	(*DeletePartyRequest)(nil),    // 8: expense.v1.DeletePartyRequest
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 10: google.protobuf.Empty
+}
+var file_expense_v1_parties_proto_depIdxs = []int32{
+	9,  // 0: expense.v1.Party.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: expense.v1.Party.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: expense.v1.Party.aliases:type_name -> expense.v1.PartyAlias
+	0,  // 3: expense.v1.ListPartiesResponse.entries:type_name -> expense.v1.Party
+	2,  // 4: expense.v1.CreatePartyRequest.party:type_name -> expense.v1.PartyInput
+	2,  // 5: expense.v1.UpdatePartyRequest.party:type_name -> expense.v1.PartyInput
+	3,  // 6: expense.v1.PartyService.ListParties:input_type -> expense.v1.ListPartiesRequest
+	5,  // 7: expense.v1.PartyService.GetParty:input_type -> expense.v1.GetPartyRequest
+	6,  // 8: expense.v1.PartyService.CreateParty:input_type -> expense.v1.CreatePartyRequest
+	7,  // 9: expense.v1.PartyService.UpdateParty:input_type -> expense.v1.UpdatePartyRequest
+	8,  // 10: expense.v1.PartyService.DeleteParty:input_type -> expense.v1.DeletePartyRequest
+	4,  // 11: expense.v1.PartyService.ListParties:output_type -> expense.v1.ListPartiesResponse
+	0,  // 12: expense.v1.PartyService.GetParty:output_type -> expense.v1.Party
+	0,  // 13: expense.v1.PartyService.CreateParty:output_type -> expense.v1.Party
+	0,  // 14: expense.v1.PartyService.UpdateParty:output_type -> expense.v1.Party
+	10, // 15: expense.v1.PartyService.DeleteParty:output_type -> google.protobuf.Empty
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_expense_v1_parties_proto_init() }
+func file_expense_v1_parties_proto_init() {
+	if File_expense_v1_parties_proto != nil {
+		return
+	}
+	file_expense_v1_parties_proto_msgTypes[2].OneofWrappers = []any{}
+	file_expense_v1_parties_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_expense_v1_parties_proto_rawDesc), len(file_expense_v1_parties_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_expense_v1_parties_proto_goTypes,
+		DependencyIndexes: file_expense_v1_parties_proto_depIdxs,
+		MessageInfos:      file_expense_v1_parties_proto_msgTypes,
+	}.Build()
+	File_expense_v1_parties_proto = out.File
+	file_expense_v1_parties_proto_goTypes = nil
+	file_expense_v1_parties_proto_depIdxs = nil
+}
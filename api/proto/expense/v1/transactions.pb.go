@@ -0,0 +1,717 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: expense/v1/transactions.proto
+
+package expensev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Transaction has its amount as a decimal string such as "-12.50"
+type Transaction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WalletId      uint32                 `protobuf:"varint,2,opt,name=wallet_id,json=walletId,proto3" json:"wallet_id,omitempty"`
+	PartyId       uint32                 `protobuf:"varint,3,opt,name=party_id,json=partyId,proto3" json:"party_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Amount        string                 `protobuf:"bytes,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Description   string                 `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	Category      string                 `protobuf:"bytes,9,opt,name=category,proto3" json:"category,omitempty"`
+	Tags          []string               `protobuf:"bytes,10,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status        string                 `protobuf:"bytes,11,opt,name=status,proto3" json:"status,omitempty"`
+	HouseholdId   uint32                 `protobuf:"varint,12,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Transaction) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetWalletId() uint32 {
+	if x != nil {
+		return x.WalletId
+	}
+	return 0
+}
+
+func (x *Transaction) GetPartyId() uint32 {
+	if x != nil {
+		return x.PartyId
+	}
+	return 0
+}
+
+func (x *Transaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Transaction) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Transaction) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Transaction) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *Transaction) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Transaction) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Transaction) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Transaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transaction) GetHouseholdId() uint32 {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return 0
+}
+
+// TransactionInput replaces the tags of a transaction only if it has some
+type TransactionInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WalletId      *uint32                `protobuf:"varint,1,opt,name=wallet_id,json=walletId,proto3,oneof" json:"wallet_id,omitempty"`
+	PartyId       *uint32                `protobuf:"varint,2,opt,name=party_id,json=partyId,proto3,oneof" json:"party_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Amount        *string                `protobuf:"bytes,4,opt,name=amount,proto3,oneof" json:"amount,omitempty"`
+	Description   *string                `protobuf:"bytes,5,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Category      *string                `protobuf:"bytes,6,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Status        *string                `protobuf:"bytes,8,opt,name=status,proto3,oneof" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionInput) Reset() {
+	*x = TransactionInput{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionInput) ProtoMessage() {}
+
+func (x *TransactionInput) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionInput.ProtoReflect.Descriptor instead.
+func (*TransactionInput) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TransactionInput) GetWalletId() uint32 {
+	if x != nil && x.WalletId != nil {
+		return *x.WalletId
+	}
+	return 0
+}
+
+func (x *TransactionInput) GetPartyId() uint32 {
+	if x != nil && x.PartyId != nil {
+		return *x.PartyId
+	}
+	return 0
+}
+
+func (x *TransactionInput) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *TransactionInput) GetAmount() string {
+	if x != nil && x.Amount != nil {
+		return *x.Amount
+	}
+	return ""
+}
+
+func (x *TransactionInput) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *TransactionInput) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *TransactionInput) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *TransactionInput) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+// ListTransactionsRequest filters the transactions by the fields that are set
+type ListTransactionsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	WalletIds []uint32               `protobuf:"varint,1,rep,packed,name=wallet_ids,json=walletIds,proto3" json:"wallet_ids,omitempty"`
+	PartyIds  []uint32               `protobuf:"varint,2,rep,packed,name=party_ids,json=partyIds,proto3" json:"party_ids,omitempty"`
+	Category  string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	Tag       string                 `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	Status    string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	// description matches the transactions whose description contains it, ignoring case
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=to,proto3" json:"to,omitempty"`
+	MinAmount     *string                `protobuf:"bytes,9,opt,name=min_amount,json=minAmount,proto3,oneof" json:"min_amount,omitempty"`
+	MaxAmount     *string                `protobuf:"bytes,10,opt,name=max_amount,json=maxAmount,proto3,oneof" json:"max_amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListTransactionsRequest) GetWalletIds() []uint32 {
+	if x != nil {
+		return x.WalletIds
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetPartyIds() []uint32 {
+	if x != nil {
+		return x.PartyIds
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetMinAmount() string {
+	if x != nil && x.MinAmount != nil {
+		return *x.MinAmount
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetMaxAmount() string {
+	if x != nil && x.MaxAmount != nil {
+		return *x.MaxAmount
+	}
+	return ""
+}
+
+type GetTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTransactionRequest) Reset() {
+	*x = GetTransactionRequest{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTransactionRequest) ProtoMessage() {}
+
+func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetTransactionRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateTransactionRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Transaction *TransactionInput      `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	// strict rejects the transaction if it is likely a duplicate
+	Strict        bool `protobuf:"varint,2,opt,name=strict,proto3" json:"strict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTransactionRequest) Reset() {
+	*x = CreateTransactionRequest{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransactionRequest) ProtoMessage() {}
+
+func (x *CreateTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransactionRequest.ProtoReflect.Descriptor instead.
+func (*CreateTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateTransactionRequest) GetTransaction() *TransactionInput {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+func (x *CreateTransactionRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+type UpdateTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Transaction   *TransactionInput      `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTransactionRequest) Reset() {
+	*x = UpdateTransactionRequest{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTransactionRequest) ProtoMessage() {}
+
+func (x *UpdateTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTransactionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateTransactionRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateTransactionRequest) GetTransaction() *TransactionInput {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+type DeleteTransactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTransactionRequest) Reset() {
+	*x = DeleteTransactionRequest{}
+	mi := &file_expense_v1_transactions_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTransactionRequest) ProtoMessage() {}
+
+func (x *DeleteTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_transactions_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTransactionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_transactions_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteTransactionRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+var File_expense_v1_transactions_proto protoreflect.FileDescriptor
+
+const file_expense_v1_transactions_proto_rawDesc = "" +
+	"\n" +
+	"\x1dexpense/v1/transactions.proto\x12\n" +
+	"expense.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaa\x03\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1b\n" +
+	"\twallet_id\x18\x02 \x01(\rR\bwalletId\x12\x19\n" +
+	"\bparty_id\x18\x03 \x01(\rR\apartyId\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06amount\x18\a \x01(\tR\x06amount\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12\x1a\n" +
+	"\bcategory\x18\t \x01(\tR\bcategory\x12\x12\n" +
+	"\x04tags\x18\n" +
+	" \x03(\tR\x04tags\x12\x16\n" +
+	"\x06status\x18\v \x01(\tR\x06status\x12!\n" +
+	"\fhousehold_id\x18\f \x01(\rR\vhouseholdId\"\xf2\x02\n" +
+	"\x10TransactionInput\x12 \n" +
+	"\twallet_id\x18\x01 \x01(\rH\x00R\bwalletId\x88\x01\x01\x12\x1e\n" +
+	"\bparty_id\x18\x02 \x01(\rH\x01R\apartyId\x88\x01\x01\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1b\n" +
+	"\x06amount\x18\x04 \x01(\tH\x02R\x06amount\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x05 \x01(\tH\x03R\vdescription\x88\x01\x01\x12\x1f\n" +
+	"\bcategory\x18\x06 \x01(\tH\x04R\bcategory\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x1b\n" +
+	"\x06status\x18\b \x01(\tH\x05R\x06status\x88\x01\x01B\f\n" +
+	"\n" +
+	"_wallet_idB\v\n" +
+	"\t_party_idB\t\n" +
+	"\a_amountB\x0e\n" +
+	"\f_descriptionB\v\n" +
+	"\t_categoryB\t\n" +
+	"\a_status\"\xff\x02\n" +
+	"\x17ListTransactionsRequest\x12\x1d\n" +
+	"\n" +
+	"wallet_ids\x18\x01 \x03(\rR\twalletIds\x12\x1b\n" +
+	"\tparty_ids\x18\x02 \x03(\rR\bpartyIds\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12\x10\n" +
+	"\x03tag\x18\x04 \x01(\tR\x03tag\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12.\n" +
+	"\x04from\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\"\n" +
+	"\n" +
+	"min_amount\x18\t \x01(\tH\x00R\tminAmount\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"max_amount\x18\n" +
+	" \x01(\tH\x01R\tmaxAmount\x88\x01\x01B\r\n" +
+	"\v_min_amountB\r\n" +
+	"\v_max_amount\"'\n" +
+	"\x15GetTransactionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"r\n" +
+	"\x18CreateTransactionRequest\x12>\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x1c.expense.v1.TransactionInputR\vtransaction\x12\x16\n" +
+	"\x06strict\x18\x02 \x01(\bR\x06strict\"j\n" +
+	"\x18UpdateTransactionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12>\n" +
+	"\vtransaction\x18\x02 \x01(\v2\x1c.expense.v1.TransactionInputR\vtransaction\"*\n" +
+	"\x18DeleteTransactionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id2\xb1\x03\n" +
+	"\x12TransactionService\x12R\n" +
+	"\x10ListTransactions\x12#.expense.v1.ListTransactionsRequest\x1a\x17.expense.v1.Transaction0\x01\x12L\n" +
+	"\x0eGetTransaction\x12!.expense.v1.GetTransactionRequest\x1a\x17.expense.v1.Transaction\x12R\n" +
+	"\x11CreateTransaction\x12$.expense.v1.CreateTransactionRequest\x1a\x17.expense.v1.Transaction\x12R\n" +
+	"\x11UpdateTransaction\x12$.expense.v1.UpdateTransactionRequest\x1a\x17.expense.v1.Transaction\x12Q\n" +
+	"\x11DeleteTransaction\x12$.expense.v1.DeleteTransactionRequest\x1a\x16.google.protobuf.EmptyB,Z*expense-api/api/proto/expense/v1;expensev1b\x06proto3"
+
+var (
+	file_expense_v1_transactions_proto_rawDescOnce sync.Once
+	file_expense_v1_transactions_proto_rawDescData []byte
+)
+
+func file_expense_v1_transactions_proto_rawDescGZIP() []byte {
+	file_expense_v1_transactions_proto_rawDescOnce.Do(func() {
+		file_expense_v1_transactions_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_expense_v1_transactions_proto_rawDesc), len(file_expense_v1_transactions_proto_rawDesc)))
+	})
+	return file_expense_v1_transactions_proto_rawDescData
+}
+
+var file_expense_v1_transactions_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_expense_v1_transactions_proto_goTypes = []any{
+	(*Transaction)(nil),              // 0: expense.v1.Transaction
+	(*TransactionInput)(nil),         // 1: expense.v1.TransactionInput
+	(*ListTransactionsRequest)(nil),  // 2: expense.v1.ListTransactionsRequest
+	(*GetTransactionRequest)(nil),    // 3: expense.v1.GetTransactionRequest
+	(*CreateTransactionRequest)(nil), // 4: expense.v1.CreateTransactionRequest
+	(*UpdateTransactionRequest)(nil), // 5: expense.v1.UpdateTransactionRequest
+	(*DeleteTransactionRequest)(nil), // 6: expense.v1.DeleteTransactionRequest
+	(*timestamppb.Timestamp)(nil),    // 7: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),            // 8: google.protobuf.Empty
+}
+var file_expense_v1_transactions_proto_depIdxs = []int32{
+	7,  // 0: expense.v1.Transaction.created_at:type_name -> google.protobuf.Timestamp
+	7,  // 1: expense.v1.Transaction.updated_at:type_name -> google.protobuf.Timestamp
+	7,  // 2: expense.v1.Transaction.timestamp:type_name -> google.protobuf.Timestamp
+	7,  // 3: expense.v1.TransactionInput.timestamp:type_name -> google.protobuf.Timestamp
+	7,  // 4: expense.v1.ListTransactionsRequest.from:type_name -> google.protobuf.Timestamp
+	7,  // 5: expense.v1.ListTransactionsRequest.to:type_name -> google.protobuf.Timestamp
+	1,  // 6: expense.v1.CreateTransactionRequest.transaction:type_name -> expense.v1.TransactionInput
+	1,  // 7: expense.v1.UpdateTransactionRequest.transaction:type_name -> expense.v1.TransactionInput
+	2,  // 8: expense.v1.TransactionService.ListTransactions:input_type -> expense.v1.ListTransactionsRequest
+	3,  // 9: expense.v1.TransactionService.GetTransaction:input_type -> expense.v1.GetTransactionRequest
+	4,  // 10: expense.v1.TransactionService.CreateTransaction:input_type -> expense.v1.CreateTransactionRequest
+	5,  // 11: expense.v1.TransactionService.UpdateTransaction:input_type -> expense.v1.UpdateTransactionRequest
+	6,  // 12: expense.v1.TransactionService.DeleteTransaction:input_type -> expense.v1.DeleteTransactionRequest
+	0,  // 13: expense.v1.TransactionService.ListTransactions:output_type -> expense.v1.Transaction
+	0,  // 14: expense.v1.TransactionService.GetTransaction:output_type -> expense.v1.Transaction
+	0,  // 15: expense.v1.TransactionService.CreateTransaction:output_type -> expense.v1.Transaction
+	0,  // 16: expense.v1.TransactionService.UpdateTransaction:output_type -> expense.v1.Transaction
+	8,  // 17: expense.v1.TransactionService.DeleteTransaction:output_type -> google.protobuf.Empty
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_expense_v1_transactions_proto_init() }
+func file_expense_v1_transactions_proto_init() {
+	if File_expense_v1_transactions_proto != nil {
+		return
+	}
+	file_expense_v1_transactions_proto_msgTypes[1].OneofWrappers = []any{}
+	file_expense_v1_transactions_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_expense_v1_transactions_proto_rawDesc), len(file_expense_v1_transactions_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_expense_v1_transactions_proto_goTypes,
+		DependencyIndexes: file_expense_v1_transactions_proto_depIdxs,
+		MessageInfos:      file_expense_v1_transactions_proto_msgTypes,
+	}.Build()
+	File_expense_v1_transactions_proto = out.File
+	file_expense_v1_transactions_proto_goTypes = nil
+	file_expense_v1_transactions_proto_depIdxs = nil
+}
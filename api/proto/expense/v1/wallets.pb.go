@@ -0,0 +1,736 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: expense/v1/wallets.proto
+
+package expensev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Wallet has its amounts as decimal strings such as "12.50"
+type Wallet struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Name            string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Description     string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Currency        string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Type            string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	OpeningBalance  string                 `protobuf:"bytes,8,opt,name=opening_balance,json=openingBalance,proto3" json:"opening_balance,omitempty"`
+	OpeningDate     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=opening_date,json=openingDate,proto3" json:"opening_date,omitempty"`
+	CreditLimit     *string                `protobuf:"bytes,10,opt,name=credit_limit,json=creditLimit,proto3,oneof" json:"credit_limit,omitempty"`
+	AvailableCredit *string                `protobuf:"bytes,11,opt,name=available_credit,json=availableCredit,proto3,oneof" json:"available_credit,omitempty"`
+	Archived        bool                   `protobuf:"varint,12,opt,name=archived,proto3" json:"archived,omitempty"`
+	DisplayOrder    int32                  `protobuf:"varint,13,opt,name=display_order,json=displayOrder,proto3" json:"display_order,omitempty"`
+	HouseholdId     uint32                 `protobuf:"varint,14,opt,name=household_id,json=householdId,proto3" json:"household_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Wallet) Reset() {
+	*x = Wallet{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Wallet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Wallet) ProtoMessage() {}
+
+func (x *Wallet) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Wallet.ProtoReflect.Descriptor instead.
+func (*Wallet) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Wallet) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Wallet) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Wallet) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Wallet) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Wallet) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Wallet) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Wallet) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Wallet) GetOpeningBalance() string {
+	if x != nil {
+		return x.OpeningBalance
+	}
+	return ""
+}
+
+func (x *Wallet) GetOpeningDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpeningDate
+	}
+	return nil
+}
+
+func (x *Wallet) GetCreditLimit() string {
+	if x != nil && x.CreditLimit != nil {
+		return *x.CreditLimit
+	}
+	return ""
+}
+
+func (x *Wallet) GetAvailableCredit() string {
+	if x != nil && x.AvailableCredit != nil {
+		return *x.AvailableCredit
+	}
+	return ""
+}
+
+func (x *Wallet) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *Wallet) GetDisplayOrder() int32 {
+	if x != nil {
+		return x.DisplayOrder
+	}
+	return 0
+}
+
+func (x *Wallet) GetHouseholdId() uint32 {
+	if x != nil {
+		return x.HouseholdId
+	}
+	return 0
+}
+
+type WalletInput struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           *string                `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Description    *string                `protobuf:"bytes,2,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Currency       *string                `protobuf:"bytes,3,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	Type           *string                `protobuf:"bytes,4,opt,name=type,proto3,oneof" json:"type,omitempty"`
+	OpeningBalance *string                `protobuf:"bytes,5,opt,name=opening_balance,json=openingBalance,proto3,oneof" json:"opening_balance,omitempty"`
+	OpeningDate    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=opening_date,json=openingDate,proto3" json:"opening_date,omitempty"`
+	CreditLimit    *string                `protobuf:"bytes,7,opt,name=credit_limit,json=creditLimit,proto3,oneof" json:"credit_limit,omitempty"`
+	Archived       *bool                  `protobuf:"varint,8,opt,name=archived,proto3,oneof" json:"archived,omitempty"`
+	DisplayOrder   *int32                 `protobuf:"varint,9,opt,name=display_order,json=displayOrder,proto3,oneof" json:"display_order,omitempty"`
+	HouseholdId    *uint32                `protobuf:"varint,10,opt,name=household_id,json=householdId,proto3,oneof" json:"household_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WalletInput) Reset() {
+	*x = WalletInput{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletInput) ProtoMessage() {}
+
+func (x *WalletInput) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletInput.ProtoReflect.Descriptor instead.
+func (*WalletInput) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WalletInput) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *WalletInput) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *WalletInput) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *WalletInput) GetType() string {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return ""
+}
+
+func (x *WalletInput) GetOpeningBalance() string {
+	if x != nil && x.OpeningBalance != nil {
+		return *x.OpeningBalance
+	}
+	return ""
+}
+
+func (x *WalletInput) GetOpeningDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpeningDate
+	}
+	return nil
+}
+
+func (x *WalletInput) GetCreditLimit() string {
+	if x != nil && x.CreditLimit != nil {
+		return *x.CreditLimit
+	}
+	return ""
+}
+
+func (x *WalletInput) GetArchived() bool {
+	if x != nil && x.Archived != nil {
+		return *x.Archived
+	}
+	return false
+}
+
+func (x *WalletInput) GetDisplayOrder() int32 {
+	if x != nil && x.DisplayOrder != nil {
+		return *x.DisplayOrder
+	}
+	return 0
+}
+
+func (x *WalletInput) GetHouseholdId() uint32 {
+	if x != nil && x.HouseholdId != nil {
+		return *x.HouseholdId
+	}
+	return 0
+}
+
+type ListWalletsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IncludeArchived bool                   `protobuf:"varint,1,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListWalletsRequest) Reset() {
+	*x = ListWalletsRequest{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletsRequest) ProtoMessage() {}
+
+func (x *ListWalletsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletsRequest.ProtoReflect.Descriptor instead.
+func (*ListWalletsRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListWalletsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ListWalletsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Entries       []*Wallet              `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListWalletsResponse) Reset() {
+	*x = ListWalletsResponse{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWalletsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWalletsResponse) ProtoMessage() {}
+
+func (x *ListWalletsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWalletsResponse.ProtoReflect.Descriptor instead.
+func (*ListWalletsResponse) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListWalletsResponse) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ListWalletsResponse) GetEntries() []*Wallet {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type GetWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWalletRequest) Reset() {
+	*x = GetWalletRequest{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWalletRequest) ProtoMessage() {}
+
+func (x *GetWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWalletRequest.ProtoReflect.Descriptor instead.
+func (*GetWalletRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetWalletRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type CreateWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wallet        *WalletInput           `protobuf:"bytes,1,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWalletRequest) Reset() {
+	*x = CreateWalletRequest{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWalletRequest) ProtoMessage() {}
+
+func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWalletRequest.ProtoReflect.Descriptor instead.
+func (*CreateWalletRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateWalletRequest) GetWallet() *WalletInput {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+type UpdateWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Wallet        *WalletInput           `protobuf:"bytes,2,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWalletRequest) Reset() {
+	*x = UpdateWalletRequest{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWalletRequest) ProtoMessage() {}
+
+func (x *UpdateWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWalletRequest.ProtoReflect.Descriptor instead.
+func (*UpdateWalletRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateWalletRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateWalletRequest) GetWallet() *WalletInput {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+type DeleteWalletRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// cascade moves the transactions of the wallet to the trash as well
+	Cascade bool `protobuf:"varint,2,opt,name=cascade,proto3" json:"cascade,omitempty"`
+	// reassign_to moves the transactions of the wallet to this wallet
+	ReassignTo    *uint32 `protobuf:"varint,3,opt,name=reassign_to,json=reassignTo,proto3,oneof" json:"reassign_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteWalletRequest) Reset() {
+	*x = DeleteWalletRequest{}
+	mi := &file_expense_v1_wallets_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteWalletRequest) ProtoMessage() {}
+
+func (x *DeleteWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_expense_v1_wallets_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteWalletRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWalletRequest) Descriptor() ([]byte, []int) {
+	return file_expense_v1_wallets_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteWalletRequest) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteWalletRequest) GetCascade() bool {
+	if x != nil {
+		return x.Cascade
+	}
+	return false
+}
+
+func (x *DeleteWalletRequest) GetReassignTo() uint32 {
+	if x != nil && x.ReassignTo != nil {
+		return *x.ReassignTo
+	}
+	return 0
+}
+
+var File_expense_v1_wallets_proto protoreflect.FileDescriptor
+
+const file_expense_v1_wallets_proto_rawDesc = "" +
+	"\n" +
+	"\x18expense/v1/wallets.proto\x12\n" +
+	"expense.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbe\x04\n" +
+	"\x06Wallet\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04type\x18\a \x01(\tR\x04type\x12'\n" +
+	"\x0fopening_balance\x18\b \x01(\tR\x0eopeningBalance\x12=\n" +
+	"\fopening_date\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vopeningDate\x12&\n" +
+	"\fcredit_limit\x18\n" +
+	" \x01(\tH\x00R\vcreditLimit\x88\x01\x01\x12.\n" +
+	"\x10available_credit\x18\v \x01(\tH\x01R\x0favailableCredit\x88\x01\x01\x12\x1a\n" +
+	"\barchived\x18\f \x01(\bR\barchived\x12#\n" +
+	"\rdisplay_order\x18\r \x01(\x05R\fdisplayOrder\x12!\n" +
+	"\fhousehold_id\x18\x0e \x01(\rR\vhouseholdIdB\x0f\n" +
+	"\r_credit_limitB\x13\n" +
+	"\x11_available_credit\"\x93\x04\n" +
+	"\vWalletInput\x12\x17\n" +
+	"\x04name\x18\x01 \x01(\tH\x00R\x04name\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x02 \x01(\tH\x01R\vdescription\x88\x01\x01\x12\x1f\n" +
+	"\bcurrency\x18\x03 \x01(\tH\x02R\bcurrency\x88\x01\x01\x12\x17\n" +
+	"\x04type\x18\x04 \x01(\tH\x03R\x04type\x88\x01\x01\x12,\n" +
+	"\x0fopening_balance\x18\x05 \x01(\tH\x04R\x0eopeningBalance\x88\x01\x01\x12=\n" +
+	"\fopening_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vopeningDate\x12&\n" +
+	"\fcredit_limit\x18\a \x01(\tH\x05R\vcreditLimit\x88\x01\x01\x12\x1f\n" +
+	"\barchived\x18\b \x01(\bH\x06R\barchived\x88\x01\x01\x12(\n" +
+	"\rdisplay_order\x18\t \x01(\x05H\aR\fdisplayOrder\x88\x01\x01\x12&\n" +
+	"\fhousehold_id\x18\n" +
+	" \x01(\rH\bR\vhouseholdId\x88\x01\x01B\a\n" +
+	"\x05_nameB\x0e\n" +
+	"\f_descriptionB\v\n" +
+	"\t_currencyB\a\n" +
+	"\x05_typeB\x12\n" +
+	"\x10_opening_balanceB\x0f\n" +
+	"\r_credit_limitB\v\n" +
+	"\t_archivedB\x10\n" +
+	"\x0e_display_orderB\x0f\n" +
+	"\r_household_id\"?\n" +
+	"\x12ListWalletsRequest\x12)\n" +
+	"\x10include_archived\x18\x01 \x01(\bR\x0fincludeArchived\"Y\n" +
+	"\x13ListWalletsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\x12,\n" +
+	"\aentries\x18\x02 \x03(\v2\x12.expense.v1.WalletR\aentries\"\"\n" +
+	"\x10GetWalletRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\"F\n" +
+	"\x13CreateWalletRequest\x12/\n" +
+	"\x06wallet\x18\x01 \x01(\v2\x17.expense.v1.WalletInputR\x06wallet\"V\n" +
+	"\x13UpdateWalletRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12/\n" +
+	"\x06wallet\x18\x02 \x01(\v2\x17.expense.v1.WalletInputR\x06wallet\"u\n" +
+	"\x13DeleteWalletRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x18\n" +
+	"\acascade\x18\x02 \x01(\bR\acascade\x12$\n" +
+	"\vreassign_to\x18\x03 \x01(\rH\x00R\n" +
+	"reassignTo\x88\x01\x01B\x0e\n" +
+	"\f_reassign_to2\xf1\x02\n" +
+	"\rWalletService\x12N\n" +
+	"\vListWallets\x12\x1e.expense.v1.ListWalletsRequest\x1a\x1f.expense.v1.ListWalletsResponse\x12=\n" +
+	"\tGetWallet\x12\x1c.expense.v1.GetWalletRequest\x1a\x12.expense.v1.Wallet\x12C\n" +
+	"\fCreateWallet\x12\x1f.expense.v1.CreateWalletRequest\x1a\x12.expense.v1.Wallet\x12C\n" +
+	"\fUpdateWallet\x12\x1f.expense.v1.UpdateWalletRequest\x1a\x12.expense.v1.Wallet\x12G\n" +
+	"\fDeleteWallet\x12\x1f.expense.v1.DeleteWalletRequest\x1a\x16.google.protobuf.EmptyB,Z*expense-api/api/proto/expense/v1;expensev1b\x06proto3"
+
+var (
+	file_expense_v1_wallets_proto_rawDescOnce sync.Once
+	file_expense_v1_wallets_proto_rawDescData []byte
+)
+
+func file_expense_v1_wallets_proto_rawDescGZIP() []byte {
+	file_expense_v1_wallets_proto_rawDescOnce.Do(func() {
+		file_expense_v1_wallets_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_expense_v1_wallets_proto_rawDesc), len(file_expense_v1_wallets_proto_rawDesc)))
+	})
+	return file_expense_v1_wallets_proto_rawDescData
+}
+
+var file_expense_v1_wallets_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_expense_v1_wallets_proto_goTypes = []any{
+	(*Wallet)(nil),                // 0: expense.v1.Wallet
+	(*WalletInput)(nil),           // 1: expense.v1.WalletInput
+	(*ListWalletsRequest)(nil),    // 2: expense.v1.ListWalletsRequest
+	(*ListWalletsResponse)(nil),   // 3: expense.v1.ListWalletsResponse
+	(*GetWalletRequest)(nil),      // 4: expense.v1.GetWalletRequest
+	(*CreateWalletRequest)(nil),   // 5: expense.v1.CreateWalletRequest
+	(*UpdateWalletRequest)(nil),   // 6: expense.v1.UpdateWalletRequest
+	(*DeleteWalletRequest)(nil),   // 7: expense.v1.DeleteWalletRequest
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 9: google.protobuf.Empty
+}
+var file_expense_v1_wallets_proto_depIdxs = []int32{
+	8,  // 0: expense.v1.Wallet.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 1: expense.v1.Wallet.updated_at:type_name -> google.protobuf.Timestamp
+	8,  // 2: expense.v1.Wallet.opening_date:type_name -> google.protobuf.Timestamp
+	8,  // 3: expense.v1.WalletInput.opening_date:type_name -> google.protobuf.Timestamp
+	0,  // 4: expense.v1.ListWalletsResponse.entries:type_name -> expense.v1.Wallet
+	1,  // 5: expense.v1.CreateWalletRequest.wallet:type_name -> expense.v1.WalletInput
+	1,  // 6: expense.v1.UpdateWalletRequest.wallet:type_name -> expense.v1.WalletInput
+	2,  // 7: expense.v1.WalletService.ListWallets:input_type -> expense.v1.ListWalletsRequest
+	4,  // 8: expense.v1.WalletService.GetWallet:input_type -> expense.v1.GetWalletRequest
+	5,  // 9: expense.v1.WalletService.CreateWallet:input_type -> expense.v1.CreateWalletRequest
+	6,  // 10: expense.v1.WalletService.UpdateWallet:input_type -> expense.v1.UpdateWalletRequest
+	7,  // 11: expense.v1.WalletService.DeleteWallet:input_type -> expense.v1.DeleteWalletRequest
+	3,  // 12: expense.v1.WalletService.ListWallets:output_type -> expense.v1.ListWalletsResponse
+	0,  // 13: expense.v1.WalletService.GetWallet:output_type -> expense.v1.Wallet
+	0,  // 14: expense.v1.WalletService.CreateWallet:output_type -> expense.v1.Wallet
+	0,  // 15: expense.v1.WalletService.UpdateWallet:output_type -> expense.v1.Wallet
+	9,  // 16: expense.v1.WalletService.DeleteWallet:output_type -> google.protobuf.Empty
+	12, // [12:17] is the sub-list for method output_type
+	7,  // [7:12] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_expense_v1_wallets_proto_init() }
+func file_expense_v1_wallets_proto_init() {
+	if File_expense_v1_wallets_proto != nil {
+		return
+	}
+	file_expense_v1_wallets_proto_msgTypes[0].OneofWrappers = []any{}
+	file_expense_v1_wallets_proto_msgTypes[1].OneofWrappers = []any{}
+	file_expense_v1_wallets_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_expense_v1_wallets_proto_rawDesc), len(file_expense_v1_wallets_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_expense_v1_wallets_proto_goTypes,
+		DependencyIndexes: file_expense_v1_wallets_proto_depIdxs,
+		MessageInfos:      file_expense_v1_wallets_proto_msgTypes,
+	}.Build()
+	File_expense_v1_wallets_proto = out.File
+	file_expense_v1_wallets_proto_goTypes = nil
+	file_expense_v1_wallets_proto_depIdxs = nil
+}
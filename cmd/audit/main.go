@@ -0,0 +1,23 @@
+package main
+
+import (
+	"expense-api/internal/app"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// audit verifies the hash chains of the audit log, exiting with 1 if one of them is broken
+func main() {
+	userID := flag.Uint("user", 0, "only verify the chain of this user")
+	flag.Parse()
+
+	valid, err := app.VerifyAudit(os.Stdout, *userID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "couldn't verify the audit log: %v\n", err)
+		os.Exit(2)
+	}
+	if !valid {
+		os.Exit(1)
+	}
+}
@@ -1,16 +1,82 @@
 package app
 
 import (
+	"context"
+	"expense-api/internal/blobstore"
+	"expense-api/internal/events"
+	"expense-api/internal/grpcapi"
 	"expense-api/internal/middleware/auth"
+	"expense-api/internal/middleware/idempotency"
 	"expense-api/internal/repository"
 	"expense-api/internal/router"
+	"expense-api/internal/stream"
+	"expense-api/internal/trash"
 	"expense-api/internal/utils"
+	"expense-api/internal/webhooks"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 func Run() {
+	env, repository := setup()
+	jwtService := auth.NewJWTService(env.Issuer.Value, env.Secret.Value)
+	hasher := utils.NewPasswordHasher()
+
+	blobs, err := NewBlobStore(env)
+	if err != nil {
+		panic(fmt.Sprintf("couldn't set up blob store: %v", err))
+	}
+
+	config := *router.DefaultConfig
+	config.IdempotencyKeyTTL, err = time.ParseDuration(env.IdempotencyKeyTTL.Value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be a duration such as 24h: %v", env.IdempotencyKeyTTL.Name, err))
+	}
+	config.ValidateRequests, err = strconv.ParseBool(env.ValidateRequests.Value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be true or false: %v", env.ValidateRequests.Name, err))
+	}
+	config.TrustedProxies, err = parseTrustedProxies(env.TrustedProxies.Value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be a comma separated list of IP addresses or CIDR ranges: %v", env.TrustedProxies.Name, err))
+	}
+
+	retention, err := time.ParseDuration(env.TrashRetention.Value)
+	if err != nil {
+		panic(fmt.Sprintf("%s must be a duration such as 720h: %v", env.TrashRetention.Name, err))
+	}
+	go trash.NewPurger(repository, blobs, retention).Run(context.Background(), trash.PurgeInterval)
+	go idempotency.Purge(context.Background(), repository, idempotency.PurgeInterval)
+
+	bus := events.NewBus()
+	webhooks.Subscribe(bus, repository)
+	stream.Subscribe(bus, repository)
+	go events.NewDispatcher(repository, bus).Run(context.Background(), events.DispatchInterval)
+
+	go webhooks.NewDispatcher(repository, webhooks.NewClient()).Run(context.Background(), webhooks.DeliveryInterval)
+
+	r := router.Setup(repository, jwtService, hasher, blobs, &config)
+
+	lis, err := net.Listen("tcp", env.GRPCPort.Value)
+	if err != nil {
+		panic(fmt.Sprintf("couldn't listen for gRPC on %s: %v", env.GRPCPort.Value, err))
+	}
+	go func() {
+		if err := grpcapi.NewServer(repository, jwtService, r).Serve(lis); err != nil {
+			panic(fmt.Sprintf("couldn't serve gRPC: %v", err))
+		}
+	}()
+
+	r.Run(env.Port.Value)
+}
+
+// setup loads the environment and connects to the database, migrating it
+func setup() (*Environment, repository.Repository) {
 	if err := godotenv.Load(".env"); err != nil {
 		panic(fmt.Sprintf("couldn't load env file: %v", err))
 	}
@@ -33,10 +99,36 @@ func Run() {
 		panic(fmt.Sprintf("error setting up database: %v", err))
 	}
 
-	repository := repository.New(dbConn)
-	jwtService := auth.NewJWTService(env.Issuer.Value, env.Secret.Value)
-	hasher := utils.NewPasswordHasher()
+	return env, repository.New(dbConn)
+}
 
-	r := router.Setup(repository, jwtService, hasher, router.DefaultConfig)
-	r.Run(env.Port.Value)
+// parseTrustedProxies splits the list of proxies, checking that each is an IP address or a CIDR range
+func parseTrustedProxies(value string) ([]string, error) {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("invalid proxy %q", proxy)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// NewBlobStore creates the blob store attachments are kept in
+func NewBlobStore(env *Environment) (blobstore.BlobStore, error) {
+	if env.BlobStore.Value == BlobStoreS3 {
+		return blobstore.NewS3(blobstore.S3Config{
+			Endpoint:        env.S3Endpoint.Value,
+			Region:          env.S3Region.Value,
+			Bucket:          env.S3Bucket.Value,
+			AccessKeyID:     env.S3AccessKeyID.Value,
+			SecretAccessKey: env.S3SecretAccessKey.Value,
+		})
+	}
+	return blobstore.NewLocal(env.BlobDir.Value)
 }
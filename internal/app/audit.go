@@ -0,0 +1,39 @@
+package app
+
+import (
+	"expense-api/internal/audit"
+	"fmt"
+	"io"
+)
+
+// VerifyAudit walks the audit hash chain of the user, or of every user if userID is 0, and writes
+// a line per user to w. It tells whether all the chains are intact.
+func VerifyAudit(w io.Writer, userID uint) (bool, error) {
+	_, repository := setup()
+	verifier := audit.NewVerifier(repository, audit.BatchSize)
+
+	var reports []*audit.Report
+	if userID == 0 {
+		var err error
+		if reports, err = verifier.VerifyAll(); err != nil {
+			return false, err
+		}
+	} else {
+		report, err := verifier.Verify(userID)
+		if err != nil {
+			return false, err
+		}
+		reports = append(reports, report)
+	}
+
+	valid := true
+	for _, r := range reports {
+		if r.Broken == nil {
+			fmt.Fprintf(w, "user %d: %d entries, intact\n", r.UserID, r.Checked)
+			continue
+		}
+		valid = false
+		fmt.Fprintf(w, "user %d: broken at entry %d (%s) after %d entries\n", r.UserID, r.Broken.EntryID, r.Broken.Reason, r.Checked)
+	}
+	return valid, nil
+}
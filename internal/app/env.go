@@ -7,12 +7,32 @@ import (
 
 const (
 	PORT        = "PORT"
+	GRPC_PORT   = "GRPC_PORT"
 	JWT_ISSUER  = "JWT_ISSUER"
 	JWT_SECRET  = "JWT_SECRET"
 	DB_USER     = "DB_USER"
 	DB_PASSWORD = "DB_PASSWORD"
 	DB_HOST     = "DB_HOST"
 	DB_NAME     = "DB_NAME"
+
+	BLOB_STORE           = "BLOB_STORE"
+	BLOB_DIR             = "BLOB_DIR"
+	S3_ENDPOINT          = "S3_ENDPOINT"
+	S3_REGION            = "S3_REGION"
+	S3_BUCKET            = "S3_BUCKET"
+	S3_ACCESS_KEY_ID     = "S3_ACCESS_KEY_ID"
+	S3_SECRET_ACCESS_KEY = "S3_SECRET_ACCESS_KEY"
+
+	IDEMPOTENCY_KEY_TTL = "IDEMPOTENCY_KEY_TTL"
+	TRASH_RETENTION     = "TRASH_RETENTION"
+	VALIDATE_REQUESTS   = "VALIDATE_REQUESTS"
+	TRUSTED_PROXIES     = "TRUSTED_PROXIES"
+)
+
+// Blob store kinds
+const (
+	BlobStoreLocal = "local"
+	BlobStoreS3    = "s3"
 )
 
 type (
@@ -23,24 +43,52 @@ type (
 
 	Environment struct {
 		Port       EnvironmentVariable
+		GRPCPort   EnvironmentVariable
 		Issuer     EnvironmentVariable
 		Secret     EnvironmentVariable
 		DBUser     EnvironmentVariable
 		DBPassword EnvironmentVariable
 		DBHost     EnvironmentVariable
 		DBName     EnvironmentVariable
+
+		BlobStore         EnvironmentVariable
+		BlobDir           EnvironmentVariable
+		S3Endpoint        EnvironmentVariable
+		S3Region          EnvironmentVariable
+		S3Bucket          EnvironmentVariable
+		S3AccessKeyID     EnvironmentVariable
+		S3SecretAccessKey EnvironmentVariable
+
+		IdempotencyKeyTTL EnvironmentVariable
+		TrashRetention    EnvironmentVariable
+		ValidateRequests  EnvironmentVariable
+		TrustedProxies    EnvironmentVariable
 	}
 )
 
 func NewDefaultEnviroment() *Environment {
 	return &Environment{
 		Port:       EnvironmentVariable{Name: PORT},
+		GRPCPort:   EnvironmentVariable{Name: GRPC_PORT},
 		Issuer:     EnvironmentVariable{Name: JWT_ISSUER},
 		Secret:     EnvironmentVariable{Name: JWT_SECRET},
 		DBUser:     EnvironmentVariable{Name: DB_USER},
 		DBPassword: EnvironmentVariable{Name: DB_PASSWORD},
 		DBHost:     EnvironmentVariable{Name: DB_HOST},
 		DBName:     EnvironmentVariable{Name: DB_NAME},
+
+		BlobStore:         EnvironmentVariable{Name: BLOB_STORE},
+		BlobDir:           EnvironmentVariable{Name: BLOB_DIR},
+		S3Endpoint:        EnvironmentVariable{Name: S3_ENDPOINT},
+		S3Region:          EnvironmentVariable{Name: S3_REGION},
+		S3Bucket:          EnvironmentVariable{Name: S3_BUCKET},
+		S3AccessKeyID:     EnvironmentVariable{Name: S3_ACCESS_KEY_ID},
+		S3SecretAccessKey: EnvironmentVariable{Name: S3_SECRET_ACCESS_KEY},
+
+		IdempotencyKeyTTL: EnvironmentVariable{Name: IDEMPOTENCY_KEY_TTL},
+		TrashRetention:    EnvironmentVariable{Name: TRASH_RETENTION},
+		ValidateRequests:  EnvironmentVariable{Name: VALIDATE_REQUESTS},
+		TrustedProxies:    EnvironmentVariable{Name: TRUSTED_PROXIES},
 	}
 }
 
@@ -50,6 +98,7 @@ func (e *Environment) LoadVariables() {
 	} else {
 		e.Port.Value = ":" + port
 	}
+	e.GRPCPort.Value = ":" + getEnvOrDefault(e.GRPCPort.Name, "9090")
 
 	e.Issuer.Value = os.Getenv(e.Issuer.Name)
 	assertEnvVarSet(e.Issuer)
@@ -69,6 +118,38 @@ func (e *Environment) LoadVariables() {
 	e.DBName.Value = os.Getenv(e.DBName.Name)
 	assertEnvVarSet(e.DBName)
 
+	e.loadBlobStoreVariables()
+
+	e.IdempotencyKeyTTL.Value = getEnvOrDefault(e.IdempotencyKeyTTL.Name, "24h")
+	e.TrashRetention.Value = getEnvOrDefault(e.TrashRetention.Name, "720h")
+	e.ValidateRequests.Value = getEnvOrDefault(e.ValidateRequests.Name, "false")
+	e.TrustedProxies.Value = os.Getenv(e.TrustedProxies.Name)
+}
+
+// loadBlobStoreVariables defaults to storing attachments on the local filesystem
+func (e *Environment) loadBlobStoreVariables() {
+	e.BlobStore.Value = getEnvOrDefault(e.BlobStore.Name, BlobStoreLocal)
+
+	switch e.BlobStore.Value {
+	case BlobStoreLocal:
+		e.BlobDir.Value = getEnvOrDefault(e.BlobDir.Name, "data/blobs")
+	case BlobStoreS3:
+		e.S3Region.Value = getEnvOrDefault(e.S3Region.Name, "us-east-1")
+
+		for _, v := range []*EnvironmentVariable{&e.S3Endpoint, &e.S3Bucket, &e.S3AccessKeyID, &e.S3SecretAccessKey} {
+			v.Value = os.Getenv(v.Name)
+			assertEnvVarSet(*v)
+		}
+	default:
+		panic(fmt.Sprintf("%s must be either '%s' or '%s'!", e.BlobStore.Name, BlobStoreLocal, BlobStoreS3))
+	}
+}
+
+func getEnvOrDefault(name, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return defaultValue
 }
 
 func assertEnvVarSet(envVar EnvironmentVariable) {
@@ -0,0 +1,104 @@
+package audit
+
+import (
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+)
+
+// BatchSize is how many entries Verify reads at once
+const BatchSize = 500
+
+// Reasons for a broken link
+const (
+	ReasonHashMismatch = "the hash of the entry doesn't match its content"
+	ReasonPrevMismatch = "the entry doesn't link to the hash of the entry before it"
+)
+
+// Report is the result of walking the hash chain of a user. Broken is the first link that doesn't
+// hold, nil if the whole chain is intact.
+type Report struct {
+	UserID  uint
+	Checked int
+	Broken  *BrokenLink
+}
+
+// BrokenLink is an entry that was edited, or that follows entries that were removed
+type BrokenLink struct {
+	EntryID uint
+	Reason  string
+}
+
+// Verifier walks the hash chains of the audit log
+type Verifier struct {
+	repo      repository.Repository
+	batchSize int
+}
+
+func NewVerifier(repo repository.Repository, batchSize int) *Verifier {
+	return &Verifier{repo, batchSize}
+}
+
+// Verify walks the chain of the user from the first entry on and stops at the first broken link
+func (v *Verifier) Verify(userID uint) (*Report, error) {
+	report := &Report{UserID: userID}
+	prevHash := ""
+	afterID := uint(0)
+
+	for {
+		entries, err := v.repo.AuditListChain(userID, afterID, v.batchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if broken, err := check(entry, prevHash); err != nil {
+				return nil, err
+			} else if broken != nil {
+				report.Broken = broken
+				return report, nil
+			}
+
+			report.Checked++
+			prevHash = entry.Hash
+			afterID = entry.ID
+		}
+
+		if len(entries) < v.batchSize {
+			return report, nil
+		}
+	}
+}
+
+// VerifyAll walks the chains of all users, the report of a user is there even if their chain is
+// intact
+func (v *Verifier) VerifyAll() ([]*Report, error) {
+	users, err := v.repo.AuditChainUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*Report, 0, len(users))
+	for _, userID := range users {
+		report, err := v.Verify(userID)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func check(entry *model.AuditEntry, prevHash string) (*BrokenLink, error) {
+	if entry.PrevHash != prevHash {
+		return &BrokenLink{entry.ID, ReasonPrevMismatch}, nil
+	}
+
+	hash, err := entry.ComputeHash()
+	if err != nil {
+		return nil, err
+	}
+	if hash != entry.Hash {
+		return &BrokenLink{entry.ID, ReasonHashMismatch}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,145 @@
+package audit_test
+
+import (
+	"expense-api/internal/audit"
+	"expense-api/internal/model"
+	"expense-api/test/spies"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+// chain creates a valid chain of entries about the user's wallet
+func chain(t *testing.T, userID uint, n int) []*model.AuditEntry {
+	entries := make([]*model.AuditEntry, n)
+	prevHash := ""
+	for i := range entries {
+		entry := &model.AuditEntry{
+			UserID:     userID,
+			PrevHash:   prevHash,
+			ActorID:    &userID,
+			RequestID:  "request",
+			IP:         "192.0.2.1",
+			Resource:   model.AuditResourceWallet,
+			ResourceID: 2,
+			Action:     model.AuditUpdate,
+			Changes:    model.AuditChanges{"name": {Before: "cash", After: "pocket money"}},
+		}
+		entry.ID = uint(i + 1)
+		entry.CreatedAt = time.Date(2021, 3, 4, 10, i, 0, 0, time.UTC)
+
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Hash = hash
+		prevHash = hash
+		entries[i] = entry
+	}
+	return entries
+}
+
+func assertReport(t *testing.T, got, expected *audit.Report) {
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected report (-expected +got):\n%s", diff)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	userID := uint(1)
+
+	t.Run("Intact chain read in batches", func(t *testing.T) {
+		entries := chain(t, userID, 5)
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("AuditListChain", userID, uint(0), 2).Return(entries[0:2], nil).Once()
+		repoSpy.On("AuditListChain", userID, uint(2), 2).Return(entries[2:4], nil).Once()
+		repoSpy.On("AuditListChain", userID, uint(4), 2).Return(entries[4:], nil).Once()
+
+		report, err := audit.NewVerifier(repoSpy, 2).Verify(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertReport(t, report, &audit.Report{UserID: userID, Checked: 5})
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Edited entry", func(t *testing.T) {
+		entries := chain(t, userID, 3)
+		entries[1].Changes["name"].After = "savings"
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("AuditListChain", userID, uint(0), audit.BatchSize).Return(entries, nil).Once()
+
+		report, err := audit.NewVerifier(repoSpy, audit.BatchSize).Verify(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertReport(t, report, &audit.Report{
+			UserID:  userID,
+			Checked: 1,
+			Broken:  &audit.BrokenLink{EntryID: 2, Reason: audit.ReasonHashMismatch},
+		})
+	})
+
+	t.Run("Removed entry", func(t *testing.T) {
+		entries := chain(t, userID, 3)
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("AuditListChain", userID, uint(0), audit.BatchSize).Return([]*model.AuditEntry{entries[0], entries[2]}, nil).Once()
+
+		report, err := audit.NewVerifier(repoSpy, audit.BatchSize).Verify(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertReport(t, report, &audit.Report{
+			UserID:  userID,
+			Checked: 1,
+			Broken:  &audit.BrokenLink{EntryID: 3, Reason: audit.ReasonPrevMismatch},
+		})
+	})
+
+	t.Run("Verify the chains of all users", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("AuditChainUsers").Return([]uint{1, 2}, nil).Once()
+		repoSpy.On("AuditListChain", uint(1), uint(0), audit.BatchSize).Return(chain(t, 1, 2), nil).Once()
+		repoSpy.On("AuditListChain", uint(2), uint(0), audit.BatchSize).Return([]*model.AuditEntry{}, nil).Once()
+
+		reports, err := audit.NewVerifier(repoSpy, audit.BatchSize).VerifyAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]*audit.Report{{UserID: 1, Checked: 2}, {UserID: 2}}, reports); diff != "" {
+			t.Errorf("unexpected reports (-expected +got):\n%s", diff)
+		}
+	})
+}
+
+func TestComputeHash(t *testing.T) {
+	t.Run("The hash survives storing the changes", func(t *testing.T) {
+		entry := chain(t, 1, 1)[0]
+		entry.Changes = model.AuditChanges{
+			"amount":  {Before: decimal.RequireFromString("10.50"), After: decimal.RequireFromString("12")},
+			"tags":    {Before: model.Tags{}, After: model.Tags{"food"}},
+			"user_id": {Before: nil, After: uint(1)},
+		}
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		value, err := entry.Changes.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored := *entry
+		stored.Changes = nil
+		if err := stored.Changes.Scan(value); err != nil {
+			t.Fatal(err)
+		}
+		stored.CreatedAt = entry.CreatedAt.In(time.FixedZone("CET", 3600))
+
+		if storedHash, err := stored.ComputeHash(); err != nil || storedHash != hash {
+			t.Errorf("expected hash %s, got %s (%v)", hash, storedHash, err)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrorNotFound   = errors.New("blob not found")
+	ErrorInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore stores opaque files under slash separated keys.
+// Deleting a key that doesn't exist is not an error.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// keys are restricted to characters that need no escaping, neither on a filesystem nor in a URL
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+
+// ValidateKey makes sure a key can't escape the store it is used with
+func ValidateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return ErrorInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "." || segment == ".." {
+			return ErrorInvalidKey
+		}
+	}
+	return nil
+}
+
+// NewKey generates a random key with the given prefix
+func NewKey(prefix string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(prefix, "/") + "/" + hex.EncodeToString(b), nil
+}
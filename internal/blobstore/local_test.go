@@ -0,0 +1,134 @@
+package blobstore_test
+
+import (
+	"context"
+	"expense-api/internal/blobstore"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := blobstore.NewLocal(dir)
+	if err != nil {
+		t.Fatalf("couldn't create store: %v", err)
+	}
+
+	testStore(t, store)
+
+	t.Run("Blob is stored below the directory", func(t *testing.T) {
+		content := "receipt"
+		if err := store.Put(context.Background(), "a/b/c", strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got, err := os.ReadFile(filepath.Join(dir, "a", "b", "c"))
+		if err != nil {
+			t.Fatalf("blob file missing: %v", err)
+		}
+
+		if string(got) != content {
+			t.Errorf("want %q, got %q", content, got)
+		}
+	})
+}
+
+func TestValidateKey(t *testing.T) {
+	testCases := []struct {
+		key   string
+		valid bool
+	}{
+		{"transactions/1/abc", true},
+		{"receipt.pdf", true},
+		{"", false},
+		{"/absolute", false},
+		{"trailing/", false},
+		{"a//b", false},
+		{"../escape", false},
+		{"a/../../escape", false},
+		{"a/./b", false},
+		{"with space", false},
+		{"percent%2F", false},
+	}
+
+	for _, tc := range testCases {
+		err := blobstore.ValidateKey(tc.key)
+		if tc.valid && err != nil {
+			t.Errorf("%q: unexpected error %v", tc.key, err)
+		}
+		if !tc.valid && err != blobstore.ErrorInvalidKey {
+			t.Errorf("%q: want ErrorInvalidKey, got %v", tc.key, err)
+		}
+	}
+}
+
+func TestNewKey(t *testing.T) {
+	a, err := blobstore.NewKey("transactions/1/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b, _ := blobstore.NewKey("transactions/1")
+
+	if !strings.HasPrefix(a, "transactions/1/") || blobstore.ValidateKey(a) != nil {
+		t.Errorf("malformed key %q", a)
+	}
+
+	if a == b {
+		t.Errorf("keys must be random, got %q twice", a)
+	}
+}
+
+// testStore runs the behaviour every BlobStore implementation shares
+func testStore(t *testing.T, store blobstore.BlobStore) {
+	ctx := context.Background()
+
+	t.Run("Get missing blob", func(t *testing.T) {
+		if _, err := store.Get(ctx, "missing"); err != blobstore.ErrorNotFound {
+			t.Errorf("want ErrorNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Put, get and delete blob", func(t *testing.T) {
+		key := "transactions/1/blob"
+		content := "%PDF-1.4 receipt"
+
+		if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "application/pdf"); err != nil {
+			t.Fatalf("couldn't put blob: %v", err)
+		}
+
+		r, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("couldn't get blob: %v", err)
+		}
+		got, _ := io.ReadAll(r)
+		r.Close()
+
+		if string(got) != content {
+			t.Errorf("want %q, got %q", content, got)
+		}
+
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("couldn't delete blob: %v", err)
+		}
+
+		if _, err := store.Get(ctx, key); err != blobstore.ErrorNotFound {
+			t.Errorf("want ErrorNotFound after delete, got %v", err)
+		}
+	})
+
+	t.Run("Delete missing blob", func(t *testing.T) {
+		if err := store.Delete(ctx, "missing"); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Invalid key", func(t *testing.T) {
+		if err := store.Put(ctx, "../escape", strings.NewReader(""), 0, ""); err != blobstore.ErrorInvalidKey {
+			t.Errorf("want ErrorInvalidKey, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package blobstore_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"expense-api/internal/blobstore"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	testAccessKeyID     = "minioadmin"
+	testSecretAccessKey = "minioadmin-secret"
+	testRegion          = "us-east-1"
+	testBucket          = "receipts"
+)
+
+// fakeS3 is a minimal stand-in for MinIO: a single bucket that only accepts requests signed with the test credentials
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+var authorizationPattern = regexp.MustCompile(`^AWS4-HMAC-SHA256 Credential=([^/]+)/(\d{8})/([^/]+)/s3/aws4_request, SignedHeaders=([^,]+), Signature=([0-9a-f]{64})$`)
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>")
+		return
+	}
+
+	prefix := "/" + testBucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "<Error><Code>NoSuchBucket</Code></Error>")
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.objects[key] = body
+		s.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// authorized verifies the AWS Signature Version 4 of a request the way S3 does
+func (s *fakeS3) authorized(r *http.Request) bool {
+	m := authorizationPattern.FindStringSubmatch(r.Header.Get("Authorization"))
+	if m == nil || m[1] != testAccessKeyID || m[3] != testRegion {
+		return false
+	}
+	date, signedHeaders, signature := m[2], m[4], m[5]
+
+	amzDate := r.Header.Get("x-amz-date")
+	if !strings.HasPrefix(amzDate, date) {
+		return false
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := r.Method + "\n" + r.URL.EscapedPath() + "\n" + r.URL.RawQuery + "\n" +
+		canonicalHeaders.String() + "\n" + signedHeaders + "\n" + r.Header.Get("x-amz-content-sha256")
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hashedRequest[:])
+
+	key := []byte("AWS4" + testSecretAccessKey)
+	for _, part := range []string{date, testRegion, "s3", "aws4_request"} {
+		key = sign(key, part)
+	}
+
+	return hmac.Equal([]byte(hex.EncodeToString(sign(key, stringToSign))), []byte(signature))
+}
+
+func sign(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func newS3Store(t *testing.T, endpoint, secretAccessKey string) blobstore.BlobStore {
+	store, err := blobstore.NewS3(blobstore.S3Config{
+		Endpoint:        endpoint,
+		Region:          testRegion,
+		Bucket:          testBucket,
+		AccessKeyID:     testAccessKeyID,
+		SecretAccessKey: secretAccessKey,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create store: %v", err)
+	}
+	return store
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := newS3Store(t, server.URL, testSecretAccessKey)
+	testStore(t, store)
+
+	t.Run("Content type is stored with the object", func(t *testing.T) {
+		content := "\x89PNG"
+		if err := store.Put(context.Background(), "image", strings.NewReader(content), int64(len(content)), "image/png"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := fake.types["image"]; got != "image/png" {
+			t.Errorf("want content type image/png, got %q", got)
+		}
+	})
+
+	t.Run("Wrong credentials", func(t *testing.T) {
+		store := newS3Store(t, server.URL, "wrong-secret")
+
+		err := store.Put(context.Background(), "key", strings.NewReader("x"), 1, "text/plain")
+		if err == nil || !strings.Contains(err.Error(), "403") {
+			t.Errorf("want 403 error, got %v", err)
+		}
+	})
+}
+
+func TestNewS3InvalidConfig(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		config blobstore.S3Config
+	}{
+		{"Relative endpoint", blobstore.S3Config{Endpoint: "localhost:9000", Bucket: testBucket}},
+		{"Missing bucket", blobstore.S3Config{Endpoint: "http://localhost:9000"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := blobstore.NewS3(tc.config); err == nil {
+				t.Errorf("want error, got nil")
+			}
+		})
+	}
+}
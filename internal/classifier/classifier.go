@@ -0,0 +1,307 @@
+package classifier
+
+import (
+	"container/list"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// Example is a classified transaction, reduced to its features
+type Example struct {
+	Features []string
+	Label    string
+}
+
+// Suggestion is a label with the probability the classifier gives it, between 0 and 1
+type Suggestion struct {
+	Label      string
+	Confidence float64
+}
+
+// Features describes a transaction by the words of its description, the sign and order of
+// magnitude of its amount and its wallet. Numbers in descriptions are left out, they are
+// mostly card numbers, dates and references that never repeat.
+func Features(description string, amount decimal.Decimal, walletID uint) []string {
+	var features []string
+
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) < 2 || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		features = append(features, "word:"+word)
+	}
+
+	if !amount.IsZero() {
+		sign := "+"
+		if amount.IsNegative() {
+			sign = "-"
+		}
+		digits := len(amount.Abs().Truncate(0).String())
+		if amount.Abs().LessThan(decimal.NewFromInt(1)) {
+			digits = 0
+		}
+		features = append(features, "amount:"+sign+strconv.Itoa(digits))
+	}
+
+	if walletID != 0 {
+		features = append(features, "wallet:"+strconv.FormatUint(uint64(walletID), 10))
+	}
+
+	return features
+}
+
+type labelStats struct {
+	examples int
+	features map[string]int
+	total    int
+}
+
+// Classifier is a multinomial naive Bayes classifier with add-one smoothing. It learns and
+// forgets examples one at a time. It is safe for concurrent use.
+type Classifier struct {
+	mu         sync.RWMutex
+	examples   int
+	labels     map[string]*labelStats
+	vocabulary map[string]int
+}
+
+func New() *Classifier {
+	return &Classifier{
+		labels:     make(map[string]*labelStats),
+		vocabulary: make(map[string]int),
+	}
+}
+
+// Train returns a classifier that learnt all examples
+func Train(examples []Example) *Classifier {
+	c := New()
+	for _, e := range examples {
+		c.Learn(e)
+	}
+	return c
+}
+
+// Learn adds the example to what the classifier knows. Examples without a label are ignored.
+func (c *Classifier) Learn(e Example) {
+	if e.Label == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.labels[e.Label]
+	if !ok {
+		stats = &labelStats{features: make(map[string]int)}
+		c.labels[e.Label] = stats
+	}
+
+	c.examples++
+	stats.examples++
+	for _, f := range e.Features {
+		stats.features[f]++
+		stats.total++
+		c.vocabulary[f]++
+	}
+}
+
+// Forget takes back an example learnt before, e.g. when the user changes a transaction's category
+func (c *Classifier) Forget(e Example) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.labels[e.Label]
+	if !ok || stats.examples == 0 {
+		return
+	}
+
+	c.examples--
+	stats.examples--
+	for _, f := range e.Features {
+		if stats.features[f] == 0 {
+			continue
+		}
+		stats.features[f]--
+		stats.total--
+		if stats.features[f] == 0 {
+			delete(stats.features, f)
+		}
+
+		c.vocabulary[f]--
+		if c.vocabulary[f] <= 0 {
+			delete(c.vocabulary, f)
+		}
+	}
+
+	if stats.examples == 0 {
+		delete(c.labels, e.Label)
+	}
+}
+
+// Suggest returns the k most likely labels for the features, most likely first. The confidences
+// of all labels add up to 1, so with few labels even a poor match can look confident.
+func (c *Classifier) Suggest(features []string, k int) []Suggestion {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.examples == 0 || k <= 0 {
+		return nil
+	}
+
+	vocabulary := float64(len(c.vocabulary))
+	suggestions := make([]Suggestion, 0, len(c.labels))
+	max := math.Inf(-1)
+
+	for label, stats := range c.labels {
+		logP := math.Log(float64(stats.examples) / float64(c.examples))
+		for _, f := range features {
+			logP += math.Log((float64(stats.features[f]) + 1) / (float64(stats.total) + vocabulary))
+		}
+
+		suggestions = append(suggestions, Suggestion{Label: label, Confidence: logP})
+		max = math.Max(max, logP)
+	}
+
+	// Normalise the log-likelihoods to probabilities without underflowing
+	var sum float64
+	for i := range suggestions {
+		suggestions[i].Confidence = math.Exp(suggestions[i].Confidence - max)
+		sum += suggestions[i].Confidence
+	}
+	for i := range suggestions {
+		suggestions[i].Confidence /= sum
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Confidence != suggestions[j].Confidence {
+			return suggestions[i].Confidence > suggestions[j].Confidence
+		}
+		return suggestions[i].Label < suggestions[j].Label
+	})
+
+	if len(suggestions) > k {
+		suggestions = suggestions[:k]
+	}
+	return suggestions
+}
+
+const (
+	// DefaultCapacity is how many users' classifiers a registry keeps by default
+	DefaultCapacity = 1000
+	// MaxAge is how long a classifier is used before it is trained again from the user's history
+	MaxAge = time.Hour
+)
+
+// Registry keeps the classifiers of the users who asked for suggestions most recently in memory, up
+// to its capacity. Each classifier is trained from the user's history and is kept along with a stamp
+// of that history; it is trained again once the stamp changes, so changes made through other
+// instances of the API are taken into account as well. Changes made through this instance are
+// learnt one by one with Update instead.
+type Registry struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[uint]*list.Element
+	// recent lists the entries, the most recently used first
+	recent *list.List
+}
+
+type registryEntry struct {
+	userID     uint
+	stamp      string
+	classifier *Classifier
+	trainedAt  time.Time
+}
+
+func NewRegistry(capacity int) *Registry {
+	return &Registry{
+		capacity: capacity,
+		entries:  make(map[uint]*list.Element),
+		recent:   list.New(),
+	}
+}
+
+// Get returns the user's classifier for the history with the stamp, training it from the examples
+// load returns if there is none yet or it was trained on a different history
+func (r *Registry) Get(userID uint, stamp string, load func() ([]Example, error)) (*Classifier, error) {
+	if c := r.lookup(userID, stamp); c != nil {
+		return c, nil
+	}
+
+	examples, err := load()
+	if err != nil {
+		return nil, err
+	}
+	trained := Train(examples)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.entries[userID]; ok {
+		r.recent.Remove(e)
+	}
+	r.entries[userID] = r.recent.PushFront(&registryEntry{userID, stamp, trained, time.Now()})
+
+	for r.recent.Len() > r.capacity {
+		oldest := r.recent.Back()
+		r.recent.Remove(oldest)
+		delete(r.entries, oldest.Value.(*registryEntry).userID)
+	}
+	return trained, nil
+}
+
+// Update lets the user's classifier learn a change of their history, if it is the classifier of the
+// history with the stamp before the change, and keeps it for the stamp after the change. Classifiers
+// of other histories are left as they are, Get trains them again.
+//
+// A change made through another instance between reading the two stamps isn't learnt; classifiers
+// are trained again after MaxAge so that it isn't missed for long.
+func (r *Registry) Update(userID uint, before, after string, learn func(c *Classifier)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[userID]
+	if !ok || e.Value.(*registryEntry).stamp != before {
+		return
+	}
+
+	entry := e.Value.(*registryEntry)
+	learn(entry.classifier)
+	entry.stamp = after
+}
+
+// lookup returns the user's classifier if it was trained on the history with the stamp and isn't
+// too old, and nil otherwise
+func (r *Registry) lookup(userID uint, stamp string) *Classifier {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.entries[userID]
+	if !ok {
+		return nil
+	}
+
+	entry := e.Value.(*registryEntry)
+	if entry.stamp != stamp || time.Since(entry.trainedAt) > MaxAge {
+		return nil
+	}
+	r.recent.MoveToFront(e)
+	return entry.classifier
+}
+
+// Len is the number of classifiers in memory
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.recent.Len()
+}
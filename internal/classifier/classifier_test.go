@@ -0,0 +1,192 @@
+package classifier_test
+
+import (
+	"errors"
+	"expense-api/internal/classifier"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestFeatures(t *testing.T) {
+	testCases := []struct {
+		desc        string
+		description string
+		amount      string
+		walletID    uint
+		expected    []string
+	}{
+		{"Bank description", "POS 1234 LIDL SAGT 56", "-23.40", 2, []string{"word:pos", "word:lidl", "word:sagt", "amount:-2", "wallet:2"}},
+		{"Income", "Salary", "2500", 1, []string{"word:salary", "amount:+4", "wallet:1"}},
+		{"Less than one", "Fee", "-0.30", 0, []string{"word:fee", "amount:-0"}},
+		{"Words with digits are kept", "Uber*Trip B2B", "0", 0, []string{"word:uber", "word:trip", "word:b2b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got := classifier.Features(tc.description, decimal.RequireFromString(tc.amount), tc.walletID)
+			if diff := cmp.Diff(tc.expected, got); diff != "" {
+				t.Errorf("unexpected features (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func example(description, amount, label string) classifier.Example {
+	return classifier.Example{
+		Features: classifier.Features(description, decimal.RequireFromString(amount), 1),
+		Label:    label,
+	}
+}
+
+func history() []classifier.Example {
+	return []classifier.Example{
+		example("POS 1234 LIDL SAGT 56", "-23.40", "groceries"),
+		example("POS 9876 LIDL BERLIN", "-41.10", "groceries"),
+		example("REWE Markt", "-18.99", "groceries"),
+		example("UBER *TRIP", "-12.50", "transport"),
+		example("DB Fernverkehr ticket", "-79.90", "transport"),
+		example("Salary ACME", "2500", "income"),
+		example("Uncategorised", "-5", ""),
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	c := classifier.Train(history())
+
+	t.Run("Most likely label first", func(t *testing.T) {
+		suggestions := c.Suggest(classifier.Features("POS 5555 LIDL MUENCHEN", decimal.NewFromInt(-30), 1), 2)
+
+		if len(suggestions) != 2 {
+			t.Fatalf("expected 2 suggestions, got %d", len(suggestions))
+		}
+		if suggestions[0].Label != "groceries" {
+			t.Errorf("expected groceries first, got %+v", suggestions)
+		}
+		if suggestions[0].Confidence <= 0.5 || suggestions[0].Confidence < suggestions[1].Confidence {
+			t.Errorf("expected a confident, ordered suggestion, got %+v", suggestions)
+		}
+	})
+
+	t.Run("Confidences add up to one", func(t *testing.T) {
+		var sum float64
+		for _, s := range c.Suggest(classifier.Features("uber", decimal.Zero, 0), 10) {
+			sum += s.Confidence
+		}
+		if sum < 0.999 || sum > 1.001 {
+			t.Errorf("expected confidences to add up to 1, got %v", sum)
+		}
+	})
+
+	t.Run("Nothing learnt", func(t *testing.T) {
+		if got := classifier.New().Suggest([]string{"word:lidl"}, 3); got != nil {
+			t.Errorf("expected no suggestions, got %+v", got)
+		}
+	})
+}
+
+func TestForget(t *testing.T) {
+	c := classifier.Train(history())
+	features := classifier.Features("UBER *TRIP", decimal.RequireFromString("-12.50"), 1)
+
+	if got := c.Suggest(features, 1); got[0].Label != "transport" {
+		t.Fatalf("expected transport, got %+v", got)
+	}
+
+	// The user recategorises their Uber trips as business expenses
+	c.Forget(example("UBER *TRIP", "-12.50", "transport"))
+	c.Learn(example("UBER *TRIP", "-12.50", "business"))
+
+	if got := c.Suggest(features, 1); got[0].Label != "business" {
+		t.Errorf("expected business after the correction, got %+v", got)
+	}
+
+	// Forgetting what was never learnt changes nothing
+	c.Forget(example("UBER *TRIP", "-12.50", "holidays"))
+	if got := c.Suggest(features, 10); len(got) != 4 {
+		t.Errorf("expected 4 labels, got %+v", got)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := classifier.NewRegistry(2)
+	loads := 0
+	load := func() ([]classifier.Example, error) {
+		loads++
+		return history(), nil
+	}
+
+	first, err := r.Get(1, "stamp-1", load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := r.Get(1, "stamp-1", load)
+	if first != second || loads != 1 {
+		t.Errorf("expected the classifier to be trained once, trained %d times", loads)
+	}
+
+	// The history changed, possibly through another instance
+	if retrained, _ := r.Get(1, "stamp-2", load); retrained == first || loads != 2 {
+		t.Errorf("expected the classifier to be trained again, trained %d times", loads)
+	}
+
+	failure := errors.New("database down")
+	if _, err := r.Get(2, "stamp-1", func() ([]classifier.Example, error) { return nil, failure }); err != failure {
+		t.Errorf("expected %v, got %v", failure, err)
+	}
+	if r.Len() != 1 {
+		t.Errorf("expected no classifier after a failed load, got %d classifiers", r.Len())
+	}
+
+	// The least recently used classifier makes room
+	r.Get(2, "stamp-1", load)
+	r.Get(1, "stamp-2", load)
+	r.Get(3, "stamp-1", load)
+	if r.Len() != 2 {
+		t.Errorf("expected 2 classifiers, got %d", r.Len())
+	}
+	loads = 0
+	r.Get(1, "stamp-2", load)
+	r.Get(2, "stamp-1", load)
+	if loads != 1 {
+		t.Errorf("expected only the classifier of user 2 to be trained again, trained %d", loads)
+	}
+}
+
+func TestRegistryUpdate(t *testing.T) {
+	r := classifier.NewRegistry(2)
+	loads := 0
+	load := func() ([]classifier.Example, error) {
+		loads++
+		return history(), nil
+	}
+	features := classifier.Features("UBER *TRIP", decimal.RequireFromString("-12.50"), 1)
+	recategorise := func(c *classifier.Classifier) {
+		c.Forget(example("UBER *TRIP", "-12.50", "transport"))
+		c.Learn(example("UBER *TRIP", "-12.50", "business"))
+	}
+
+	trained, _ := r.Get(1, "stamp-1", load)
+
+	// This instance changed the history it was trained on
+	r.Update(1, "stamp-1", "stamp-2", recategorise)
+	updated, _ := r.Get(1, "stamp-2", load)
+	if updated != trained || loads != 1 {
+		t.Errorf("expected the change to be learnt without training again, trained %d times", loads)
+	}
+	if got := updated.Suggest(features, 1); got[0].Label != "business" {
+		t.Errorf("expected business after the change, got %+v", got)
+	}
+
+	// The history changed through another instance before this one changed it
+	r.Update(1, "stamp-3", "stamp-4", recategorise)
+	if _, err := r.Get(1, "stamp-4", load); err != nil || loads != 2 {
+		t.Errorf("expected the classifier to be trained again, trained %d times", loads)
+	}
+
+	// Classifiers that aren't in memory have nothing to learn
+	r.Update(2, "stamp-1", "stamp-2", func(*classifier.Classifier) {
+		t.Error("expected no classifier to learn the change")
+	})
+}
@@ -0,0 +1,64 @@
+package currency
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Default is the currency used for wallets and users that don't specify one
+const Default = "EUR"
+
+// Pivot is the currency through which cross rates are calculated (the ECB publishes all rates against EUR)
+const Pivot = "EUR"
+
+var (
+	ErrorUnknownCurrency = errors.New("unknown ISO 4217 currency code")
+	ErrorAmountPrecision = errors.New("amount has more decimal places than the currency allows")
+)
+
+// minorUnits maps ISO 4217 currency codes to the number of digits after the decimal separator
+var minorUnits = map[string]int32{
+	"AED": 2, "AUD": 2, "BGN": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2,
+	"CLP": 0, "CNY": 2, "COP": 2, "CZK": 2, "DKK": 2, "EGP": 2, "EUR": 2,
+	"GBP": 2, "HKD": 2, "HRK": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0, "KWD": 3, "MAD": 2, "MXN": 2,
+	"MYR": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PHP": 2, "PLN": 2, "RON": 2,
+	"RSD": 2, "RUB": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TND": 3,
+	"TRY": 2, "TWD": 2, "UAH": 2, "USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// Normalize upper-cases a currency code and strips surrounding whitespace
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValid checks if the code is a supported ISO 4217 currency code
+func IsValid(code string) bool {
+	_, ok := minorUnits[code]
+	return ok
+}
+
+// MinorUnits returns the number of decimal places allowed for the currency
+func MinorUnits(code string) (int32, error) {
+	units, ok := minorUnits[code]
+	if !ok {
+		return 0, ErrorUnknownCurrency
+	}
+	return units, nil
+}
+
+// ValidateAmount checks that the amount doesn't use more decimal places than the currency's minor units
+func ValidateAmount(code string, amount decimal.Decimal) error {
+	units, err := MinorUnits(code)
+	if err != nil {
+		return err
+	}
+
+	if !amount.Round(units).Equal(amount) {
+		return ErrorAmountPrecision
+	}
+
+	return nil
+}
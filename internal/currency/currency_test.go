@@ -0,0 +1,75 @@
+package currency_test
+
+import (
+	"expense-api/internal/currency"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestValidateAmount(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		code   string
+		amount string
+		err    error
+	}{
+		{
+			desc:   "Whole amount in a currency with cents",
+			code:   "EUR",
+			amount: "100",
+		},
+		{
+			desc:   "Amount with cents in a currency with cents",
+			code:   "USD",
+			amount: "-15.99",
+		},
+		{
+			desc:   "Amount with fractions of a cent",
+			code:   "GBP",
+			amount: "15.999",
+			err:    currency.ErrorAmountPrecision,
+		},
+		{
+			desc:   "Trailing zeros don't count as extra precision",
+			code:   "JPY",
+			amount: "500.00",
+		},
+		{
+			desc:   "Amount with decimals in a currency without minor units",
+			code:   "JPY",
+			amount: "500.5",
+			err:    currency.ErrorAmountPrecision,
+		},
+		{
+			desc:   "Three decimals in a currency with three minor units",
+			code:   "KWD",
+			amount: "1.125",
+		},
+		{
+			desc:   "Unknown currency",
+			code:   "XYZ",
+			amount: "1",
+			err:    currency.ErrorUnknownCurrency,
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := currency.ValidateAmount(tC.code, decimal.RequireFromString(tC.amount))
+			if got != tC.err {
+				t.Errorf("Got error: '%v'; Want error: '%v'", got, tC.err)
+			}
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	if !currency.IsValid(currency.Normalize(" usd ")) {
+		t.Errorf("normalized 'usd' should be a valid currency")
+	}
+
+	if currency.IsValid("usd") {
+		t.Errorf("currency codes are expected to be normalized")
+	}
+}
@@ -0,0 +1,139 @@
+package currency
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"expense-api/internal/model"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	SourceECB    = "ecb"
+	SourceManual = "manual"
+)
+
+// DateLayout is the layout used for exchange rate dates
+const DateLayout = "2006-01-02"
+
+var ErrorMalformedECBFile = errors.New("malformed ECB exchange rate file")
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ParseECBXML parses the ECB euro foreign exchange reference rates in their XML format
+// (eurofxref-daily.xml, eurofxref-hist.xml, ...)
+func ParseECBXML(r io.Reader) ([]*model.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, ErrorMalformedECBFile
+	}
+
+	var rates []*model.ExchangeRate
+	for _, day := range envelope.Cube.Days {
+		date, err := time.Parse(DateLayout, day.Time)
+		if err != nil {
+			return nil, ErrorMalformedECBFile
+		}
+
+		for _, r := range day.Rates {
+			rate, ok, err := newECBRate(date, r.Currency, r.Rate)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				rates = append(rates, rate)
+			}
+		}
+	}
+
+	return rates, nil
+}
+
+// ParseECBCSV parses the ECB euro foreign exchange reference rates in their CSV format
+// (eurofxref.csv, eurofxref-hist.csv, ...), where the first column is the date and each
+// following column holds the rates of one currency
+func ParseECBCSV(r io.Reader) ([]*model.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, ErrorMalformedECBFile
+	}
+
+	header := records[0]
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "date") {
+		return nil, ErrorMalformedECBFile
+	}
+
+	var rates []*model.ExchangeRate
+	for _, record := range records[1:] {
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		date, err := parseECBDate(record[0])
+		if err != nil {
+			return nil, ErrorMalformedECBFile
+		}
+
+		for i := 1; i < len(record) && i < len(header); i++ {
+			rate, ok, err := newECBRate(date, header[i], record[i])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				rates = append(rates, rate)
+			}
+		}
+	}
+
+	return rates, nil
+}
+
+// newECBRate creates a rate against EUR, skipping empty cells and currencies that are not supported (anymore)
+func newECBRate(date time.Time, code, value string) (*model.ExchangeRate, bool, error) {
+	code = Normalize(code)
+	value = strings.TrimSpace(value)
+
+	if code == "" || value == "" || value == "N/A" || !IsValid(code) {
+		return nil, false, nil
+	}
+
+	rate, err := decimal.NewFromString(value)
+	if err != nil || !rate.IsPositive() {
+		return nil, false, ErrorMalformedECBFile
+	}
+
+	return &model.ExchangeRate{
+		Date:   date,
+		Base:   Pivot,
+		Quote:  code,
+		Rate:   rate,
+		Source: SourceECB,
+	}, true, nil
+}
+
+// parseECBDate accepts both the ISO date used in the CSV history and the '02 January 2006' layout of the daily CSV
+func parseECBDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(DateLayout, value); err == nil {
+		return date, nil
+	}
+	return time.Parse("02 January 2006", value)
+}
@@ -0,0 +1,94 @@
+package currency_test
+
+import (
+	"expense-api/internal/currency"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time='2021-11-19'>
+			<Cube currency='USD' rate='1.1370'/>
+			<Cube currency='GBP' rate='0.84108'/>
+		</Cube>
+		<Cube time='2021-11-18'>
+			<Cube currency='USD' rate='1.1338'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+const ecbCSV = `Date,USD,JPY,CYP,GBP,
+2021-11-19,1.1370,129.84,N/A,0.84108,
+2021-11-18,1.1338,129.55,N/A,0.84023,
+`
+
+func TestParseECBXML(t *testing.T) {
+	rates, err := currency.ParseECBXML(strings.NewReader(ecbXML))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rates) != 3 {
+		t.Fatalf("expected 3 rates, got %d", len(rates))
+	}
+
+	first := rates[0]
+	if first.Base != "EUR" || first.Quote != "USD" || !first.Rate.Equal(decimal.RequireFromString("1.137")) {
+		t.Errorf("unexpected first rate: %+v", first)
+	}
+
+	if !first.Date.Equal(time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date: %v", first.Date)
+	}
+
+	if first.Source != currency.SourceECB {
+		t.Errorf("expected source %s, got %s", currency.SourceECB, first.Source)
+	}
+}
+
+func TestParseECBCSV(t *testing.T) {
+	t.Run("History file with withdrawn currencies", func(t *testing.T) {
+		rates, err := currency.ParseECBCSV(strings.NewReader(ecbCSV))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// CYP isn't supported and has no values, so only USD, JPY and GBP are imported
+		if len(rates) != 6 {
+			t.Fatalf("expected 6 rates, got %d", len(rates))
+		}
+
+		last := rates[len(rates)-1]
+		if last.Quote != "GBP" || !last.Rate.Equal(decimal.RequireFromString("0.84023")) {
+			t.Errorf("unexpected last rate: %+v", last)
+		}
+	})
+
+	t.Run("Daily file", func(t *testing.T) {
+		daily := "Date, USD, JPY\n19 November 2021, 1.1370, 129.84\n"
+
+		rates, err := currency.ParseECBCSV(strings.NewReader(daily))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(rates) != 2 || !rates[0].Date.Equal(time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("unexpected rates: %+v", rates)
+		}
+	})
+
+	t.Run("Malformed file", func(t *testing.T) {
+		if _, err := currency.ParseECBCSV(strings.NewReader("USD,JPY\n1,2\n")); err != currency.ErrorMalformedECBFile {
+			t.Errorf("expected %v, got %v", currency.ErrorMalformedECBFile, err)
+		}
+	})
+}
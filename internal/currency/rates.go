@@ -0,0 +1,91 @@
+package currency
+
+import (
+	"errors"
+	"expense-api/internal/model"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var ErrorRateNotFound = errors.New("no exchange rate found")
+
+type pair struct {
+	base  string
+	quote string
+}
+
+// Rates converts amounts between currencies using the latest rate known on a given date
+type Rates struct {
+	pairs map[pair][]*model.ExchangeRate
+}
+
+// NewRates indexes exchange rates by currency pair, ordered by date
+func NewRates(rates []*model.ExchangeRate) *Rates {
+	pairs := map[pair][]*model.ExchangeRate{}
+	for _, r := range rates {
+		p := pair{r.Base, r.Quote}
+		pairs[p] = append(pairs[p], r)
+	}
+
+	for _, list := range pairs {
+		sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
+	}
+
+	return &Rates{pairs}
+}
+
+// Rate returns how many units of 'to' one unit of 'from' was worth at the given time.
+// Direct rates are preferred over inverted ones, which are preferred over cross rates through the Pivot currency.
+func (r *Rates) Rate(from, to string, at time.Time) (decimal.Decimal, error) {
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	if rate, ok := r.lookup(from, to, at); ok {
+		return rate, nil
+	}
+
+	if rate, ok := r.lookup(to, from, at); ok {
+		return decimal.NewFromInt(1).DivRound(rate, 16), nil
+	}
+
+	if from != Pivot && to != Pivot {
+		fromPivot, errFrom := r.Rate(from, Pivot, at)
+		pivotTo, errTo := r.Rate(Pivot, to, at)
+		if errFrom == nil && errTo == nil {
+			return fromPivot.Mul(pivotTo), nil
+		}
+	}
+
+	return decimal.Zero, fmt.Errorf("%w for %s/%s on %s", ErrorRateNotFound, from, to, at.Format(DateLayout))
+}
+
+// Convert converts the amount and rounds it to the minor units of the target currency
+func (r *Rates) Convert(amount decimal.Decimal, from, to string, at time.Time) (decimal.Decimal, error) {
+	units, err := MinorUnits(to)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	rate, err := r.Rate(from, to, at)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return amount.Mul(rate).Round(units), nil
+}
+
+// lookup finds the most recent rate for the pair that is not dated after 'at'
+func (r *Rates) lookup(base, quote string, at time.Time) (decimal.Decimal, bool) {
+	list := r.pairs[pair{base, quote}]
+
+	i := sort.Search(len(list), func(i int) bool { return list[i].Date.After(at) })
+	if i == 0 {
+		return decimal.Zero, false
+	}
+
+	return list[i-1].Rate, true
+}
@@ -0,0 +1,96 @@
+package currency_test
+
+import (
+	"errors"
+	"expense-api/internal/currency"
+	"expense-api/internal/model"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func newRate(date time.Time, base, quote, rate string) *model.ExchangeRate {
+	return &model.ExchangeRate{Date: date, Base: base, Quote: quote, Rate: decimal.RequireFromString(rate)}
+}
+
+func TestRatesConvert(t *testing.T) {
+	day1 := time.Date(2021, 11, 18, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC)
+
+	rates := currency.NewRates([]*model.ExchangeRate{
+		newRate(day2, "EUR", "USD", "1.25"),
+		newRate(day1, "EUR", "USD", "1.10"),
+		newRate(day2, "EUR", "GBP", "0.80"),
+	})
+
+	testCases := []struct {
+		desc     string
+		amount   string
+		from     string
+		to       string
+		at       time.Time
+		expected string
+	}{
+		{
+			desc:     "Same currency",
+			amount:   "10.00",
+			from:     "USD",
+			to:       "USD",
+			at:       day1,
+			expected: "10",
+		},
+		{
+			desc:     "Direct rate on an older date",
+			amount:   "10",
+			from:     "EUR",
+			to:       "USD",
+			at:       day1.Add(12 * time.Hour),
+			expected: "11",
+		},
+		{
+			desc:     "Direct rate uses the latest rate",
+			amount:   "10",
+			from:     "EUR",
+			to:       "USD",
+			at:       day2.AddDate(0, 1, 0),
+			expected: "12.5",
+		},
+		{
+			desc:     "Inverted rate",
+			amount:   "12.50",
+			from:     "USD",
+			to:       "EUR",
+			at:       day2,
+			expected: "10",
+		},
+		{
+			desc:     "Cross rate through the pivot currency",
+			amount:   "100",
+			from:     "USD",
+			to:       "GBP",
+			at:       day2,
+			expected: "64",
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := rates.Convert(decimal.RequireFromString(tC.amount), tC.from, tC.to, tC.at)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !got.Equal(decimal.RequireFromString(tC.expected)) {
+				t.Errorf("expected %s, got %s", tC.expected, got)
+			}
+		})
+	}
+
+	t.Run("No rate before the transaction date", func(t *testing.T) {
+		_, err := rates.Convert(decimal.NewFromInt(1), "EUR", "GBP", day1)
+		if !errors.Is(err, currency.ErrorRateNotFound) {
+			t.Errorf("expected %v, got %v", currency.ErrorRateNotFound, err)
+		}
+	})
+}
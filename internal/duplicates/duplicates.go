@@ -0,0 +1,125 @@
+package duplicates
+
+import (
+	"expense-api/internal/model"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Window is how far apart two transactions can be and still be the same purchase. Card payments
+// are often booked by the bank a few days after they were entered by hand.
+const Window = 72 * time.Hour
+
+// MinSimilarity is how similar descriptions of duplicates have to be, see Similarity
+const MinSimilarity = 0.5
+
+// Pair is two transactions that are likely the same, the older one first
+type Pair struct {
+	First      *model.Transaction
+	Second     *model.Transaction
+	Similarity float64
+}
+
+// Similarity compares descriptions by their words, ignoring case, punctuation and numbers. It is
+// the share of the words of the shorter description that also appear in the longer one, so a
+// hand-written "Lidl" fully matches the bank's "POS 1234 LIDL SAGT 56". Descriptions without words
+// say nothing either way and are fully similar to anything.
+func Similarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 1
+	}
+
+	if len(wordsA) > len(wordsB) {
+		wordsA, wordsB = wordsB, wordsA
+	}
+
+	common := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(wordsA))
+}
+
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if strings.IndexFunc(w, unicode.IsLetter) >= 0 {
+			set[w] = true
+		}
+	}
+	return set
+}
+
+// IsDuplicate tells whether two different transactions are likely the same purchase: in the same
+// wallet, of the same amount, within the Window and with similar descriptions
+func IsDuplicate(a, b *model.Transaction) bool {
+	return a.ID != b.ID &&
+		a.WalletID == b.WalletID &&
+		a.Amount.Equal(b.Amount) &&
+		withinWindow(a.Timestamp, b.Timestamp) &&
+		Similarity(a.Description, b.Description) >= MinSimilarity
+}
+
+func withinWindow(a, b time.Time) bool {
+	d := a.Sub(b)
+	return d <= Window && d >= -Window
+}
+
+// Find returns the transactions among candidates that are likely duplicates of t
+func Find(t *model.Transaction, candidates []*model.Transaction) []*model.Transaction {
+	var found []*model.Transaction
+	for _, c := range candidates {
+		if IsDuplicate(t, c) {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// Pairs finds all pairs of likely duplicates among the transactions, most similar first and the
+// most recent first on equal similarity
+func Pairs(transactions []*model.Transaction) []*Pair {
+	sorted := make([]*model.Transaction, len(transactions))
+	copy(sorted, transactions)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].WalletID != sorted[j].WalletID {
+			return sorted[i].WalletID < sorted[j].WalletID
+		}
+		if c := sorted[i].Amount.Cmp(sorted[j].Amount); c != 0 {
+			return c < 0
+		}
+		if !sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	// Duplicates are next to each other once sorted, only look ahead as long as they can be
+	var pairs []*Pair
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if b.WalletID != a.WalletID || !b.Amount.Equal(a.Amount) || !withinWindow(a.Timestamp, b.Timestamp) {
+				break
+			}
+
+			if IsDuplicate(a, b) {
+				pairs = append(pairs, &Pair{a, b, Similarity(a.Description, b.Description)})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		return pairs[i].Second.Timestamp.After(pairs[j].Second.Timestamp)
+	})
+	return pairs
+}
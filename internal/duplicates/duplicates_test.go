@@ -0,0 +1,99 @@
+package duplicates_test
+
+import (
+	"expense-api/internal/duplicates"
+	"expense-api/internal/model"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		a, b     string
+		expected float64
+	}{
+		{"Same words in other case", "Lidl Berlin", "LIDL berlin", 1},
+		{"Hand-written against bank description", "Lidl", "POS 1234 LIDL SAGT 56", 1},
+		{"Half of the words", "Lidl groceries", "LIDL Berlin Mitte", 0.5},
+		{"Nothing in common", "Rent", "Salary", 0},
+		{"Numbers are ignored", "Invoice 2021-11", "Invoice 2021-12", 1},
+		{"Empty description", "", "Rent", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := duplicates.Similarity(tc.a, tc.b); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func transaction(id, walletID uint, amount string, timestamp time.Time, description string) *model.Transaction {
+	return &model.Transaction{
+		Model:       model.Model{ID: id},
+		WalletID:    walletID,
+		Amount:      decimal.RequireFromString(amount),
+		Timestamp:   timestamp,
+		Description: description,
+	}
+}
+
+func TestIsDuplicate(t *testing.T) {
+	now := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	original := transaction(1, 1, "-23.40", now, "Lidl")
+
+	testCases := []struct {
+		desc     string
+		other    *model.Transaction
+		expected bool
+	}{
+		{"Booked two days later", transaction(2, 1, "-23.4", now.Add(48*time.Hour), "POS 1234 LIDL SAGT 56"), true},
+		{"Itself", original, false},
+		{"Other wallet", transaction(2, 2, "-23.40", now, "Lidl"), false},
+		{"Other amount", transaction(2, 1, "-23.41", now, "Lidl"), false},
+		{"Outside the window", transaction(2, 1, "-23.40", now.Add(-duplicates.Window-time.Second), "Lidl"), false},
+		{"Different description", transaction(2, 1, "-23.40", now, "Rewe"), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := duplicates.IsDuplicate(original, tc.other); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPairs(t *testing.T) {
+	now := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	transactions := []*model.Transaction{
+		transaction(1, 1, "-23.40", now, "Lidl groceries"),
+		transaction(2, 1, "-9.99", now, "Netflix"),
+		transaction(3, 1, "-23.40", now.Add(24*time.Hour), "POS 1234 LIDL SAGT 56"),
+		transaction(4, 1, "-9.99", now.Add(30*24*time.Hour), "Netflix"),
+		transaction(5, 2, "-23.40", now, "Lidl"),
+		transaction(6, 1, "-23.40", now.Add(-time.Hour), "Lidl"),
+	}
+
+	pairs := duplicates.Pairs(transactions)
+
+	var got [][2]uint
+	for _, p := range pairs {
+		got = append(got, [2]uint{p.First.ID, p.Second.ID})
+	}
+	expected := [][2]uint{{6, 3}, {6, 1}, {1, 3}}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected pairs %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected pairs %v, got %v", expected, got)
+			break
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DispatchInterval is how often Run looks for new events in the outbox
+	DispatchInterval = time.Second
+	// MaxAttempts is how often an event is published before it is given up on
+	MaxAttempts = 10
+
+	batchSize = 100
+)
+
+// Event is a change of a wallet, party or transaction, see model.OutboxEvent
+type Event struct {
+	ID string
+	// Sequence is the position of the event in the outbox
+	Sequence    uint
+	Type        string
+	Aggregate   string
+	AggregateID uint
+	// UserID owns the record, HouseholdID is the household it belongs to if any
+	UserID      uint
+	HouseholdID *uint
+	// ActorID made the change, nil for changes nobody asked for
+	ActorID    *uint
+	OccurredAt time.Time
+	Object     json.RawMessage
+	Changes    model.AuditChanges
+}
+
+// FromOutbox is the event stored in the outbox
+func FromOutbox(e *model.OutboxEvent) *Event {
+	return &Event{
+		ID:          e.EventID,
+		Sequence:    e.ID,
+		Type:        e.Type,
+		Aggregate:   e.Aggregate,
+		AggregateID: e.AggregateID,
+		UserID:      e.UserID,
+		HouseholdID: e.HouseholdID,
+		ActorID:     e.ActorID,
+		OccurredAt:  e.CreatedAt,
+		Object:      json.RawMessage(e.Object),
+		Changes:     e.Changes,
+	}
+}
+
+// Handler handles the events a subscriber subscribed to. Events are delivered at least once, so
+// handlers have to cope with getting an event again, e.g. by remembering the IDs of the events
+// they handled.
+type Handler func(ctx context.Context, e *Event) error
+
+type subscription struct {
+	name    string
+	types   []string
+	handler Handler
+}
+
+// Bus publishes the events to the subscribers in this process
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe makes the handler get the events of the types, which can also be "<aggregate>.*" for
+// all events of an aggregate or "*" for all events. The name tells the subscriber apart in errors.
+func (b *Bus) Subscribe(name string, handler Handler, types ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, &subscription{name, types, handler})
+}
+
+// Publish hands the event to its subscribers in the order they subscribed, stopping at the first
+// one that fails
+func (b *Bus) Publish(ctx context.Context, e *Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, s := range b.subscriptions {
+		if !Matches(s.types, e.Type) {
+			continue
+		}
+		if err := s.handler(ctx, e); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+	}
+	return nil
+}
+
+// Matches tells whether the event type is one of the types, or matches one of their wildcards
+func Matches(types []string, eventType string) bool {
+	aggregate := strings.SplitN(eventType, ".", 2)[0]
+	for _, t := range types {
+		if t == eventType || t == "*" || t == aggregate+".*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher publishes the events of the outbox to the bus
+type Dispatcher struct {
+	repo repository.Repository
+	bus  *Bus
+}
+
+func NewDispatcher(repo repository.Repository, bus *Bus) *Dispatcher {
+	return &Dispatcher{repo, bus}
+}
+
+// Dispatch publishes the events of the outbox in the order they were written, until none is left.
+// When an event can't be published, the later events of the same aggregate wait for it, so the
+// subscribers see the changes of every record in order. Events that still fail after MaxAttempts
+// are logged and given up on.
+func (d *Dispatcher) Dispatch(ctx context.Context) error {
+	for {
+		failed := false
+		n, err := d.repo.OutboxDispatch(batchSize, func(outbox []*model.OutboxEvent) {
+			waiting := map[string]bool{}
+			for _, e := range outbox {
+				aggregate := fmt.Sprintf("%s/%d", e.Aggregate, e.AggregateID)
+				if waiting[aggregate] {
+					continue
+				}
+
+				e.Attempts++
+				err := d.bus.Publish(ctx, FromOutbox(e))
+				if err == nil {
+					now := time.Now()
+					e.PublishedAt = &now
+					e.Error = ""
+					continue
+				}
+
+				e.Error = err.Error()
+				if e.Attempts >= MaxAttempts {
+					log.Printf("giving up on event %s (%s of %s): %v", e.EventID, e.Type, aggregate, err)
+					now := time.Now()
+					e.PublishedAt = &now
+					continue
+				}
+				waiting[aggregate] = true
+				failed = true
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		// Failed events are retried on the next run, not right away
+		if n < batchSize || failed {
+			return nil
+		}
+	}
+}
+
+// Run dispatches right away and then every interval, until the context is done
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Dispatch(ctx); err != nil {
+			log.Printf("couldn't dispatch the outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"expense-api/internal/events"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/test/spies"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/mock"
+)
+
+func outboxEvent(id uint, eventType, aggregate string, aggregateID uint) *model.OutboxEvent {
+	e := &model.OutboxEvent{
+		EventID:     fmt.Sprintf("event-%d", id),
+		Type:        eventType,
+		Aggregate:   aggregate,
+		AggregateID: aggregateID,
+		UserID:      1,
+		Object:      `{"id":1}`,
+	}
+	e.ID = id
+	return e
+}
+
+// dispatching makes the repository hand the events to the dispatcher once
+func dispatching(repoSpy *spies.RepositorySpy, outbox ...*model.OutboxEvent) {
+	repoSpy.On("OutboxDispatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func([]*model.OutboxEvent))(outbox)
+	}).Return(len(outbox), nil).Once()
+}
+
+func TestBus(t *testing.T) {
+	t.Run("Publish to the matching subscribers in order", func(t *testing.T) {
+		var got []string
+		subscriber := func(name string) events.Handler {
+			return func(ctx context.Context, e *events.Event) error {
+				got = append(got, name+" "+e.Type)
+				return nil
+			}
+		}
+
+		bus := events.NewBus()
+		bus.Subscribe("all", subscriber("all"), "*")
+		bus.Subscribe("wallets", subscriber("wallets"), "wallet.*")
+		bus.Subscribe("deletions", subscriber("deletions"), model.EventWalletDeleted, model.EventTransactionDeleted)
+
+		for _, eventType := range []string{model.EventWalletCreated, model.EventWalletDeleted, model.EventPartyUpdated} {
+			if err := bus.Publish(context.Background(), &events.Event{Type: eventType}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+
+		expected := []string{
+			"all wallet.created", "wallets wallet.created",
+			"all wallet.deleted", "wallets wallet.deleted", "deletions wallet.deleted",
+			"all party.updated",
+		}
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Errorf("unexpected handled events (-expected +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Stop at the first failing subscriber", func(t *testing.T) {
+		failure := errors.New("down")
+		called := false
+
+		bus := events.NewBus()
+		bus.Subscribe("failing", func(ctx context.Context, e *events.Event) error { return failure }, "*")
+		bus.Subscribe("next", func(ctx context.Context, e *events.Event) error { called = true; return nil }, "*")
+
+		err := bus.Publish(context.Background(), &events.Event{Type: model.EventWalletCreated})
+		if !errors.Is(err, failure) || err.Error() != "failing: down" {
+			t.Errorf("expected the error of the subscriber, got %v", err)
+		}
+		if called {
+			t.Error("expected the next subscriber not to be called")
+		}
+	})
+}
+
+func TestDispatch(t *testing.T) {
+	t.Run("Publish the events in order", func(t *testing.T) {
+		outbox := []*model.OutboxEvent{
+			outboxEvent(1, model.EventWalletCreated, model.AuditResourceWallet, 3),
+			outboxEvent(2, model.EventTransactionCreated, model.AuditResourceTransaction, 5),
+			outboxEvent(3, model.EventWalletUpdated, model.AuditResourceWallet, 3),
+		}
+		repoSpy := &spies.RepositorySpy{}
+		dispatching(repoSpy, outbox...)
+
+		var got []string
+		bus := events.NewBus()
+		bus.Subscribe("test", func(ctx context.Context, e *events.Event) error {
+			got = append(got, e.Type)
+			return nil
+		}, "*")
+
+		if err := events.NewDispatcher(repoSpy, bus).Dispatch(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		expected := []string{model.EventWalletCreated, model.EventTransactionCreated, model.EventWalletUpdated}
+		if diff := cmp.Diff(expected, got); diff != "" {
+			t.Errorf("unexpected published events (-expected +got):\n%s", diff)
+		}
+		for _, e := range outbox {
+			if e.PublishedAt == nil || e.Attempts != 1 {
+				t.Errorf("expected event %d to be published on the first attempt, got %+v", e.ID, e)
+			}
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Hold back the later events of an aggregate that failed", func(t *testing.T) {
+		outbox := []*model.OutboxEvent{
+			outboxEvent(1, model.EventWalletCreated, model.AuditResourceWallet, 3),
+			outboxEvent(2, model.EventWalletUpdated, model.AuditResourceWallet, 3),
+			outboxEvent(3, model.EventWalletCreated, model.AuditResourceWallet, 4),
+		}
+		repoSpy := &spies.RepositorySpy{}
+		dispatching(repoSpy, outbox...)
+
+		var got []string
+		bus := events.NewBus()
+		bus.Subscribe("test", func(ctx context.Context, e *events.Event) error {
+			if e.AggregateID == 3 {
+				return errors.New("down")
+			}
+			got = append(got, e.ID)
+			return nil
+		}, "*")
+
+		if err := events.NewDispatcher(repoSpy, bus).Dispatch(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if diff := cmp.Diff([]string{outbox[2].EventID}, got); diff != "" {
+			t.Errorf("unexpected published events (-expected +got):\n%s", diff)
+		}
+		if e := outbox[0]; e.PublishedAt != nil || e.Attempts != 1 || e.Error != "test: down" {
+			t.Errorf("expected the failed event to be retried, got %+v", e)
+		}
+		if e := outbox[1]; e.PublishedAt != nil || e.Attempts != 0 {
+			t.Errorf("expected the later event to wait, got %+v", e)
+		}
+	})
+
+	t.Run("Give up after the last attempt", func(t *testing.T) {
+		e := outboxEvent(1, model.EventPartyDeleted, model.AuditResourceParty, 2)
+		e.Attempts = events.MaxAttempts - 1
+		repoSpy := &spies.RepositorySpy{}
+		dispatching(repoSpy, e)
+
+		bus := events.NewBus()
+		bus.Subscribe("test", func(ctx context.Context, e *events.Event) error { return errors.New("down") }, "*")
+
+		if err := events.NewDispatcher(repoSpy, bus).Dispatch(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if e.PublishedAt == nil || e.Attempts != events.MaxAttempts || e.Error != "test: down" {
+			t.Errorf("expected the event to be given up on, got %+v", e)
+		}
+	})
+
+	t.Run("Outbox can't be read", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("OutboxDispatch", mock.Anything, mock.Anything).Return(0, repository.ErrorOther).Once()
+
+		if err := events.NewDispatcher(repoSpy, events.NewBus()).Dispatch(context.Background()); err != repository.ErrorOther {
+			t.Errorf("expected %v, got %v", repository.ErrorOther, err)
+		}
+	})
+}
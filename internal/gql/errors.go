@@ -0,0 +1,65 @@
+package gql
+
+import (
+	"expense-api/internal/repository"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Error is the error of a field. Its extensions carry the HTTP status the REST API answers with in
+// the same case, and a code derived from it like "NOT_FOUND".
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   strings.ToUpper(strings.ReplaceAll(http.StatusText(e.Status), " ", "_")),
+		"status": e.Status,
+	}
+}
+
+func newError(status int, message string) *Error {
+	if message == "" {
+		message = strings.ToLower(http.StatusText(status))
+	}
+	return &Error{Status: status, Message: message}
+}
+
+var (
+	errNotFound  = newError(http.StatusNotFound, "")
+	errForbidden = newError(http.StatusForbidden, "")
+	errInternal  = newError(http.StatusInternalServerError, "")
+	errPageSize  = newError(http.StatusBadRequest, fmt.Sprintf("first must be a number between 1 and %d", MaxPageSize))
+	errCursor    = newError(http.StatusBadRequest, "after must be the endCursor of a page")
+)
+
+// repositoryError hides the details of errors of the repository from clients
+func repositoryError(err error) error {
+	if err == repository.ErrorRecordNotFound {
+		return errNotFound
+	}
+	return errInternal
+}
+
+// Errors of requests that aren't executed
+var (
+	ErrorMissingQuery     = requestError("BAD_REQUEST", "request must be a JSON object with a GraphQL query in 'query'")
+	ErrorQueryTooDeep     = requestError("QUERY_TOO_DEEP", fmt.Sprintf("query must not be nested deeper than %d fields", MaxDepth))
+	ErrorQueryTooComplex  = requestError("QUERY_TOO_COMPLEX", fmt.Sprintf("query must not ask for more than %d fields", MaxComplexity))
+	ErrorUnknownOperation = requestError("BAD_REQUEST", "operationName must name one of the operations of the query")
+)
+
+func requestError(code, message string) gqlerrors.FormattedError {
+	err := gqlerrors.NewFormattedError(message)
+	err.Extensions = map[string]interface{}{"code": code}
+	return err
+}
@@ -0,0 +1,179 @@
+package gql
+
+import (
+	"context"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Handler answers GraphQL requests about the users, wallets, parties and transactions. Queries read
+// the repository directly, mutations go through the REST handlers so that they validate and audit
+// the changes the same way.
+type Handler struct {
+	repo   repository.Repository
+	rest   http.Handler
+	schema graphql.Schema
+}
+
+// New creates the GraphQL handler, rest serves the REST API the mutations are sent to
+func New(repo repository.Repository, rest http.Handler) *Handler {
+	schema, err := newSchema()
+	if err != nil {
+		// The schema is static, it can only be invalid while it is being written
+		panic(err)
+	}
+	return &Handler{repo, rest, schema}
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeGraphQL executes the query of the request. Like other GraphQL servers it answers with 200 OK
+// whenever the request could be read, errors of the query are in the "errors" of the response.
+func (h *Handler) ServeGraphQL(ctx *gin.Context) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var req request
+	if err := ctx.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Query) == "" {
+		ctx.JSON(http.StatusBadRequest, failed(ErrorMissingQuery))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusOK, failed(gqlerrors.FormatError(err)))
+		return
+	}
+
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		ctx.JSON(http.StatusOK, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+
+	if err := checkLimits(&h.schema, doc, req.OperationName, req.Variables); err != nil {
+		ctx.JSON(http.StatusOK, failed(*err))
+		return
+	}
+
+	s := newSession(h, ctx, userID)
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx.Request.Context(), sessionKey{}, s),
+	})
+	ctx.JSON(http.StatusOK, result)
+}
+
+func failed(errs ...gqlerrors.FormattedError) *graphql.Result {
+	return &graphql.Result{Errors: errs}
+}
+
+// session holds what the resolvers of a request share: the user, their roles in households and the
+// loaders that batch the records the query refers to
+type session struct {
+	*Handler
+	ctx    *gin.Context
+	userID uint
+
+	rolesOnce sync.Once
+	roles     permissions.Roles
+	rolesErr  error
+
+	users   *loader[*model.User]
+	wallets *loader[*model.Wallet]
+	parties *loader[*model.Party]
+
+	mu sync.Mutex
+	// transactions load the transactions of wallets or parties, by the filter of the field
+	transactions map[string]*loader[[]*model.Transaction]
+}
+
+type sessionKey struct{}
+
+func newSession(h *Handler, ctx *gin.Context, userID uint) *session {
+	s := &session{Handler: h, ctx: ctx, userID: userID, transactions: map[string]*loader[[]*model.Transaction]{}}
+	s.users = newLoader(func(ids []uint) (map[uint]*model.User, error) {
+		users, err := h.repo.UserListByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		return byID(users, func(u *model.User) uint { return u.ID }), nil
+	})
+	s.wallets = newLoader(func(ids []uint) (map[uint]*model.Wallet, error) {
+		wallets, err := h.repo.WalletListByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		return visibleRecords(s, wallets, func(w *model.Wallet) (uint, uint, *uint) { return w.ID, w.UserID, w.HouseholdID })
+	})
+	s.parties = newLoader(func(ids []uint) (map[uint]*model.Party, error) {
+		parties, err := h.repo.PartyListByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		return visibleRecords(s, parties, func(p *model.Party) (uint, uint, *uint) { return p.ID, p.UserID, p.HouseholdID })
+	})
+	return s
+}
+
+func sessionFrom(ctx context.Context) *session {
+	return ctx.Value(sessionKey{}).(*session)
+}
+
+// allows tells whether the user may read a record of the owner and household
+func (s *session) allows(ownerID uint, householdID *uint) (bool, error) {
+	s.rolesOnce.Do(func() {
+		members, err := s.repo.HouseholdListByUser(s.userID)
+		s.roles, s.rolesErr = permissions.NewRoles(members), err
+	})
+	if s.rolesErr != nil {
+		return false, s.rolesErr
+	}
+	return s.roles.Check(s.userID, ownerID, householdID, permissions.RoleViewer), nil
+}
+
+// visibleRecords leaves out the records the user may not read, records resolve to null for them
+func visibleRecords[T any](s *session, records []T, key func(T) (uint, uint, *uint)) (map[uint]T, error) {
+	visible := make(map[uint]T, len(records))
+	for _, r := range records {
+		id, ownerID, householdID := key(r)
+		allowed, err := s.allows(ownerID, householdID)
+		if err != nil {
+			return nil, err
+		}
+		if allowed {
+			visible[id] = r
+		}
+	}
+	return visible, nil
+}
+
+func byID[T any](records []T, id func(T) uint) map[uint]T {
+	m := make(map[uint]T, len(records))
+	for _, r := range records {
+		m[id(r)] = r
+	}
+	return m
+}
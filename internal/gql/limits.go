@@ -0,0 +1,165 @@
+package gql
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// MaxDepth is how deep queries may nest fields
+	MaxDepth = 10
+	// MaxComplexity is how many fields a query may ask for at most. The fields of the nodes of a
+	// connection count as many times as the page may have nodes.
+	MaxComplexity = 5000
+)
+
+// checkLimits rejects operations that are nested too deep or may ask for too many fields before they
+// are executed. Introspection doesn't count.
+func checkLimits(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}) *gqlerrors.FormattedError {
+	var operation *ast.OperationDefinition
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.OperationDefinition:
+			if operationName == "" || definition.Name != nil && definition.Name.Value == operationName {
+				operation = definition
+			}
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		}
+	}
+	if operation == nil {
+		return &ErrorUnknownOperation
+	}
+
+	root := schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	// Page sizes can come from the defaults of variables as well
+	values := map[string]interface{}{}
+	for _, v := range operation.VariableDefinitions {
+		if value, ok := v.DefaultValue.(*ast.IntValue); ok {
+			values[v.Variable.Name.Value], _ = strconv.Atoi(value.Value)
+		}
+	}
+	for name, value := range variables {
+		values[name] = value
+	}
+
+	w := &limitWalker{schema: schema, fragments: fragments, variables: values}
+	depth, complexity := w.selections(root, operation.SelectionSet, map[string]bool{})
+	if depth > MaxDepth {
+		return &ErrorQueryTooDeep
+	}
+	if complexity > MaxComplexity {
+		return &ErrorQueryTooComplex
+	}
+	return nil
+}
+
+type limitWalker struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// selections measures the depth and complexity of a selection set of the type. visiting holds the
+// fragments spread on the way there, validation already rejected cycles but this keeps the walk
+// from relying on it.
+func (w *limitWalker) selections(parent *graphql.Object, set *ast.SelectionSet, visiting map[string]bool) (depth, complexity int) {
+	if set == nil || parent == nil {
+		return 0, 0
+	}
+
+	for _, selection := range set.Selections {
+		var d, c int
+		switch selection := selection.(type) {
+		case *ast.Field:
+			d, c = w.field(parent, selection, visiting)
+		case *ast.InlineFragment:
+			d, c = w.selections(w.condition(parent, selection.TypeCondition), selection.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			d, c = w.selections(w.condition(parent, fragment.TypeCondition), fragment.SelectionSet, visiting)
+			delete(visiting, name)
+		}
+
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+func (w *limitWalker) field(parent *graphql.Object, field *ast.Field, visiting map[string]bool) (depth, complexity int) {
+	name := field.Name.Value
+	definition, ok := parent.Fields()[name]
+	if !ok || strings.HasPrefix(name, "__") {
+		return 0, 0
+	}
+
+	child, _ := graphql.GetNamed(definition.Type).(*graphql.Object)
+	depth, complexity = w.selections(child, field.SelectionSet, visiting)
+	return depth + 1, 1 + w.multiplier(definition, field)*complexity
+}
+
+// multiplier is the size of the pages of connection fields, the fields of their nodes are returned
+// that many times
+func (w *limitWalker) multiplier(definition *graphql.FieldDefinition, field *ast.Field) int {
+	for _, arg := range definition.Args {
+		if arg.Name() != "first" {
+			continue
+		}
+
+		first := DefaultPageSize
+		for _, a := range field.Arguments {
+			if a.Name.Value == "first" {
+				if n, ok := w.intValue(a.Value); ok {
+					first = n
+				}
+			}
+		}
+		if first < 1 || first > MaxPageSize {
+			// The field fails without resolving any node
+			return 0
+		}
+		return first
+	}
+	return 1
+}
+
+func (w *limitWalker) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := w.variables[value.Name.Value].(type) {
+		case float64:
+			return int(n), true
+		case int:
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+func (w *limitWalker) condition(parent *graphql.Object, condition *ast.Named) *graphql.Object {
+	if condition == nil {
+		return parent
+	}
+	object, _ := w.schema.Type(condition.Name.Value).(*graphql.Object)
+	return object
+}
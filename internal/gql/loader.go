@@ -0,0 +1,83 @@
+package gql
+
+import "sync"
+
+// loader batches the loads of records by ID. The executor only calls the thunks resolvers return
+// after all resolvers of a level of the query ran, so the first thunk that needs a record fetches
+// the records of every resolver of the level in one query.
+type loader[V any] struct {
+	fetch func(ids []uint) (map[uint]V, error)
+
+	mu      sync.Mutex
+	pending []uint
+	fetched map[uint]bool
+	results map[uint]V
+	err     error
+}
+
+// newLoader creates a loader that fetches records with fetch, which leaves out the missing ones
+func newLoader[V any](fetch func(ids []uint) (map[uint]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, fetched: map[uint]bool{}, results: map[uint]V{}}
+}
+
+// load asks for the record with the ID and returns a function that gets it, fetching it together
+// with the other records asked for until then
+func (l *loader[V]) load(id uint) func() (V, bool, error) {
+	l.mu.Lock()
+	if !l.fetched[id] {
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if !l.fetched[id] && len(l.pending) > 0 {
+			ids := uniqueIDs(l.pending)
+			l.pending = nil
+
+			results, err := l.fetch(ids)
+			if err != nil {
+				l.err = err
+			}
+			for _, id := range ids {
+				l.fetched[id] = true
+			}
+			for id, v := range results {
+				l.results[id] = v
+			}
+		}
+
+		if l.err != nil {
+			var zero V
+			return zero, false, l.err
+		}
+		v, ok := l.results[id]
+		return v, ok, nil
+	}
+}
+
+// thunk loads the record with the ID for a resolver, nil if there is none
+func thunk[V any](l *loader[V], id uint) func() (interface{}, error) {
+	get := l.load(id)
+	return func() (interface{}, error) {
+		v, ok, err := get()
+		if err != nil || !ok {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
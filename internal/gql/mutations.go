@@ -0,0 +1,315 @@
+package gql
+
+import (
+	"bytes"
+	"encoding/json"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/graphql-go/graphql"
+)
+
+// restPrefix is where the REST API the mutations are sent to is served
+const restPrefix = "/api/v1"
+
+// resource is what the mutations of wallets, parties or transactions need to know about them
+type resource struct {
+	path string
+	// get reads the record after it was changed
+	get func(s *session, id uint) (interface{}, error)
+}
+
+var (
+	walletResource      = &resource{"/wallets/", func(s *session, id uint) (interface{}, error) { return s.repo.WalletGet(id) }}
+	partyResource       = &resource{"/parties/", func(s *session, id uint) (interface{}, error) { return s.repo.PartyGet(id) }}
+	transactionResource = &resource{"/transactions/", func(s *session, id uint) (interface{}, error) { return s.repo.TransactionGet(id) }}
+)
+
+func newMutation(walletType, partyType, transactionType *graphql.Object) *graphql.Object {
+	walletInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "WalletInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":           {Type: graphql.String},
+			"description":    {Type: graphql.String},
+			"currency":       {Type: graphql.String},
+			"type":           {Type: graphql.String},
+			"openingBalance": {Type: Decimal},
+			"openingDate":    {Type: graphql.DateTime},
+			"creditLimit":    {Type: Decimal},
+			"monthlyBudget":  {Type: Decimal},
+			"archived":       {Type: graphql.Boolean},
+			"displayOrder":   {Type: graphql.Int},
+			"householdId":    {Type: graphql.Int},
+		},
+	})
+	partyInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PartyInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.String},
+			"householdId": {Type: graphql.Int},
+		},
+	})
+	transactionInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "TransactionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"walletId":    {Type: graphql.Int},
+			"partyId":     {Type: graphql.Int},
+			"timestamp":   {Type: graphql.DateTime},
+			"amount":      {Type: Decimal},
+			"description": {Type: graphql.String},
+			"category":    {Type: graphql.String},
+			"tags":        {Type: graphql.NewList(nonNullString)},
+			"status":      {Type: graphql.String},
+		},
+	})
+
+	versionArg := &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Only change the record if it still has this version",
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createWallet": {
+				Type:    walletType,
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(walletInput)}},
+				Resolve: create(walletResource),
+			},
+			"updateWallet": {
+				Type: walletType,
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: nonNullInt},
+					"input":   {Type: graphql.NewNonNull(walletInput)},
+					"version": versionArg,
+				},
+				Resolve: update(walletResource),
+			},
+			"deleteWallet": {
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id":         {Type: nonNullInt},
+					"cascade":    {Type: graphql.Boolean, Description: "Move the transactions of the wallet to the trash as well"},
+					"reassignTo": {Type: graphql.Int, Description: "Move the transactions of the wallet to this wallet"},
+					"version":    versionArg,
+				},
+				Resolve: remove(walletResource, "cascade", "reassignTo"),
+			},
+			"createParty": {
+				Type:    partyType,
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(partyInput)}},
+				Resolve: create(partyResource),
+			},
+			"updateParty": {
+				Type: partyType,
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: nonNullInt},
+					"input":   {Type: graphql.NewNonNull(partyInput)},
+					"version": versionArg,
+				},
+				Resolve: update(partyResource),
+			},
+			"deleteParty": {
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id":         {Type: nonNullInt},
+					"cascade":    {Type: graphql.Boolean, Description: "Move the transactions of the party to the trash as well"},
+					"reassignTo": {Type: graphql.Int, Description: "Move the transactions of the party to this party"},
+					"version":    versionArg,
+				},
+				Resolve: remove(partyResource, "cascade", "reassignTo"),
+			},
+			"createTransaction": {
+				Type: transactionType,
+				Args: graphql.FieldConfigArgument{
+					"input":  {Type: graphql.NewNonNull(transactionInput)},
+					"strict": {Type: graphql.Boolean, Description: "Reject the transaction if it is likely a duplicate"},
+				},
+				Resolve: create(transactionResource, "strict"),
+			},
+			"updateTransaction": {
+				Type: transactionType,
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: nonNullInt},
+					"input":   {Type: graphql.NewNonNull(transactionInput)},
+					"version": versionArg,
+				},
+				Resolve: update(transactionResource),
+			},
+			"deleteTransaction": {
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: graphql.FieldConfigArgument{
+					"id":      {Type: nonNullInt},
+					"version": versionArg,
+				},
+				Resolve: remove(transactionResource),
+			},
+		},
+	})
+}
+
+func create(r *resource, options ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		s := sessionFrom(p.Context)
+		body, err := s.rest(http.MethodPost, r.path, query(p.Args, options), p.Args["input"], nil)
+		if err != nil {
+			return nil, err
+		}
+		return s.reload(r, body)
+	}
+}
+
+// update changes the fields of the input and leaves the others alone, like the merge patches of
+// the REST API
+func update(r *resource) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		s := sessionFrom(p.Context)
+		id, ok := idArg(p.Args, "id")
+		if !ok {
+			return nil, errNotFound
+		}
+
+		body, err := s.rest(http.MethodPatch, r.path+strconv.Itoa(int(id)), nil, p.Args["input"], p.Args["version"])
+		if err != nil {
+			return nil, err
+		}
+		return s.reload(r, body)
+	}
+}
+
+func remove(r *resource, options ...string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		s := sessionFrom(p.Context)
+		id, ok := idArg(p.Args, "id")
+		if !ok {
+			return nil, errNotFound
+		}
+
+		if _, err := s.rest(http.MethodDelete, r.path+strconv.Itoa(int(id)), query(p.Args, options), nil, p.Args["version"]); err != nil {
+			return nil, err
+		}
+		return true, nil
+	}
+}
+
+// query passes the arguments to the REST API as query parameters
+func query(args map[string]interface{}, names []string) url.Values {
+	values := url.Values{}
+	for _, name := range names {
+		if value, ok := args[name]; ok {
+			values.Set(snakeCase(name), fmt.Sprint(value))
+		}
+	}
+	return values
+}
+
+// reload reads the record the REST API answered with, so the query can ask for its relations
+func (s *session) reload(r *resource, body []byte) (interface{}, error) {
+	var created struct {
+		ID uint `json:"id"`
+	}
+	if err := json.Unmarshal(body, &created); err != nil || created.ID == 0 {
+		return nil, errInternal
+	}
+
+	record, err := r.get(s, created.ID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return record, nil
+}
+
+// rest sends the request to the REST API as if the client had, and returns the body of the response.
+// The input is the JSON body with the names of the REST API, the version is sent as If-Match.
+func (s *session) rest(method, path string, query url.Values, input interface{}, version interface{}) ([]byte, error) {
+	var body bytes.Buffer
+	if input != nil {
+		if err := json.NewEncoder(&body).Encode(restInput(input.(map[string]interface{}))); err != nil {
+			return nil, errInternal
+		}
+	}
+
+	target := restPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(s.ctx.Request.Context(), method, target, &body)
+	if err != nil {
+		return nil, errInternal
+	}
+
+	// The request is the client's, it is authorized and audited the same way
+	req.RemoteAddr = s.ctx.Request.RemoteAddr
+	for _, header := range []string{"Authorization", "X-Forwarded-For", "X-Real-IP"} {
+		if value := s.ctx.GetHeader(header); value != "" {
+			req.Header.Set(header, value)
+		}
+	}
+	req.Header.Set(middleware.HeaderRequestID, middleware.GetRequestIDFromContext(s.ctx))
+	req.Header.Set("Content-Type", "application/json")
+	if v, ok := version.(int); ok {
+		req.Header.Set("If-Match", handlers.ETag(uint(v)))
+	}
+
+	res := newResponseBuffer()
+	s.Handler.rest.ServeHTTP(res, req)
+
+	if res.status >= http.StatusBadRequest {
+		var message handlers.ErrorMessage
+		_ = json.Unmarshal(res.body.Bytes(), &message)
+		return nil, newError(res.status, message.Message)
+	}
+	return res.body.Bytes(), nil
+}
+
+// restInput renames the fields of the input to the ones of the REST API
+func restInput(input map[string]interface{}) map[string]interface{} {
+	renamed := make(map[string]interface{}, len(input))
+	for name, value := range input {
+		renamed[snakeCase(name)] = value
+	}
+	return renamed
+}
+
+// snakeCase turns names like "walletId" into "wallet_id"
+func snakeCase(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// responseBuffer keeps the response of the REST API
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *responseBuffer) Header() http.Header {
+	return r.header
+}
+
+func (r *responseBuffer) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseBuffer) WriteHeader(status int) {
+	r.status = status
+}
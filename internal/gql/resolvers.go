@@ -0,0 +1,331 @@
+package gql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultPageSize is how many nodes connections return if the query doesn't say
+	DefaultPageSize = 50
+	// MaxPageSize is how many nodes connections return at most
+	MaxPageSize = 100
+)
+
+// connection is a page of nodes
+type connection struct {
+	nodes       interface{}
+	totalCount  int
+	endCursor   interface{}
+	hasNextPage bool
+}
+
+// paginate picks the page of the records the first and after arguments ask for. Cursors are the
+// offsets of the next pages, encoded so clients don't rely on them being numbers.
+func paginate[T any](records []T, args map[string]interface{}) (*connection, error) {
+	first, _ := args["first"].(int)
+	if first < 1 || first > MaxPageSize {
+		return nil, errPageSize
+	}
+
+	start := 0
+	if after, ok := args["after"].(string); ok {
+		offset, err := decodeCursor(after)
+		if err != nil || offset > len(records) {
+			return nil, errCursor
+		}
+		start = offset
+	}
+
+	end := start + first
+	if end > len(records) {
+		end = len(records)
+	}
+
+	page := &connection{nodes: records[start:end], totalCount: len(records), hasNextPage: end < len(records)}
+	if end > start {
+		page.endCursor = encodeCursor(end)
+	}
+	return page, nil
+}
+
+const cursorPrefix = "offset:"
+
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(b), cursorPrefix) {
+		return 0, errCursor
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(b), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, errCursor
+	}
+	return offset, nil
+}
+
+// idArg is the ID in the argument, IDs are never 0
+func idArg(args map[string]interface{}, name string) (uint, bool) {
+	id, ok := args[name].(int)
+	return uint(id), ok && id > 0
+}
+
+func idsArg(args map[string]interface{}, name string) []uint {
+	values, _ := args[name].([]interface{})
+	ids := make([]uint, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(int); ok && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+func filterArg(args map[string]interface{}) map[string]interface{} {
+	filter, _ := args["filter"].(map[string]interface{})
+	return filter
+}
+
+func containsFold(s, part string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(part))
+}
+
+// inHousehold tells whether a record of the household passes the householdId filter
+func inHousehold(filter map[string]interface{}, householdID *uint) bool {
+	id, ok := filter["householdId"].(int)
+	return !ok || householdID != nil && int(*householdID) == id
+}
+
+// checked hands the record to the query if the user may read it
+func (s *session) checked(record interface{}, ownerID uint, householdID *uint) (interface{}, error) {
+	allowed, err := s.allows(ownerID, householdID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	if !allowed {
+		return nil, errForbidden
+	}
+	return record, nil
+}
+
+func resolveMe(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	u, err := s.repo.UserGet(s.userID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return u, nil
+}
+
+func resolveUser[T any](userID func(T) uint) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return thunk(sessionFrom(p.Context).users, userID(p.Source.(T))), nil
+	}
+}
+
+func resolveWallet(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	id, ok := idArg(p.Args, "id")
+	if !ok {
+		return nil, errNotFound
+	}
+
+	w, err := s.repo.WalletGet(id)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return s.checked(w, w.UserID, w.HouseholdID)
+}
+
+func resolveWallets(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	wallets, err := s.repo.WalletList(s.userID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	filter := filterArg(p.Args)
+	includeArchived, _ := filter["includeArchived"].(bool)
+	walletType, _ := filter["type"].(string)
+	name, _ := filter["name"].(string)
+
+	listed := make([]*model.Wallet, 0, len(wallets))
+	for _, w := range wallets {
+		if w.Archived && !includeArchived || walletType != "" && w.Type != walletType ||
+			!containsFold(w.Name, name) || !inHousehold(filter, w.HouseholdID) {
+			continue
+		}
+		listed = append(listed, w)
+	}
+	return paginate(listed, p.Args)
+}
+
+func resolveParty(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	id, ok := idArg(p.Args, "id")
+	if !ok {
+		return nil, errNotFound
+	}
+
+	party, err := s.repo.PartyGet(id)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return s.checked(party, party.UserID, party.HouseholdID)
+}
+
+func resolveParties(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	parties, err := s.repo.PartyList(s.userID)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+
+	filter := filterArg(p.Args)
+	name, _ := filter["name"].(string)
+
+	listed := make([]*model.Party, 0, len(parties))
+	for _, party := range parties {
+		if containsFold(party.Name, name) && inHousehold(filter, party.HouseholdID) {
+			listed = append(listed, party)
+		}
+	}
+	return paginate(listed, p.Args)
+}
+
+func resolveTransaction(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	id, ok := idArg(p.Args, "id")
+	if !ok {
+		return nil, errNotFound
+	}
+
+	t, err := s.repo.TransactionGet(id)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return s.checked(t, t.UserID, t.HouseholdID)
+}
+
+func resolveTransactions(p graphql.ResolveParams) (interface{}, error) {
+	s := sessionFrom(p.Context)
+	filter := filterArg(p.Args)
+	f := transactionFilter(filter)
+	f.WalletIDs = idsArg(filter, "walletIds")
+	f.PartyIDs = idsArg(filter, "partyIds")
+
+	transactions, err := s.repo.TransactionListFiltered(s.userID, f)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return paginate(transactions, p.Args)
+}
+
+// transactionFilter reads the filter fields transactions of wallets and parties have as well
+func transactionFilter(filter map[string]interface{}) *repository.TransactionFilter {
+	f := &repository.TransactionFilter{}
+	f.Category, _ = filter["category"].(string)
+	f.Tag, _ = filter["tag"].(string)
+	f.Status, _ = filter["status"].(string)
+	f.Description, _ = filter["description"].(string)
+	if from, ok := filter["from"].(time.Time); ok {
+		f.From = &from
+	}
+	if to, ok := filter["to"].(time.Time); ok {
+		f.To = &to
+	}
+	if min, ok := filter["minAmount"].(decimal.Decimal); ok {
+		f.MinAmount = &min
+	}
+	if max, ok := filter["maxAmount"].(decimal.Decimal); ok {
+		f.MaxAmount = &max
+	}
+	return f
+}
+
+// relation is how the transactions of a wallet or party are filtered and told apart
+type relation struct {
+	name     string
+	sourceID func(source interface{}) uint
+	filter   func(f *repository.TransactionFilter, ids []uint)
+	id       func(t *model.Transaction) uint
+}
+
+var (
+	byWallet = &relation{
+		name:     "wallet",
+		sourceID: func(source interface{}) uint { return source.(*model.Wallet).ID },
+		filter:   func(f *repository.TransactionFilter, ids []uint) { f.WalletIDs = ids },
+		id:       func(t *model.Transaction) uint { return t.WalletID },
+	}
+	byParty = &relation{
+		name:     "party",
+		sourceID: func(source interface{}) uint { return source.(*model.Party).ID },
+		filter:   func(f *repository.TransactionFilter, ids []uint) { f.PartyIDs = ids },
+		id:       func(t *model.Transaction) uint { return t.PartyID },
+	}
+)
+
+// resolveRelatedTransactions lists the transactions of wallets or parties. The transactions of all
+// wallets or parties of a level of the query that use the same filter are read at once.
+func resolveRelatedTransactions(r *relation) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		s := sessionFrom(p.Context)
+		l, err := s.transactionLoader(r, filterArg(p.Args))
+		if err != nil {
+			return nil, errInternal
+		}
+
+		get := l.load(r.sourceID(p.Source))
+		return func() (interface{}, error) {
+			transactions, _, err := get()
+			if err != nil {
+				return nil, repositoryError(err)
+			}
+			return paginate(transactions, p.Args)
+		}, nil
+	}
+}
+
+func (s *session) transactionLoader(r *relation, filter map[string]interface{}) (*loader[[]*model.Transaction], error) {
+	key, err := json.Marshal(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := r.name + string(key)
+	if l, ok := s.transactions[k]; ok {
+		return l, nil
+	}
+
+	l := newLoader(func(ids []uint) (map[uint][]*model.Transaction, error) {
+		f := transactionFilter(filter)
+		r.filter(f, ids)
+		transactions, err := s.repo.TransactionListFiltered(s.userID, f)
+		if err != nil {
+			return nil, err
+		}
+
+		grouped := make(map[uint][]*model.Transaction, len(ids))
+		for _, t := range transactions {
+			grouped[r.id(t)] = append(grouped[r.id(t)], t)
+		}
+		return grouped, nil
+	})
+	s.transactions[k] = l
+	return l, nil
+}
@@ -0,0 +1,319 @@
+package gql
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/model"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/shopspring/decimal"
+)
+
+// Decimal is an exact number like the amounts of the REST API. It is written as a string, and read
+// from strings and numbers.
+var Decimal = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Decimal",
+	Description: "An exact decimal number, written as a string like \"-12.50\"",
+	Serialize: func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case decimal.Decimal:
+			return value.String()
+		case *decimal.Decimal:
+			if value != nil {
+				return value.String()
+			}
+		}
+		return nil
+	},
+	ParseValue: func(value interface{}) interface{} {
+		switch value := value.(type) {
+		case string:
+			if d, err := decimal.NewFromString(value); err == nil {
+				return d
+			}
+		case float64:
+			return decimal.NewFromFloat(value)
+		}
+		return nil
+	},
+	ParseLiteral: func(value ast.Value) interface{} {
+		switch value := value.(type) {
+		case *ast.StringValue, *ast.IntValue, *ast.FloatValue:
+			if d, err := decimal.NewFromString(value.GetValue().(string)); err == nil {
+				return d
+			}
+		}
+		return nil
+	},
+})
+
+// field resolves a field of the records of type T
+func field[T any](typ graphql.Output, get func(T) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(T)), nil
+		},
+	}
+}
+
+// modelFields adds the fields every record has to fields
+func modelFields[T any](m func(T) *model.Model, fields graphql.Fields) graphql.Fields {
+	fields["id"] = field(graphql.NewNonNull(graphql.Int), func(r T) interface{} { return m(r).ID })
+	fields["createdAt"] = field(graphql.NewNonNull(graphql.DateTime), func(r T) interface{} { return m(r).CreatedAt })
+	fields["updatedAt"] = field(graphql.NewNonNull(graphql.DateTime), func(r T) interface{} { return m(r).UpdatedAt })
+	fields["version"] = field(graphql.NewNonNull(graphql.Int), func(r T) interface{} { return m(r).Version })
+	return fields
+}
+
+// householdID is the household of a record, null for records outside of households
+func householdID(id *uint) interface{} {
+	if id == nil {
+		return nil
+	}
+	return *id
+}
+
+var (
+	nonNullString = graphql.NewNonNull(graphql.String)
+	nonNullInt    = graphql.NewNonNull(graphql.Int)
+)
+
+// pageArgs adds the arguments of connections to args
+func pageArgs(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	args["first"] = &graphql.ArgumentConfig{
+		Type:         graphql.Int,
+		DefaultValue: DefaultPageSize,
+		Description:  fmt.Sprintf("How many nodes to return, at most %d", MaxPageSize),
+	}
+	args["after"] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The endCursor of the previous page",
+	}
+	return args
+}
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"endCursor":   field(graphql.String, func(c *connection) interface{} { return c.endCursor }),
+		"hasNextPage": field(graphql.NewNonNull(graphql.Boolean), func(c *connection) interface{} { return c.hasNextPage }),
+	},
+})
+
+// connectionOf is the type of the pages of the nodes
+func connectionOf(node *graphql.Object) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: node.Name() + "Connection",
+		Fields: graphql.Fields{
+			"nodes":      field(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(node))), func(c *connection) interface{} { return c.nodes }),
+			"totalCount": field(nonNullInt, func(c *connection) interface{} { return c.totalCount }),
+			"pageInfo":   field(graphql.NewNonNull(pageInfoType), func(c *connection) interface{} { return c }),
+		},
+	})
+}
+
+// transactionFilterFields filter the transactions of wallets and parties, transactionFilterType
+// filters all transactions
+func transactionFilterFields() graphql.InputObjectConfigFieldMap {
+	return graphql.InputObjectConfigFieldMap{
+		"category":    {Type: graphql.String},
+		"tag":         {Type: graphql.String},
+		"status":      {Type: graphql.String},
+		"description": {Type: graphql.String, Description: "Part of the description, regardless of case"},
+		"from":        {Type: graphql.DateTime},
+		"to":          {Type: graphql.DateTime},
+		"minAmount":   {Type: Decimal},
+		"maxAmount":   {Type: Decimal},
+	}
+}
+
+var (
+	relatedTransactionFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:   "RelatedTransactionFilter",
+		Fields: transactionFilterFields(),
+	})
+
+	transactionFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "TransactionFilter",
+		Fields: func() graphql.InputObjectConfigFieldMap {
+			fields := transactionFilterFields()
+			fields["walletIds"] = &graphql.InputObjectFieldConfig{Type: graphql.NewList(nonNullInt)}
+			fields["partyIds"] = &graphql.InputObjectFieldConfig{Type: graphql.NewList(nonNullInt)}
+			return fields
+		}(),
+	})
+
+	walletFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "WalletFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"includeArchived": {Type: graphql.Boolean},
+			"type":            {Type: graphql.String},
+			"householdId":     {Type: graphql.Int},
+			"name":            {Type: graphql.String, Description: "Part of the name, regardless of case"},
+		},
+	})
+
+	partyFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PartyFilter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"householdId": {Type: graphql.Int},
+			"name":        {Type: graphql.String, Description: "Part of the name, regardless of case"},
+		},
+	})
+)
+
+func newSchema() (graphql.Schema, error) {
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: modelFields(func(u *model.User) *model.Model { return &u.Model }, graphql.Fields{
+			"firstName": field(nonNullString, func(u *model.User) interface{} { return u.FirstName }),
+			"lastName":  field(nonNullString, func(u *model.User) interface{} { return u.LastName }),
+			"email":     field(nonNullString, func(u *model.User) interface{} { return u.Email }),
+		}),
+	})
+
+	var walletType, partyType, transactionType *graphql.Object
+	var transactionConnection *graphql.Object
+
+	walletType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Wallet",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return modelFields(func(w *model.Wallet) *model.Model { return &w.Model }, graphql.Fields{
+				"name":           field(nonNullString, func(w *model.Wallet) interface{} { return w.Name }),
+				"description":    field(nonNullString, func(w *model.Wallet) interface{} { return w.Description }),
+				"currency":       field(nonNullString, func(w *model.Wallet) interface{} { return handlers.WalletCurrency(w) }),
+				"type":           field(nonNullString, func(w *model.Wallet) interface{} { return w.Type }),
+				"openingBalance": field(graphql.NewNonNull(Decimal), func(w *model.Wallet) interface{} { return w.OpeningBalance }),
+				"openingDate":    field(graphql.DateTime, func(w *model.Wallet) interface{} { return w.OpeningDate }),
+				"creditLimit":    field(Decimal, func(w *model.Wallet) interface{} { return w.CreditLimit }),
+				"monthlyBudget":  field(Decimal, func(w *model.Wallet) interface{} { return w.MonthlyBudget }),
+				"archived":       field(graphql.NewNonNull(graphql.Boolean), func(w *model.Wallet) interface{} { return w.Archived }),
+				"displayOrder":   field(nonNullInt, func(w *model.Wallet) interface{} { return w.DisplayOrder }),
+				"householdId":    field(graphql.Int, func(w *model.Wallet) interface{} { return householdID(w.HouseholdID) }),
+				"user": {
+					Type:    userType,
+					Resolve: resolveUser(func(w *model.Wallet) uint { return w.UserID }),
+				},
+				"transactions": {
+					Type:    graphql.NewNonNull(transactionConnection),
+					Args:    pageArgs(graphql.FieldConfigArgument{"filter": {Type: relatedTransactionFilterType}}),
+					Resolve: resolveRelatedTransactions(byWallet),
+				},
+			})
+		}),
+	})
+
+	partyType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Party",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return modelFields(func(p *model.Party) *model.Model { return &p.Model }, graphql.Fields{
+				"name":        field(nonNullString, func(p *model.Party) interface{} { return p.Name }),
+				"householdId": field(graphql.Int, func(p *model.Party) interface{} { return householdID(p.HouseholdID) }),
+				"aliases": field(graphql.NewNonNull(graphql.NewList(nonNullString)), func(p *model.Party) interface{} {
+					aliases := make([]string, 0, len(p.Aliases))
+					for _, a := range p.Aliases {
+						aliases = append(aliases, a.Name)
+					}
+					return aliases
+				}),
+				"user": {
+					Type:    userType,
+					Resolve: resolveUser(func(p *model.Party) uint { return p.UserID }),
+				},
+				"transactions": {
+					Type:    graphql.NewNonNull(transactionConnection),
+					Args:    pageArgs(graphql.FieldConfigArgument{"filter": {Type: relatedTransactionFilterType}}),
+					Resolve: resolveRelatedTransactions(byParty),
+				},
+			})
+		}),
+	})
+
+	transactionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Transaction",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return modelFields(func(t *model.Transaction) *model.Model { return &t.Model }, graphql.Fields{
+				"timestamp":   field(graphql.NewNonNull(graphql.DateTime), func(t *model.Transaction) interface{} { return t.Timestamp }),
+				"amount":      field(graphql.NewNonNull(Decimal), func(t *model.Transaction) interface{} { return t.Amount }),
+				"description": field(nonNullString, func(t *model.Transaction) interface{} { return t.Description }),
+				"category":    field(nonNullString, func(t *model.Transaction) interface{} { return t.Category }),
+				"tags": field(graphql.NewNonNull(graphql.NewList(nonNullString)), func(t *model.Transaction) interface{} {
+					return handlers.TransactionModelToResponse(t).Tags
+				}),
+				"status":      field(nonNullString, func(t *model.Transaction) interface{} { return t.Status }),
+				"householdId": field(graphql.Int, func(t *model.Transaction) interface{} { return householdID(t.HouseholdID) }),
+				"walletId":    field(nonNullInt, func(t *model.Transaction) interface{} { return t.WalletID }),
+				"partyId":     field(nonNullInt, func(t *model.Transaction) interface{} { return t.PartyID }),
+				"wallet": {
+					Type: walletType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return thunk(sessionFrom(p.Context).wallets, p.Source.(*model.Transaction).WalletID), nil
+					},
+				},
+				"party": {
+					Type: partyType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return thunk(sessionFrom(p.Context).parties, p.Source.(*model.Transaction).PartyID), nil
+					},
+				},
+				"user": {
+					Type:    userType,
+					Resolve: resolveUser(func(t *model.Transaction) uint { return t.UserID }),
+				},
+			})
+		}),
+	})
+
+	transactionConnection = connectionOf(transactionType)
+	walletConnection := connectionOf(walletType)
+	partyConnection := connectionOf(partyType)
+
+	idArgs := graphql.FieldConfigArgument{"id": {Type: nonNullInt}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": {
+				Type:    graphql.NewNonNull(userType),
+				Resolve: resolveMe,
+			},
+			"wallet": {
+				Type:    walletType,
+				Args:    idArgs,
+				Resolve: resolveWallet,
+			},
+			"wallets": {
+				Type:    graphql.NewNonNull(walletConnection),
+				Args:    pageArgs(graphql.FieldConfigArgument{"filter": {Type: walletFilterType}}),
+				Resolve: resolveWallets,
+			},
+			"party": {
+				Type:    partyType,
+				Args:    idArgs,
+				Resolve: resolveParty,
+			},
+			"parties": {
+				Type:    graphql.NewNonNull(partyConnection),
+				Args:    pageArgs(graphql.FieldConfigArgument{"filter": {Type: partyFilterType}}),
+				Resolve: resolveParties,
+			},
+			"transaction": {
+				Type:    transactionType,
+				Args:    idArgs,
+				Resolve: resolveTransaction,
+			},
+			"transactions": {
+				Type:    graphql.NewNonNull(transactionConnection),
+				Args:    pageArgs(graphql.FieldConfigArgument{"filter": {Type: transactionFilterType}}),
+				Resolve: resolveTransactions,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: newMutation(walletType, partyType, transactionType),
+	})
+}
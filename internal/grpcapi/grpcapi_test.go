@@ -0,0 +1,224 @@
+package grpcapi_test
+
+import (
+	"context"
+	"errors"
+	expensev1 "expense-api/api/proto/expense/v1"
+	"expense-api/internal/grpcapi"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const token = "valid-token"
+
+// dial serves the API on an in-memory listener and connects to it
+func dial(t *testing.T, repoSpy *spies.RepositorySpy, jwtServiceSpy *spies.JWTServiceSpy) *grpc.ClientConn {
+	t.Helper()
+	r := router.Setup(repoSpy, jwtServiceSpy, &spies.PasswordHasherSpy{}, &spies.BlobStoreSpy{}, router.TestConfig)
+	server := grpcapi.NewServer(repoSpy, jwtServiceSpy, r)
+
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("couldn't connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func newRepositorySpy() *spies.RepositorySpy {
+	repoSpy := &spies.RepositorySpy{}
+	repoSpy.On("WithActor", mock.Anything).Return(repoSpy).Maybe()
+	return repoSpy
+}
+
+func authorized(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func assertStatus(t *testing.T, err error, code codes.Code, message string) {
+	t.Helper()
+	s, _ := status.FromError(err)
+	if s.Code() != code || s.Message() != message {
+		t.Errorf("expected %s %q, got %s %q", code, message, s.Code(), s.Message())
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	jwtServiceSpy.On("ValidateJWT", "invalid-token").Return(nil, errors.New("invalid"))
+	conn := dial(t, newRepositorySpy(), jwtServiceSpy)
+	wallets := expensev1.NewWalletServiceClient(conn)
+
+	t.Run("Missing token", func(t *testing.T) {
+		_, err := wallets.ListWallets(context.Background(), &expensev1.ListWalletsRequest{})
+		assertStatus(t, err, codes.Unauthenticated, auth.ErrMsgMalformedToken)
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		_, err := wallets.ListWallets(authorized("invalid-token"), &expensev1.ListWalletsRequest{})
+		assertStatus(t, err, codes.Unauthenticated, "Invalid token")
+	})
+
+	t.Run("Invalid token on a stream", func(t *testing.T) {
+		stream, err := expensev1.NewTransactionServiceClient(conn).ListTransactions(authorized("invalid-token"), &expensev1.ListTransactionsRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		assertStatus(t, err, codes.Unauthenticated, "Invalid token")
+	})
+
+	t.Run("Sign up without a token", func(t *testing.T) {
+		_, err := expensev1.NewAuthServiceClient(conn).SignUp(context.Background(), &expensev1.SignUpRequest{Email: "ada@example.com"})
+		assertStatus(t, err, codes.InvalidArgument, handlers.ErrorName.Message)
+	})
+}
+
+func TestWallets(t *testing.T) {
+	userID := uint(1)
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	t.Run("Get wallet", func(t *testing.T) {
+		repoSpy := newRepositorySpy()
+		wallets := expensev1.NewWalletServiceClient(dial(t, repoSpy, jwtServiceSpy))
+
+		w := &model.Wallet{Name: "Cash", Currency: "EUR", Type: model.WalletCash, UserID: userID, OpeningBalance: decimal.RequireFromString("10.5")}
+		w.ID = 3
+		w.Version = 2
+		repoSpy.On("WalletGet", w.ID).Return(w, nil)
+
+		var header metadata.MD
+		got, err := wallets.GetWallet(authorized(token), &expensev1.GetWalletRequest{Id: 3}, grpc.Header(&header))
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if got.GetId() != 3 || got.GetName() != "Cash" || got.GetOpeningBalance() != "10.5" || got.CreditLimit != nil {
+			t.Errorf("unexpected wallet %v", got)
+		}
+		if etag := header.Get("etag"); !cmp.Equal(etag, []string{handlers.ETag(2)}) {
+			t.Errorf("expected etag %s, got %v", handlers.ETag(2), etag)
+		}
+	})
+
+	t.Run("Get wallet of another user", func(t *testing.T) {
+		repoSpy := newRepositorySpy()
+		wallets := expensev1.NewWalletServiceClient(dial(t, repoSpy, jwtServiceSpy))
+
+		w := &model.Wallet{Name: "Cash", UserID: 2}
+		w.ID = 3
+		repoSpy.On("WalletGet", w.ID).Return(w, nil)
+
+		_, err := wallets.GetWallet(authorized(token), &expensev1.GetWalletRequest{Id: 3})
+		assertStatus(t, err, codes.PermissionDenied, "Forbidden")
+	})
+
+	t.Run("Create wallet without a name", func(t *testing.T) {
+		wallets := expensev1.NewWalletServiceClient(dial(t, newRepositorySpy(), jwtServiceSpy))
+
+		currency := "EUR"
+		_, err := wallets.CreateWallet(authorized(token), &expensev1.CreateWalletRequest{Wallet: &expensev1.WalletInput{Currency: &currency}})
+		assertStatus(t, err, codes.InvalidArgument, handlers.ErrorWalletName.Message)
+	})
+
+	t.Run("Update wallet with an outdated version", func(t *testing.T) {
+		repoSpy := newRepositorySpy()
+		wallets := expensev1.NewWalletServiceClient(dial(t, repoSpy, jwtServiceSpy))
+
+		w := &model.Wallet{Name: "Cash", Currency: "EUR", Type: model.WalletCash, UserID: userID}
+		w.ID = 3
+		w.Version = 2
+		repoSpy.On("WalletGet", w.ID).Return(w, nil)
+
+		name := "Pocket"
+		ctx := metadata.AppendToOutgoingContext(authorized(token), "if-match", handlers.ETag(1))
+		_, err := wallets.UpdateWallet(ctx, &expensev1.UpdateWalletRequest{Id: 3, Wallet: &expensev1.WalletInput{Name: &name}})
+		assertStatus(t, err, codes.FailedPrecondition, handlers.ErrorPreconditionFailed.Message)
+	})
+}
+
+func TestListTransactions(t *testing.T) {
+	userID := uint(1)
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	t.Run("Stream the filtered transactions", func(t *testing.T) {
+		repoSpy := newRepositorySpy()
+		transactions := expensev1.NewTransactionServiceClient(dial(t, repoSpy, jwtServiceSpy))
+
+		var models []*model.Transaction
+		for i, amount := range []string{"-4.5", "100", "-12"} {
+			m := &model.Transaction{WalletID: 1, PartyID: 5, UserID: userID, Amount: decimal.RequireFromString(amount), Tags: []string{"food"}}
+			m.ID = uint(10 + i)
+			models = append(models, m)
+		}
+		repoSpy.On("TransactionListFiltered", userID, mock.MatchedBy(func(f *repository.TransactionFilter) bool {
+			return cmp.Equal(f.WalletIDs, []uint{1}) && f.Tag == "food" && f.MinAmount.Equal(decimal.NewFromInt(-20)) && f.MaxAmount == nil
+		})).Return(models, nil).Once()
+
+		minAmount := "-20"
+		stream, err := transactions.ListTransactions(authorized(token), &expensev1.ListTransactionsRequest{
+			WalletIds: []uint32{1},
+			Tag:       "food",
+			MinAmount: &minAmount,
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var amounts []string
+		for {
+			tx, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tx.GetWalletId() != 1 || !cmp.Equal(tx.GetTags(), []string{"food"}) {
+				t.Errorf("unexpected transaction %v", tx)
+			}
+			amounts = append(amounts, tx.GetAmount())
+		}
+
+		if diff := cmp.Diff([]string{"-4.5", "100", "-12"}, amounts); diff != "" {
+			t.Errorf("unexpected amounts (-expected +got):\n%s", diff)
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Invalid amount filter", func(t *testing.T) {
+		transactions := expensev1.NewTransactionServiceClient(dial(t, newRepositorySpy(), jwtServiceSpy))
+
+		maxAmount := "a lot"
+		stream, err := transactions.ListTransactions(authorized(token), &expensev1.ListTransactionsRequest{MaxAmount: &maxAmount})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		assertStatus(t, err, codes.InvalidArgument, "max_amount must be a decimal number")
+	})
+}
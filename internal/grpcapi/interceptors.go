@@ -0,0 +1,99 @@
+package grpcapi
+
+import (
+	"context"
+	expensev1 "expense-api/api/proto/expense/v1"
+	"expense-api/internal/middleware/auth"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// publicMethods can be called without a token
+var publicMethods = map[string]bool{
+	expensev1.AuthService_SignUp_FullMethodName: true,
+	expensev1.AuthService_Login_FullMethodName:  true,
+}
+
+const errMsgInvalidToken = "Invalid token"
+
+type claimsKey struct{}
+
+// claimsFrom is the authenticated user of the call
+func claimsFrom(ctx context.Context) *auth.CustomClaims {
+	claims, _ := ctx.Value(claimsKey{}).(*auth.CustomClaims)
+	return claims
+}
+
+type authenticator struct {
+	jwtService auth.JWTService
+}
+
+// authenticate checks the token of the authorization metadata, which is "Bearer <token>" as in the
+// Authorization header of the REST API
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 || !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, auth.ErrMsgMalformedToken)
+	}
+
+	claims, err := a.jwtService.ValidateJWT(strings.TrimPrefix(values[0], "Bearer "))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, errMsgInvalidToken)
+	}
+	return context.WithValue(ctx, claimsKey{}, claims), nil
+}
+
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := a.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticatedStream is a stream whose context has the claims of the user
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func logUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	logCall(info.FullMethod, start, err)
+	return res, err
+}
+
+func logStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logCall(info.FullMethod, start, err)
+	return err
+}
+
+func logCall(method string, start time.Time, err error) {
+	log.Printf("[gRPC] %s | %s | %v", method, status.Code(err), time.Since(start))
+}
@@ -0,0 +1,147 @@
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware"
+	"expense-api/internal/middleware/idempotency"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// restPrefix is where the REST API the calls are sent to is served
+const restPrefix = "/api/v1"
+
+// forwardedHeaders are the metadata of a call that are sent to the REST API as headers
+var forwardedHeaders = []string{
+	"Authorization",
+	"If-Match",
+	idempotency.HeaderKey,
+	middleware.HeaderRequestID,
+	"X-Forwarded-For",
+	"X-Real-IP",
+}
+
+var (
+	// The messages have the field names of the REST API, unset optional fields are left out so
+	// updates only change what was set
+	marshalOptions   = protojson.MarshalOptions{UseProtoNames: true}
+	unmarshalOptions = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// rest sends the call to the REST API as if the client had and reads the response into out. in is
+// the JSON body, nil for none. The ETag of the response is sent back as the etag header metadata.
+func (s *Server) rest(ctx context.Context, method, path string, query url.Values, in, out proto.Message) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = marshalOptions.Marshal(in); err != nil {
+			return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+		}
+	}
+
+	target := restPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		req.RemoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, header := range forwardedHeaders {
+		if values := md.Get(header); len(values) > 0 {
+			req.Header.Set(header, values[0])
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res := newResponse()
+	s.api.ServeHTTP(res, req)
+
+	if res.status >= http.StatusBadRequest {
+		return restError(method, res)
+	}
+
+	if etag := res.header.Get("ETag"); etag != "" {
+		_ = grpc.SetHeader(ctx, metadata.Pairs("etag", etag))
+	}
+
+	if out != nil && res.body.Len() > 0 {
+		if err := unmarshalOptions.Unmarshal(res.body.Bytes(), out); err != nil {
+			return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+		}
+	}
+	return nil
+}
+
+// codeOfStatus is the gRPC code of the REST API's error status
+var codeOfStatus = map[int]codes.Code{
+	http.StatusBadRequest:            codes.InvalidArgument,
+	http.StatusUnauthorized:          codes.Unauthenticated,
+	http.StatusForbidden:             codes.PermissionDenied,
+	http.StatusNotFound:              codes.NotFound,
+	http.StatusConflict:              codes.Aborted,
+	http.StatusPreconditionFailed:    codes.FailedPrecondition,
+	http.StatusRequestEntityTooLarge: codes.ResourceExhausted,
+	http.StatusUnprocessableEntity:   codes.InvalidArgument,
+	http.StatusTooManyRequests:       codes.ResourceExhausted,
+	http.StatusInternalServerError:   codes.Internal,
+	http.StatusServiceUnavailable:    codes.Unavailable,
+}
+
+// restError turns the error response of the REST API into a status with the same message
+func restError(method string, res *response) error {
+	code, ok := codeOfStatus[res.status]
+	if !ok {
+		code = codes.Unknown
+	}
+	// Creating something that exists conflicts as well
+	if res.status == http.StatusConflict && method == http.MethodPost {
+		code = codes.AlreadyExists
+	}
+
+	var message handlers.ErrorMessage
+	if err := json.Unmarshal(res.body.Bytes(), &message); err != nil || message.Message == "" {
+		message.Message = http.StatusText(res.status)
+	}
+	return status.Error(code, message.Message)
+}
+
+// response keeps the response of the REST API
+type response struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponse() *response {
+	return &response{header: http.Header{}, status: http.StatusOK}
+}
+
+func (r *response) Header() http.Header {
+	return r.header
+}
+
+func (r *response) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *response) WriteHeader(status int) {
+	r.status = status
+}
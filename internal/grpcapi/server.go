@@ -0,0 +1,37 @@
+// Package grpcapi serves the API over gRPC. The calls that read or change a single record are
+// sent to the REST handlers, so both APIs validate, authorize and audit them the same way.
+package grpcapi
+
+import (
+	expensev1 "expense-api/api/proto/expense/v1"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"google.golang.org/grpc"
+)
+
+// Server holds what the services share
+type Server struct {
+	repo repository.Repository
+	// api is the REST API the unary calls are sent to
+	api http.Handler
+}
+
+// NewServer creates a gRPC server with all services registered. Calls are logged, and all but the
+// ones of the AuthService need a token in the authorization metadata, like the REST API.
+func NewServer(repo repository.Repository, jwtService auth.JWTService, rest http.Handler) *grpc.Server {
+	a := &authenticator{jwtService: jwtService}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logUnary, a.unary),
+		grpc.ChainStreamInterceptor(logStream, a.stream),
+	)
+
+	s := &Server{repo: repo, api: rest}
+	expensev1.RegisterAuthServiceServer(server, &authService{Server: s})
+	expensev1.RegisterAccountServiceServer(server, &accountService{Server: s})
+	expensev1.RegisterWalletServiceServer(server, &walletService{Server: s})
+	expensev1.RegisterPartyServiceServer(server, &partyService{Server: s})
+	expensev1.RegisterTransactionServiceServer(server, &transactionService{Server: s})
+	return server
+}
@@ -0,0 +1,168 @@
+package grpcapi
+
+import (
+	"context"
+	expensev1 "expense-api/api/proto/expense/v1"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type authService struct {
+	expensev1.UnimplementedAuthServiceServer
+	*Server
+}
+
+func (s *authService) SignUp(ctx context.Context, req *expensev1.SignUpRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.rest(ctx, http.MethodPost, "/auth/signup", nil, req, nil)
+}
+
+func (s *authService) Login(ctx context.Context, req *expensev1.LoginRequest) (*expensev1.LoginResponse, error) {
+	res := &expensev1.LoginResponse{}
+	return res, s.rest(ctx, http.MethodPost, "/auth/login", nil, req, res)
+}
+
+type accountService struct {
+	expensev1.UnimplementedAccountServiceServer
+	*Server
+}
+
+func (s *accountService) GetAccount(ctx context.Context, req *expensev1.GetAccountRequest) (*expensev1.Account, error) {
+	res := &expensev1.Account{}
+	return res, s.rest(ctx, http.MethodGet, "/account/", nil, nil, res)
+}
+
+func (s *accountService) UpdateAccount(ctx context.Context, req *expensev1.UpdateAccountRequest) (*expensev1.Account, error) {
+	res := &expensev1.Account{}
+	return res, s.rest(ctx, http.MethodPatch, "/account/", nil, orEmpty(req.GetAccount()), res)
+}
+
+func (s *accountService) DeleteAccount(ctx context.Context, req *expensev1.DeleteAccountRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.rest(ctx, http.MethodDelete, "/account/", nil, nil, nil)
+}
+
+type walletService struct {
+	expensev1.UnimplementedWalletServiceServer
+	*Server
+}
+
+func (s *walletService) ListWallets(ctx context.Context, req *expensev1.ListWalletsRequest) (*expensev1.ListWalletsResponse, error) {
+	query := url.Values{}
+	if req.GetIncludeArchived() {
+		query.Set("include_archived", "true")
+	}
+
+	res := &expensev1.ListWalletsResponse{}
+	return res, s.rest(ctx, http.MethodGet, "/wallets/", query, nil, res)
+}
+
+func (s *walletService) GetWallet(ctx context.Context, req *expensev1.GetWalletRequest) (*expensev1.Wallet, error) {
+	res := &expensev1.Wallet{}
+	return res, s.rest(ctx, http.MethodGet, recordPath("/wallets/", req.GetId()), nil, nil, res)
+}
+
+func (s *walletService) CreateWallet(ctx context.Context, req *expensev1.CreateWalletRequest) (*expensev1.Wallet, error) {
+	res := &expensev1.Wallet{}
+	return res, s.rest(ctx, http.MethodPost, "/wallets/", nil, orEmpty(req.GetWallet()), res)
+}
+
+func (s *walletService) UpdateWallet(ctx context.Context, req *expensev1.UpdateWalletRequest) (*expensev1.Wallet, error) {
+	res := &expensev1.Wallet{}
+	return res, s.rest(ctx, http.MethodPatch, recordPath("/wallets/", req.GetId()), nil, orEmpty(req.GetWallet()), res)
+}
+
+func (s *walletService) DeleteWallet(ctx context.Context, req *expensev1.DeleteWalletRequest) (*emptypb.Empty, error) {
+	query := url.Values{}
+	if req.GetCascade() {
+		query.Set("cascade", "true")
+	}
+	if req.ReassignTo != nil {
+		query.Set("reassign_to", strconv.Itoa(int(req.GetReassignTo())))
+	}
+	return &emptypb.Empty{}, s.rest(ctx, http.MethodDelete, recordPath("/wallets/", req.GetId()), query, nil, nil)
+}
+
+type partyService struct {
+	expensev1.UnimplementedPartyServiceServer
+	*Server
+}
+
+func (s *partyService) ListParties(ctx context.Context, req *expensev1.ListPartiesRequest) (*expensev1.ListPartiesResponse, error) {
+	res := &expensev1.ListPartiesResponse{}
+	return res, s.rest(ctx, http.MethodGet, "/parties/", nil, nil, res)
+}
+
+func (s *partyService) GetParty(ctx context.Context, req *expensev1.GetPartyRequest) (*expensev1.Party, error) {
+	res := &expensev1.Party{}
+	return res, s.rest(ctx, http.MethodGet, recordPath("/parties/", req.GetId()), nil, nil, res)
+}
+
+func (s *partyService) CreateParty(ctx context.Context, req *expensev1.CreatePartyRequest) (*expensev1.Party, error) {
+	res := &expensev1.Party{}
+	return res, s.rest(ctx, http.MethodPost, "/parties/", nil, orEmpty(req.GetParty()), res)
+}
+
+func (s *partyService) UpdateParty(ctx context.Context, req *expensev1.UpdatePartyRequest) (*expensev1.Party, error) {
+	res := &expensev1.Party{}
+	return res, s.rest(ctx, http.MethodPatch, recordPath("/parties/", req.GetId()), nil, orEmpty(req.GetParty()), res)
+}
+
+func (s *partyService) DeleteParty(ctx context.Context, req *expensev1.DeletePartyRequest) (*emptypb.Empty, error) {
+	query := url.Values{}
+	if req.GetCascade() {
+		query.Set("cascade", "true")
+	}
+	if req.ReassignTo != nil {
+		query.Set("reassign_to", strconv.Itoa(int(req.GetReassignTo())))
+	}
+	return &emptypb.Empty{}, s.rest(ctx, http.MethodDelete, recordPath("/parties/", req.GetId()), query, nil, nil)
+}
+
+type transactionService struct {
+	expensev1.UnimplementedTransactionServiceServer
+	*Server
+}
+
+func (s *transactionService) GetTransaction(ctx context.Context, req *expensev1.GetTransactionRequest) (*expensev1.Transaction, error) {
+	res := &expensev1.Transaction{}
+	return res, s.rest(ctx, http.MethodGet, recordPath("/transactions/", req.GetId()), nil, nil, res)
+}
+
+func (s *transactionService) CreateTransaction(ctx context.Context, req *expensev1.CreateTransactionRequest) (*expensev1.Transaction, error) {
+	query := url.Values{}
+	if req.GetStrict() {
+		query.Set("strict", "true")
+	}
+
+	res := &expensev1.Transaction{}
+	return res, s.rest(ctx, http.MethodPost, "/transactions/", query, orEmpty(req.GetTransaction()), res)
+}
+
+func (s *transactionService) UpdateTransaction(ctx context.Context, req *expensev1.UpdateTransactionRequest) (*expensev1.Transaction, error) {
+	res := &expensev1.Transaction{}
+	return res, s.rest(ctx, http.MethodPatch, recordPath("/transactions/", req.GetId()), nil, orEmpty(req.GetTransaction()), res)
+}
+
+func (s *transactionService) DeleteTransaction(ctx context.Context, req *expensev1.DeleteTransactionRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, s.rest(ctx, http.MethodDelete, recordPath("/transactions/", req.GetId()), nil, nil, nil)
+}
+
+func recordPath(collection string, id uint32) string {
+	return fmt.Sprintf("%s%d", collection, id)
+}
+
+// orEmpty sends an empty body for an input the call doesn't have, which the REST API rejects the
+// same way it does for any other missing field
+func orEmpty[M any, P interface {
+	*M
+	proto.Message
+}](input P) proto.Message {
+	if input == nil {
+		return P(new(M))
+	}
+	return input
+}
@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	expensev1 "expense-api/api/proto/expense/v1"
+	"expense-api/internal/handlers"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ListTransactions streams the transactions one by one instead of answering with a list, so
+// clients can start with the first ones while the others are still sent
+func (s *transactionService) ListTransactions(req *expensev1.ListTransactionsRequest, stream grpc.ServerStreamingServer[expensev1.Transaction]) error {
+	filter, err := transactionFilter(req)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := s.repo.TransactionListFiltered(claimsFrom(stream.Context()).ID, filter)
+	if err != nil {
+		return status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+	}
+
+	for _, t := range transactions {
+		if err := stream.Send(transactionToMessage(handlers.TransactionModelToResponse(t))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func transactionFilter(req *expensev1.ListTransactionsRequest) (*repository.TransactionFilter, error) {
+	f := &repository.TransactionFilter{
+		WalletIDs:   ids(req.GetWalletIds()),
+		PartyIDs:    ids(req.GetPartyIds()),
+		Category:    req.GetCategory(),
+		Tag:         req.GetTag(),
+		Status:      req.GetStatus(),
+		Description: req.GetDescription(),
+	}
+	if req.From != nil {
+		from := req.GetFrom().AsTime()
+		f.From = &from
+	}
+	if req.To != nil {
+		to := req.GetTo().AsTime()
+		f.To = &to
+	}
+
+	var err error
+	if f.MinAmount, err = amount(req.MinAmount, "min_amount"); err != nil {
+		return nil, err
+	}
+	if f.MaxAmount, err = amount(req.MaxAmount, "max_amount"); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func ids(values []uint32) []uint {
+	converted := make([]uint, 0, len(values))
+	for _, v := range values {
+		converted = append(converted, uint(v))
+	}
+	return converted
+}
+
+func amount(value *string, field string) (*decimal.Decimal, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	d, err := decimal.NewFromString(*value)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s must be a decimal number", field)
+	}
+	return &d, nil
+}
+
+func transactionToMessage(t *handlers.Transaction) *expensev1.Transaction {
+	return &expensev1.Transaction{
+		Id:          uint32(t.ID),
+		WalletId:    uint32(t.WalletID),
+		PartyId:     uint32(t.PartyID),
+		CreatedAt:   timestamppb.New(t.CreatedAt),
+		UpdatedAt:   timestamppb.New(t.UpdatedAt),
+		Timestamp:   timestamppb.New(t.Timestamp),
+		Amount:      t.Amount.String(),
+		Description: t.Description,
+		Category:    t.Category,
+		Tags:        t.Tags,
+		Status:      t.Status,
+		HouseholdId: uint32(t.HouseholdID),
+	}
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"expense-api/internal/currency"
 	"expense-api/internal/middleware/auth"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
 
@@ -14,6 +16,7 @@ type AccountHandler interface {
 	DeleteAccount(ctx *gin.Context)
 }
 
+// UpdateAccount applies a JSON Merge Patch to the account of the current user
 func (h *handler) UpdateAccount(ctx *gin.Context) {
 	id, err := auth.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -21,33 +24,56 @@ func (h *handler) UpdateAccount(ctx *gin.Context) {
 		return
 	}
 
+	current, err := h.repo(ctx).UserGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if !checkIfMatch(ctx, current.Version) {
+		return
+	}
+
 	var accountBody Account
-	if err := ctx.Bind(&accountBody); err != nil {
-		ctx.Status(http.StatusBadRequest)
+	if err := bindPatch(ctx, UserModelToAccountResponse(current), &accountBody); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
 		return
 	}
 
-	if err := accountBody.ValidateUpdateBody(); err != nil {
+	accountBody.BaseCurrency = currency.Normalize(accountBody.BaseCurrency)
+
+	if err := accountBody.Validate(); err != nil {
 		ctx.JSON(http.StatusBadRequest, err)
 		return
 	}
 
-	userModel, err := h.repo.UserUpdate(
-		id,
-		accountBody.FirstName,
-		accountBody.LastName,
-		accountBody.Email,
-	)
+	uModel := AccountRequestToModel(&accountBody)
+	uModel.Version = current.Version
+
+	userModel, err := h.repo(ctx).UserUpdate(id, uModel)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
 			return
 		}
+		if err == repository.ErrorUniqueConstaintViolation {
+			ctx.JSON(http.StatusConflict, ErrorEmailConflict)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
 
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
 
+	ctx.Header("ETag", ETag(userModel.Version))
 	accountResponse := UserModelToAccountResponse(userModel)
 	ctx.JSON(http.StatusOK, accountResponse)
 }
@@ -59,7 +85,40 @@ func (h *handler) DeleteAccount(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.repo.UserDelete(id); err != nil {
+	// The account only has to be read to compare its version with If-Match
+	if ctx.GetHeader("If-Match") != "" {
+		current, err := h.repo(ctx).UserGet(id)
+		if err != nil {
+			if err == repository.ErrorRecordNotFound {
+				ctx.Status(http.StatusNotFound)
+				return
+			}
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !checkIfMatch(ctx, current.Version) {
+			return
+		}
+	}
+
+	errMessage, err := h.handOverHouseholds(ctx, id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if errMessage != nil {
+		ctx.JSON(http.StatusConflict, errMessage)
+		return
+	}
+
+	attachments, err := h.repo(ctx).AttachmentListByUser(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.repo(ctx).UserDelete(id); err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
 			return
@@ -69,6 +128,7 @@ func (h *handler) DeleteAccount(ctx *gin.Context) {
 		return
 	}
 
+	h.removeBlobs(ctx, attachments)
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -79,7 +139,7 @@ func (h *handler) GetAccount(ctx *gin.Context) {
 		return
 	}
 
-	userModel, err := h.repo.UserGet(id)
+	userModel, err := h.repo(ctx).UserGet(id)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
@@ -90,6 +150,58 @@ func (h *handler) GetAccount(ctx *gin.Context) {
 		return
 	}
 
+	if notModified(ctx, ETag(userModel.Version)) {
+		return
+	}
+
 	accountResponse := UserModelToAccountResponse(userModel)
 	ctx.JSON(http.StatusOK, accountResponse)
 }
+
+// handOverHouseholds keeps what the user created in their households from being deleted together with
+// their account: it is handed over to another owner of each household. Households without other
+// members are deleted. The last owner of a household with other members has to pass on ownership
+// first; then nothing is changed.
+func (h *handler) handOverHouseholds(ctx *gin.Context, userID uint) (*ErrorMessage, error) {
+	memberships, err := h.repo(ctx).HouseholdListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Successors by household, 0 for the households the user is the only member of
+	successors := make(map[uint]uint, len(memberships))
+	for _, m := range memberships {
+		members, err := h.repo(ctx).HouseholdMemberList(m.HouseholdID)
+		if err != nil {
+			return nil, err
+		}
+
+		successors[m.HouseholdID] = 0
+		if len(members) == 1 {
+			continue
+		}
+		for _, other := range members {
+			if other.UserID != userID && other.Role == permissions.RoleOwner {
+				successors[m.HouseholdID] = other.UserID
+				break
+			}
+		}
+		if successors[m.HouseholdID] == 0 {
+			return ErrorLastOwner, nil
+		}
+	}
+
+	for _, m := range memberships {
+		successorID := successors[m.HouseholdID]
+		if successorID == 0 {
+			err = h.repo(ctx).HouseholdDelete(m.HouseholdID)
+		} else {
+			err = h.repo(ctx).HouseholdHandOver(m.HouseholdID, userID, successorID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
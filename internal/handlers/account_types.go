@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"expense-api/internal/currency"
 	"expense-api/internal/model"
 	"expense-api/internal/utils"
 	"time"
@@ -8,34 +9,50 @@ import (
 
 // Account is a user with an omitted 'password' field
 type Account struct {
-	ID        uint      `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	FirstName string    `json:"first_name"`
-	LastName  string    `json:"last_name"`
-	Email     string    `json:"email"`
+	ID           uint      `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	Email        string    `json:"email"`
+	BaseCurrency string    `json:"base_currency"`
 }
 
-func (a *Account) ValidateUpdateBody() *ErrorMessage {
-	if a.FirstName == "" && a.LastName == "" && a.Email == "" {
-		return ErrorEmptyBody
+// Validate checks an account as it is after an update
+func (a *Account) Validate() *ErrorMessage {
+	if a.FirstName == "" || a.LastName == "" {
+		return ErrorName
 	}
 
-	if a.Email != "" && !utils.IsEmailValid(a.Email) {
+	if !utils.IsEmailValid(a.Email) {
 		return ErrorEmail
 	}
 
+	if !currency.IsValid(a.BaseCurrency) {
+		return ErrorInvalidCurrency
+	}
+
 	return nil
 }
 
 // UserModelToAccountResponse cretes a user struct that doesn't expose the password of a user
 func UserModelToAccountResponse(u *model.User) *Account {
 	return &Account{
-		ID:        u.ID,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-		FirstName: u.FirstName,
-		LastName:  u.LastName,
-		Email:     u.Email,
+		ID:           u.ID,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Email:        u.Email,
+		BaseCurrency: u.BaseCurrency,
+	}
+}
+
+func AccountRequestToModel(a *Account) *model.User {
+	return &model.User{
+		FirstName:    a.FirstName,
+		LastName:     a.LastName,
+		Email:        a.Email,
+		BaseCurrency: a.BaseCurrency,
 	}
 }
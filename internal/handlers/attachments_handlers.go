@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"expense-api/internal/blobstore"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentsHandler interface {
+	ListAttachments(ctx *gin.Context)
+	CreateAttachment(ctx *gin.Context)
+	GetAttachment(ctx *gin.Context)
+	DownloadAttachment(ctx *gin.Context)
+	DeleteAttachment(ctx *gin.Context)
+}
+
+// attachmentFormField is the multipart form field holding the uploaded file
+const attachmentFormField = "file"
+
+// multipartOverhead leaves room for the multipart boundaries and headers around the file
+const multipartOverhead = 1 << 20
+
+func (h *handler) ListAttachments(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	aModels, err := h.repo(ctx).AttachmentList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	aResponse := make([]*Attachment, 0, len(aModels))
+	for _, a := range aModels {
+		aResponse = append(aResponse, AttachmentModelToResponse(a))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(aResponse))
+}
+
+// CreateAttachment stores an uploaded file in the blob store and attaches it to the transaction
+func (h *handler) CreateAttachment(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	if ctx.Request.ContentLength > MaxAttachmentSize+multipartOverhead {
+		ctx.JSON(http.StatusRequestEntityTooLarge, ErrorAttachmentTooLarge)
+		return
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, MaxAttachmentSize+multipartOverhead)
+
+	fileHeader, err := ctx.FormFile(attachmentFormField)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorAttachmentMissing)
+		return
+	}
+
+	if fileHeader.Size > MaxAttachmentSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, ErrorAttachmentTooLarge)
+		return
+	}
+
+	if fileHeader.Size == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorAttachmentEmpty)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, MaxAttachmentSize))
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	contentType := attachmentContentType(fileHeader.Header.Get("Content-Type"), content)
+	if !allowedAttachmentTypes[contentType] {
+		ctx.JSON(http.StatusUnsupportedMediaType, ErrorAttachmentType)
+		return
+	}
+
+	key, err := blobstore.NewKey(fmt.Sprintf("transactions/%d", id))
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	checksum := sha256.Sum256(content)
+	aModel := &model.Attachment{
+		TransactionID: id,
+		UserID:        userID,
+		FileName:      attachmentFileName(fileHeader.Filename),
+		ContentType:   contentType,
+		Size:          int64(len(content)),
+		Checksum:      hex.EncodeToString(checksum[:]),
+		StorageKey:    key,
+	}
+
+	if err := h.blobs.Put(ctx.Request.Context(), key, bytes.NewReader(content), aModel.Size, contentType); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.repo(ctx).AttachmentCreate(aModel); err != nil {
+		h.removeBlobs(ctx, []*model.Attachment{aModel})
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, AttachmentModelToResponse(aModel))
+}
+
+func (h *handler) GetAttachment(ctx *gin.Context) {
+	aModel, ok := h.transactionAttachment(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, AttachmentModelToResponse(aModel))
+}
+
+// DownloadAttachment responds with the attached file after verifying its checksum
+func (h *handler) DownloadAttachment(ctx *gin.Context) {
+	aModel, ok := h.transactionAttachment(ctx)
+	if !ok {
+		return
+	}
+
+	blob, err := h.blobs.Get(ctx.Request.Context(), aModel.StorageKey)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	defer blob.Close()
+
+	content, err := io.ReadAll(io.LimitReader(blob, MaxAttachmentSize+1))
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	checksum := sha256.Sum256(content)
+	if hex.EncodeToString(checksum[:]) != aModel.Checksum {
+		ctx.JSON(http.StatusInternalServerError, ErrorChecksumMismatch)
+		return
+	}
+
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": aModel.FileName}))
+	ctx.Header("Digest", "sha-256="+base64.StdEncoding.EncodeToString(checksum[:]))
+	ctx.Header("ETag", `"`+aModel.Checksum+`"`)
+	ctx.Data(http.StatusOK, aModel.ContentType, content)
+}
+
+func (h *handler) DeleteAttachment(ctx *gin.Context) {
+	aModel, ok := h.transactionAttachment(ctx)
+	if !ok {
+		return
+	}
+
+	// The blob goes first, if that fails the attachment is still there to retry
+	if err := h.blobs.Delete(ctx.Request.Context(), aModel.StorageKey); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.repo(ctx).AttachmentDelete(aModel.ID); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// transactionAttachment loads the attachment from the URL and makes sure it belongs to the
+// transaction from the URL. It responds with an error and returns false otherwise.
+func (h *handler) transactionAttachment(ctx *gin.Context) (*model.Attachment, bool) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	attachmentID, err := strconv.Atoi(ctx.Param("attachment_id"))
+	if err != nil || attachmentID <= 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorBadAttachmentID)
+		return nil, false
+	}
+
+	aModel, err := h.repo(ctx).AttachmentGet(uint(attachmentID))
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return nil, false
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return nil, false
+	}
+
+	if aModel.TransactionID != id {
+		ctx.Status(http.StatusNotFound)
+		return nil, false
+	}
+
+	return aModel, true
+}
+
+// removeBlobs deletes the files of attachments whose records are already gone. Failures don't fail
+// the request, they are recorded on the context so they show up in the logs.
+func (h *handler) removeBlobs(ctx *gin.Context, attachments []*model.Attachment) {
+	for _, a := range attachments {
+		if err := h.blobs.Delete(ctx.Request.Context(), a.StorageKey); err != nil {
+			ctx.Error(fmt.Errorf("couldn't delete blob %s: %w", a.StorageKey, err))
+		}
+	}
+}
+
+// attachmentFileName strips any directories from an uploaded file's name
+func attachmentFileName(name string) string {
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
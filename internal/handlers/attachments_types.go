@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MaxAttachmentSize is the largest file that can be attached to a transaction
+const MaxAttachmentSize = 10 << 20
+
+// allowedAttachmentTypes are the MIME types of receipts and documents that can be attached
+var allowedAttachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"image/heic":      true,
+	"text/plain":      true,
+	"text/csv":        true,
+}
+
+// Attachment is the metadata of a file attached to a transaction
+type Attachment struct {
+	ID            uint      `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	TransactionID uint      `json:"transaction_id"`
+	UserID        uint      `json:"user_id"`
+	FileName      string    `json:"file_name"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	Checksum      string    `json:"checksum_sha256"`
+}
+
+func AttachmentModelToResponse(a *model.Attachment) *Attachment {
+	return &Attachment{
+		ID:            a.ID,
+		CreatedAt:     a.CreatedAt,
+		TransactionID: a.TransactionID,
+		UserID:        a.UserID,
+		FileName:      a.FileName,
+		ContentType:   a.ContentType,
+		Size:          a.Size,
+		Checksum:      a.Checksum,
+	}
+}
+
+// attachmentContentType determines the MIME type of an upload from its content, falling back to
+// the declared type where sniffing can't tell (e.g. HEIC images or CSV files, which sniff as text)
+func attachmentContentType(declared string, content []byte) string {
+	sniffed := mediaType(http.DetectContentType(content))
+	declared = mediaType(declared)
+
+	if sniffed == "application/octet-stream" || (sniffed == "text/plain" && strings.HasPrefix(declared, "text/")) {
+		return declared
+	}
+	return sniffed
+}
+
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
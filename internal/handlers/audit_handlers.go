@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"expense-api/internal/audit"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler interface {
+	ListAudit(ctx *gin.Context)
+	ListTransactionHistory(ctx *gin.Context)
+	VerifyAudit(ctx *gin.Context)
+}
+
+// ListAudit lists the changes of the user, wallet, party or transaction given by the 'resource' and
+// 'id' query parameters, the oldest first. Wallets, parties and transactions in the trash have their
+// changes listed too.
+func (h *handler) ListAudit(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	resource := ctx.Query("resource")
+	if resource != model.AuditResourceUser && resource != model.AuditResourceWallet &&
+		resource != model.AuditResourceParty && resource != model.AuditResourceTransaction {
+		ctx.JSON(http.StatusBadRequest, ErrorAuditResource)
+		return
+	}
+
+	id, err := strconv.ParseUint(ctx.Query("id"), 10, 32)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorAuditID)
+		return
+	}
+
+	ownerID, householdID, err := h.auditedRecordOwner(ctx, resource, uint(id))
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	allowed, err := permissions.Check(h.repo(ctx), userID, ownerID, householdID, permissions.RoleViewer)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	h.respondAuditEntries(ctx, resource, uint(id))
+}
+
+// ListTransactionHistory lists the changes of the transaction, the oldest first
+func (h *handler) ListTransactionHistory(ctx *gin.Context) {
+	h.respondAuditEntries(ctx, model.AuditResourceTransaction, middleware.GetIDParamFromContext(ctx))
+}
+
+// VerifyAudit walks the hash chain of the audit entries about the user's records and reports the
+// first broken link
+func (h *handler) VerifyAudit(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	report, err := audit.NewVerifier(h.repo(ctx), audit.BatchSize).Verify(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	ctx.JSON(http.StatusOK, AuditReportToResponse(report))
+}
+
+func (h *handler) respondAuditEntries(ctx *gin.Context, resource string, id uint) {
+	eModels, err := h.repo(ctx).AuditList(resource, id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	eResponse := make([]*AuditEntry, 0, len(eModels))
+	for _, e := range eModels {
+		eResponse = append(eResponse, AuditEntryModelToResponse(e))
+	}
+	ctx.JSON(http.StatusOK, NewListResponse(eResponse))
+}
+
+// auditedRecordOwner finds the owner and household of the record, looking into the trash if it
+// isn't there. Users own themselves.
+func (h *handler) auditedRecordOwner(ctx *gin.Context, resource string, id uint) (uint, *uint, error) {
+	repo := h.repo(ctx)
+
+	switch resource {
+	case model.AuditResourceWallet:
+		w, err := repo.WalletGet(id)
+		if err == repository.ErrorRecordNotFound {
+			w, err = repo.WalletGetTrashed(id)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		return w.UserID, w.HouseholdID, nil
+	case model.AuditResourceParty:
+		p, err := repo.PartyGet(id)
+		if err == repository.ErrorRecordNotFound {
+			p, err = repo.PartyGetTrashed(id)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		return p.UserID, p.HouseholdID, nil
+	case model.AuditResourceTransaction:
+		t, err := repo.TransactionGet(id)
+		if err == repository.ErrorRecordNotFound {
+			t, err = repo.TransactionGetTrashed(id)
+		}
+		if err != nil {
+			return 0, nil, err
+		}
+		return t.UserID, t.HouseholdID, nil
+	}
+	return id, nil, nil
+}
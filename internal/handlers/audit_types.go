@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"expense-api/internal/audit"
+	"expense-api/internal/model"
+	"time"
+)
+
+// AuditEntry is a change of a user, wallet, party or transaction: who made it, when, from where and
+// which fields it changed
+type AuditEntry struct {
+	ID         uint                    `json:"id"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UserID     uint                    `json:"user_id"`
+	PrevHash   string                  `json:"prev_hash"`
+	Hash       string                  `json:"hash"`
+	ActorID    *uint                   `json:"actor_id"`
+	RequestID  string                  `json:"request_id"`
+	IP         string                  `json:"ip"`
+	Resource   string                  `json:"resource"`
+	ResourceID uint                    `json:"resource_id"`
+	Action     string                  `json:"action"`
+	Changes    map[string]*AuditChange `json:"changes"`
+}
+
+// AuditChange is the value of a field before and after the change, null where the record didn't exist
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func AuditEntryModelToResponse(e *model.AuditEntry) *AuditEntry {
+	changes := make(map[string]*AuditChange, len(e.Changes))
+	for field, change := range e.Changes {
+		changes[field] = &AuditChange{Before: change.Before, After: change.After}
+	}
+
+	return &AuditEntry{
+		ID:         e.ID,
+		CreatedAt:  e.CreatedAt,
+		UserID:     e.UserID,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+		ActorID:    e.ActorID,
+		RequestID:  e.RequestID,
+		IP:         e.IP,
+		Resource:   e.Resource,
+		ResourceID: e.ResourceID,
+		Action:     e.Action,
+		Changes:    changes,
+	}
+}
+
+// AuditVerification tells whether the hash chain of the user's audit entries is intact
+type AuditVerification struct {
+	UserID     uint             `json:"user_id"`
+	Checked    int              `json:"checked"`
+	Valid      bool             `json:"valid"`
+	BrokenLink *AuditBrokenLink `json:"broken_link"`
+}
+
+// AuditBrokenLink is the first entry of the chain that was edited or follows removed entries
+type AuditBrokenLink struct {
+	EntryID uint   `json:"entry_id"`
+	Reason  string `json:"reason"`
+}
+
+func AuditReportToResponse(r *audit.Report) *AuditVerification {
+	v := &AuditVerification{UserID: r.UserID, Checked: r.Checked, Valid: r.Broken == nil}
+	if r.Broken != nil {
+		v.BrokenLink = &AuditBrokenLink{EntryID: r.Broken.EntryID, Reason: r.Broken.Reason}
+	}
+	return v
+}
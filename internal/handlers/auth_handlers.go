@@ -36,7 +36,7 @@ func (h *handler) SignUp(ctx *gin.Context) {
 		return
 	}
 
-	if _, err := h.repo.UserCreate(
+	if _, err := h.repo(ctx).UserCreate(
 		signUpInfo.FirstName,
 		signUpInfo.LastName,
 		signUpInfo.Email,
@@ -69,7 +69,7 @@ func (h *handler) Login(ctx *gin.Context) {
 		return
 	}
 
-	user, err := h.repo.UserGetWithEmail(loginInfo.Email)
+	user, err := h.repo(ctx).UserGetWithEmail(loginInfo.Email)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.JSON(http.StatusNotFound, ErrorNonExistentUser)
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"expense-api/internal/duplicates"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	transactions_middleware "expense-api/internal/middleware/transactions"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DuplicatesHandler interface {
+	ListDuplicateTransactions(ctx *gin.Context)
+	MergeTransactions(ctx *gin.Context)
+}
+
+// ListDuplicateTransactions lists the pairs of transactions the user can see that are likely the same purchase
+func (h *handler) ListDuplicateTransactions(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	tModels, err := h.repo(ctx).TransactionList(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	pairs := duplicates.Pairs(tModels)
+	pResponse := make([]*DuplicatePair, 0, len(pairs))
+
+	for _, p := range pairs {
+		pResponse = append(pResponse, DuplicatePairToResponse(p))
+	}
+
+	res := NewListResponse(pResponse)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// MergeTransactions keeps the transaction from the URL and deletes its duplicate, moving the
+// duplicate's attachments and tags over to it. Both have to be in the same wallet, and the
+// duplicate can't have splits or shares.
+func (h *handler) MergeTransactions(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+	survivor := transactions_middleware.GetTransactionFromContext(ctx)
+
+	if !checkIfMatch(ctx, survivor.Version) || !allowChange(ctx, survivor) {
+		return
+	}
+
+	var mRequest TransactionMerge
+	if err := ctx.Bind(&mRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if mRequest.DuplicateID == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredDuplicateID)
+		return
+	}
+
+	if mRequest.DuplicateID == id {
+		ctx.JSON(http.StatusBadRequest, ErrorMergeWithItself)
+		return
+	}
+
+	duplicate, err := h.repo(ctx).TransactionGet(mRequest.DuplicateID)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.JSON(http.StatusBadRequest, ErrorDuplicateNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	allowed, err := permissions.Check(h.repo(ctx), userID, duplicate.UserID, duplicate.HouseholdID, permissions.RoleEditor)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, ErrorBadDuplicateID)
+		return
+	}
+
+	if !allowChange(ctx, duplicate) {
+		return
+	}
+
+	if duplicate.WalletID != survivor.WalletID {
+		ctx.JSON(http.StatusBadRequest, ErrorDuplicateWallet)
+		return
+	}
+
+	stamps := h.historyStamps(ctx, duplicate.UserID)
+	merged, err := h.repo(ctx).TransactionMerge(id, duplicate.ID)
+	if err != nil {
+		switch err {
+		case repository.ErrorRecordNotFound:
+			ctx.Status(http.StatusNotFound)
+		case repository.ErrorDifferentWallets:
+			ctx.JSON(http.StatusBadRequest, ErrorDuplicateWallet)
+		case repository.ErrorHasSplitsOrShares:
+			ctx.JSON(http.StatusConflict, ErrorDuplicateSplit)
+		default:
+			ctx.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+	// Only the duplicate changes for the classifier, the survivor just gets its tags
+	h.learnCategories(ctx, stamps, categoryChange{before: duplicate})
+
+	ctx.Header("ETag", ETag(merged.Version))
+	ctx.JSON(http.StatusOK, TransactionModelToResponse(merged))
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"expense-api/internal/duplicates"
+	"expense-api/internal/model"
+)
+
+// DuplicateWarning points out the existing transactions a new transaction likely duplicates
+type DuplicateWarning struct {
+	Message        string `json:"message"`
+	TransactionIDs []uint `json:"transaction_ids"`
+}
+
+// DuplicatePair is two transactions that are likely the same purchase, the older one first
+type DuplicatePair struct {
+	Transaction *Transaction `json:"transaction"`
+	Duplicate   *Transaction `json:"duplicate"`
+	Similarity  float64      `json:"similarity"`
+}
+
+// TransactionMerge names the duplicate to merge into a transaction
+type TransactionMerge struct {
+	DuplicateID uint `json:"duplicate_id"`
+}
+
+func NewDuplicateWarning(found []*model.Transaction) *DuplicateWarning {
+	ids := make([]uint, 0, len(found))
+	for _, t := range found {
+		ids = append(ids, t.ID)
+	}
+	return &DuplicateWarning{Message: ErrorDuplicateTransaction.Message, TransactionIDs: ids}
+}
+
+func DuplicatePairToResponse(p *duplicates.Pair) *DuplicatePair {
+	return &DuplicatePair{
+		Transaction: TransactionModelToResponse(p.First),
+		Duplicate:   TransactionModelToResponse(p.Second),
+		Similarity:  p.Similarity,
+	}
+}
@@ -6,7 +6,17 @@ type ErrorMessage struct {
 
 var (
 	// Generic
-	ErrorEmptyBody = &ErrorMessage{Message: "empty body"}
+	ErrorInvalidPatch = &ErrorMessage{Message: "patch must be a JSON object whose members are valid for the resource"}
+	// Preconditions
+	ErrorPreconditionFailed = &ErrorMessage{Message: "the resource was changed, its current ETag doesn't match If-Match"}
+	ErrorConcurrentUpdate   = &ErrorMessage{Message: "the resource was changed by another request at the same time, get it again and retry"}
+	// Currency
+	ErrorInvalidCurrency   = &ErrorMessage{Message: "currency must be a supported ISO 4217 code"}
+	ErrorAmountPrecision   = &ErrorMessage{Message: "amount has more decimal places than the wallet's currency allows"}
+	ErrorInvalidDate       = &ErrorMessage{Message: "date must be formatted as YYYY-MM-DD"}
+	ErrorInvalidRate       = &ErrorMessage{Message: "exchange rate must be positive and between two different currencies"}
+	ErrorUnknownRateFormat = &ErrorMessage{Message: "exchange rate file format must be either 'xml' or 'csv'"}
+	ErrorMalformedRateFile = &ErrorMessage{Message: "exchange rate file is not in the ECB format"}
 	// Auth/Account
 	ErrorName                   = &ErrorMessage{Message: "first and/or last name missing"}
 	ErrorEmail                  = &ErrorMessage{Message: "invalid email address"}
@@ -15,15 +25,126 @@ var (
 	ErrorEmailConflict          = &ErrorMessage{Message: "user with this email already exists"}
 	ErrorWrongPassword          = &ErrorMessage{Message: "wrong password"}
 	// Party
-	ErrorPartyNameTaken = &ErrorMessage{Message: "party with the same name, belonging to the same user already exists"}
+	ErrorPartyNameTaken    = &ErrorMessage{Message: "party with the same name, belonging to the same user already exists"}
+	ErrorPartyName         = &ErrorMessage{Message: "party name missing"}
+	ErrorRequiredSourceIDs = &ErrorMessage{Message: "the ids of the parties to merge must be specified"}
+	ErrorMergeIntoItself   = &ErrorMessage{Message: "cannot merge a party into itself"}
+	ErrorBadAliasID        = &ErrorMessage{Message: "missing/not-a-number alias ID in request"}
+	ErrorPartyReconciled   = &ErrorMessage{Message: "party has reconciled transactions, which can no longer be moved or deleted"}
+	ErrorPartyTransactions = &ErrorMessage{Message: "party still has transactions, delete them with 'cascade=true' or move them with 'reassign_to'"}
+	ErrorPartyReassignID   = &ErrorMessage{Message: "'reassign_to' must be the id of another party"}
+	ErrorReassignHousehold = &ErrorMessage{Message: "transactions can only be moved to a party of the same household"}
+	ErrorPartyInUse        = &ErrorMessage{Message: "party is used by transactions outside the household it would move to"}
+	ErrorMergeHousehold    = &ErrorMessage{Message: "parties can only be merged into a party of the same household"}
 	// Wallet
-	ErrorWalletNameTaken = &ErrorMessage{Message: "wallet with the same name, belonging to the same user already exists"}
+	ErrorWalletNameTaken       = &ErrorMessage{Message: "wallet with the same name, belonging to the same user already exists"}
+	ErrorWalletName            = &ErrorMessage{Message: "wallet name missing"}
+	ErrorInvalidWalletType     = &ErrorMessage{Message: "wallet type must be one of 'cash', 'checking', 'savings', 'credit_card', 'loan' or 'investment'"}
+	ErrorCreditLimitType       = &ErrorMessage{Message: "only credit card wallets can have a credit limit"}
+	ErrorNegativeCreditLimit   = &ErrorMessage{Message: "credit limit must not be negative"}
+	ErrorMonthlyBudget         = &ErrorMessage{Message: "monthly budget must be greater than 0"}
+	ErrorWalletCurrencyChange  = &ErrorMessage{Message: "the currency of a wallet cannot be changed"}
+	ErrorWalletHasTransactions = &ErrorMessage{Message: "wallet still has transactions, delete them with 'cascade=true' or move them with 'reassign_to'"}
+	ErrorCascadeAndReassign    = &ErrorMessage{Message: "only one of 'cascade' and 'reassign_to' can be used"}
+	ErrorBadReassignID         = &ErrorMessage{Message: "'reassign_to' must be the id of another wallet"}
+	ErrorReassignCurrency      = &ErrorMessage{Message: "transactions can only be moved to a wallet with the same currency"}
+	ErrorWalletReconciled      = &ErrorMessage{Message: "wallet has reconciled transactions, which can no longer be moved or deleted"}
+	ErrorWalletForeignParties  = &ErrorMessage{Message: "wallet has transactions with parties outside the household they would move to"}
+	// Household
+	ErrorInvalidRole          = &ErrorMessage{Message: "role must be one of 'owner', 'editor' or 'viewer'"}
+	ErrorHouseholdName        = &ErrorMessage{Message: "household name missing"}
+	ErrorBadHouseholdID       = &ErrorMessage{Message: "only editors and owners of the household can add to it"}
+	ErrorAlreadyMember        = &ErrorMessage{Message: "user is already a member of the household"}
+	ErrorInvitationPending    = &ErrorMessage{Message: "user was already invited to the household"}
+	ErrorInvitationNotPending = &ErrorMessage{Message: "invitation was already accepted or declined"}
+	ErrorLastOwner            = &ErrorMessage{Message: "a household needs at least one owner"}
+	ErrorBadMemberID          = &ErrorMessage{Message: "missing/not-a-number user ID in request"}
+	ErrorHouseholdMembers     = &ErrorMessage{Message: "household still has other members, who have to leave or be removed first"}
+	ErrorHouseholdNameTaken   = &ErrorMessage{Message: "a wallet or party of the household has the same name as one of yours, rename it first"}
 	// Transaction
-	ErrorRequiredAmount   = &ErrorMessage{Message: "cannot create new transaction with an amount of 0"}
-	ErrorRequiredWalletID = &ErrorMessage{Message: "a valid wallet id must be specified to register a new transaction"}
-	ErrorRequiredPartyID  = &ErrorMessage{Message: "a valid wallet id must be specified to register a new transaction"}
-	ErrorWalletNotFound   = &ErrorMessage{Message: "wallet with specified id not found"}
-	ErrorBadWalletID      = &ErrorMessage{Message: "wallet with specified id belongs to another user"}
-	ErrorPartyNotFound    = &ErrorMessage{Message: "party with specified id not found"}
-	ErrorBadPartyID       = &ErrorMessage{Message: "party with specified id belongs to another user"}
+	ErrorRequiredAmount    = &ErrorMessage{Message: "cannot create new transaction with an amount of 0"}
+	ErrorRequiredWalletID  = &ErrorMessage{Message: "a valid wallet id must be specified to register a new transaction"}
+	ErrorRequiredPartyID   = &ErrorMessage{Message: "a valid party id must be specified to register a new transaction"}
+	ErrorRequiredTimestamp = &ErrorMessage{Message: "the timestamp of a transaction cannot be removed"}
+	ErrorWalletNotFound    = &ErrorMessage{Message: "wallet with specified id not found"}
+	ErrorBadWalletID       = &ErrorMessage{Message: "wallet with specified id belongs to another user"}
+	ErrorPartyNotFound     = &ErrorMessage{Message: "party with specified id not found"}
+	ErrorBadPartyID        = &ErrorMessage{Message: "party with specified id belongs to another user"}
+	ErrorPartyHousehold    = &ErrorMessage{Message: "party must belong to the household of the wallet, or be a personal party for a personal wallet"}
+	ErrorInvalidTag        = &ErrorMessage{Message: "tags must not be empty or longer than 50 characters"}
+	ErrorInvalidStatus     = &ErrorMessage{Message: "status must be either 'uncleared' or 'cleared'"}
+	// Duplicates
+	ErrorDuplicateTransaction = &ErrorMessage{Message: "transaction is likely a duplicate of an existing transaction"}
+	ErrorRequiredDuplicateID  = &ErrorMessage{Message: "the id of the duplicate to merge must be specified"}
+	ErrorMergeWithItself      = &ErrorMessage{Message: "cannot merge a transaction with itself"}
+	ErrorDuplicateNotFound    = &ErrorMessage{Message: "duplicate with specified id not found"}
+	ErrorBadDuplicateID       = &ErrorMessage{Message: "duplicate with specified id belongs to another user"}
+	ErrorDuplicateWallet      = &ErrorMessage{Message: "only transactions in the same wallet can be merged"}
+	ErrorDuplicateSplit       = &ErrorMessage{Message: "duplicate has splits or shares, which would be lost; remove them first"}
+	// Bulk
+	ErrorInvalidBulkMode        = &ErrorMessage{Message: "mode must be either 'all_or_nothing' or 'best_effort'"}
+	ErrorBulkOperationCount     = &ErrorMessage{Message: "between 1 and 500 operations must be sent at once"}
+	ErrorInvalidBulkOperation   = &ErrorMessage{Message: "op must be either 'create', 'update' or 'delete'"}
+	ErrorInvalidBulkTransaction = &ErrorMessage{Message: "transaction to create must be a JSON object"}
+	ErrorRequiredTransactionID  = &ErrorMessage{Message: "the id of the transaction to update or delete must be specified"}
+	ErrorBulkDuplicateID        = &ErrorMessage{Message: "a transaction can only be updated or deleted once per request"}
+	ErrorTransactionNotFound    = &ErrorMessage{Message: "transaction with specified id not found"}
+	ErrorBadTransactionID       = &ErrorMessage{Message: "transaction with specified id belongs to another user"}
+	ErrorBulkNotApplied         = &ErrorMessage{Message: "operation was not applied because another operation failed"}
+	ErrorBulkOperationFailed    = &ErrorMessage{Message: "operation failed because of an internal error"}
+	// Trash
+	ErrorParentTrashed = &ErrorMessage{Message: "the wallet or party of the transaction is in the trash, restore it first"}
+	// Audit
+	ErrorAuditResource = &ErrorMessage{Message: "resource must be one of 'user', 'wallet', 'party' or 'transaction'"}
+	ErrorAuditID       = &ErrorMessage{Message: "missing/not-a-number id of the resource"}
+	// Reconciliation
+	ErrorTransactionReconciled    = &ErrorMessage{Message: "transaction is reconciled and can no longer be changed"}
+	ErrorRequiredStatementDate    = &ErrorMessage{Message: "the date of the statement must be specified"}
+	ErrorRequiredStatementBalance = &ErrorMessage{Message: "the ending balance of the statement must be specified"}
+	ErrorStatementOutOfBalance    = &ErrorMessage{Message: "cleared transactions changed and no longer match the statement balance"}
+	// Transaction Splits
+	ErrorInvalidSplits           = &ErrorMessage{Message: "some of the splits are invalid"}
+	ErrorTooFewSplits            = &ErrorMessage{Message: "a transaction must be split into at least 2 lines"}
+	ErrorTransactionAlreadySplit = &ErrorMessage{Message: "transaction is already split, replace its splits instead"}
+	ErrorTransactionNotSplit     = &ErrorMessage{Message: "transaction is not split"}
+	ErrorSplitsOutOfBalance      = &ErrorMessage{Message: "transaction is split, its amount can only change together with its splits"}
+	// Attachments
+	ErrorAttachmentMissing  = &ErrorMessage{Message: "a file must be uploaded in the 'file' field of a multipart form"}
+	ErrorAttachmentEmpty    = &ErrorMessage{Message: "attached file is empty"}
+	ErrorAttachmentTooLarge = &ErrorMessage{Message: "attached file must not be larger than 10 MiB"}
+	ErrorAttachmentType     = &ErrorMessage{Message: "only PDF documents, images and text files can be attached"}
+	ErrorBadAttachmentID    = &ErrorMessage{Message: "missing/not-a-number attachment ID in request"}
+	ErrorChecksumMismatch   = &ErrorMessage{Message: "stored file doesn't match its checksum"}
+	// Search
+	ErrorSearchQuery = &ErrorMessage{Message: "search query must contain at least one word"}
+	ErrorSearchType  = &ErrorMessage{Message: "search type must be one of 'transaction', 'party' or 'wallet'"}
+	ErrorSearchLimit = &ErrorMessage{Message: "limit must be a number between 1 and 100"}
+	// Rules
+	ErrorRuleName        = &ErrorMessage{Message: "rule name missing"}
+	ErrorRuleNoCondition = &ErrorMessage{Message: "a rule needs at least one condition"}
+	ErrorRuleNoAction    = &ErrorMessage{Message: "a rule needs at least one action"}
+	ErrorRuleRegex       = &ErrorMessage{Message: "description regex is not a valid regular expression"}
+	ErrorRuleAmountRange = &ErrorMessage{Message: "minimum amount must not be greater than the maximum amount"}
+	// Webhooks
+	ErrorWebhookURL    = &ErrorMessage{Message: "webhook url must be an absolute http or https URL of a public host"}
+	ErrorWebhookEvents = &ErrorMessage{Message: "events must be one or more of 'transaction.created', 'transaction.updated', 'transaction.deleted', 'transaction.restored', 'wallet.created', 'wallet.updated', 'wallet.deleted', 'wallet.restored', 'budget.exceeded', 'transaction.*', 'wallet.*', 'budget.*' or '*'"}
+	ErrorBadDeliveryID = &ErrorMessage{Message: "missing/not-a-number delivery ID in request"}
+	// Event stream
+	ErrorBadLastEventID = &ErrorMessage{Message: "Last-Event-ID must be the ID of an event of the stream"}
+	// Suggestions
+	ErrorSuggestionText   = &ErrorMessage{Message: "a description to suggest categories for must be given in 'q'"}
+	ErrorSuggestionAmount = &ErrorMessage{Message: "amount must be a number"}
+	ErrorSuggestionLimit  = &ErrorMessage{Message: "k must be a number between 1 and 20"}
+	// Shared expenses
+	ErrorInvalidShareMethod   = &ErrorMessage{Message: "share method must be one of 'equal', 'percentage' or 'exact'"}
+	ErrorShareIncome          = &ErrorMessage{Message: "only expenses can be shared"}
+	ErrorParticipantNotFound  = &ErrorMessage{Message: "participant is not a member of any household of the payer"}
+	ErrorDuplicateParticipant = &ErrorMessage{Message: "every user can only participate once in a shared expense"}
+	ErrorNoOtherParticipant   = &ErrorMessage{Message: "an expense must be shared with at least one other user"}
+	ErrorPercentagesNot100    = &ErrorMessage{Message: "percentages must be positive and add up to 100"}
+	ErrorSharesMismatch       = &ErrorMessage{Message: "shares must not be negative and must add up to the transaction amount"}
+	ErrorSharesOutOfBalance   = &ErrorMessage{Message: "transaction is shared, its amount can only change together with its shares"}
+	ErrorUserNotFound         = &ErrorMessage{Message: "user with specified id is not a member of any of your households"}
+	ErrorSettleWithSelf       = &ErrorMessage{Message: "cannot settle up with yourself"}
+	ErrorSettlementAmount     = &ErrorMessage{Message: "settlement amount must be positive"}
 )
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EventsHandler interface {
+	StreamEvents(ctx *gin.Context)
+}
+
+const (
+	// HeartbeatInterval is how often the event stream sends a comment to keep the connection open
+	HeartbeatInterval = 15 * time.Second
+	// streamResumeBatch is how many missed events are read at once when a stream resumes
+	streamResumeBatch = 100
+	// streamMaxReplay is how many missed events a stream replays before it tells the client to reset
+	streamMaxReplay = 1000
+)
+
+// StreamEvents streams the changes of the user's wallets, parties and transactions and of the ones
+// of their households as server-sent events. The ID of every event is its position in the outbox, so
+// a client that reconnects with the Last-Event-ID header gets the events it missed first, or a reset
+// event when it missed too many.
+func (h *handler) StreamEvents(ctx *gin.Context) {
+	userID, err := auth.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var lastID uint64
+	if header := ctx.GetHeader("Last-Event-ID"); header != "" {
+		if lastID, err = strconv.ParseUint(header, 10, 32); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorBadLastEventID)
+			return
+		}
+	}
+
+	households, err := h.streamHouseholds(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	householdIDs := streamHouseholdIDs(households)
+	var missed []*model.OutboxEvent
+	if lastID > 0 {
+		if missed, err = h.repository.OutboxList(userID, householdIDs, uint(lastID), streamResumeBatch); err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Keeps proxies such as nginx from buffering the stream
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.WriteHeaderNow()
+	ctx.Writer.Flush()
+
+	sent := map[uint]bool{}
+	resumeID, ok := h.replayEvents(ctx, userID, householdIDs, uint(lastID), missed, sent)
+	if !ok {
+		return
+	}
+
+	events, unsubscribe := h.streams.Subscribe()
+	defer unsubscribe()
+
+	// The events published while the stream caught up were missed by the subscription
+	if lastID > 0 {
+		if missed, err = h.repository.OutboxList(userID, householdIDs, resumeID, streamResumeBatch); err != nil {
+			return
+		}
+		if _, ok := h.replayEvents(ctx, userID, householdIDs, resumeID, missed, sent); !ok {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case e, ok := <-events:
+			// The hub dropped the stream, the client catches up when it reconnects
+			if !ok {
+				return
+			}
+			if sent[e.ID] || !(e.UserID == userID || e.HouseholdID != nil && households[*e.HouseholdID]) {
+				continue
+			}
+			if !writeStreamEvent(ctx, e) {
+				return
+			}
+		case <-heartbeat.C:
+			// Members who left a household stop getting its events by the next heartbeat
+			if households, err = h.streamHouseholds(userID); err != nil {
+				return
+			}
+			if _, err := fmt.Fprint(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
+
+// streamHouseholds are the IDs of the households the user is a member of
+func (h *handler) streamHouseholds(userID uint) (map[uint]bool, error) {
+	members, err := h.repository.HouseholdListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	households := map[uint]bool{}
+	for _, m := range members {
+		households[m.HouseholdID] = true
+	}
+	return households, nil
+}
+
+func streamHouseholdIDs(households map[uint]bool) []uint {
+	ids := make([]uint, 0, len(households))
+	for id := range households {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// replayEvents sends the missed events after lastID a batch at a time, starting with the batch that
+// was already read. When more than streamMaxReplay events were missed, the client is told to reset
+// instead, and the stream resumes after the latest event. It returns the ID the stream resumes
+// after, and whether the client is still there.
+func (h *handler) replayEvents(ctx *gin.Context, userID uint, householdIDs []uint, lastID uint, batch []*model.OutboxEvent, sent map[uint]bool) (uint, bool) {
+	replayed := 0
+	for {
+		for _, e := range batch {
+			if !writeStreamEvent(ctx, e) {
+				return lastID, false
+			}
+			sent[e.ID] = true
+			lastID = e.ID
+		}
+		if len(batch) < streamResumeBatch {
+			return lastID, true
+		}
+
+		if replayed += len(batch); replayed >= streamMaxReplay {
+			latestID, err := h.repository.OutboxLatestID()
+			if err != nil {
+				return lastID, false
+			}
+			if _, err := fmt.Fprintf(ctx.Writer, "id: %d\nevent: reset\ndata: {}\n\n", latestID); err != nil {
+				return lastID, false
+			}
+			ctx.Writer.Flush()
+			return latestID, true
+		}
+
+		var err error
+		if batch, err = h.repository.OutboxList(userID, householdIDs, lastID, streamResumeBatch); err != nil {
+			return lastID, false
+		}
+	}
+}
+
+// writeStreamEvent sends the event to the client, it tells whether the client is still there
+func writeStreamEvent(ctx *gin.Context, e *model.OutboxEvent) bool {
+	data, err := json.Marshal(StreamEventModelToResponse(e))
+	if err != nil {
+		return false
+	}
+	if _, err := fmt.Fprintf(ctx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+		return false
+	}
+	ctx.Writer.Flush()
+	return true
+}
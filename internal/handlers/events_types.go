@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"encoding/json"
+	"expense-api/internal/model"
+	"time"
+)
+
+// StreamEvent is the data of a server-sent event about a change of a wallet, party or transaction.
+// Object is the record after the change, or before it if it was deleted for good.
+type StreamEvent struct {
+	ID          string             `json:"id"`
+	Event       string             `json:"event"`
+	Resource    string             `json:"resource"`
+	ResourceID  uint               `json:"resource_id"`
+	HouseholdID *uint              `json:"household_id"`
+	ActorID     *uint              `json:"actor_id"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Object      json.RawMessage    `json:"object"`
+	Changes     model.AuditChanges `json:"changes"`
+}
+
+func StreamEventModelToResponse(e *model.OutboxEvent) *StreamEvent {
+	return &StreamEvent{
+		ID:          e.EventID,
+		Event:       e.Type,
+		Resource:    e.Aggregate,
+		ResourceID:  e.AggregateID,
+		HouseholdID: e.HouseholdID,
+		ActorID:     e.ActorID,
+		CreatedAt:   e.CreatedAt,
+		Object:      json.RawMessage(e.Object),
+		Changes:     e.Changes,
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/model"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRatesHandler interface {
+	ListExchangeRates(ctx *gin.Context)
+	CreateExchangeRate(ctx *gin.Context)
+	ImportExchangeRates(ctx *gin.Context)
+}
+
+func (h *handler) ListExchangeRates(ctx *gin.Context) {
+	base := currency.Normalize(ctx.Query("base"))
+	quote := currency.Normalize(ctx.Query("quote"))
+
+	rModels, err := h.repo(ctx).ExchangeRateList(base, quote)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	rResponse := make([]*ExchangeRate, 0, len(rModels))
+	for _, r := range rModels {
+		rResponse = append(rResponse, ExchangeRateModelToResponse(r))
+	}
+
+	res := NewListResponse(rResponse)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *handler) CreateExchangeRate(ctx *gin.Context) {
+	var rRequest ExchangeRate
+	if err := ctx.Bind(&rRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	rModel, errMsg := ExchangeRateRequestToModel(&rRequest)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	if err := h.repo(ctx).ExchangeRateUpsert([]*model.ExchangeRate{rModel}); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	rResponse := ExchangeRateModelToResponse(rModel)
+	ctx.JSON(http.StatusCreated, rResponse)
+}
+
+// ImportExchangeRates imports an ECB reference rate file, sent either as the raw request body or
+// as the 'file' field of a multipart form. The format is taken from the 'format' query parameter,
+// the file extension or the content type, in that order.
+func (h *handler) ImportExchangeRates(ctx *gin.Context) {
+	var (
+		body        io.Reader = ctx.Request.Body
+		filename    string
+		contentType = ctx.ContentType()
+	)
+
+	if strings.HasPrefix(contentType, "multipart/") {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			ctx.Status(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		body = file
+		filename = fileHeader.Filename
+		contentType = fileHeader.Header.Get("Content-Type")
+	}
+
+	parse := exchangeRateParser(ctx.Query("format"), filename, contentType)
+	if parse == nil {
+		ctx.JSON(http.StatusBadRequest, ErrorUnknownRateFormat)
+		return
+	}
+
+	rates, err := parse(body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorMalformedRateFile)
+		return
+	}
+
+	if err := h.repo(ctx).ExchangeRateUpsert(rates); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &ExchangeRateImport{Imported: len(rates)})
+}
+
+func exchangeRateParser(format, filename, contentType string) func(io.Reader) ([]*model.ExchangeRate, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+
+	if format == "" {
+		switch {
+		case strings.Contains(contentType, "xml"):
+			format = "xml"
+		case strings.Contains(contentType, "csv"):
+			format = "csv"
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case "xml":
+		return currency.ParseECBXML
+	case "csv":
+		return currency.ParseECBCSV
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate states that on 'date' one unit of 'base' was worth 'rate' units of 'quote'
+type ExchangeRate struct {
+	ID        uint            `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Date      string          `json:"date"`
+	Base      string          `json:"base"`
+	Quote     string          `json:"quote"`
+	Rate      decimal.Decimal `json:"rate"`
+	Source    string          `json:"source"`
+}
+
+// ExchangeRateImport is the result of importing an ECB exchange rate file
+type ExchangeRateImport struct {
+	Imported int `json:"imported"`
+}
+
+func ExchangeRateModelToResponse(r *model.ExchangeRate) *ExchangeRate {
+	return &ExchangeRate{
+		ID:        r.ID,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+		Date:      r.Date.Format(currency.DateLayout),
+		Base:      r.Base,
+		Quote:     r.Quote,
+		Rate:      r.Rate,
+		Source:    r.Source,
+	}
+}
+
+// ExchangeRateRequestToModel validates a manually entered exchange rate and converts it to a model
+func ExchangeRateRequestToModel(r *ExchangeRate) (*model.ExchangeRate, *ErrorMessage) {
+	date, err := time.Parse(currency.DateLayout, r.Date)
+	if err != nil {
+		return nil, ErrorInvalidDate
+	}
+
+	base := currency.Normalize(r.Base)
+	quote := currency.Normalize(r.Quote)
+	if !currency.IsValid(base) || !currency.IsValid(quote) {
+		return nil, ErrorInvalidCurrency
+	}
+
+	if base == quote || !r.Rate.IsPositive() {
+		return nil, ErrorInvalidRate
+	}
+
+	return &model.ExchangeRate{
+		Date:   date,
+		Base:   base,
+		Quote:  quote,
+		Rate:   r.Rate,
+		Source: currency.SourceManual,
+	}, nil
+}
@@ -1,29 +1,67 @@
 package handlers
 
 import (
+	"expense-api/internal/blobstore"
+	"expense-api/internal/classifier"
+	"expense-api/internal/middleware"
 	"expense-api/internal/middleware/auth"
 	"expense-api/internal/repository"
+	"expense-api/internal/stream"
 	"expense-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
 )
 
 type Handler interface {
 	AuthHandler
 	AccountHandler
 	TransactionsHandler
+	DuplicatesHandler
+	TransactionSplitsHandler
+	TransactionSharesHandler
+	AttachmentsHandler
 	WalletsHandler
+	ReconciliationsHandler
 	PartiesHandler
+	HouseholdsHandler
+	ExchangeRatesHandler
+	ReportsHandler
+	RulesHandler
+	SearchHandler
+	SuggestionsHandler
+	SharedHandler
+	TrashHandler
+	AuditHandler
+	WebhooksHandler
+	EventsHandler
 }
 
 type handler struct {
-	repo       repository.Repository
+	repository repository.Repository
 	jwtService auth.JWTService
 	hasher     utils.PasswordHasher
+	blobs      blobstore.BlobStore
+	// classifiers keep the users' category classifiers in memory, see categoryClassifier
+	classifiers *classifier.Registry
+	// streams hands the published events to the event streams of this instance
+	streams *stream.Hub
 }
 
 func New(
 	repo repository.Repository,
 	jwtService auth.JWTService,
 	hasher utils.PasswordHasher,
+	blobs blobstore.BlobStore,
 ) Handler {
-	return &handler{repo, jwtService, hasher}
+	return &handler{repo, jwtService, hasher, blobs, classifier.NewRegistry(classifier.DefaultCapacity), stream.NewHub(repo)}
+}
+
+// repo is the repository for the request, its changes are audited as made by the user of the request
+func (h *handler) repo(ctx *gin.Context) repository.Repository {
+	userID, _ := auth.GetUserIDFromContext(ctx)
+	return h.repository.WithActor(&repository.Actor{
+		UserID:    userID,
+		RequestID: middleware.GetRequestIDFromContext(ctx),
+		IP:        ctx.ClientIP(),
+	})
 }
@@ -0,0 +1,506 @@
+package handlers
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	households_middleware "expense-api/internal/middleware/households"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HouseholdsHandler interface {
+	ListHouseholds(ctx *gin.Context)
+	CreateHousehold(ctx *gin.Context)
+	GetHousehold(ctx *gin.Context)
+	UpdateHousehold(ctx *gin.Context)
+	DeleteHousehold(ctx *gin.Context)
+	ListHouseholdMembers(ctx *gin.Context)
+	UpdateHouseholdMember(ctx *gin.Context)
+	RemoveHouseholdMember(ctx *gin.Context)
+	ListHouseholdInvitations(ctx *gin.Context)
+	CreateHouseholdInvitation(ctx *gin.Context)
+	ListInvitations(ctx *gin.Context)
+	AcceptInvitation(ctx *gin.Context)
+	DeclineInvitation(ctx *gin.Context)
+}
+
+func (h *handler) ListHouseholds(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	members, err := h.repo(ctx).HouseholdListByUser(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	hResponse := make([]*Household, 0, len(members))
+	for _, m := range members {
+		hResponse = append(hResponse, HouseholdModelToResponse(&m.Household, m.Role))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(hResponse))
+}
+
+// CreateHousehold creates a household with the current user as its owner
+func (h *handler) CreateHousehold(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var hRequest Household
+	if err := ctx.Bind(&hRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if hRequest.Name == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorHouseholdName)
+		return
+	}
+
+	hModel := &model.Household{Name: hRequest.Name}
+	if err := h.repo(ctx).HouseholdCreate(hModel, userID, permissions.RoleOwner); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, HouseholdModelToResponse(hModel, permissions.RoleOwner))
+}
+
+func (h *handler) GetHousehold(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	hModel, err := h.repo(ctx).HouseholdGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, HouseholdModelToResponse(hModel, households_middleware.GetRoleFromContext(ctx)))
+}
+
+// UpdateHousehold applies a JSON Merge Patch to the household
+func (h *handler) UpdateHousehold(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+	role := households_middleware.GetRoleFromContext(ctx)
+
+	current, err := h.repo(ctx).HouseholdGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	var hRequest Household
+	if err := bindPatch(ctx, HouseholdModelToResponse(current, role), &hRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	if hRequest.Name == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorHouseholdName)
+		return
+	}
+
+	hModel, err := h.repo(ctx).HouseholdUpdate(id, &model.Household{Model: model.Model{Version: current.Version}, Name: hRequest.Name})
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, HouseholdModelToResponse(hModel, role))
+}
+
+// DeleteHousehold deletes a household without other members, its wallets, parties and transactions go to the member deleting it
+func (h *handler) DeleteHousehold(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	if err := h.repo(ctx).HouseholdDelete(id); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrorHouseholdHasMembers {
+			ctx.JSON(http.StatusConflict, ErrorHouseholdMembers)
+			return
+		}
+		if err == repository.ErrorUniqueConstaintViolation {
+			ctx.JSON(http.StatusConflict, ErrorHouseholdNameTaken)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *handler) ListHouseholdMembers(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	members, err := h.repo(ctx).HouseholdMemberList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	mResponse := make([]*HouseholdMember, 0, len(members))
+	for _, m := range members {
+		mResponse = append(mResponse, HouseholdMemberModelToResponse(m))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(mResponse))
+}
+
+// UpdateHouseholdMember applies a JSON Merge Patch to a member, only their role can change
+func (h *handler) UpdateHouseholdMember(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	memberID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil || memberID <= 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorBadMemberID)
+		return
+	}
+
+	current, err := h.repo(ctx).HouseholdMemberGet(id, uint(memberID))
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	var mRequest HouseholdMember
+	if err := bindPatch(ctx, HouseholdMemberModelToResponse(current), &mRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	if !permissions.IsValidRole(mRequest.Role) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidRole)
+		return
+	}
+
+	if mRequest.Role != permissions.RoleOwner {
+		isLastOwner, err := h.isLastOwner(ctx, id, uint(memberID))
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		if isLastOwner {
+			ctx.JSON(http.StatusConflict, ErrorLastOwner)
+			return
+		}
+	}
+
+	if _, err := h.repo(ctx).HouseholdMemberUpdate(id, uint(memberID), mRequest.Role); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// RemoveHouseholdMember removes a member from the household. Owners can remove anybody, every member can leave.
+func (h *handler) RemoveHouseholdMember(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	memberID, err := strconv.Atoi(ctx.Param("user_id"))
+	if err != nil || memberID <= 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorBadMemberID)
+		return
+	}
+
+	if uint(memberID) != userID && households_middleware.GetRoleFromContext(ctx) != permissions.RoleOwner {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	isLastOwner, err := h.isLastOwner(ctx, id, uint(memberID))
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if isLastOwner {
+		ctx.JSON(http.StatusConflict, ErrorLastOwner)
+		return
+	}
+
+	if err := h.repo(ctx).HouseholdMemberDelete(id, uint(memberID)); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (h *handler) ListHouseholdInvitations(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	invitations, err := h.repo(ctx).HouseholdInvitationList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	iResponse := make([]*HouseholdInvitation, 0, len(invitations))
+	for _, i := range invitations {
+		iResponse = append(iResponse, HouseholdInvitationModelToResponse(i))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(iResponse))
+}
+
+// CreateHouseholdInvitation invites a user by email. The invitation shows up for whoever
+// signs up or is registered with that email, see ListInvitations.
+func (h *handler) CreateHouseholdInvitation(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	var iRequest HouseholdInvitation
+	if err := ctx.Bind(&iRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if errMessage := iRequest.validate(); errMessage != nil {
+		ctx.JSON(http.StatusBadRequest, errMessage)
+		return
+	}
+
+	invitee, err := h.repo(ctx).UserGetWithEmail(iRequest.Email)
+	if err != nil && err != repository.ErrorRecordNotFound {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if invitee != nil {
+		_, err := h.repo(ctx).HouseholdMemberGet(id, invitee.ID)
+		if err == nil {
+			ctx.JSON(http.StatusConflict, ErrorAlreadyMember)
+			return
+		}
+		if err != repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	invitations, err := h.repo(ctx).HouseholdInvitationList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	for _, i := range invitations {
+		if i.Email == iRequest.Email && i.Status == repository.InvitationPending {
+			ctx.JSON(http.StatusConflict, ErrorInvitationPending)
+			return
+		}
+	}
+
+	iModel := &model.HouseholdInvitation{
+		HouseholdID: id,
+		Email:       iRequest.Email,
+		Role:        iRequest.Role,
+		InvitedByID: userID,
+	}
+
+	if err := h.repo(ctx).HouseholdInvitationCreate(iModel); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, HouseholdInvitationModelToResponse(iModel))
+}
+
+// ListInvitations lists the pending invitations sent to the current user's email
+func (h *handler) ListInvitations(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	user, err := h.repo(ctx).UserGet(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	invitations, err := h.repo(ctx).HouseholdInvitationListByEmail(user.Email)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	iResponse := make([]*HouseholdInvitation, 0, len(invitations))
+	for _, i := range invitations {
+		iResponse = append(iResponse, HouseholdInvitationModelToResponse(i))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(iResponse))
+}
+
+func (h *handler) AcceptInvitation(ctx *gin.Context) {
+	userID, invitation, ok := h.pendingInvitation(ctx)
+	if !ok {
+		return
+	}
+
+	if _, err := h.repo(ctx).HouseholdMemberGet(invitation.HouseholdID, userID); err == nil {
+		ctx.JSON(http.StatusConflict, ErrorAlreadyMember)
+		return
+	} else if err != repository.ErrorRecordNotFound {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	member, err := h.repo(ctx).HouseholdInvitationAccept(invitation.ID, userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	household, err := h.repo(ctx).HouseholdGet(invitation.HouseholdID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, HouseholdModelToResponse(household, member.Role))
+}
+
+func (h *handler) DeclineInvitation(ctx *gin.Context) {
+	_, invitation, ok := h.pendingInvitation(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.repo(ctx).HouseholdInvitationDecline(invitation.ID); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// pendingInvitation loads the invitation of the request, making sure it was sent to the
+// current user and hasn't been answered yet. If not, the response is already written.
+func (h *handler) pendingInvitation(ctx *gin.Context) (uint, *model.HouseholdInvitation, bool) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return 0, nil, false
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	invitation, err := h.repo(ctx).HouseholdInvitationGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return 0, nil, false
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return 0, nil, false
+	}
+
+	user, err := h.repo(ctx).UserGet(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return 0, nil, false
+	}
+
+	if invitation.Email != user.Email {
+		ctx.Status(http.StatusNotFound)
+		return 0, nil, false
+	}
+
+	if invitation.Status != repository.InvitationPending {
+		ctx.JSON(http.StatusConflict, ErrorInvitationNotPending)
+		return 0, nil, false
+	}
+
+	return userID, invitation, true
+}
+
+// isLastOwner checks if the user is the only owner of the household
+func (h *handler) isLastOwner(ctx *gin.Context, householdID, userID uint) (bool, error) {
+	members, err := h.repo(ctx).HouseholdMemberList(householdID)
+	if err != nil {
+		return false, err
+	}
+
+	owners := 0
+	isOwner := false
+	for _, m := range members {
+		if m.Role == permissions.RoleOwner {
+			owners++
+			isOwner = isOwner || m.UserID == userID
+		}
+	}
+
+	return isOwner && owners == 1, nil
+}
+
+// checkHousehold checks that the user may add resources to the household. A household ID of 0
+// means the resource stays personal.
+func (h *handler) checkHousehold(ctx *gin.Context, userID, householdID uint) (*uint, bool, error) {
+	if householdID == 0 {
+		return nil, true, nil
+	}
+
+	allowed, err := permissions.Check(h.repo(ctx), userID, 0, &householdID, permissions.RoleEditor)
+	if err != nil || !allowed {
+		return nil, false, err
+	}
+	return &householdID, true, nil
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/utils"
+	"time"
+)
+
+// Household is a workspace whose wallets and parties are shared between its members. Role is the current user's role.
+type Household struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+}
+
+// HouseholdMember is a user with access to a household
+type HouseholdMember struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Role      string    `json:"role"`
+	JoinedAt  time.Time `json:"joined_at"`
+}
+
+// HouseholdInvitation invites whoever registered with the email to join a household with the role
+type HouseholdInvitation struct {
+	ID            uint      `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	HouseholdID   uint      `json:"household_id"`
+	HouseholdName string    `json:"household_name"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	InvitedByID   uint      `json:"invited_by_id"`
+	Status        string    `json:"status"`
+}
+
+func (i *HouseholdInvitation) validate() *ErrorMessage {
+	if !utils.IsEmailValid(i.Email) {
+		return ErrorEmail
+	}
+
+	if !permissions.IsValidRole(i.Role) {
+		return ErrorInvalidRole
+	}
+
+	return nil
+}
+
+func HouseholdModelToResponse(h *model.Household, role string) *Household {
+	return &Household{
+		ID:        h.ID,
+		CreatedAt: h.CreatedAt,
+		UpdatedAt: h.UpdatedAt,
+		Name:      h.Name,
+		Role:      role,
+	}
+}
+
+func HouseholdMemberModelToResponse(m *model.HouseholdMember) *HouseholdMember {
+	return &HouseholdMember{
+		UserID:    m.UserID,
+		Email:     m.User.Email,
+		FirstName: m.User.FirstName,
+		LastName:  m.User.LastName,
+		Role:      m.Role,
+		JoinedAt:  m.CreatedAt,
+	}
+}
+
+func HouseholdInvitationModelToResponse(i *model.HouseholdInvitation) *HouseholdInvitation {
+	return &HouseholdInvitation{
+		ID:            i.ID,
+		CreatedAt:     i.CreatedAt,
+		HouseholdID:   i.HouseholdID,
+		HouseholdName: i.Household.Name,
+		Email:         i.Email,
+		Role:          i.Role,
+		InvitedByID:   i.InvitedByID,
+		Status:        i.Status,
+	}
+}
+
+// householdIDToResponse returns 0 for resources that don't belong to a household
+func householdIDToResponse(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
@@ -3,8 +3,11 @@ package handlers
 import (
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	parties_middleware "expense-api/internal/middleware/parties"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +19,8 @@ type PartiesHandler interface {
 	UpdateParty(ctx *gin.Context)
 	DeleteParty(ctx *gin.Context)
 	ListTransactionsByParty(ctx *gin.Context)
+	MergeParties(ctx *gin.Context)
+	DeletePartyAlias(ctx *gin.Context)
 }
 
 func (h *handler) CreateParty(ctx *gin.Context) {
@@ -31,9 +36,37 @@ func (h *handler) CreateParty(ctx *gin.Context) {
 		return
 	}
 
+	if wRequest.Name == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorPartyName)
+		return
+	}
+
+	// A name that was merged into another party stands for that party
+	canonical, err := h.repo(ctx).PartyGetByAlias(userID, wRequest.Name)
+	if err == nil {
+		ctx.JSON(http.StatusOK, PartyModelToResponse(canonical))
+		return
+	}
+	if err != repository.ErrorRecordNotFound {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
 	wModel := PartyRequestToModel(&wRequest, userID)
 
-	if err := h.repo.PartyCreate(wModel); err != nil {
+	householdID, allowed, err := h.checkHousehold(ctx, userID, wRequest.HouseholdID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, ErrorBadHouseholdID)
+		return
+	}
+	wModel.HouseholdID = householdID
+
+	if err := h.repo(ctx).PartyCreate(wModel); err != nil {
 		if err == repository.ErrorUniqueConstaintViolation {
 			ctx.JSON(http.StatusConflict, ErrorPartyNameTaken)
 			return
@@ -49,7 +82,7 @@ func (h *handler) CreateParty(ctx *gin.Context) {
 func (h *handler) GetParty(ctx *gin.Context) {
 	id := middleware.GetIDParamFromContext(ctx)
 
-	wModel, err := h.repo.PartyGet(id)
+	wModel, err := h.repo(ctx).PartyGet(id)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
@@ -59,10 +92,15 @@ func (h *handler) GetParty(ctx *gin.Context) {
 		return
 	}
 
+	if notModified(ctx, ETag(wModel.Version)) {
+		return
+	}
+
 	wResponse := PartyModelToResponse(wModel)
 	ctx.JSON(http.StatusOK, wResponse)
 }
 
+// UpdateParty applies a JSON Merge Patch to the party
 func (h *handler) UpdateParty(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -71,15 +109,42 @@ func (h *handler) UpdateParty(ctx *gin.Context) {
 	}
 
 	id := middleware.GetIDParamFromContext(ctx)
+	current := parties_middleware.GetPartyFromContext(ctx)
+
+	if !checkIfMatch(ctx, current.Version) {
+		return
+	}
 
 	var wRequest Party
-	if err := ctx.Bind(&wRequest); err != nil {
-		ctx.Status(http.StatusBadRequest)
+	if err := bindPatch(ctx, PartyModelToResponse(current), &wRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	if wRequest.Name == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorPartyName)
 		return
 	}
 
 	wModel := PartyRequestToModel(&wRequest, userID)
-	updatedWModel, err := h.repo.PartyUpdate(id, wModel)
+
+	wModel.HouseholdID = current.HouseholdID
+	if wRequest.HouseholdID != householdIDToResponse(current.HouseholdID) {
+		householdID, allowed, err := h.checkHousehold(ctx, userID, wRequest.HouseholdID)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, ErrorBadHouseholdID)
+			return
+		}
+		wModel.HouseholdID = householdID
+	}
+
+	wModel.Version = current.Version
+	updatedWModel, err := h.repo(ctx).PartyUpdate(id, wModel)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
@@ -89,22 +154,77 @@ func (h *handler) UpdateParty(ctx *gin.Context) {
 			ctx.JSON(http.StatusConflict, ErrorPartyNameTaken)
 			return
 		}
+		if err == repository.ErrorPartyHousehold {
+			ctx.JSON(http.StatusConflict, ErrorPartyInUse)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
 
+	ctx.Header("ETag", ETag(updatedWModel.Version))
 	wResponse := PartyModelToResponse(updatedWModel)
 	ctx.JSON(http.StatusOK, wResponse)
 }
 
+// DeleteParty moves the party and its transactions to the trash, unless any of them is reconciled
 func (h *handler) DeleteParty(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
 	id := middleware.GetIDParamFromContext(ctx)
 
-	if err := h.repo.PartyDelete(id); err != nil {
+	if !checkIfMatch(ctx, parties_middleware.GetPartyFromContext(ctx).Version) {
+		return
+	}
+
+	cascade, _ := strconv.ParseBool(ctx.Query("cascade"))
+
+	if value := ctx.Query("reassign_to"); value != "" {
+		if cascade {
+			ctx.JSON(http.StatusBadRequest, ErrorCascadeAndReassign)
+			return
+		}
+
+		var targetID uint64
+		targetID, err = strconv.ParseUint(value, 10, 32)
+		if err != nil || targetID == 0 || uint(targetID) == id {
+			ctx.JSON(http.StatusBadRequest, ErrorPartyReassignID)
+			return
+		}
+
+		if !h.checkPartyReassignTarget(ctx, userID, uint(targetID)) {
+			return
+		}
+		err = h.repo(ctx).PartyReassign(id, uint(targetID))
+	} else {
+		err = h.repo(ctx).PartyDelete(id, cascade)
+	}
+
+	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
 			return
 		}
+		if err == repository.ErrorPartyNotEmpty {
+			ctx.JSON(http.StatusConflict, ErrorPartyTransactions)
+			return
+		}
+		if err == repository.ErrorHasReconciled {
+			ctx.JSON(http.StatusConflict, ErrorPartyReconciled)
+			return
+		}
+		if err == repository.ErrorPartyHousehold {
+			ctx.JSON(http.StatusConflict, ErrorReassignHousehold)
+			return
+		}
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
@@ -112,6 +232,31 @@ func (h *handler) DeleteParty(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// checkPartyReassignTarget makes sure the transactions of a party can be moved to the target party
+func (h *handler) checkPartyReassignTarget(ctx *gin.Context, userID uint, targetID uint) bool {
+	target, err := h.repo(ctx).PartyGet(targetID)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.JSON(http.StatusBadRequest, ErrorPartyNotFound)
+			return false
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return false
+	}
+
+	allowed, err := permissions.Check(h.repo(ctx), userID, target.UserID, target.HouseholdID, permissions.RoleEditor)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return false
+	}
+
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, ErrorBadPartyID)
+		return false
+	}
+	return true
+}
+
 func (h *handler) ListParties(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -119,7 +264,7 @@ func (h *handler) ListParties(ctx *gin.Context) {
 		return
 	}
 
-	wModels, err := h.repo.PartyList(userID)
+	wModels, err := h.repo(ctx).PartyList(userID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		return
@@ -144,7 +289,7 @@ func (h *handler) ListTransactionsByParty(ctx *gin.Context) {
 
 	id := middleware.GetIDParamFromContext(ctx)
 
-	tModels, err := h.repo.TransactionListByParty(userID, id)
+	tModels, err := h.repo(ctx).TransactionListByParty(userID, id)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		return
@@ -159,3 +304,104 @@ func (h *handler) ListTransactionsByParty(ctx *gin.Context) {
 	res := NewListResponse(tResponse)
 	ctx.JSON(http.StatusOK, res)
 }
+
+// MergeParties merges the source parties into the party from the URL. Their transactions move to it
+// and their names become its aliases.
+func (h *handler) MergeParties(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	if !checkIfMatch(ctx, parties_middleware.GetPartyFromContext(ctx).Version) {
+		return
+	}
+
+	var mRequest PartyMerge
+	if err := ctx.Bind(&mRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if len(mRequest.SourceIDs) == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredSourceIDs)
+		return
+	}
+
+	sourceIDs := make([]uint, 0, len(mRequest.SourceIDs))
+	seen := make(map[uint]bool, len(mRequest.SourceIDs))
+	for _, sourceID := range mRequest.SourceIDs {
+		if sourceID == id {
+			ctx.JSON(http.StatusBadRequest, ErrorMergeIntoItself)
+			return
+		}
+
+		if seen[sourceID] {
+			continue
+		}
+		seen[sourceID] = true
+		sourceIDs = append(sourceIDs, sourceID)
+
+		source, err := h.repo(ctx).PartyGet(sourceID)
+		if err != nil {
+			if err == repository.ErrorRecordNotFound {
+				ctx.JSON(http.StatusBadRequest, ErrorPartyNotFound)
+				return
+			}
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		allowed, err := permissions.Check(h.repo(ctx), userID, source.UserID, source.HouseholdID, permissions.RoleEditor)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, ErrorBadPartyID)
+			return
+		}
+	}
+
+	pModel, err := h.repo(ctx).PartyMerge(id, sourceIDs)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrorPartyHousehold {
+			ctx.JSON(http.StatusConflict, ErrorMergeHousehold)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, PartyModelToResponse(pModel))
+}
+
+// DeletePartyAlias stops a name from standing for the party from the URL
+func (h *handler) DeletePartyAlias(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	aliasID, err := strconv.Atoi(ctx.Param("alias_id"))
+	if err != nil || aliasID <= 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorBadAliasID)
+		return
+	}
+
+	if err := h.repo(ctx).PartyAliasDelete(id, uint(aliasID)); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
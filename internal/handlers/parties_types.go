@@ -7,18 +7,39 @@ import (
 
 // Party is a list of transactions belonging to an account
 type Party struct {
-	ID        uint      `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Name      string    `json:"name"`
+	ID          uint      `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name"`
+	HouseholdID uint      `json:"household_id"`
+	// Aliases are read-only, they are created by merging parties
+	Aliases []*PartyAlias `json:"aliases,omitempty"`
+}
+
+// PartyAlias is the name of a party that was merged into another one
+type PartyAlias struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// PartyMerge lists the parties to merge into another one
+type PartyMerge struct {
+	SourceIDs []uint `json:"source_ids"`
 }
 
 func PartyModelToResponse(p *model.Party) *Party {
+	var aliases []*PartyAlias
+	for _, a := range p.Aliases {
+		aliases = append(aliases, &PartyAlias{ID: a.ID, Name: a.Name})
+	}
+
 	return &Party{
-		ID:        p.ID,
-		CreatedAt: p.CreatedAt,
-		UpdatedAt: p.UpdatedAt,
-		Name:      p.Name,
+		ID:          p.ID,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+		Name:        p.Name,
+		HouseholdID: householdIDToResponse(p.HouseholdID),
+		Aliases:     aliases,
 	}
 }
 
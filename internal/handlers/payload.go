@@ -1,7 +1,13 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"expense-api/internal/mergepatch"
 	"reflect"
+
+	"github.com/gin-gonic/gin"
 )
 
 // ListResponse is a 'generic' json response whenever the response is a list of something
@@ -25,3 +31,32 @@ func NewListResponse(slice interface{}) *ListResponse {
 		Entries: entries,
 	}
 }
+
+// bindPatch applies the JSON Merge Patch (RFC 7396) in the request body to the current
+// representation of a resource and binds the result to patched. Members missing from the patch keep
+// their current value and members that are null are cleared.
+func bindPatch(ctx *gin.Context, current, patched interface{}) error {
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		return err
+	}
+	return applyPatch(patch, current, patched)
+}
+
+// applyPatch is bindPatch for a patch that isn't the request body
+func applyPatch(patch []byte, current, patched interface{}) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(patch), []byte("{")) {
+		return errors.New("patch is not a JSON object")
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	merged, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(merged, patched)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag is the entity tag of a version of a resource
+func ETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// DerivedETag is the entity tag of a version of a resource whose representation has figures derived
+// from other resources as well, like the available credit of a wallet. The figures are hashed into
+// the tag after the version, so that the tag changes with them. If-Match only compares the version.
+func DerivedETag(version uint, derived string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(derived))
+	return fmt.Sprintf(`"%d-%08x"`, version, hash.Sum32())
+}
+
+// notModified sets the ETag of the resource and responds with 304 when the client already has this
+// representation, according to If-None-Match
+func notModified(ctx *gin.Context, etag string) bool {
+	ctx.Header("ETag", etag)
+
+	if header := ctx.GetHeader("If-None-Match"); header != "" && matchETag(header, etag, true) {
+		ctx.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// checkIfMatch tells whether the request may change the resource. If the request has an If-Match
+// header which doesn't name the current version of the resource, it responds with 412.
+func checkIfMatch(ctx *gin.Context, version uint) bool {
+	header := ctx.GetHeader("If-Match")
+	if header == "" || matchETag(header, ETag(version), false) {
+		return true
+	}
+
+	ctx.JSON(http.StatusPreconditionFailed, ErrorPreconditionFailed)
+	return false
+}
+
+// matchETag looks for the etag in the list of entity tags of an If-Match or If-None-Match header.
+// Weak comparison, which If-None-Match uses, ignores the W/ prefix. Strong comparison, which If-Match
+// uses, ignores the derived figures of a DerivedETag.
+func matchETag(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		} else if i := strings.Index(tag, "-"); strings.HasPrefix(tag, `"`) && i > 0 {
+			tag = tag[:i] + `"`
+		}
+
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationsHandler interface {
+	ReconcileWallet(ctx *gin.Context)
+	ListReconciliations(ctx *gin.Context)
+}
+
+// ReconcileWallet compares a bank statement with the cleared transactions of the wallet up to the
+// statement date. When they match, those transactions become reconciled and can no longer be changed.
+func (h *handler) ReconcileWallet(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	var rRequest ReconciliationRequest
+	if err := ctx.Bind(&rRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if rRequest.StatementDate.IsZero() {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredStatementDate)
+		return
+	}
+
+	if rRequest.StatementBalance == nil {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredStatementBalance)
+		return
+	}
+
+	wallet, err := h.repo(ctx).WalletGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if err := currency.ValidateAmount(WalletCurrency(wallet), *rRequest.StatementBalance); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+		return
+	}
+
+	cleared, err := h.repo(ctx).WalletClearedBalance(id, rRequest.StatementDate)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	result := &ReconciliationResult{
+		StatementDate:    rRequest.StatementDate,
+		StatementBalance: *rRequest.StatementBalance,
+		ClearedBalance:   cleared,
+		Difference:       rRequest.StatementBalance.Sub(cleared),
+	}
+
+	if !result.Difference.IsZero() {
+		ctx.JSON(http.StatusOK, result)
+		return
+	}
+
+	rModel := &model.Reconciliation{
+		WalletID:         id,
+		UserID:           userID,
+		StatementDate:    rRequest.StatementDate,
+		StatementBalance: *rRequest.StatementBalance,
+	}
+
+	if err := h.repo(ctx).ReconciliationCreate(rModel); err != nil {
+		if err == repository.ErrorStatementOutOfBalance {
+			ctx.JSON(http.StatusConflict, ErrorStatementOutOfBalance)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	result.Reconciled = true
+	result.Reconciliation = ReconciliationModelToResponse(rModel)
+	ctx.JSON(http.StatusCreated, result)
+}
+
+func (h *handler) ListReconciliations(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	rModels, err := h.repo(ctx).ReconciliationList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	rResponse := make([]*Reconciliation, 0, len(rModels))
+
+	for _, r := range rModels {
+		rResponse = append(rResponse, ReconciliationModelToResponse(r))
+	}
+
+	res := NewListResponse(rResponse)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// allowChange makes sure a transaction isn't reconciled before it is changed
+func allowChange(ctx *gin.Context, t *model.Transaction) bool {
+	if t.Status == model.TransactionReconciled {
+		ctx.JSON(http.StatusConflict, ErrorTransactionReconciled)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Reconciliation is a bank statement the cleared transactions of a wallet were reconciled against
+type Reconciliation struct {
+	ID               uint            `json:"id"`
+	CreatedAt        time.Time       `json:"created_at"`
+	WalletID         uint            `json:"wallet_id"`
+	UserID           uint            `json:"user_id"`
+	StatementDate    time.Time       `json:"statement_date"`
+	StatementBalance decimal.Decimal `json:"statement_balance"`
+	TransactionCount int             `json:"transaction_count"`
+}
+
+// ReconciliationRequest is the ending balance of a bank statement. The balance is a pointer because
+// a statement balance of 0 is perfectly valid.
+type ReconciliationRequest struct {
+	StatementDate    time.Time        `json:"statement_date"`
+	StatementBalance *decimal.Decimal `json:"statement_balance"`
+}
+
+// ReconciliationResult compares the statement with the cleared transactions of the wallet. Only when
+// the difference is 0 the wallet is reconciled.
+type ReconciliationResult struct {
+	StatementDate    time.Time       `json:"statement_date"`
+	StatementBalance decimal.Decimal `json:"statement_balance"`
+	ClearedBalance   decimal.Decimal `json:"cleared_balance"`
+	Difference       decimal.Decimal `json:"difference"`
+	Reconciled       bool            `json:"reconciled"`
+	Reconciliation   *Reconciliation `json:"reconciliation,omitempty"`
+}
+
+func ReconciliationModelToResponse(r *model.Reconciliation) *Reconciliation {
+	return &Reconciliation{
+		ID:               r.ID,
+		CreatedAt:        r.CreatedAt,
+		WalletID:         r.WalletID,
+		UserID:           r.UserID,
+		StatementDate:    r.StatementDate,
+		StatementBalance: r.StatementBalance,
+		TransactionCount: r.TransactionCount,
+	}
+}
@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"errors"
+	"expense-api/internal/currency"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type ReportsHandler interface {
+	GetBalanceReport(ctx *gin.Context)
+	GetPartyReport(ctx *gin.Context)
+	GetCategoryReport(ctx *gin.Context)
+}
+
+// reportData holds everything needed to convert a user's transactions to their base currency
+type reportData struct {
+	baseCurrency string
+	wallets      map[uint]*model.Wallet
+	transactions []*model.Transaction
+	splits       map[uint][]*model.TransactionSplit
+	rates        *currency.Rates
+}
+
+// reportLine is the part of a transaction that is attributed to a single party and category
+type reportLine struct {
+	partyID  uint
+	category string
+	amount   decimal.Decimal
+}
+
+func (h *handler) loadReportData(ctx *gin.Context, userID uint, from, to time.Time) (*reportData, error) {
+	user, err := h.repo(ctx).UserGet(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCurrency := user.BaseCurrency
+	if baseCurrency == "" {
+		baseCurrency = currency.Default
+	}
+
+	wModels, err := h.repo(ctx).WalletList(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets := make(map[uint]*model.Wallet, len(wModels))
+	currencies := map[string]bool{baseCurrency: true, currency.Pivot: true}
+	for _, w := range wModels {
+		wallets[w.ID] = w
+		currencies[WalletCurrency(w)] = true
+	}
+
+	tModels, err := h.repo(ctx).TransactionList(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*model.Transaction, 0, len(tModels))
+	for _, t := range tModels {
+		if (from.IsZero() || !t.Timestamp.Before(from)) && (to.IsZero() || t.Timestamp.Before(to)) {
+			transactions = append(transactions, t)
+		}
+	}
+
+	codes := make([]string, 0, len(currencies))
+	for code := range currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	rModels, err := h.repo(ctx).ExchangeRateListByCurrencies(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	sModels, err := h.repo(ctx).TransactionSplitListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	splits := map[uint][]*model.TransactionSplit{}
+	for _, s := range sModels {
+		splits[s.TransactionID] = append(splits[s.TransactionID], s)
+	}
+
+	return &reportData{
+		baseCurrency: baseCurrency,
+		wallets:      wallets,
+		transactions: transactions,
+		splits:       splits,
+		rates:        currency.NewRates(rModels),
+	}, nil
+}
+
+// lines returns the splits of a split transaction, or the transaction itself as a single line,
+// with every amount converted to the base currency
+func (d *reportData) lines(t *model.Transaction) ([]reportLine, error) {
+	splits, ok := d.splits[t.ID]
+	if !ok {
+		converted, err := d.convert(t, t.Amount)
+		if err != nil {
+			return nil, err
+		}
+		return []reportLine{{partyID: t.PartyID, category: t.Category, amount: converted}}, nil
+	}
+
+	lines := make([]reportLine, 0, len(splits))
+	for _, s := range splits {
+		converted, err := d.convert(t, s.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		partyID := t.PartyID
+		if s.PartyID != nil {
+			partyID = *s.PartyID
+		}
+
+		lines = append(lines, reportLine{partyID: partyID, category: s.Category, amount: converted})
+	}
+	return lines, nil
+}
+
+// convert converts an amount of the transaction to the base currency using the rate valid at the transaction's timestamp
+func (d *reportData) convert(t *model.Transaction, amount decimal.Decimal) (decimal.Decimal, error) {
+	walletCurrency := currency.Default
+	if w, ok := d.wallets[t.WalletID]; ok {
+		walletCurrency = WalletCurrency(w)
+	}
+	return d.rates.Convert(amount, walletCurrency, d.baseCurrency, t.Timestamp)
+}
+
+// parsePeriod reads the optional 'from' and 'to' query parameters of reports and searches; both dates are inclusive
+func parsePeriod(ctx *gin.Context) (from, to time.Time, errMsg *ErrorMessage) {
+	if value := ctx.Query("from"); value != "" {
+		date, err := time.Parse(currency.DateLayout, value)
+		if err != nil {
+			return from, to, ErrorInvalidDate
+		}
+		from = date
+	}
+
+	if value := ctx.Query("to"); value != "" {
+		date, err := time.Parse(currency.DateLayout, value)
+		if err != nil {
+			return from, to, ErrorInvalidDate
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+func (h *handler) respondWithReportError(ctx *gin.Context, err error) {
+	if errors.Is(err, currency.ErrorRateNotFound) {
+		ctx.JSON(http.StatusUnprocessableEntity, &ErrorMessage{Message: err.Error()})
+		return
+	}
+	ctx.Status(http.StatusInternalServerError)
+}
+
+func (h *handler) GetBalanceReport(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	from, to, errMsg := parsePeriod(ctx)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	data, err := h.loadReportData(ctx, userID, from, to)
+	if err != nil {
+		h.respondWithReportError(ctx, err)
+		return
+	}
+
+	balances := map[uint]*WalletBalance{}
+	report := &BalanceReport{
+		BaseCurrency: data.baseCurrency,
+		Total:        decimal.Zero,
+		Wallets:      make([]*WalletBalance, 0, len(data.wallets)),
+	}
+
+	for id, w := range data.wallets {
+		balances[id] = &WalletBalance{
+			WalletID:         id,
+			Name:             w.Name,
+			Currency:         WalletCurrency(w),
+			Balance:          decimal.Zero,
+			ConvertedBalance: decimal.Zero,
+		}
+		report.Wallets = append(report.Wallets, balances[id])
+
+		// The opening balance counts like a transaction on the day the wallet was opened
+		opened := w.CreatedAt
+		if w.OpeningDate != nil {
+			opened = *w.OpeningDate
+		}
+
+		if w.OpeningBalance.IsZero() || (!from.IsZero() && opened.Before(from)) || (!to.IsZero() && !opened.Before(to)) {
+			continue
+		}
+
+		converted, err := data.rates.Convert(w.OpeningBalance, WalletCurrency(w), data.baseCurrency, opened)
+		if err != nil {
+			h.respondWithReportError(ctx, err)
+			return
+		}
+
+		balances[id].Balance = w.OpeningBalance
+		balances[id].ConvertedBalance = converted
+		report.Total = report.Total.Add(converted)
+	}
+	sort.Slice(report.Wallets, func(i, j int) bool { return report.Wallets[i].WalletID < report.Wallets[j].WalletID })
+
+	for _, t := range data.transactions {
+		converted, err := data.convert(t, t.Amount)
+		if err != nil {
+			h.respondWithReportError(ctx, err)
+			return
+		}
+
+		if balance, ok := balances[t.WalletID]; ok {
+			balance.Balance = balance.Balance.Add(t.Amount)
+			balance.ConvertedBalance = balance.ConvertedBalance.Add(converted)
+		}
+		report.Total = report.Total.Add(converted)
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (h *handler) GetPartyReport(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	from, to, errMsg := parsePeriod(ctx)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	data, err := h.loadReportData(ctx, userID, from, to)
+	if err != nil {
+		h.respondWithReportError(ctx, err)
+		return
+	}
+
+	pModels, err := h.repo(ctx).PartyList(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	totals := make(map[uint]*PartyTotal, len(pModels))
+	report := &PartyReport{
+		BaseCurrency: data.baseCurrency,
+		Parties:      make([]*PartyTotal, 0, len(pModels)),
+	}
+
+	for _, p := range pModels {
+		totals[p.ID] = &PartyTotal{PartyID: p.ID, Name: p.Name, Total: decimal.Zero}
+		report.Parties = append(report.Parties, totals[p.ID])
+	}
+
+	for _, t := range data.transactions {
+		lines, err := data.lines(t)
+		if err != nil {
+			h.respondWithReportError(ctx, err)
+			return
+		}
+
+		for _, line := range lines {
+			if total, ok := totals[line.partyID]; ok {
+				total.Count++
+				total.Total = total.Total.Add(line.amount)
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+func (h *handler) GetCategoryReport(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	from, to, errMsg := parsePeriod(ctx)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	data, err := h.loadReportData(ctx, userID, from, to)
+	if err != nil {
+		h.respondWithReportError(ctx, err)
+		return
+	}
+
+	totals := map[string]*CategoryTotal{}
+	report := &CategoryReport{
+		BaseCurrency: data.baseCurrency,
+		Categories:   []*CategoryTotal{},
+	}
+
+	for _, t := range data.transactions {
+		lines, err := data.lines(t)
+		if err != nil {
+			h.respondWithReportError(ctx, err)
+			return
+		}
+
+		for _, line := range lines {
+			total, ok := totals[line.category]
+			if !ok {
+				total = &CategoryTotal{Category: line.category, Total: decimal.Zero}
+				totals[line.category] = total
+				report.Categories = append(report.Categories, total)
+			}
+
+			total.Count++
+			total.Total = total.Total.Add(line.amount)
+		}
+	}
+
+	sort.Slice(report.Categories, func(i, j int) bool { return report.Categories[i].Category < report.Categories[j].Category })
+
+	ctx.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// WalletBalance is the sum of a wallet's transactions, in the wallet's and in the user's base currency
+type WalletBalance struct {
+	WalletID         uint            `json:"wallet_id"`
+	Name             string          `json:"name"`
+	Currency         string          `json:"currency"`
+	Balance          decimal.Decimal `json:"balance"`
+	ConvertedBalance decimal.Decimal `json:"converted_balance"`
+}
+
+// BalanceReport lists the balances of all of the user's wallets and their total in the base currency
+type BalanceReport struct {
+	BaseCurrency string           `json:"base_currency"`
+	Total        decimal.Decimal  `json:"total"`
+	Wallets      []*WalletBalance `json:"wallets"`
+}
+
+// PartyTotal is the sum of all transactions (or splits) with a party, in the user's base currency
+type PartyTotal struct {
+	PartyID uint            `json:"party_id"`
+	Name    string          `json:"name"`
+	Count   int             `json:"count"`
+	Total   decimal.Decimal `json:"total"`
+}
+
+// PartyReport lists the totals per party in the base currency
+type PartyReport struct {
+	BaseCurrency string        `json:"base_currency"`
+	Parties      []*PartyTotal `json:"parties"`
+}
+
+// CategoryTotal is the sum of all transactions (or splits) in a category, in the user's base currency.
+// Uncategorized transactions are grouped under an empty category.
+type CategoryTotal struct {
+	Category string          `json:"category"`
+	Count    int             `json:"count"`
+	Total    decimal.Decimal `json:"total"`
+}
+
+// CategoryReport lists the totals per category in the base currency
+type CategoryReport struct {
+	BaseCurrency string           `json:"base_currency"`
+	Categories   []*CategoryTotal `json:"categories"`
+}
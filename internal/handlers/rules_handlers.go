@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	rules_middleware "expense-api/internal/middleware/rules"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"expense-api/internal/rules"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RulesHandler interface {
+	ListRules(ctx *gin.Context)
+	CreateRule(ctx *gin.Context)
+	GetRule(ctx *gin.Context)
+	UpdateRule(ctx *gin.Context)
+	DeleteRule(ctx *gin.Context)
+	ApplyRules(ctx *gin.Context)
+}
+
+func (h *handler) ListRules(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	rModels, err := h.repo(ctx).RuleList(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	rResponse := make([]*Rule, 0, len(rModels))
+
+	for _, r := range rModels {
+		rResponse = append(rResponse, RuleModelToResponse(r))
+	}
+
+	res := NewListResponse(rResponse)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func (h *handler) CreateRule(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var rRequest RuleRequest
+	if err := ctx.Bind(&rRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	rModel := RuleRequestToModel(&rRequest, &model.Rule{UserID: userID, Enabled: true})
+
+	if !h.validateRule(ctx, userID, rModel) {
+		return
+	}
+
+	if err := h.repo(ctx).RuleCreate(rModel); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, RuleModelToResponse(rModel))
+}
+
+func (h *handler) GetRule(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	rModel, err := h.repo(ctx).RuleGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RuleModelToResponse(rModel))
+}
+
+// UpdateRule applies a JSON Merge Patch to the rule. Conditions and actions are patched one by one.
+func (h *handler) UpdateRule(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+	current := rules_middleware.GetRuleFromContext(ctx)
+
+	var rRequest RuleRequest
+	if err := bindPatch(ctx, RuleModelToResponse(current), &rRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	// Members cleared by the patch are missing from the request and fall back to their zero value
+	rModel := RuleRequestToModel(&rRequest, &model.Rule{UserID: current.UserID})
+
+	if !h.validateRule(ctx, userID, rModel) {
+		return
+	}
+
+	rModel.Version = current.Version
+	updatedRModel, err := h.repo(ctx).RuleUpdate(id, rModel)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, RuleModelToResponse(updatedRModel))
+}
+
+func (h *handler) DeleteRule(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	if err := h.repo(ctx).RuleDelete(id); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ApplyRules re-applies the user's rules to all unreconciled transactions they created, overwriting
+// the party, category and description the rules set. With 'dry_run=true' nothing is saved and the
+// response only shows what would change.
+func (h *handler) ApplyRules(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(ctx.Query("dry_run"))
+
+	rModels, err := h.repo(ctx).RuleList(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	rules.Sort(rModels)
+
+	tModels, err := h.repo(ctx).TransactionList(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	result := &ApplyRulesResult{DryRun: dryRun, Changes: []*RuleChange{}}
+	hits := make(map[uint]uint)
+	var changed []*model.Transaction
+
+	for _, before := range tModels {
+		if before.UserID != userID || before.Status == model.TransactionReconciled {
+			continue
+		}
+
+		after := *before
+		after.Tags = append(model.Tags(nil), before.Tags...)
+
+		matched := rules.Apply(rModels, &after, true)
+		if len(matched) == 0 {
+			continue
+		}
+		result.Matched++
+
+		// Rules only add tags, so the number of tags tells whether they changed. Transactions the
+		// rules already were applied to don't count as hits again.
+		if after.PartyID == before.PartyID && after.Category == before.Category &&
+			after.Description == before.Description && len(after.Tags) == len(before.Tags) {
+			continue
+		}
+
+		for _, id := range matched {
+			hits[id]++
+		}
+		changed = append(changed, &after)
+		result.Changes = append(result.Changes, &RuleChange{
+			TransactionID: before.ID,
+			Rules:         matched,
+			Before:        TransactionModelToResponse(before),
+			After:         TransactionModelToResponse(&after),
+		})
+	}
+	result.Changed = len(changed)
+
+	if !dryRun && len(changed) > 0 {
+		if err := h.repo(ctx).TransactionApplyRules(changed, hits); err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// applyRulesOnCreate fills in what the user's rules set on a new transaction, keeping the party and
+// category the user gave explicitly. It returns the IDs of the rules that matched.
+func (h *handler) applyRulesOnCreate(ctx *gin.Context, userID uint, t *model.Transaction) ([]uint, error) {
+	rModels, err := h.repo(ctx).RuleList(userID)
+	if err != nil {
+		return nil, err
+	}
+	rules.Sort(rModels)
+
+	return rules.Apply(rModels, t, false), nil
+}
+
+// recordRuleHits counts a transaction towards the statistics of the rules that matched it. The
+// transaction is already saved, so failures are only recorded on the context.
+func (h *handler) recordRuleHits(ctx *gin.Context, matched []uint) {
+	if len(matched) == 0 {
+		return
+	}
+
+	hits := make(map[uint]uint, len(matched))
+	for _, id := range matched {
+		hits[id]++
+	}
+
+	if err := h.repo(ctx).RuleRecordHits(hits); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// validateRule checks the rule's definition and that the user may use the wallet and party it refers
+// to. It responds with an error and returns false otherwise.
+func (h *handler) validateRule(ctx *gin.Context, userID uint, r *model.Rule) bool {
+	if r.Name == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorRuleName)
+		return false
+	}
+
+	tags, errMsg := normalizeTags(r.AddTags)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return false
+	}
+	r.AddTags = tags
+
+	switch rules.Validate(r) {
+	case nil:
+	case rules.ErrorNoCondition:
+		ctx.JSON(http.StatusBadRequest, ErrorRuleNoCondition)
+		return false
+	case rules.ErrorNoAction:
+		ctx.JSON(http.StatusBadRequest, ErrorRuleNoAction)
+		return false
+	case rules.ErrorInvalidRegex:
+		ctx.JSON(http.StatusBadRequest, ErrorRuleRegex)
+		return false
+	case rules.ErrorAmountRange:
+		ctx.JSON(http.StatusBadRequest, ErrorRuleAmountRange)
+		return false
+	}
+
+	if r.WalletID != nil {
+		wallet, err := h.repo(ctx).WalletGet(*r.WalletID)
+		if err != nil {
+			if err == repository.ErrorRecordNotFound {
+				ctx.JSON(http.StatusBadRequest, ErrorWalletNotFound)
+				return false
+			}
+			ctx.Status(http.StatusInternalServerError)
+			return false
+		}
+
+		allowed, err := permissions.Check(h.repo(ctx), userID, wallet.UserID, wallet.HouseholdID, permissions.RoleViewer)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return false
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, ErrorBadWalletID)
+			return false
+		}
+	}
+
+	if r.SetPartyID != nil {
+		party, err := h.repo(ctx).PartyGet(*r.SetPartyID)
+		if err != nil {
+			if err == repository.ErrorRecordNotFound {
+				ctx.JSON(http.StatusBadRequest, ErrorPartyNotFound)
+				return false
+			}
+			ctx.Status(http.StatusInternalServerError)
+			return false
+		}
+
+		allowed, err := permissions.Check(h.repo(ctx), userID, party.UserID, party.HouseholdID, permissions.RoleViewer)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return false
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, ErrorBadPartyID)
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rule assigns a party, category, tags or a cleaner description to the transactions matching its conditions
+type Rule struct {
+	ID             uint            `json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Name           string          `json:"name"`
+	Priority       int             `json:"priority"`
+	Enabled        bool            `json:"enabled"`
+	StopProcessing bool            `json:"stop_processing"`
+	Conditions     *RuleConditions `json:"conditions"`
+	Actions        *RuleActions    `json:"actions"`
+	HitCount       int64           `json:"hit_count"`
+	LastHitAt      *time.Time      `json:"last_hit_at"`
+}
+
+// RuleConditions must all hold for a rule to match a transaction
+type RuleConditions struct {
+	DescriptionContains string           `json:"description_contains"`
+	DescriptionRegex    string           `json:"description_regex"`
+	AmountMin           *decimal.Decimal `json:"amount_min"`
+	AmountMax           *decimal.Decimal `json:"amount_max"`
+	WalletID            *uint            `json:"wallet_id"`
+}
+
+// RuleActions are applied to the transactions a rule matches
+type RuleActions struct {
+	PartyID     *uint    `json:"party_id"`
+	Category    string   `json:"category"`
+	Tags        []string `json:"tags"`
+	Description string   `json:"description"`
+}
+
+// RuleRequest creates a rule or is the result of patching one
+type RuleRequest struct {
+	Name           string          `json:"name"`
+	Priority       *int            `json:"priority"`
+	Enabled        *bool           `json:"enabled"`
+	StopProcessing *bool           `json:"stop_processing"`
+	Conditions     *RuleConditions `json:"conditions"`
+	Actions        *RuleActions    `json:"actions"`
+}
+
+// RuleChange is how re-applying the rules changes a transaction
+type RuleChange struct {
+	TransactionID uint         `json:"transaction_id"`
+	Rules         []uint       `json:"rules"`
+	Before        *Transaction `json:"before"`
+	After         *Transaction `json:"after"`
+}
+
+// ApplyRulesResult summarises a (dry) run of the rules over all transactions
+type ApplyRulesResult struct {
+	DryRun  bool          `json:"dry_run"`
+	Matched int           `json:"matched"`
+	Changed int           `json:"changed"`
+	Changes []*RuleChange `json:"changes"`
+}
+
+func RuleModelToResponse(r *model.Rule) *Rule {
+	return &Rule{
+		ID:             r.ID,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		Name:           r.Name,
+		Priority:       r.Priority,
+		Enabled:        r.Enabled,
+		StopProcessing: r.StopProcessing,
+		Conditions: &RuleConditions{
+			DescriptionContains: r.DescriptionContains,
+			DescriptionRegex:    r.DescriptionRegex,
+			AmountMin:           r.AmountMin,
+			AmountMax:           r.AmountMax,
+			WalletID:            r.WalletID,
+		},
+		Actions: &RuleActions{
+			PartyID:     r.SetPartyID,
+			Category:    r.SetCategory,
+			Tags:        tagsToResponse(r.AddTags),
+			Description: r.SetDescription,
+		},
+		HitCount:  r.HitCount,
+		LastHitAt: r.LastHitAt,
+	}
+}
+
+// RuleRequestToModel merges the request into the rule, which is a new, enabled rule on create
+// and an empty rule on update
+func RuleRequestToModel(req *RuleRequest, r *model.Rule) *model.Rule {
+	if req.Name != "" {
+		r.Name = req.Name
+	}
+
+	if req.Priority != nil {
+		r.Priority = *req.Priority
+	}
+
+	if req.Enabled != nil {
+		r.Enabled = *req.Enabled
+	}
+
+	if req.StopProcessing != nil {
+		r.StopProcessing = *req.StopProcessing
+	}
+
+	if c := req.Conditions; c != nil {
+		r.DescriptionContains = c.DescriptionContains
+		r.DescriptionRegex = c.DescriptionRegex
+		r.AmountMin = c.AmountMin
+		r.AmountMax = c.AmountMax
+		r.WalletID = c.WalletID
+	}
+
+	if a := req.Actions; a != nil {
+		r.SetPartyID = a.PartyID
+		r.SetCategory = a.Category
+		r.AddTags = a.Tags
+		r.SetDescription = a.Description
+	}
+
+	return r
+}
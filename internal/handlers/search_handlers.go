@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/search"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler interface {
+	Search(ctx *gin.Context)
+}
+
+// Search ranks the user's transactions, parties and wallets against the 'q' query parameter.
+// Results can be narrowed down with 'type' (comma separated), 'from', 'to' and 'limit'.
+func (h *handler) Search(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	tsQuery, ok := search.ToTSQuery(ctx.Query("q"))
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, ErrorSearchQuery)
+		return
+	}
+
+	query := &search.Query{
+		TSQuery: tsQuery,
+		Types:   search.Types,
+		Limit:   defaultSearchLimit,
+	}
+
+	if value := ctx.Query("type"); value != "" {
+		query.Types = nil
+		for _, t := range strings.Split(value, ",") {
+			t = strings.TrimSpace(t)
+			if !search.IsValidType(t) {
+				ctx.JSON(http.StatusBadRequest, ErrorSearchType)
+				return
+			}
+			query.Types = append(query.Types, t)
+		}
+	}
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxSearchLimit {
+			ctx.JSON(http.StatusBadRequest, ErrorSearchLimit)
+			return
+		}
+		query.Limit = limit
+	}
+
+	var errMsg *ErrorMessage
+	query.From, query.To, errMsg = parsePeriod(ctx)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	results, err := h.repo(ctx).Search(userID, query)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	rResponse := make([]*SearchResult, 0, len(results))
+	for _, r := range results {
+		rResponse = append(rResponse, SearchResultToResponse(r))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(rResponse))
+}
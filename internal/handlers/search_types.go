@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"expense-api/internal/search"
+	"time"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchResult is a transaction, party or wallet matching a search.
+// Headline is an HTML-escaped excerpt of the match with the matching words wrapped in <mark> tags.
+type SearchResult struct {
+	Type     string    `json:"type"`
+	ID       uint      `json:"id"`
+	Title    string    `json:"title"`
+	Headline string    `json:"headline"`
+	Rank     float64   `json:"rank"`
+	Date     time.Time `json:"date"`
+}
+
+func SearchResultToResponse(r *search.Result) *SearchResult {
+	return &SearchResult{
+		Type:     r.Type,
+		ID:       r.ID,
+		Title:    r.Title,
+		Headline: r.Headline,
+		Rank:     r.Rank,
+		Date:     r.Date,
+	}
+}
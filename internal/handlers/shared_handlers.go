@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/settleup"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SharedHandler interface {
+	GetSharedBalances(ctx *gin.Context)
+	SimplifySharedDebts(ctx *gin.Context)
+	ListSettlements(ctx *gin.Context)
+	CreateSettlement(ctx *gin.Context)
+	DeleteSettlement(ctx *gin.Context)
+}
+
+// GetSharedBalances lists what every user the current user shares expenses with owes them, per currency
+func (h *handler) GetSharedBalances(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	debts, err := h.loadDebts(ctx, []uint{userID})
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	balances := settleup.Balances(userID, debts)
+
+	ids := make([]uint, 0, len(balances))
+	for _, b := range balances {
+		ids = append(ids, b.UserID)
+	}
+
+	users, err := h.loadUsers(ctx, ids)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	bResponse := make([]*SharedBalance, 0, len(balances))
+	for _, b := range balances {
+		u := users[b.UserID]
+		bResponse = append(bResponse, &SharedBalance{
+			UserID:    b.UserID,
+			Email:     u.Email,
+			FirstName: u.FirstName,
+			LastName:  u.LastName,
+			Currency:  b.Currency,
+			Amount:    b.Amount,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(bResponse))
+}
+
+// SimplifySharedDebts suggests the fewest payments that settle up the current user and everybody
+// they share expenses with, including the debts between those users
+func (h *handler) SimplifySharedDebts(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	debts, err := h.loadDebts(ctx, []uint{userID})
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	group := map[uint]bool{userID: true}
+	for _, d := range debts {
+		group[d.From] = true
+		group[d.To] = true
+	}
+
+	ids := make([]uint, 0, len(group))
+	for id := range group {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	if len(ids) > 1 {
+		debts, err = h.loadDebts(ctx, ids)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	groupDebts := make([]settleup.Debt, 0, len(debts))
+	for _, d := range debts {
+		if group[d.From] && group[d.To] {
+			groupDebts = append(groupDebts, d)
+		}
+	}
+
+	users, err := h.loadUsers(ctx, ids)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	payments := settleup.Simplify(groupDebts)
+
+	pResponse := make([]*Payment, 0, len(payments))
+	for _, p := range payments {
+		pResponse = append(pResponse, &Payment{
+			FromUserID: p.From,
+			FromEmail:  users[p.From].Email,
+			ToUserID:   p.To,
+			ToEmail:    users[p.To].Email,
+			Currency:   p.Currency,
+			Amount:     p.Amount,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(pResponse))
+}
+
+func (h *handler) ListSettlements(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	sModels, err := h.repo(ctx).SettlementListByUsers([]uint{userID})
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	sResponse := make([]*Settlement, 0, len(sModels))
+	for _, s := range sModels {
+		sResponse = append(sResponse, SettlementModelToResponse(s))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(sResponse))
+}
+
+// CreateSettlement records a payment another user made to the current user. Only the user who got paid
+// can record it, so nobody can cancel their debts on their own.
+func (h *handler) CreateSettlement(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var sRequest Settlement
+	if err := ctx.Bind(&sRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	sRequest.Currency = currency.Normalize(sRequest.Currency)
+
+	if sRequest.FromUserID == userID {
+		ctx.JSON(http.StatusBadRequest, ErrorSettleWithSelf)
+		return
+	}
+
+	if !sRequest.Amount.IsPositive() {
+		ctx.JSON(http.StatusBadRequest, ErrorSettlementAmount)
+		return
+	}
+
+	if !currency.IsValid(sRequest.Currency) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidCurrency)
+		return
+	}
+
+	if err := currency.ValidateAmount(sRequest.Currency, sRequest.Amount); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+		return
+	}
+
+	shared, err := h.repo(ctx).HouseholdSharedByUsers(userID, sRequest.FromUserID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if !shared {
+		ctx.JSON(http.StatusBadRequest, ErrorUserNotFound)
+		return
+	}
+
+	sModel := SettlementRequestToModel(&sRequest, userID)
+
+	if err := h.repo(ctx).SettlementCreate(sModel); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, SettlementModelToResponse(sModel))
+}
+
+func (h *handler) DeleteSettlement(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	if err := h.repo(ctx).SettlementDelete(id); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// loadDebts turns the shares and settlements involving any of the users into debts. A share is owed
+// to the owner of the transaction; a settlement counts as a debt in the opposite direction.
+func (h *handler) loadDebts(ctx *gin.Context, userIDs []uint) ([]settleup.Debt, error) {
+	shares, err := h.repo(ctx).TransactionShareListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	settlements, err := h.repo(ctx).SettlementListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	debts := make([]settleup.Debt, 0, len(shares)+len(settlements))
+	for _, s := range shares {
+		payerID := s.Transaction.UserID
+		if s.UserID == payerID {
+			continue
+		}
+		debts = append(debts, settleup.Debt{
+			From:     s.UserID,
+			To:       payerID,
+			Currency: WalletCurrency(&s.Transaction.Wallet),
+			Amount:   s.Amount,
+		})
+	}
+
+	for _, s := range settlements {
+		debts = append(debts, settleup.Debt{
+			From:     s.ToUserID,
+			To:       s.FromUserID,
+			Currency: s.Currency,
+			Amount:   s.Amount,
+		})
+	}
+
+	return debts, nil
+}
+
+func (h *handler) loadUsers(ctx *gin.Context, ids []uint) (map[uint]*model.User, error) {
+	users := map[uint]*model.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	uModels, err := h.repo(ctx).UserListByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range uModels {
+		users[u.ID] = u
+	}
+	for _, id := range ids {
+		if users[id] == nil {
+			users[id] = &model.User{}
+		}
+	}
+	return users, nil
+}
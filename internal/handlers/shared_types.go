@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SharedBalance is what another user owes the current user in one currency;
+// a negative amount means the current user owes them
+type SharedBalance struct {
+	UserID    uint            `json:"user_id"`
+	Email     string          `json:"email"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	Currency  string          `json:"currency"`
+	Amount    decimal.Decimal `json:"amount"`
+}
+
+// Payment is a suggested payment that settles up debts
+type Payment struct {
+	FromUserID uint            `json:"from_user_id"`
+	FromEmail  string          `json:"from_email"`
+	ToUserID   uint            `json:"to_user_id"`
+	ToEmail    string          `json:"to_email"`
+	Currency   string          `json:"currency"`
+	Amount     decimal.Decimal `json:"amount"`
+}
+
+// Settlement is a payment from one user to another that pays back shared expenses
+type Settlement struct {
+	ID         uint            `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+	FromUserID uint            `json:"from_user_id"`
+	ToUserID   uint            `json:"to_user_id"`
+	Amount     decimal.Decimal `json:"amount"`
+	Currency   string          `json:"currency"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Note       string          `json:"note"`
+}
+
+func SettlementModelToResponse(s *model.Settlement) *Settlement {
+	return &Settlement{
+		ID:         s.ID,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+		FromUserID: s.FromUserID,
+		ToUserID:   s.ToUserID,
+		Amount:     s.Amount,
+		Currency:   s.Currency,
+		Timestamp:  s.Timestamp,
+		Note:       s.Note,
+	}
+}
+
+func SettlementRequestToModel(s *Settlement, userID uint) *model.Settlement {
+	return &model.Settlement{
+		FromUserID: s.FromUserID,
+		ToUserID:   userID,
+		Amount:     s.Amount,
+		Currency:   s.Currency,
+		Timestamp:  s.Timestamp,
+		Note:       s.Note,
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"expense-api/internal/classifier"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type SuggestionsHandler interface {
+	SuggestCategories(ctx *gin.Context)
+}
+
+// SuggestCategories ranks the categories the user's history suggests for the description in 'q'.
+// 'amount' and 'wallet_id' sharpen the suggestions, 'k' limits how many come back.
+func (h *handler) SuggestCategories(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	description := ctx.Query("q")
+	if description == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorSuggestionText)
+		return
+	}
+
+	amount := decimal.Zero
+	if a := ctx.Query("amount"); a != "" {
+		if amount, err = decimal.NewFromString(a); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorSuggestionAmount)
+			return
+		}
+	}
+
+	var walletID uint
+	if w := ctx.Query("wallet_id"); w != "" {
+		id, err := strconv.ParseUint(w, 10, 32)
+		if err != nil || id == 0 {
+			ctx.JSON(http.StatusBadRequest, ErrorBadWalletID)
+			return
+		}
+		walletID = uint(id)
+	}
+
+	k := defaultSuggestionLimit
+	if l := ctx.Query("k"); l != "" {
+		if k, err = strconv.Atoi(l); err != nil || k < 1 || k > maxSuggestionLimit {
+			ctx.JSON(http.StatusBadRequest, ErrorSuggestionLimit)
+			return
+		}
+	}
+
+	c, err := h.categoryClassifier(ctx, userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	suggestions := SuggestionsToResponse(c.Suggest(classifier.Features(description, amount, walletID), k))
+	if suggestions == nil {
+		suggestions = []*CategorySuggestion{}
+	}
+
+	res := NewListResponse(suggestions)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// categoryClassifier returns the user's classifier, training it on the categories of the
+// transactions they created if it isn't in memory yet or they changed since it was trained
+func (h *handler) categoryClassifier(ctx *gin.Context, userID uint) (*classifier.Classifier, error) {
+	stamp, err := h.repo(ctx).TransactionStamp(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.classifiers.Get(userID, stamp, func() ([]classifier.Example, error) {
+		tModels, err := h.repo(ctx).TransactionList(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		var examples []classifier.Example
+		for _, t := range tModels {
+			if t.UserID == userID && t.Category != "" {
+				examples = append(examples, transactionExample(t))
+			}
+		}
+		return examples, nil
+	})
+}
+
+// categoryChange is a change of a transaction for the classifier of its owner to learn. before is
+// nil for new transactions, after for deleted ones.
+type categoryChange struct {
+	before, after *model.Transaction
+}
+
+func (c categoryChange) owner() uint {
+	if c.before != nil {
+		return c.before.UserID
+	}
+	return c.after.UserID
+}
+
+// historyStamps reads the stamps of the histories of the users before their transactions change, for
+// learnCategories. Users whose stamp can't be read are left out; their classifiers are trained again
+// when they are needed.
+func (h *handler) historyStamps(ctx *gin.Context, userIDs ...uint) map[uint]string {
+	stamps := make(map[uint]string, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := stamps[userID]; ok {
+			continue
+		}
+		stamp, err := h.repo(ctx).TransactionStamp(userID)
+		if err != nil {
+			ctx.Error(err)
+			continue
+		}
+		stamps[userID] = stamp
+	}
+	return stamps
+}
+
+// learnCategories teaches the classifiers of the owners the changes of their transactions: they
+// forget what they learnt from the transactions before and learn them after the change. Only the
+// classifiers trained on the histories with the stamps from before the changes learn them, see
+// classifier.Registry.Update.
+func (h *handler) learnCategories(ctx *gin.Context, stamps map[uint]string, changes ...categoryChange) {
+	byOwner := make(map[uint][]categoryChange)
+	for _, c := range changes {
+		byOwner[c.owner()] = append(byOwner[c.owner()], c)
+	}
+
+	for userID, changes := range byOwner {
+		before, ok := stamps[userID]
+		if !ok {
+			continue
+		}
+		after, err := h.repo(ctx).TransactionStamp(userID)
+		if err != nil {
+			ctx.Error(err)
+			continue
+		}
+
+		h.classifiers.Update(userID, before, after, func(c *classifier.Classifier) {
+			for _, change := range changes {
+				if change.before != nil {
+					c.Forget(transactionExample(change.before))
+				}
+				if change.after != nil {
+					c.Learn(transactionExample(change.after))
+				}
+			}
+		})
+	}
+}
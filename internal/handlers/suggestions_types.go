@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"expense-api/internal/classifier"
+	"expense-api/internal/model"
+)
+
+const (
+	// createSuggestions is how many categories come with a new transaction that has none
+	createSuggestions      = 3
+	defaultSuggestionLimit = 5
+	maxSuggestionLimit     = 20
+)
+
+// CategorySuggestion is a category the user's history suggests, with the probability it is right
+type CategorySuggestion struct {
+	Category   string  `json:"category"`
+	Confidence float64 `json:"confidence"`
+}
+
+func SuggestionsToResponse(suggestions []classifier.Suggestion) []*CategorySuggestion {
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	res := make([]*CategorySuggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		res = append(res, &CategorySuggestion{Category: s.Label, Confidence: s.Confidence})
+	}
+	return res
+}
+
+// transactionExample is what the classifier learns from a transaction
+func transactionExample(t *model.Transaction) classifier.Example {
+	return classifier.Example{
+		Features: classifier.Features(t.Description, t.Amount, t.WalletID),
+		Label:    t.Category,
+	}
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/middleware"
+	transactions_middleware "expense-api/internal/middleware/transactions"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/settleup"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type TransactionSharesHandler interface {
+	GetTransactionShares(ctx *gin.Context)
+	UpdateTransactionShares(ctx *gin.Context)
+	DeleteTransactionShares(ctx *gin.Context)
+}
+
+func (h *handler) GetTransactionShares(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	sModels, err := h.repo(ctx).TransactionShareList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, TransactionSharesModelToResponse(sModels))
+}
+
+// UpdateTransactionShares shares an expense between its owner, who paid it, and other users,
+// replacing any previous shares
+func (h *handler) UpdateTransactionShares(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+	tModel := transactions_middleware.GetTransactionFromContext(ctx)
+
+	if !checkIfMatch(ctx, tModel.Version) {
+		return
+	}
+
+	var sRequest TransactionShares
+	if err := ctx.Bind(&sRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if !settleup.IsValidMethod(sRequest.Method) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidShareMethod)
+		return
+	}
+
+	if !tModel.Amount.IsNegative() {
+		ctx.JSON(http.StatusBadRequest, ErrorShareIncome)
+		return
+	}
+
+	users, errMessage, err := h.resolveShareParticipants(ctx, tModel.UserID, sRequest.Participants)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if errMessage != nil {
+		ctx.JSON(http.StatusBadRequest, errMessage)
+		return
+	}
+
+	wallet, err := h.repo(ctx).WalletGet(tModel.WalletID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	amounts, errMessage := shareAmounts(WalletCurrency(wallet), tModel.Amount.Abs(), &sRequest)
+	if errMessage != nil {
+		ctx.JSON(http.StatusBadRequest, errMessage)
+		return
+	}
+
+	sModels := make([]*model.TransactionShare, 0, len(users))
+	for i, u := range users {
+		share := &model.TransactionShare{
+			TransactionID: id,
+			UserID:        u.ID,
+			Method:        sRequest.Method,
+			Amount:        amounts[i],
+		}
+		if sRequest.Method == settleup.MethodPercentage {
+			share.Percentage = sRequest.Participants[i].Percentage
+		}
+		sModels = append(sModels, share)
+	}
+
+	if err := h.repo(ctx).TransactionShareReplace(id, tModel.Version, sModels); err != nil {
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	for i, u := range users {
+		sModels[i].User = *u
+	}
+
+	ctx.JSON(http.StatusOK, TransactionSharesModelToResponse(sModels))
+}
+
+// DeleteTransactionShares stops sharing the expense, making its owner responsible for all of it again
+func (h *handler) DeleteTransactionShares(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+	tModel := transactions_middleware.GetTransactionFromContext(ctx)
+
+	if !checkIfMatch(ctx, tModel.Version) {
+		return
+	}
+
+	if err := h.repo(ctx).TransactionShareReplace(id, tModel.Version, nil); err != nil {
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// resolveShareParticipants looks up the user behind every participant, making sure nobody takes part
+// twice, that everybody else shares a household with the payer and that the payer doesn't share the
+// expense only with themselves. Users outside the payer's households are reported just like unknown
+// users, so that emails can't be probed.
+func (h *handler) resolveShareParticipants(ctx *gin.Context, payerID uint, participants []*ShareParticipant) ([]*model.User, *ErrorMessage, error) {
+	users := make([]*model.User, 0, len(participants))
+	seen := map[uint]bool{}
+	others := 0
+
+	for _, p := range participants {
+		var (
+			user *model.User
+			err  error
+		)
+		if p.UserID != 0 {
+			user, err = h.repo(ctx).UserGet(p.UserID)
+		} else if p.Email != "" {
+			user, err = h.repo(ctx).UserGetWithEmail(p.Email)
+		} else {
+			return nil, ErrorParticipantNotFound, nil
+		}
+
+		if err != nil {
+			if err == repository.ErrorRecordNotFound {
+				return nil, ErrorParticipantNotFound, nil
+			}
+			return nil, nil, err
+		}
+
+		if seen[user.ID] {
+			return nil, ErrorDuplicateParticipant, nil
+		}
+		seen[user.ID] = true
+
+		if user.ID != payerID {
+			shared, err := h.repo(ctx).HouseholdSharedByUsers(payerID, user.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !shared {
+				return nil, ErrorParticipantNotFound, nil
+			}
+			others++
+		}
+		users = append(users, user)
+	}
+
+	if others == 0 {
+		return nil, ErrorNoOtherParticipant, nil
+	}
+
+	return users, nil, nil
+}
+
+// shareAmounts calculates what each participant owes, in the same order as the participants
+func shareAmounts(code string, total decimal.Decimal, sRequest *TransactionShares) ([]decimal.Decimal, *ErrorMessage) {
+	places, err := currency.MinorUnits(code)
+	if err != nil {
+		return nil, ErrorInvalidCurrency
+	}
+
+	switch sRequest.Method {
+	case settleup.MethodPercentage:
+		percentages := make([]decimal.Decimal, 0, len(sRequest.Participants))
+		for _, p := range sRequest.Participants {
+			if p.Percentage == nil {
+				return nil, ErrorPercentagesNot100
+			}
+			percentages = append(percentages, *p.Percentage)
+		}
+
+		amounts, err := settleup.SplitByPercentage(total, places, percentages)
+		if err == settleup.ErrorTotalPrecision {
+			return nil, ErrorAmountPrecision
+		}
+		if err != nil {
+			return nil, ErrorPercentagesNot100
+		}
+		return amounts, nil
+
+	case settleup.MethodExact:
+		amounts := make([]decimal.Decimal, 0, len(sRequest.Participants))
+		for _, p := range sRequest.Participants {
+			if p.Amount.IsNegative() {
+				return nil, ErrorSharesMismatch
+			}
+			if err := currency.ValidateAmount(code, p.Amount); err != nil {
+				return nil, ErrorAmountPrecision
+			}
+			amounts = append(amounts, p.Amount)
+		}
+
+		if err := settleup.CheckExact(total, amounts); err != nil {
+			return nil, ErrorSharesMismatch
+		}
+		return amounts, nil
+
+	default:
+		amounts, err := settleup.SplitEqually(total, places, len(sRequest.Participants))
+		if err != nil {
+			return nil, ErrorAmountPrecision
+		}
+		return amounts, nil
+	}
+}
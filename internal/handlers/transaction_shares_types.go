@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+// ShareParticipant is a user taking part in a shared expense. In requests a participant is
+// identified either by user ID or by email; the percentage is only used by the 'percentage'
+// method and the amount only by the 'exact' method.
+type ShareParticipant struct {
+	UserID     uint             `json:"user_id"`
+	Email      string           `json:"email"`
+	Percentage *decimal.Decimal `json:"percentage,omitempty"`
+	Amount     decimal.Decimal  `json:"amount"`
+}
+
+// TransactionShares describes how an expense is shared between users
+type TransactionShares struct {
+	Method       string              `json:"method"`
+	Participants []*ShareParticipant `json:"participants"`
+}
+
+func TransactionSharesModelToResponse(sModels []*model.TransactionShare) *TransactionShares {
+	res := &TransactionShares{Participants: make([]*ShareParticipant, 0, len(sModels))}
+	for _, s := range sModels {
+		res.Method = s.Method
+		res.Participants = append(res.Participants, &ShareParticipant{
+			UserID:     s.UserID,
+			Email:      s.User.Email,
+			Percentage: s.Percentage,
+			Amount:     s.Amount,
+		})
+	}
+	return res
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	transactions_middleware "expense-api/internal/middleware/transactions"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type TransactionSplitsHandler interface {
+	ListTransactionSplits(ctx *gin.Context)
+	CreateTransactionSplits(ctx *gin.Context)
+	UpdateTransactionSplits(ctx *gin.Context)
+	DeleteTransactionSplits(ctx *gin.Context)
+}
+
+func (h *handler) ListTransactionSplits(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	sModels, err := h.repo(ctx).TransactionSplitList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	res := NewListResponse(transactionSplitsToResponse(sModels))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// CreateTransactionSplits splits a transaction that isn't split yet
+func (h *handler) CreateTransactionSplits(ctx *gin.Context) {
+	h.saveTransactionSplits(ctx, false)
+}
+
+// UpdateTransactionSplits replaces all splits of a transaction that is already split
+func (h *handler) UpdateTransactionSplits(ctx *gin.Context) {
+	h.saveTransactionSplits(ctx, true)
+}
+
+func (h *handler) saveTransactionSplits(ctx *gin.Context, replace bool) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+	tModel := transactions_middleware.GetTransactionFromContext(ctx)
+
+	if !checkIfMatch(ctx, tModel.Version) || !allowChange(ctx, tModel) {
+		return
+	}
+
+	var sRequest TransactionSplits
+	if err := ctx.Bind(&sRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.repo(ctx).TransactionSplitList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if !replace && len(existing) > 0 {
+		ctx.JSON(http.StatusConflict, ErrorTransactionAlreadySplit)
+		return
+	}
+
+	if replace && len(existing) == 0 {
+		ctx.JSON(http.StatusNotFound, ErrorTransactionNotSplit)
+		return
+	}
+
+	validationErr, err := h.validateTransactionSplits(ctx, userID, tModel, sRequest.Splits)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if validationErr != nil {
+		ctx.JSON(http.StatusBadRequest, validationErr)
+		return
+	}
+
+	sModels := make([]*model.TransactionSplit, 0, len(sRequest.Splits))
+	for _, s := range sRequest.Splits {
+		sModels = append(sModels, TransactionSplitRequestToModel(s, id))
+	}
+
+	if err := h.repo(ctx).TransactionSplitReplace(id, tModel.Version, sModels); err != nil {
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusCreated
+	if replace {
+		status = http.StatusOK
+	}
+
+	res := NewListResponse(transactionSplitsToResponse(sModels))
+	ctx.JSON(status, res)
+}
+
+// DeleteTransactionSplits removes all splits, turning the transaction back into a single line
+func (h *handler) DeleteTransactionSplits(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+	tModel := transactions_middleware.GetTransactionFromContext(ctx)
+
+	if !checkIfMatch(ctx, tModel.Version) || !allowChange(ctx, tModel) {
+		return
+	}
+
+	if err := h.repo(ctx).TransactionSplitReplace(id, tModel.Version, nil); err != nil {
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// validateTransactionSplits checks each line on its own and then that all lines add up to the transaction's amount
+func (h *handler) validateTransactionSplits(ctx *gin.Context, userID uint, t *model.Transaction, splits []*TransactionSplit) (*SplitValidationError, error) {
+	validationErr := &SplitValidationError{Message: ErrorInvalidSplits.Message}
+
+	if len(splits) < 2 {
+		validationErr.Message = ErrorTooFewSplits.Message
+		return validationErr, nil
+	}
+
+	wallet, err := h.repo(ctx).WalletGet(t.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	walletCurrency := WalletCurrency(wallet)
+
+	sum := decimal.Zero
+	for i, s := range splits {
+		line := i + 1
+		sum = sum.Add(s.Amount)
+
+		if s.Amount.IsZero() {
+			validationErr.addLine(line, "amount must not be 0")
+		} else if s.Amount.Sign() != t.Amount.Sign() {
+			validationErr.addLine(line, fmt.Sprintf("amount %s must have the same sign as the transaction amount %s", s.Amount, t.Amount))
+		} else if err := currency.ValidateAmount(walletCurrency, s.Amount); err != nil {
+			units, _ := currency.MinorUnits(walletCurrency)
+			validationErr.addLine(line, fmt.Sprintf("amount %s has more decimal places than %s allows (%d)", s.Amount, walletCurrency, units))
+		}
+
+		if s.PartyID != 0 {
+			party, err := h.repo(ctx).PartyGet(s.PartyID)
+			if err != nil && err != repository.ErrorRecordNotFound {
+				return nil, err
+			}
+
+			if err == repository.ErrorRecordNotFound {
+				validationErr.addLine(line, fmt.Sprintf("party %d not found", s.PartyID))
+			} else if allowed, err := permissions.Check(h.repo(ctx), userID, party.UserID, party.HouseholdID, permissions.RoleViewer); err != nil {
+				return nil, err
+			} else if !allowed {
+				validationErr.addLine(line, fmt.Sprintf("party %d belongs to another user", s.PartyID))
+			} else if !sameHousehold(party.HouseholdID, t.HouseholdID) {
+				validationErr.addLine(line, fmt.Sprintf("party %d doesn't belong to the household of the transaction", s.PartyID))
+			}
+		}
+	}
+
+	if difference := sum.Sub(t.Amount); !difference.IsZero() {
+		validationErr.Message = fmt.Sprintf(
+			"splits add up to %s, but the transaction amount is %s (off by %s)",
+			sum, t.Amount, difference,
+		)
+		validationErr.Difference = &difference
+	}
+
+	if validationErr.Difference == nil && len(validationErr.Lines) == 0 {
+		return nil, nil
+	}
+	return validationErr, nil
+}
+
+func transactionSplitsToResponse(sModels []*model.TransactionSplit) []*TransactionSplit {
+	sResponse := make([]*TransactionSplit, 0, len(sModels))
+	for _, s := range sModels {
+		sResponse = append(sResponse, TransactionSplitModelToResponse(s))
+	}
+	return sResponse
+}
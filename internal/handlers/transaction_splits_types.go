@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TransactionSplit is one line of a split transaction. A party ID of 0 means the line belongs to the transaction's party.
+type TransactionSplit struct {
+	ID        uint            `json:"id"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Amount    decimal.Decimal `json:"amount"`
+	PartyID   uint            `json:"party_id"`
+	Category  string          `json:"category"`
+	Note      string          `json:"note"`
+}
+
+// TransactionSplits is the request body for creating or replacing all splits of a transaction
+type TransactionSplits struct {
+	Splits []*TransactionSplit `json:"splits"`
+}
+
+// SplitLineError describes what is wrong with a single split line; lines are counted from 1
+type SplitLineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// SplitValidationError is returned when splits can't be saved. Difference is the amount the splits
+// are off by (the sum of the splits minus the transaction's amount).
+type SplitValidationError struct {
+	Message    string           `json:"message"`
+	Difference *decimal.Decimal `json:"difference,omitempty"`
+	Lines      []SplitLineError `json:"lines,omitempty"`
+}
+
+func (e *SplitValidationError) addLine(line int, message string) {
+	e.Lines = append(e.Lines, SplitLineError{Line: line, Message: message})
+}
+
+func TransactionSplitModelToResponse(s *model.TransactionSplit) *TransactionSplit {
+	var partyID uint
+	if s.PartyID != nil {
+		partyID = *s.PartyID
+	}
+
+	return &TransactionSplit{
+		ID:        s.ID,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+		Amount:    s.Amount,
+		PartyID:   partyID,
+		Category:  s.Category,
+		Note:      s.Note,
+	}
+}
+
+func TransactionSplitRequestToModel(s *TransactionSplit, transactionID uint) *model.TransactionSplit {
+	var partyID *uint
+	if s.PartyID != 0 {
+		id := s.PartyID
+		partyID = &id
+	}
+
+	return &model.TransactionSplit{
+		TransactionID: transactionID,
+		Amount:        s.Amount,
+		PartyID:       partyID,
+		Category:      s.Category,
+		Note:          s.Note,
+	}
+}
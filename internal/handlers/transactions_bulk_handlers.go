@@ -0,0 +1,572 @@
+package handlers
+
+import (
+	"encoding/json"
+	"expense-api/internal/classifier"
+	"expense-api/internal/currency"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"expense-api/internal/rules"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// bulkItem is an operation of a bulk request on its way to being applied
+type bulkItem struct {
+	op *BulkOperation
+	// current is the transaction to update or delete
+	current *model.Transaction
+	// tModel is the transaction to create or the updated transaction
+	tModel       *model.Transaction
+	matchedRules []uint
+	result       *BulkResult
+}
+
+func (i *bulkItem) fail(status int, err *ErrorMessage) {
+	i.result.Status = status
+	i.result.Error = err
+}
+
+func (i *bulkItem) failed() bool {
+	return i.result.Error != nil
+}
+
+// BulkTransactions validates all operations of the request like the single create, update and delete
+// routes would, but loads the transactions, wallets and parties they refer to in one query each
+func (h *handler) BulkTransactions(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var bRequest BulkTransactions
+	if err := ctx.Bind(&bRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	if bRequest.Mode == "" {
+		bRequest.Mode = BulkAllOrNothing
+	}
+
+	if !isValidBulkMode(bRequest.Mode) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidBulkMode)
+		return
+	}
+
+	if len(bRequest.Operations) == 0 || len(bRequest.Operations) > maxBulkOperations {
+		ctx.JSON(http.StatusBadRequest, ErrorBulkOperationCount)
+		return
+	}
+
+	items := make([]*bulkItem, len(bRequest.Operations))
+	for i, op := range bRequest.Operations {
+		items[i] = &bulkItem{op: op, result: &BulkResult{Index: i, Op: op.Op}}
+	}
+
+	memberships, err := h.repo(ctx).HouseholdListByUser(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	roles := permissions.NewRoles(memberships)
+
+	if err := h.prepareBulkItems(ctx, userID, roles, items); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	stamps := h.historyStamps(ctx, bulkOwners(userID, items)...)
+
+	status := http.StatusOK
+	if bRequest.Mode == BulkAllOrNothing {
+		status, err = h.applyBulkAtomically(ctx, items)
+	} else {
+		h.applyBulkOneByOne(ctx, items)
+	}
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	res := &BulkTransactionsResponse{Mode: bRequest.Mode, Results: make([]*BulkResult, 0, len(items))}
+	var matchedRules []uint
+	var changes []categoryChange
+	for _, item := range items {
+		res.Results = append(res.Results, item.result)
+		if item.failed() {
+			res.Failed++
+			continue
+		}
+		res.Succeeded++
+
+		switch item.op.Op {
+		case repository.OperationCreate:
+			matchedRules = append(matchedRules, item.matchedRules...)
+			changes = append(changes, categoryChange{after: item.tModel})
+		case repository.OperationUpdate:
+			changes = append(changes, categoryChange{before: item.current, after: item.tModel})
+		case repository.OperationDelete:
+			changes = append(changes, categoryChange{before: item.current})
+		}
+	}
+	h.recordRuleHits(ctx, matchedRules)
+	h.learnCategories(ctx, stamps, changes...)
+	h.suggestBulkCategories(ctx, userID, items)
+
+	ctx.JSON(status, res)
+}
+
+// bulkOwners lists the users whose transactions the operations change: the user for new ones, the
+// owners of the others
+func bulkOwners(userID uint, items []*bulkItem) []uint {
+	owners := []uint{userID}
+	for _, item := range items {
+		if item.current != nil {
+			owners = append(owners, item.current.UserID)
+		}
+	}
+	return owners
+}
+
+// suggestBulkCategories adds category suggestions to the results of the transactions created without
+// a category, like CreateTransaction does
+func (h *handler) suggestBulkCategories(ctx *gin.Context, userID uint, items []*bulkItem) {
+	var c *classifier.Classifier
+	for _, item := range items {
+		if item.failed() || item.op.Op != repository.OperationCreate || item.tModel.Category != "" {
+			continue
+		}
+
+		if c == nil {
+			var err error
+			// Suggestions are a convenience, the transactions are created either way
+			if c, err = h.categoryClassifier(ctx, userID); err != nil {
+				ctx.Error(err)
+				return
+			}
+		}
+		features := transactionExample(item.tModel).Features
+		item.result.Transaction.Suggestions = SuggestionsToResponse(c.Suggest(features, createSuggestions))
+	}
+}
+
+// prepareBulkItems validates the operations and builds the transactions to save. Operations that
+// can't be applied are marked as failed.
+func (h *handler) prepareBulkItems(ctx *gin.Context, userID uint, roles permissions.Roles, items []*bulkItem) error {
+	for _, item := range items {
+		prepareBulkRequest(userID, item)
+	}
+
+	if err := h.loadBulkTransactions(ctx, userID, roles, items); err != nil {
+		return err
+	}
+
+	if err := h.checkBulkWallets(ctx, userID, roles, items); err != nil {
+		return err
+	}
+
+	// Rules may set the party, so they are applied before the parties are checked
+	if creates := pendingBulkItems(items, repository.OperationCreate); len(creates) > 0 {
+		rModels, err := h.repo(ctx).RuleList(userID)
+		if err != nil {
+			return err
+		}
+		rules.Sort(rModels)
+
+		for _, item := range creates {
+			item.matchedRules = rules.Apply(rModels, item.tModel, false)
+		}
+	}
+
+	return h.checkBulkParties(ctx, userID, roles, items)
+}
+
+// prepareBulkRequest checks what can be checked without the database and parses the transactions to create
+func prepareBulkRequest(userID uint, item *bulkItem) {
+	op := item.op
+
+	if !isValidBulkOperation(op.Op) {
+		item.fail(http.StatusBadRequest, ErrorInvalidBulkOperation)
+		return
+	}
+
+	if op.Op != repository.OperationCreate {
+		if op.ID == 0 {
+			item.fail(http.StatusBadRequest, ErrorRequiredTransactionID)
+		}
+		return
+	}
+
+	var tRequest Transaction
+	if err := json.Unmarshal(op.Transaction, &tRequest); err != nil {
+		item.fail(http.StatusBadRequest, ErrorInvalidBulkTransaction)
+		return
+	}
+
+	if tRequest.Amount.Cmp(decimal.Zero) == 0 {
+		item.fail(http.StatusBadRequest, ErrorRequiredAmount)
+		return
+	}
+
+	if tRequest.Status == "" {
+		tRequest.Status = model.TransactionUncleared
+	}
+
+	if errMsg := validateBulkTransaction(&tRequest); errMsg != nil {
+		item.fail(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	item.tModel = TransactionRequestToModel(&tRequest, userID)
+	if item.tModel.Timestamp.IsZero() {
+		item.tModel.Timestamp = time.Now()
+	}
+}
+
+// validateBulkTransaction checks the fields of a transaction to create or of a patched transaction
+// and normalizes its tags. The party is checked after the rules are applied.
+func validateBulkTransaction(t *Transaction) *ErrorMessage {
+	if t.WalletID == 0 {
+		return ErrorRequiredWalletID
+	}
+
+	tags, errMsg := normalizeTags(t.Tags)
+	if errMsg != nil {
+		return errMsg
+	}
+	t.Tags = tags
+
+	if !isValidStatus(t.Status) {
+		return ErrorInvalidStatus
+	}
+	return nil
+}
+
+// loadBulkTransactions loads the transactions to update or delete, checks that the user may change
+// them and applies the patches of the updates
+func (h *handler) loadBulkTransactions(ctx *gin.Context, userID uint, roles permissions.Roles, items []*bulkItem) error {
+	var ids []uint
+	for _, item := range items {
+		if !item.failed() && item.op.Op != repository.OperationCreate {
+			ids = append(ids, item.op.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tModels, err := h.repo(ctx).TransactionListByIDs(ids)
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*model.Transaction, len(tModels))
+	for _, t := range tModels {
+		byID[t.ID] = t
+	}
+
+	seen := make(map[uint]bool, len(ids))
+	for _, item := range items {
+		if item.failed() || item.op.Op == repository.OperationCreate {
+			continue
+		}
+
+		if seen[item.op.ID] {
+			item.fail(http.StatusBadRequest, ErrorBulkDuplicateID)
+			continue
+		}
+		seen[item.op.ID] = true
+
+		current, ok := byID[item.op.ID]
+		if !ok {
+			item.fail(http.StatusNotFound, ErrorTransactionNotFound)
+			continue
+		}
+		item.current = current
+
+		if !roles.Check(userID, current.UserID, current.HouseholdID, permissions.RoleEditor) {
+			item.fail(http.StatusForbidden, ErrorBadTransactionID)
+			continue
+		}
+
+		if item.op.Version != nil && *item.op.Version != current.Version {
+			item.fail(http.StatusPreconditionFailed, ErrorPreconditionFailed)
+			continue
+		}
+
+		if current.Status == model.TransactionReconciled {
+			item.fail(http.StatusConflict, ErrorTransactionReconciled)
+			continue
+		}
+
+		if item.op.Op == repository.OperationUpdate {
+			applyBulkPatch(item)
+		}
+	}
+	return nil
+}
+
+func applyBulkPatch(item *bulkItem) {
+	var tRequest Transaction
+	if err := applyPatch(item.op.Transaction, TransactionModelToResponse(item.current), &tRequest); err != nil {
+		item.fail(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	if tRequest.Timestamp.IsZero() {
+		item.fail(http.StatusBadRequest, ErrorRequiredTimestamp)
+		return
+	}
+
+	if errMsg := validateBulkTransaction(&tRequest); errMsg != nil {
+		item.fail(http.StatusBadRequest, errMsg)
+		return
+	}
+
+	item.tModel = TransactionRequestToModel(&tRequest, item.current.UserID)
+	item.tModel.Version = item.current.Version
+	item.tModel.HouseholdID = item.current.HouseholdID
+}
+
+// checkBulkWallets checks that the user may add transactions to the wallets they are created in or
+// moved to and validates the amounts against the currencies of the wallets
+func (h *handler) checkBulkWallets(ctx *gin.Context, userID uint, roles permissions.Roles, items []*bulkItem) error {
+	var ids []uint
+	for _, item := range items {
+		if item.tModel != nil && !item.failed() {
+			ids = append(ids, item.tModel.WalletID)
+			if item.current != nil {
+				ids = append(ids, item.current.WalletID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	wModels, err := h.repo(ctx).WalletListByIDs(uniqueIDs(ids))
+	if err != nil {
+		return err
+	}
+	byID := make(map[uint]*model.Wallet, len(wModels))
+	for _, w := range wModels {
+		byID[w.ID] = w
+	}
+
+	for _, item := range items {
+		if item.tModel == nil || item.failed() {
+			continue
+		}
+
+		wallet, ok := byID[item.tModel.WalletID]
+		if !ok {
+			item.fail(http.StatusBadRequest, ErrorWalletNotFound)
+			continue
+		}
+
+		moved := item.current == nil || item.tModel.WalletID != item.current.WalletID
+		if moved {
+			if !roles.Check(userID, wallet.UserID, wallet.HouseholdID, permissions.RoleEditor) {
+				item.fail(http.StatusForbidden, ErrorBadWalletID)
+				continue
+			}
+			item.tModel.HouseholdID = wallet.HouseholdID
+		}
+
+		if moved || !item.tModel.Amount.Equal(item.current.Amount) {
+			if err := currency.ValidateAmount(WalletCurrency(wallet), item.tModel.Amount); err != nil {
+				item.fail(http.StatusBadRequest, ErrorAmountPrecision)
+			}
+		}
+	}
+	return nil
+}
+
+// checkBulkParties checks that the user may use the parties of new transactions and the parties
+// transactions are changed to, and that they are parties of the household of the transaction
+func (h *handler) checkBulkParties(ctx *gin.Context, userID uint, roles permissions.Roles, items []*bulkItem) error {
+	var ids []uint
+	for _, item := range items {
+		if item.tModel == nil || item.failed() || item.tModel.PartyID == 0 {
+			continue
+		}
+		if checkBulkParty(item) {
+			ids = append(ids, item.tModel.PartyID)
+		}
+	}
+
+	byID := map[uint]*model.Party{}
+	if len(ids) > 0 {
+		pModels, err := h.repo(ctx).PartyListByIDs(uniqueIDs(ids))
+		if err != nil {
+			return err
+		}
+		for _, p := range pModels {
+			byID[p.ID] = p
+		}
+	}
+
+	for _, item := range items {
+		if item.tModel == nil || item.failed() {
+			continue
+		}
+
+		if item.tModel.PartyID == 0 {
+			item.fail(http.StatusBadRequest, ErrorRequiredPartyID)
+			continue
+		}
+
+		if !checkBulkParty(item) {
+			continue
+		}
+
+		party, ok := byID[item.tModel.PartyID]
+		if !ok {
+			item.fail(http.StatusBadRequest, ErrorPartyNotFound)
+			continue
+		}
+
+		if !roles.Check(userID, party.UserID, party.HouseholdID, permissions.RoleViewer) {
+			item.fail(http.StatusForbidden, ErrorBadPartyID)
+		} else if !sameHousehold(party.HouseholdID, item.tModel.HouseholdID) {
+			item.fail(http.StatusBadRequest, ErrorPartyHousehold)
+		}
+	}
+	return nil
+}
+
+// checkBulkParty is whether the party of the item has to be checked: it is new, or the transaction
+// gets another party or moves to another household
+func checkBulkParty(item *bulkItem) bool {
+	return item.current == nil || item.tModel.PartyID != item.current.PartyID ||
+		!sameHousehold(item.tModel.HouseholdID, item.current.HouseholdID)
+}
+
+// applyBulkAtomically applies all operations in one database transaction, unless one of them
+// already failed. It returns the status of the whole request.
+func (h *handler) applyBulkAtomically(ctx *gin.Context, items []*bulkItem) (int, error) {
+	ops := make([]*repository.TransactionOperation, 0, len(items))
+	for _, item := range items {
+		if item.failed() {
+			markNotApplied(items)
+			return http.StatusUnprocessableEntity, nil
+		}
+		ops = append(ops, bulkOperationToRepository(item))
+	}
+
+	failed, err := h.repo(ctx).TransactionBulk(ops)
+	if err != nil {
+		status, errMsg := bulkErrorToResponse(err)
+		if failed < 0 || status == http.StatusInternalServerError {
+			return 0, err
+		}
+
+		items[failed].fail(status, errMsg)
+		markNotApplied(items)
+		return http.StatusUnprocessableEntity, nil
+	}
+
+	for i, item := range items {
+		setBulkResult(item, ops[i])
+	}
+	return http.StatusOK, nil
+}
+
+// applyBulkOneByOne applies each operation on its own
+func (h *handler) applyBulkOneByOne(ctx *gin.Context, items []*bulkItem) {
+	for _, item := range items {
+		if item.failed() {
+			continue
+		}
+
+		op := bulkOperationToRepository(item)
+
+		var err error
+		switch op.Kind {
+		case repository.OperationCreate:
+			err = h.repo(ctx).TransactionCreate(op.Transaction)
+		case repository.OperationUpdate:
+			op.Transaction, err = h.repo(ctx).TransactionUpdate(op.ID, op.Transaction)
+		case repository.OperationDelete:
+			err = h.repo(ctx).TransactionDelete(op.ID)
+		}
+
+		if err != nil {
+			item.fail(bulkErrorToResponse(err))
+			continue
+		}
+		setBulkResult(item, op)
+	}
+}
+
+func bulkOperationToRepository(item *bulkItem) *repository.TransactionOperation {
+	return &repository.TransactionOperation{Kind: item.op.Op, ID: item.op.ID, Transaction: item.tModel}
+}
+
+func setBulkResult(item *bulkItem, op *repository.TransactionOperation) {
+	switch op.Kind {
+	case repository.OperationCreate:
+		item.result.Status = http.StatusCreated
+	case repository.OperationUpdate:
+		item.result.Status = http.StatusOK
+	case repository.OperationDelete:
+		item.result.Status = http.StatusNoContent
+		return
+	}
+
+	item.tModel = op.Transaction
+	item.result.Transaction = TransactionModelToResponse(op.Transaction)
+}
+
+// markNotApplied marks the operations which didn't fail themselves in a failed all or nothing request
+func markNotApplied(items []*bulkItem) {
+	for _, item := range items {
+		if !item.failed() {
+			item.fail(http.StatusFailedDependency, ErrorBulkNotApplied)
+		}
+	}
+}
+
+func bulkErrorToResponse(err error) (int, *ErrorMessage) {
+	switch err {
+	case repository.ErrorRecordNotFound:
+		return http.StatusNotFound, ErrorTransactionNotFound
+	case repository.ErrorSplitsOutOfBalance:
+		return http.StatusConflict, ErrorSplitsOutOfBalance
+	case repository.ErrorSharesOutOfBalance:
+		return http.StatusConflict, ErrorSharesOutOfBalance
+	case repository.ErrorVersionConflict:
+		return http.StatusConflict, ErrorConcurrentUpdate
+	}
+	return http.StatusInternalServerError, ErrorBulkOperationFailed
+}
+
+// pendingBulkItems returns the operations of the kind that didn't fail so far
+func pendingBulkItems(items []*bulkItem, op string) []*bulkItem {
+	var pending []*bulkItem
+	for _, item := range items {
+		if item.op.Op == op && !item.failed() {
+			pending = append(pending, item)
+		}
+	}
+	return pending
+}
+
+// uniqueIDs drops repeated IDs, keeping the order of the first ones
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"expense-api/internal/repository"
+)
+
+// Modes of a bulk request
+const (
+	BulkAllOrNothing = "all_or_nothing"
+	BulkBestEffort   = "best_effort"
+)
+
+// maxBulkOperations is the most operations a bulk request can have
+const maxBulkOperations = 500
+
+// BulkTransactions is a batch of changes to transactions. In all_or_nothing mode either every
+// operation is applied or none, in best_effort mode each operation succeeds or fails on its own.
+type BulkTransactions struct {
+	Mode       string           `json:"mode"`
+	Operations []*BulkOperation `json:"operations"`
+}
+
+// BulkOperation creates, updates or deletes one transaction. Transaction is the transaction to
+// create or the JSON Merge Patch of the transaction to update. Version is optional and works like
+// If-Match for the transaction with the ID.
+type BulkOperation struct {
+	Op          string          `json:"op"`
+	ID          uint            `json:"id"`
+	Version     *uint           `json:"version"`
+	Transaction json.RawMessage `json:"transaction"`
+}
+
+// BulkResult is the outcome of the operation at Index. Status is the HTTP status the operation
+// would have gotten on its own.
+type BulkResult struct {
+	Index       int           `json:"index"`
+	Op          string        `json:"op"`
+	Status      int           `json:"status"`
+	Transaction *Transaction  `json:"transaction,omitempty"`
+	Error       *ErrorMessage `json:"error,omitempty"`
+}
+
+type BulkTransactionsResponse struct {
+	Mode      string        `json:"mode"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []*BulkResult `json:"results"`
+}
+
+func isValidBulkMode(mode string) bool {
+	return mode == BulkAllOrNothing || mode == BulkBestEffort
+}
+
+func isValidBulkOperation(op string) bool {
+	return op == repository.OperationCreate || op == repository.OperationUpdate || op == repository.OperationDelete
+}
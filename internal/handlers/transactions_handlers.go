@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"expense-api/internal/currency"
+	"expense-api/internal/duplicates"
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	transactions_middleware "expense-api/internal/middleware/transactions"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
@@ -16,6 +23,7 @@ type TransactionsHandler interface {
 	GetTransaction(ctx *gin.Context)
 	UpdateTransaction(ctx *gin.Context)
 	DeleteTransaction(ctx *gin.Context)
+	BulkTransactions(ctx *gin.Context)
 }
 
 func (h *handler) CreateTransaction(ctx *gin.Context) {
@@ -36,6 +44,22 @@ func (h *handler) CreateTransaction(ctx *gin.Context) {
 		return
 	}
 
+	tags, errMsg := normalizeTags(tRequest.Tags)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	tRequest.Tags = tags
+
+	if tRequest.Status == "" {
+		tRequest.Status = model.TransactionUncleared
+	}
+
+	if !isValidStatus(tRequest.Status) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidStatus)
+		return
+	}
+
 	tModel := TransactionRequestToModel(&tRequest, userID)
 
 	{ // Validate wallet ownership
@@ -44,7 +68,7 @@ func (h *handler) CreateTransaction(ctx *gin.Context) {
 			return
 		}
 
-		wallet, err := h.repo.WalletGet(tModel.WalletID)
+		wallet, err := h.repo(ctx).WalletGet(tModel.WalletID)
 		if err != nil {
 			if err == repository.ErrorRecordNotFound {
 				ctx.JSON(http.StatusBadRequest, ErrorWalletNotFound)
@@ -54,10 +78,28 @@ func (h *handler) CreateTransaction(ctx *gin.Context) {
 			return
 		}
 
-		if wallet.UserID != userID {
+		allowed, err := permissions.Check(h.repo(ctx), userID, wallet.UserID, wallet.HouseholdID, permissions.RoleEditor)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
 			ctx.JSON(http.StatusForbidden, ErrorBadWalletID)
 			return
 		}
+		tModel.HouseholdID = wallet.HouseholdID
+
+		if err := currency.ValidateAmount(WalletCurrency(wallet), tModel.Amount); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+			return
+		}
+	}
+
+	matchedRules, err := h.applyRulesOnCreate(ctx, userID, tModel)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
 	}
 
 	{ // Validate party ownership
@@ -66,7 +108,7 @@ func (h *handler) CreateTransaction(ctx *gin.Context) {
 			return
 		}
 
-		party, err := h.repo.PartyGet(tModel.PartyID)
+		party, err := h.repo(ctx).PartyGet(tModel.PartyID)
 		if err != nil {
 			if err == repository.ErrorRecordNotFound {
 				ctx.JSON(http.StatusBadRequest, ErrorPartyNotFound)
@@ -76,25 +118,71 @@ func (h *handler) CreateTransaction(ctx *gin.Context) {
 			return
 		}
 
-		if party.UserID != userID {
+		allowed, err := permissions.Check(h.repo(ctx), userID, party.UserID, party.HouseholdID, permissions.RoleViewer)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
 			ctx.JSON(http.StatusForbidden, ErrorBadPartyID)
 			return
 		}
+
+		if !sameHousehold(party.HouseholdID, tModel.HouseholdID) {
+			ctx.JSON(http.StatusBadRequest, ErrorPartyHousehold)
+			return
+		}
 	}
 
-	if err := h.repo.TransactionCreate(tModel); err != nil {
+	// Compare with the time the transaction is going to get
+	if tModel.Timestamp.IsZero() {
+		tModel.Timestamp = time.Now()
+	}
+
+	candidates, err := h.repo(ctx).TransactionListDuplicateCandidates(tModel, duplicates.Window)
+	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
 
+	var warning *DuplicateWarning
+	if found := duplicates.Find(tModel, candidates); len(found) > 0 {
+		warning = NewDuplicateWarning(found)
+
+		if strict, _ := strconv.ParseBool(ctx.Query("strict")); strict {
+			ctx.JSON(http.StatusConflict, warning)
+			return
+		}
+	}
+
+	stamps := h.historyStamps(ctx, userID)
+	if err := h.repo(ctx).TransactionCreate(tModel); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	h.recordRuleHits(ctx, matchedRules)
+	h.learnCategories(ctx, stamps, categoryChange{after: tModel})
+
 	tResponse := TransactionModelToResponse(tModel)
+	tResponse.Warning = warning
+
+	if tModel.Category == "" {
+		// Suggestions are a convenience, the transaction is created either way
+		if c, err := h.categoryClassifier(ctx, userID); err != nil {
+			ctx.Error(err)
+		} else {
+			tResponse.Suggestions = SuggestionsToResponse(c.Suggest(transactionExample(tModel).Features, createSuggestions))
+		}
+	}
+
 	ctx.JSON(http.StatusCreated, tResponse)
 }
 
 func (h *handler) GetTransaction(ctx *gin.Context) {
 	id := middleware.GetIDParamFromContext(ctx)
 
-	tModel, err := h.repo.TransactionGet(id)
+	tModel, err := h.repo(ctx).TransactionGet(id)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
@@ -104,10 +192,15 @@ func (h *handler) GetTransaction(ctx *gin.Context) {
 		return
 	}
 
+	if notModified(ctx, ETag(tModel.Version)) {
+		return
+	}
+
 	tResponse := TransactionModelToResponse(tModel)
 	ctx.JSON(http.StatusOK, tResponse)
 }
 
+// UpdateTransaction applies a JSON Merge Patch to the transaction
 func (h *handler) UpdateTransaction(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -116,18 +209,47 @@ func (h *handler) UpdateTransaction(ctx *gin.Context) {
 	}
 
 	id := middleware.GetIDParamFromContext(ctx)
+	current := transactions_middleware.GetTransactionFromContext(ctx)
+
+	if !checkIfMatch(ctx, current.Version) || !allowChange(ctx, current) {
+		return
+	}
 
 	var tRequest Transaction
-	if err := ctx.Bind(&tRequest); err != nil {
-		ctx.Status(http.StatusBadRequest)
+	if err := bindPatch(ctx, TransactionModelToResponse(current), &tRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	if tRequest.Timestamp.IsZero() {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredTimestamp)
+		return
+	}
+
+	tags, errMsg := normalizeTags(tRequest.Tags)
+	if errMsg != nil {
+		ctx.JSON(http.StatusBadRequest, errMsg)
+		return
+	}
+	tRequest.Tags = tags
+
+	if !isValidStatus(tRequest.Status) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidStatus)
 		return
 	}
 
 	tModel := TransactionRequestToModel(&tRequest, userID)
 
-	// Validate wallet ownership
-	if tModel.WalletID != 0 {
-		wallet, err := h.repo.WalletGet(tModel.WalletID)
+	// Validate ownership of the wallet the transaction is moved to
+	if tModel.WalletID == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredWalletID)
+		return
+	}
+
+	tModel.HouseholdID = current.HouseholdID
+	var wallet *model.Wallet
+	if tModel.WalletID != current.WalletID {
+		wallet, err = h.repo(ctx).WalletGet(tModel.WalletID)
 		if err != nil {
 			if err == repository.ErrorRecordNotFound {
 				ctx.JSON(http.StatusBadRequest, ErrorWalletNotFound)
@@ -137,15 +259,43 @@ func (h *handler) UpdateTransaction(ctx *gin.Context) {
 			return
 		}
 
-		if wallet.UserID != userID {
+		allowed, err := permissions.Check(h.repo(ctx), userID, wallet.UserID, wallet.HouseholdID, permissions.RoleEditor)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
 			ctx.JSON(http.StatusForbidden, ErrorBadWalletID)
 			return
 		}
+		tModel.HouseholdID = wallet.HouseholdID
+	}
+
+	// Validate the amount against the currency of the wallet the transaction ends up in
+	if wallet != nil || !tModel.Amount.Equal(current.Amount) {
+		if wallet == nil {
+			if wallet, err = h.repo(ctx).WalletGet(current.WalletID); err != nil {
+				ctx.Status(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if err := currency.ValidateAmount(WalletCurrency(wallet), tModel.Amount); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+			return
+		}
 	}
 
-	// Validate party ownership
-	if tModel.PartyID != 0 {
-		party, err := h.repo.PartyGet(tModel.PartyID)
+	// Validate ownership of the new party
+	if tModel.PartyID == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorRequiredPartyID)
+		return
+	}
+
+	// The party has to be one of the household the transaction ends up in
+	if tModel.PartyID != current.PartyID || !sameHousehold(tModel.HouseholdID, current.HouseholdID) {
+		party, err := h.repo(ctx).PartyGet(tModel.PartyID)
 		if err != nil {
 			if err == repository.ErrorRecordNotFound {
 				ctx.JSON(http.StatusBadRequest, ErrorPartyNotFound)
@@ -155,26 +305,60 @@ func (h *handler) UpdateTransaction(ctx *gin.Context) {
 			return
 		}
 
-		if party.UserID != userID {
+		allowed, err := permissions.Check(h.repo(ctx), userID, party.UserID, party.HouseholdID, permissions.RoleViewer)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
 			ctx.JSON(http.StatusForbidden, ErrorBadPartyID)
 			return
 		}
+
+		if !sameHousehold(party.HouseholdID, tModel.HouseholdID) {
+			ctx.JSON(http.StatusBadRequest, ErrorPartyHousehold)
+			return
+		}
 	}
 
-	updatedTModel, err := h.repo.TransactionUpdate(id, tModel)
+	tModel.Version = current.Version
+	stamps := h.historyStamps(ctx, current.UserID)
+	updatedTModel, err := h.repo(ctx).TransactionUpdate(id, tModel)
 	if err != nil {
+		if err == repository.ErrorSplitsOutOfBalance {
+			ctx.JSON(http.StatusConflict, ErrorSplitsOutOfBalance)
+			return
+		}
+		if err == repository.ErrorSharesOutOfBalance {
+			ctx.JSON(http.StatusConflict, ErrorSharesOutOfBalance)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	h.learnCategories(ctx, stamps, categoryChange{before: current, after: updatedTModel})
 
+	ctx.Header("ETag", ETag(updatedTModel.Version))
 	tResponse := TransactionModelToResponse(updatedTModel)
 	ctx.JSON(http.StatusOK, tResponse)
 }
 
+// DeleteTransaction moves the transaction to the trash, its attachments are kept until it is purged
 func (h *handler) DeleteTransaction(ctx *gin.Context) {
 	id := middleware.GetIDParamFromContext(ctx)
+	current := transactions_middleware.GetTransactionFromContext(ctx)
 
-	if err := h.repo.TransactionDelete(id); err != nil {
+	if !checkIfMatch(ctx, current.Version) || !allowChange(ctx, current) {
+		return
+	}
+
+	stamps := h.historyStamps(ctx, current.UserID)
+	if err := h.repo(ctx).TransactionDelete(id); err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
 			return
@@ -182,6 +366,7 @@ func (h *handler) DeleteTransaction(ctx *gin.Context) {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
+	h.learnCategories(ctx, stamps, categoryChange{before: current})
 
 	ctx.Status(http.StatusNoContent)
 }
@@ -193,7 +378,7 @@ func (h *handler) ListTransactions(ctx *gin.Context) {
 		return
 	}
 
-	tModels, err := h.repo.TransactionList(userID)
+	tModels, err := h.repo(ctx).TransactionList(userID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		return
@@ -207,3 +392,11 @@ func (h *handler) ListTransactions(ctx *gin.Context) {
 	res := NewListResponse(tResponse)
 	ctx.JSON(http.StatusOK, res)
 }
+
+// sameHousehold is whether both are the same household, or both are personal
+func sameHousehold(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
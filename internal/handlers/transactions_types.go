@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"expense-api/internal/model"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/shopspring/decimal"
 )
@@ -17,6 +19,14 @@ type Transaction struct {
 	Timestamp   time.Time       `json:"timestamp"`
 	Amount      decimal.Decimal `json:"amount"`
 	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Tags        []string        `json:"tags"`
+	Status      string          `json:"status"`
+	HouseholdID uint            `json:"household_id"`
+	// Suggestions are the likely categories of a new transaction created without one
+	Suggestions []*CategorySuggestion `json:"suggestions,omitempty"`
+	// Warning is set on a new transaction that is likely a duplicate
+	Warning *DuplicateWarning `json:"warning,omitempty"`
 }
 
 func TransactionModelToResponse(t *model.Transaction) *Transaction {
@@ -29,6 +39,10 @@ func TransactionModelToResponse(t *model.Transaction) *Transaction {
 		Timestamp:   t.Timestamp,
 		Amount:      t.Amount,
 		Description: t.Description,
+		Category:    t.Category,
+		Tags:        tagsToResponse(t.Tags),
+		Status:      t.Status,
+		HouseholdID: householdIDToResponse(t.HouseholdID),
 	}
 }
 
@@ -37,8 +51,50 @@ func TransactionRequestToModel(t *Transaction, userID uint) *model.Transaction {
 		Amount:      t.Amount,
 		Timestamp:   t.Timestamp,
 		Description: t.Description,
+		Category:    t.Category,
+		Tags:        t.Tags,
+		Status:      t.Status,
 		WalletID:    t.WalletID,
 		PartyID:     t.PartyID,
 		UserID:      userID,
 	}
 }
+
+// isValidStatus tells whether the user may set the status of a transaction to status. Transactions
+// only become reconciled by reconciling their wallet.
+func isValidStatus(status string) bool {
+	return status == model.TransactionUncleared || status == model.TransactionCleared
+}
+
+// maxTagLength is the longest tag a transaction can have
+const maxTagLength = 50
+
+// normalizeTags lowercases and trims tags and drops duplicates. A nil slice stays nil, so updates
+// can tell "leave the tags alone" from "remove all tags".
+func normalizeTags(tags []string) ([]string, *ErrorMessage) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, ErrorInvalidTag
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+func tagsToResponse(tags model.Tags) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
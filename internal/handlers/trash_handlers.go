@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler interface {
+	ListTrash(ctx *gin.Context)
+	RestoreWallet(ctx *gin.Context)
+	RestoreParty(ctx *gin.Context)
+	RestoreTransaction(ctx *gin.Context)
+}
+
+// ListTrash lists the deleted wallets, parties and transactions the user can see, the most recently
+// deleted first
+func (h *handler) ListTrash(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	wallets, err := h.repo(ctx).WalletListTrashed(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	parties, err := h.repo(ctx).PartyListTrashed(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	transactions, err := h.repo(ctx).TransactionListTrashed(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, NewTrash(wallets, parties, transactions))
+}
+
+// RestoreWallet takes the wallet out of the trash together with the transactions deleted with it
+func (h *handler) RestoreWallet(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	trashed, err := h.repo(ctx).WalletGetTrashed(id)
+	if err != nil {
+		respondTrashError(ctx, err)
+		return
+	}
+
+	if !h.allowRestore(ctx, trashed.UserID, trashed.HouseholdID) {
+		return
+	}
+
+	wModel, err := h.repo(ctx).WalletRestore(id)
+	if err != nil {
+		if err == repository.ErrorUniqueConstaintViolation {
+			ctx.JSON(http.StatusConflict, ErrorWalletNameTaken)
+			return
+		}
+		respondTrashError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", ETag(wModel.Version))
+	wResponse := WalletModelToResponse(wModel)
+	if err := h.setAvailableCredit(ctx, []*model.Wallet{wModel}, []*Wallet{wResponse}); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	ctx.JSON(http.StatusOK, wResponse)
+}
+
+// RestoreParty takes the party out of the trash together with the transactions deleted with it
+func (h *handler) RestoreParty(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	trashed, err := h.repo(ctx).PartyGetTrashed(id)
+	if err != nil {
+		respondTrashError(ctx, err)
+		return
+	}
+
+	if !h.allowRestore(ctx, trashed.UserID, trashed.HouseholdID) {
+		return
+	}
+
+	pModel, err := h.repo(ctx).PartyRestore(id)
+	if err != nil {
+		if err == repository.ErrorUniqueConstaintViolation {
+			ctx.JSON(http.StatusConflict, ErrorPartyNameTaken)
+			return
+		}
+		respondTrashError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", ETag(pModel.Version))
+	ctx.JSON(http.StatusOK, PartyModelToResponse(pModel))
+}
+
+// RestoreTransaction takes the transaction out of the trash, as long as its wallet and party aren't
+// in the trash themselves
+func (h *handler) RestoreTransaction(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	trashed, err := h.repo(ctx).TransactionGetTrashed(id)
+	if err != nil {
+		respondTrashError(ctx, err)
+		return
+	}
+
+	if !h.allowRestore(ctx, trashed.UserID, trashed.HouseholdID) {
+		return
+	}
+
+	stamps := h.historyStamps(ctx, trashed.UserID)
+	tModel, err := h.repo(ctx).TransactionRestore(id)
+	if err != nil {
+		if err == repository.ErrorParentTrashed {
+			ctx.JSON(http.StatusConflict, ErrorParentTrashed)
+			return
+		}
+		respondTrashError(ctx, err)
+		return
+	}
+	h.learnCategories(ctx, stamps, categoryChange{after: tModel})
+
+	ctx.Header("ETag", ETag(tModel.Version))
+	ctx.JSON(http.StatusOK, TransactionModelToResponse(tModel))
+}
+
+// allowRestore checks that the user may edit what they want to restore, or responds with 403
+func (h *handler) allowRestore(ctx *gin.Context, ownerID uint, householdID *uint) bool {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return false
+	}
+
+	allowed, err := permissions.Check(h.repo(ctx), userID, ownerID, householdID, permissions.RoleEditor)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return false
+	}
+
+	if !allowed {
+		ctx.Status(http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func respondTrashError(ctx *gin.Context, err error) {
+	if err == repository.ErrorRecordNotFound {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+	ctx.Status(http.StatusInternalServerError)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"expense-api/internal/model"
+	"time"
+)
+
+// Trash lists the deleted wallets, parties and transactions that can still be restored
+type Trash struct {
+	Wallets      []*TrashedWallet      `json:"wallets"`
+	Parties      []*TrashedParty       `json:"parties"`
+	Transactions []*TrashedTransaction `json:"transactions"`
+}
+
+type TrashedWallet struct {
+	*Wallet
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedParty struct {
+	*Party
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashedTransaction struct {
+	*Transaction
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func NewTrash(wallets []*model.Wallet, parties []*model.Party, transactions []*model.Transaction) *Trash {
+	trash := &Trash{
+		Wallets:      make([]*TrashedWallet, 0, len(wallets)),
+		Parties:      make([]*TrashedParty, 0, len(parties)),
+		Transactions: make([]*TrashedTransaction, 0, len(transactions)),
+	}
+
+	for _, w := range wallets {
+		trash.Wallets = append(trash.Wallets, &TrashedWallet{WalletModelToResponse(w), w.DeletedAt.Time})
+	}
+	for _, p := range parties {
+		trash.Parties = append(trash.Parties, &TrashedParty{PartyModelToResponse(p), p.DeletedAt.Time})
+	}
+	for _, t := range transactions {
+		trash.Transactions = append(trash.Transactions, &TrashedTransaction{TransactionModelToResponse(t), t.DeletedAt.Time})
+	}
+	return trash
+}
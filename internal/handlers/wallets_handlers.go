@@ -1,12 +1,18 @@
 package handlers
 
 import (
+	"expense-api/internal/currency"
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	wallets_middleware "expense-api/internal/middleware/wallets"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 type WalletsHandler interface {
@@ -32,8 +38,32 @@ func (h *handler) CreateWallet(ctx *gin.Context) {
 	}
 
 	wModel := WalletRequestToModel(&wRequest, userID)
+	if wModel.Type == "" {
+		wModel.Type = model.WalletChecking
+	}
+
+	householdID, allowed, err := h.checkHousehold(ctx, userID, wRequest.HouseholdID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, ErrorBadHouseholdID)
+		return
+	}
+	wModel.HouseholdID = householdID
+
+	if wModel.Currency != "" && !currency.IsValid(wModel.Currency) {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidCurrency)
+		return
+	}
 
-	if err := h.repo.WalletCreate(wModel); err != nil {
+	if !validateWallet(ctx, wModel) {
+		return
+	}
+
+	if err := h.repo(ctx).WalletCreate(wModel); err != nil {
 		if err == repository.ErrorUniqueConstaintViolation {
 			ctx.JSON(http.StatusConflict, ErrorWalletNameTaken)
 			return
@@ -42,10 +72,13 @@ func (h *handler) CreateWallet(ctx *gin.Context) {
 		return
 	}
 
+	// A new wallet has no transactions yet
 	wResponse := WalletModelToResponse(wModel)
+	wResponse.AvailableCredit = availableCredit(wModel, decimal.Zero)
 	ctx.JSON(http.StatusCreated, wResponse)
 }
 
+// UpdateWallet applies a JSON Merge Patch to the wallet
 func (h *handler) UpdateWallet(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -54,15 +87,46 @@ func (h *handler) UpdateWallet(ctx *gin.Context) {
 	}
 
 	id := middleware.GetIDParamFromContext(ctx)
+	current := wallets_middleware.GetWalletFromContext(ctx)
+
+	if !checkIfMatch(ctx, current.Version) {
+		return
+	}
 
 	var wRequest Wallet
-	if err := ctx.Bind(&wRequest); err != nil {
-		ctx.Status(http.StatusBadRequest)
+	if err := bindPatch(ctx, WalletModelToResponse(current), &wRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
 		return
 	}
 
 	wModel := WalletRequestToModel(&wRequest, userID)
-	updatedWModel, err := h.repo.WalletUpdate(id, wModel)
+
+	if wModel.Currency != current.Currency {
+		ctx.JSON(http.StatusBadRequest, ErrorWalletCurrencyChange)
+		return
+	}
+
+	wModel.HouseholdID = current.HouseholdID
+	if wRequest.HouseholdID != householdIDToResponse(current.HouseholdID) {
+		householdID, allowed, err := h.checkHousehold(ctx, userID, wRequest.HouseholdID)
+		if err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, ErrorBadHouseholdID)
+			return
+		}
+		wModel.HouseholdID = householdID
+	}
+
+	if !validateWallet(ctx, wModel) {
+		return
+	}
+
+	wModel.Version = current.Version
+	updatedWModel, err := h.repo(ctx).WalletUpdate(id, wModel)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
@@ -72,22 +136,84 @@ func (h *handler) UpdateWallet(ctx *gin.Context) {
 			ctx.JSON(http.StatusConflict, ErrorWalletNameTaken)
 			return
 		}
+		if err == repository.ErrorPartyHousehold {
+			ctx.JSON(http.StatusConflict, ErrorWalletForeignParties)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
 
+	ctx.Header("ETag", ETag(updatedWModel.Version))
 	wResponse := WalletModelToResponse(updatedWModel)
+	if err := h.setAvailableCredit(ctx, []*model.Wallet{updatedWModel}, []*Wallet{wResponse}); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
 	ctx.JSON(http.StatusOK, wResponse)
 }
 
+// DeleteWallet moves the wallet to the trash. A wallet that still has transactions is only deleted
+// with 'cascade=true', which moves its transactions to the trash as well, or with 'reassign_to' set
+// to the wallet its transactions should be moved to.
 func (h *handler) DeleteWallet(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
 	id := middleware.GetIDParamFromContext(ctx)
+	current := wallets_middleware.GetWalletFromContext(ctx)
+
+	if !checkIfMatch(ctx, current.Version) {
+		return
+	}
+
+	cascade, _ := strconv.ParseBool(ctx.Query("cascade"))
+
+	if value := ctx.Query("reassign_to"); value != "" {
+		if cascade {
+			ctx.JSON(http.StatusBadRequest, ErrorCascadeAndReassign)
+			return
+		}
+
+		var targetID uint64
+		targetID, err = strconv.ParseUint(value, 10, 32)
+		if err != nil || targetID == 0 || uint(targetID) == id {
+			ctx.JSON(http.StatusBadRequest, ErrorBadReassignID)
+			return
+		}
+
+		if !h.checkReassignTarget(ctx, userID, current, uint(targetID)) {
+			return
+		}
+		err = h.repo(ctx).WalletReassign(id, uint(targetID))
+	} else {
+		err = h.repo(ctx).WalletDelete(id, cascade)
+	}
 
-	if err := h.repo.WalletDelete(id); err != nil {
+	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
 			return
 		}
+		if err == repository.ErrorWalletNotEmpty {
+			ctx.JSON(http.StatusConflict, ErrorWalletHasTransactions)
+			return
+		}
+		if err == repository.ErrorHasReconciled {
+			ctx.JSON(http.StatusConflict, ErrorWalletReconciled)
+			return
+		}
+		if err == repository.ErrorPartyHousehold {
+			ctx.JSON(http.StatusConflict, ErrorWalletForeignParties)
+			return
+		}
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
@@ -95,10 +221,40 @@ func (h *handler) DeleteWallet(ctx *gin.Context) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// checkReassignTarget makes sure the transactions of the wallet can be moved to the target wallet
+func (h *handler) checkReassignTarget(ctx *gin.Context, userID uint, wallet *model.Wallet, targetID uint) bool {
+	target, err := h.repo(ctx).WalletGet(targetID)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.JSON(http.StatusBadRequest, ErrorWalletNotFound)
+			return false
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return false
+	}
+
+	allowed, err := permissions.Check(h.repo(ctx), userID, target.UserID, target.HouseholdID, permissions.RoleEditor)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return false
+	}
+
+	if !allowed {
+		ctx.JSON(http.StatusForbidden, ErrorBadWalletID)
+		return false
+	}
+
+	if WalletCurrency(target) != WalletCurrency(wallet) {
+		ctx.JSON(http.StatusBadRequest, ErrorReassignCurrency)
+		return false
+	}
+	return true
+}
+
 func (h *handler) GetWallet(ctx *gin.Context) {
 	id := middleware.GetIDParamFromContext(ctx)
 
-	wModel, err := h.repo.WalletGet(id)
+	wModel, err := h.repo(ctx).WalletGet(id)
 	if err != nil {
 		if err == repository.ErrorRecordNotFound {
 			ctx.Status(http.StatusNotFound)
@@ -109,9 +265,24 @@ func (h *handler) GetWallet(ctx *gin.Context) {
 	}
 
 	wResponse := WalletModelToResponse(wModel)
+	if err := h.setAvailableCredit(ctx, []*model.Wallet{wModel}, []*Wallet{wResponse}); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	// The available credit changes with the transactions of the wallet, not with the wallet itself
+	etag := ETag(wModel.Version)
+	if wResponse.AvailableCredit != nil {
+		etag = DerivedETag(wModel.Version, wResponse.AvailableCredit.String())
+	}
+	if notModified(ctx, etag) {
+		return
+	}
+
 	ctx.JSON(http.StatusOK, wResponse)
 }
 
+// ListWallets lists the wallets in display order, without the archived ones unless 'include_archived=true'
 func (h *handler) ListWallets(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -119,18 +290,30 @@ func (h *handler) ListWallets(ctx *gin.Context) {
 		return
 	}
 
-	wModels, err := h.repo.WalletList(userID)
+	includeArchived, _ := strconv.ParseBool(ctx.Query("include_archived"))
+
+	wModels, err := h.repo(ctx).WalletList(userID)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		return
 	}
 
+	listed := make([]*model.Wallet, 0, len(wModels))
 	wResponse := make([]*Wallet, 0, len(wModels))
 
 	for _, w := range wModels {
+		if w.Archived && !includeArchived {
+			continue
+		}
+		listed = append(listed, w)
 		wResponse = append(wResponse, WalletModelToResponse(w))
 	}
 
+	if err := h.setAvailableCredit(ctx, listed, wResponse); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
 	res := NewListResponse(wResponse)
 	ctx.JSON(http.StatusOK, res)
 }
@@ -144,7 +327,7 @@ func (h *handler) ListTransactionsByWallet(ctx *gin.Context) {
 
 	id := middleware.GetIDParamFromContext(ctx)
 
-	tModels, err := h.repo.TransactionListByWallet(userID, id)
+	tModels, err := h.repo(ctx).TransactionListByWallet(userID, id)
 	if err != nil {
 		ctx.Status(http.StatusInternalServerError)
 		return
@@ -159,3 +342,76 @@ func (h *handler) ListTransactionsByWallet(ctx *gin.Context) {
 	res := NewListResponse(tResponse)
 	ctx.JSON(http.StatusOK, res)
 }
+
+// validateWallet checks the type, opening balance, credit limit and budget of a new or patched wallet
+func validateWallet(ctx *gin.Context, w *model.Wallet) bool {
+	if w.Name == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorWalletName)
+		return false
+	}
+
+	if !walletTypes[w.Type] {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidWalletType)
+		return false
+	}
+
+	if err := currency.ValidateAmount(WalletCurrency(w), w.OpeningBalance); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+		return false
+	}
+
+	if w.CreditLimit != nil {
+		if w.Type != model.WalletCreditCard {
+			ctx.JSON(http.StatusBadRequest, ErrorCreditLimitType)
+			return false
+		}
+
+		if w.CreditLimit.IsNegative() {
+			ctx.JSON(http.StatusBadRequest, ErrorNegativeCreditLimit)
+			return false
+		}
+
+		if err := currency.ValidateAmount(WalletCurrency(w), *w.CreditLimit); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+			return false
+		}
+	}
+
+	if w.MonthlyBudget != nil {
+		if !w.MonthlyBudget.IsPositive() {
+			ctx.JSON(http.StatusBadRequest, ErrorMonthlyBudget)
+			return false
+		}
+
+		if err := currency.ValidateAmount(WalletCurrency(w), *w.MonthlyBudget); err != nil {
+			ctx.JSON(http.StatusBadRequest, ErrorAmountPrecision)
+			return false
+		}
+	}
+	return true
+}
+
+// setAvailableCredit fills in the available credit of the responses of the wallets that have a
+// credit limit. The responses are in the same order as the wallets.
+func (h *handler) setAvailableCredit(ctx *gin.Context, wallets []*model.Wallet, responses []*Wallet) error {
+	var ids []uint
+	for _, w := range wallets {
+		if w.CreditLimit != nil {
+			ids = append(ids, w.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	sums, err := h.repo(ctx).TransactionSumByWallet(ids)
+	if err != nil {
+		return err
+	}
+
+	for i, w := range wallets {
+		responses[i].AvailableCredit = availableCredit(w, sums[w.ID])
+	}
+	return nil
+}
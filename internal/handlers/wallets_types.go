@@ -1,33 +1,92 @@
 package handlers
 
 import (
+	"expense-api/internal/currency"
 	"expense-api/internal/model"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // Wallet is a list of transactions belonging to an account
 type Wallet struct {
-	ID          uint      `json:"id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
+	ID             uint             `json:"id"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Name           string           `json:"name"`
+	Description    string           `json:"description"`
+	Currency       string           `json:"currency"`
+	Type           string           `json:"type"`
+	OpeningBalance decimal.Decimal  `json:"opening_balance"`
+	OpeningDate    *time.Time       `json:"opening_date"`
+	CreditLimit    *decimal.Decimal `json:"credit_limit"`
+	MonthlyBudget  *decimal.Decimal `json:"monthly_budget"`
+	// AvailableCredit is the credit limit plus the balance of a card wallet, it is never read from requests
+	AvailableCredit *decimal.Decimal `json:"available_credit,omitempty"`
+	Archived        bool             `json:"archived"`
+	DisplayOrder    int              `json:"display_order"`
+	HouseholdID     uint             `json:"household_id"`
 }
 
 func WalletModelToResponse(w *model.Wallet) *Wallet {
 	return &Wallet{
-		ID:          w.ID,
-		CreatedAt:   w.CreatedAt,
-		UpdatedAt:   w.UpdatedAt,
-		Name:        w.Name,
-		Description: w.Description,
+		ID:             w.ID,
+		CreatedAt:      w.CreatedAt,
+		UpdatedAt:      w.UpdatedAt,
+		Name:           w.Name,
+		Description:    w.Description,
+		Currency:       w.Currency,
+		Type:           w.Type,
+		OpeningBalance: w.OpeningBalance,
+		OpeningDate:    w.OpeningDate,
+		CreditLimit:    w.CreditLimit,
+		MonthlyBudget:  w.MonthlyBudget,
+		Archived:       w.Archived,
+		DisplayOrder:   w.DisplayOrder,
+		HouseholdID:    householdIDToResponse(w.HouseholdID),
 	}
 }
 
 func WalletRequestToModel(w *Wallet, userID uint) *model.Wallet {
 	return &model.Wallet{
-		Name:        w.Name,
-		Description: w.Description,
-		UserID:      userID,
+		Name:           w.Name,
+		Description:    w.Description,
+		Currency:       currency.Normalize(w.Currency),
+		Type:           w.Type,
+		OpeningBalance: w.OpeningBalance,
+		OpeningDate:    w.OpeningDate,
+		CreditLimit:    w.CreditLimit,
+		MonthlyBudget:  w.MonthlyBudget,
+		Archived:       w.Archived,
+		DisplayOrder:   w.DisplayOrder,
+		UserID:         userID,
+	}
+}
+
+// WalletCurrency returns the wallet's currency, falling back to the default for wallets created before currencies existed
+func WalletCurrency(w *model.Wallet) string {
+	if w.Currency == "" {
+		return currency.Default
+	}
+	return w.Currency
+}
+
+var walletTypes = map[string]bool{
+	model.WalletCash:       true,
+	model.WalletChecking:   true,
+	model.WalletSavings:    true,
+	model.WalletCreditCard: true,
+	model.WalletLoan:       true,
+	model.WalletInvestment: true,
+}
+
+// availableCredit is how much can still be spent with a card wallet whose transactions add up to sum.
+// Wallets without a credit limit have none.
+func availableCredit(w *model.Wallet, sum decimal.Decimal) *decimal.Decimal {
+	if w.CreditLimit == nil {
+		return nil
 	}
+
+	available := w.CreditLimit.Add(w.OpeningBalance).Add(sum)
+	return &available
 }
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	webhooks_middleware "expense-api/internal/middleware/webhooks"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/webhooks"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhooksHandler interface {
+	ListWebhooks(ctx *gin.Context)
+	CreateWebhook(ctx *gin.Context)
+	GetWebhook(ctx *gin.Context)
+	UpdateWebhook(ctx *gin.Context)
+	DeleteWebhook(ctx *gin.Context)
+	ListWebhookDeliveries(ctx *gin.Context)
+	ReplayWebhookDelivery(ctx *gin.Context)
+}
+
+// webhookEvents can be subscribed to, besides the wildcards
+var webhookEvents = map[string]bool{
+	model.EventTransactionCreated:  true,
+	model.EventTransactionUpdated:  true,
+	model.EventTransactionDeleted:  true,
+	model.EventTransactionRestored: true,
+	model.EventWalletCreated:       true,
+	model.EventWalletUpdated:       true,
+	model.EventWalletDeleted:       true,
+	model.EventWalletRestored:      true,
+	model.EventBudgetExceeded:      true,
+	"transaction.*":                true,
+	"wallet.*":                     true,
+	"budget.*":                     true,
+	"*":                            true,
+}
+
+const webhookSecretBytes = 32
+
+func (h *handler) ListWebhooks(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	wModels, err := h.repo(ctx).WebhookList(userID)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	wResponse := make([]*Webhook, 0, len(wModels))
+	for _, w := range wModels {
+		wResponse = append(wResponse, WebhookModelToResponse(w))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(wResponse))
+}
+
+// CreateWebhook creates an enabled webhook. The response is the only one that shows the secret.
+func (h *handler) CreateWebhook(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	var wRequest WebhookRequest
+	if err := ctx.Bind(&wRequest); err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	wModel := WebhookRequestToModel(&wRequest, &model.Webhook{UserID: userID, Enabled: true})
+	if !validateWebhook(ctx, wModel) {
+		return
+	}
+
+	if wModel.Secret == "" {
+		if wModel.Secret, err = generateWebhookSecret(); err != nil {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := h.repo(ctx).WebhookCreate(wModel); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	wResponse := WebhookModelToResponse(wModel)
+	wResponse.Secret = wModel.Secret
+	ctx.JSON(http.StatusCreated, wResponse)
+}
+
+func (h *handler) GetWebhook(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, WebhookModelToResponse(webhooks_middleware.GetWebhookFromContext(ctx)))
+}
+
+// UpdateWebhook applies a JSON Merge Patch to the webhook. The secret only changes if the patch
+// sets a new one.
+func (h *handler) UpdateWebhook(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+	current := webhooks_middleware.GetWebhookFromContext(ctx)
+
+	var wRequest WebhookRequest
+	if err := bindPatch(ctx, WebhookModelToResponse(current), &wRequest); err != nil {
+		ctx.JSON(http.StatusBadRequest, ErrorInvalidPatch)
+		return
+	}
+
+	wModel := WebhookRequestToModel(&wRequest, &model.Webhook{UserID: current.UserID, Enabled: current.Enabled})
+	if !validateWebhook(ctx, wModel) {
+		return
+	}
+	if wModel.Secret == "" {
+		wModel.Secret = current.Secret
+	}
+
+	wModel.Version = current.Version
+	updatedWModel, err := h.repo(ctx).WebhookUpdate(id, wModel)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		if err == repository.ErrorVersionConflict {
+			ctx.JSON(http.StatusConflict, ErrorConcurrentUpdate)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, WebhookModelToResponse(updatedWModel))
+}
+
+// DeleteWebhook deletes the webhook together with its deliveries
+func (h *handler) DeleteWebhook(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	if err := h.repo(ctx).WebhookDelete(id); err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries is the delivery log of the webhook, the newest first
+func (h *handler) ListWebhookDeliveries(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	dModels, err := h.repo(ctx).WebhookDeliveryList(id)
+	if err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	dResponse := make([]*WebhookDelivery, 0, len(dModels))
+	for _, d := range dModels {
+		dResponse = append(dResponse, WebhookDeliveryModelToResponse(d))
+	}
+
+	ctx.JSON(http.StatusOK, NewListResponse(dResponse))
+}
+
+// ReplayWebhookDelivery queues the event of a delivery to be sent once more, as a new delivery with
+// the same event ID and payload
+func (h *handler) ReplayWebhookDelivery(ctx *gin.Context) {
+	id := middleware.GetIDParamFromContext(ctx)
+
+	deliveryID, err := strconv.Atoi(ctx.Param("delivery_id"))
+	if err != nil || deliveryID <= 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorBadDeliveryID)
+		return
+	}
+
+	dModel, err := h.repo(ctx).WebhookDeliveryGet(uint(deliveryID))
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.Status(http.StatusNotFound)
+			return
+		}
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	if dModel.WebhookID != id {
+		ctx.Status(http.StatusNotFound)
+		return
+	}
+
+	replay := &model.WebhookDelivery{
+		WebhookID:     id,
+		EventID:       dModel.EventID,
+		Event:         dModel.Event,
+		Payload:       dModel.Payload,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+		ReplayOfID:    &dModel.ID,
+	}
+	if err := h.repo(ctx).WebhookDeliveryCreate(replay); err != nil {
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, WebhookDeliveryModelToResponse(replay))
+}
+
+// validateWebhook only accepts webhooks that can be reached over http(s) and subscribe to at least
+// one known event. URLs of local or private addresses are refused right away; names are checked when
+// deliveries are sent, once they are resolved.
+func validateWebhook(ctx *gin.Context, w *model.Webhook) bool {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		ctx.JSON(http.StatusBadRequest, ErrorWebhookURL)
+		return false
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !webhooks.IsPublicAddress(addr) || strings.EqualFold(u.Hostname(), "localhost") {
+		ctx.JSON(http.StatusBadRequest, ErrorWebhookURL)
+		return false
+	}
+
+	if len(w.Events) == 0 {
+		ctx.JSON(http.StatusBadRequest, ErrorWebhookEvents)
+		return false
+	}
+	for _, event := range w.Events {
+		if !webhookEvents[event] {
+			ctx.JSON(http.StatusBadRequest, ErrorWebhookEvents)
+			return false
+		}
+	}
+	return true
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
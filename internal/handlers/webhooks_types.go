@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"expense-api/internal/model"
+	"time"
+)
+
+// Webhook sends events about the user's wallets and transactions to a URL. The secret deliveries are
+// signed with is only shown when the webhook is created.
+type Webhook struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	Secret    string    `json:"secret,omitempty"`
+}
+
+// WebhookRequest creates a webhook or is the result of patching one. A secret is generated if it
+// is missing.
+type WebhookRequest struct {
+	URL     string   `json:"url"`
+	Events  []string `json:"events"`
+	Enabled *bool    `json:"enabled"`
+	Secret  string   `json:"secret"`
+}
+
+// WebhookDelivery is an event sent, or still to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             uint            `json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	ResponseStatus int             `json:"response_status"`
+	Error          string          `json:"error"`
+	ReplayOfID     *uint           `json:"replay_of_id"`
+}
+
+func WebhookModelToResponse(w *model.Webhook) *Webhook {
+	return &Webhook{
+		ID:        w.ID,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+		URL:       w.URL,
+		Events:    tagsToResponse(w.Events),
+		Enabled:   w.Enabled,
+	}
+}
+
+func WebhookRequestToModel(w *WebhookRequest, m *model.Webhook) *model.Webhook {
+	m.URL = w.URL
+	m.Events = w.Events
+	m.Secret = w.Secret
+	if w.Enabled != nil {
+		m.Enabled = *w.Enabled
+	}
+	return m
+}
+
+// WebhookDeliveryModelToResponse leaves out when the next attempt is due once the delivery is done
+func WebhookDeliveryModelToResponse(d *model.WebhookDelivery) *WebhookDelivery {
+	response := &WebhookDelivery{
+		ID:             d.ID,
+		CreatedAt:      d.CreatedAt,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		ReplayOfID:     d.ReplayOfID,
+	}
+	if d.Status == model.WebhookDeliveryPending {
+		response.NextAttemptAt = &d.NextAttemptAt
+	}
+	return response
+}
@@ -0,0 +1,49 @@
+// Package mergepatch applies JSON Merge Patches (RFC 7396), the format of the API's PATCH requests
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrorInvalidJSON = errors.New("document or patch is not valid JSON")
+
+// Apply returns doc with patch applied. Members of a patch object replace the members of the
+// document with the same name, except for objects, which are merged recursively, and null, which
+// removes the member. A patch that isn't an object replaces the whole document.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrorInvalidJSON
+	}
+
+	var d interface{}
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &d); err != nil {
+			return nil, ErrorInvalidJSON
+		}
+	}
+
+	return json.Marshal(merge(d, p))
+}
+
+func merge(doc, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	d, ok := doc.(map[string]interface{})
+	if !ok {
+		d = map[string]interface{}{}
+	}
+
+	for name, value := range p {
+		if value == nil {
+			delete(d, name)
+		} else {
+			d[name] = merge(d[name], value)
+		}
+	}
+	return d
+}
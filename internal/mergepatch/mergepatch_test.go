@@ -0,0 +1,57 @@
+package mergepatch_test
+
+import (
+	"encoding/json"
+	"expense-api/internal/mergepatch"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// The examples of RFC 7396, appendix A
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.doc+" + "+tc.patch, func(t *testing.T) {
+			got, err := mergepatch.Apply([]byte(tc.doc), []byte(tc.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gotValue, expectedValue interface{}
+			json.Unmarshal(got, &gotValue)
+			json.Unmarshal([]byte(tc.expected), &expectedValue)
+
+			if !cmp.Equal(gotValue, expectedValue) {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestApplyInvalidJSON(t *testing.T) {
+	if _, err := mergepatch.Apply([]byte(`{}`), []byte(`{"a":`)); err != mergepatch.ErrorInvalidJSON {
+		t.Errorf("expected %v, got %v", mergepatch.ErrorInvalidJSON, err)
+	}
+}
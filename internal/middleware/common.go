@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"net/http"
 	"strconv"
 
@@ -11,8 +13,16 @@ const ErrorMesageBadID = "missing/not-a-number ID in request"
 
 const idKey = "id"
 
+const (
+	HeaderRequestID    = "X-Request-ID"
+	MaxRequestIDLength = 128
+
+	requestIDKey = "request_id"
+)
+
 type CommonMiddleware interface {
 	SetIDParamToContext(ctx *gin.Context)
+	SetRequestID(ctx *gin.Context)
 }
 
 type commonMiddleware struct{}
@@ -38,3 +48,26 @@ func GetIDParamFromContext(ctx *gin.Context) uint {
 	id, _ := ctx.Get(idKey)
 	return id.(uint)
 }
+
+// SetRequestID identifies the request by its X-Request-ID header, or a random ID if it has none, and
+// sends the ID back in the same header
+func (c *commonMiddleware) SetRequestID(ctx *gin.Context) {
+	id := ctx.GetHeader(HeaderRequestID)
+	if id == "" || len(id) > MaxRequestIDLength {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		id = hex.EncodeToString(b)
+	}
+
+	ctx.Set(requestIDKey, id)
+	ctx.Header(HeaderRequestID, id)
+	ctx.Next()
+}
+
+// GetRequestIDFromContext returns the ID of the request, empty if SetRequestID didn't run
+func GetRequestIDFromContext(ctx *gin.Context) string {
+	return ctx.GetString(requestIDKey)
+}
@@ -0,0 +1,47 @@
+package exchangerate
+
+import (
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRatesMiddleware interface {
+	ValidateAdmin(*gin.Context)
+}
+
+type exchangeRatesMiddleware struct {
+	repo repository.Repository
+}
+
+func New(repo repository.Repository) ExchangeRatesMiddleware {
+	return &exchangeRatesMiddleware{repo}
+}
+
+// ValidateAdmin only lets administrators change the exchange rates, which are shared between all users
+func (e *exchangeRatesMiddleware) ValidateAdmin(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	user, err := e.repo.UserGet(userID)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if !user.Admin {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	ctx.Next()
+}
@@ -0,0 +1,63 @@
+package household
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const roleKey = "householdRole"
+
+type HouseholdsMiddleware interface {
+	ValidateMembership(role string) gin.HandlerFunc
+}
+
+type householdsMiddleware struct {
+	repo repository.Repository
+}
+
+func New(repo repository.Repository) HouseholdsMiddleware {
+	return &householdsMiddleware{repo}
+}
+
+// ValidateMembership only lets members of the household with at least the given role pass and
+// stores their role in the context. Non-members get a 404 so households of others stay hidden.
+func (h *householdsMiddleware) ValidateMembership(role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, err := auth_middleware.GetUserIDFromContext(ctx)
+		if err != nil {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		id := middleware.GetIDParamFromContext(ctx)
+
+		member, err := h.repo.HouseholdMemberGet(id, userID)
+		if err != nil {
+			if err == repository.ErrorRecordNotFound {
+				ctx.AbortWithStatus(http.StatusNotFound)
+				return
+			}
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if !permissions.Allows(member.Role, role) {
+			ctx.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		ctx.Set(roleKey, member.Role)
+		ctx.Next()
+	}
+}
+
+// GetRoleFromContext returns the role of the current user in the household of the request
+func GetRoleFromContext(ctx *gin.Context) string {
+	role, _ := ctx.Get(roleKey)
+	return role.(string)
+}
@@ -0,0 +1,203 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+	MaxKeyLength   = 255
+
+	// MaxBodySize is the largest request body that is read to tell requests apart, it leaves room
+	// for the largest attachment upload
+	MaxBodySize = 16 << 20
+	// Lease is how long a key stays reserved for the first request, a reservation that is older
+	// was abandoned, e.g. because the server stopped, and the key can be used again
+	Lease = 5 * time.Minute
+
+	ErrMsgInvalidKey = "Idempotency-Key must be at most 255 characters long"
+	ErrMsgTooLarge   = "request body is too large"
+	ErrMsgKeyReused  = "Idempotency-Key was already used for a different request"
+	ErrMsgInProgress = "a request with the same Idempotency-Key is still being processed"
+)
+
+type IdempotencyMiddleware interface {
+	HandleIdempotencyKey(*gin.Context)
+}
+
+type idempotencyMiddleware struct {
+	repo repository.Repository
+	ttl  time.Duration
+}
+
+// New creates the middleware, responses are kept for ttl after the first request
+func New(repo repository.Repository, ttl time.Duration) IdempotencyMiddleware {
+	return &idempotencyMiddleware{repo, ttl}
+}
+
+// HandleIdempotencyKey makes POST requests with an Idempotency-Key header safe to retry. The first
+// request with a key runs as usual and its response is stored for the user, retries with the same key
+// get the stored response. Reusing a key for a different request results in 422. Server errors and
+// panics are not stored, so the request can be retried.
+func (i *idempotencyMiddleware) HandleIdempotencyKey(ctx *gin.Context) {
+	key := ctx.GetHeader(HeaderKey)
+	if ctx.Request.Method != http.MethodPost || key == "" {
+		ctx.Next()
+		return
+	}
+
+	if len(key) > MaxKeyLength {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"message": ErrMsgInvalidKey,
+		})
+		return
+	}
+
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	hash, err := requestHash(ctx)
+	if err == errBodyTooLarge {
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+			"message": ErrMsgTooLarge,
+		})
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+		return
+	}
+
+	stored, err := i.repo.IdempotencyKeyGet(userID, key)
+	if err == nil {
+		replay(ctx, stored, hash)
+		return
+	}
+	if err != repository.ErrorRecordNotFound {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	k := &model.IdempotencyKey{
+		UserID:         userID,
+		Key:            key,
+		RequestHash:    hash,
+		ExpiresAt:      now.Add(i.ttl),
+		LeaseExpiresAt: now.Add(Lease),
+	}
+	if err := i.repo.IdempotencyKeyCreate(k); err != nil {
+		if err == repository.ErrorUniqueConstaintViolation {
+			// Another request with the key was faster
+			ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"message": ErrMsgInProgress,
+			})
+			return
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	// A panicking handler stored nothing, the key is released so the request can be retried
+	defer func() {
+		if p := recover(); p != nil {
+			if err := i.repo.IdempotencyKeyDelete(k.ID); err != nil {
+				ctx.Error(fmt.Errorf("couldn't release idempotency key %s: %w", key, err))
+			}
+			panic(p)
+		}
+	}()
+
+	recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+	ctx.Writer = recorder
+	ctx.Next()
+
+	if status := recorder.Status(); status >= http.StatusInternalServerError {
+		err = i.repo.IdempotencyKeyDelete(k.ID)
+	} else {
+		err = i.repo.IdempotencyKeyComplete(k.ID, status, recorder.body.Bytes())
+	}
+	if err != nil {
+		ctx.Error(fmt.Errorf("couldn't store the response for idempotency key %s: %w", key, err))
+	}
+}
+
+// replay responds with the stored response, if the key was used for the same request
+func replay(ctx *gin.Context, stored *model.IdempotencyKey, hash string) {
+	if stored.RequestHash != hash {
+		ctx.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"message": ErrMsgKeyReused,
+		})
+		return
+	}
+
+	if stored.StatusCode == 0 {
+		ctx.AbortWithStatusJSON(http.StatusConflict, gin.H{
+			"message": ErrMsgInProgress,
+		})
+		return
+	}
+
+	ctx.Header(HeaderReplayed, "true")
+	if len(stored.Body) == 0 {
+		ctx.AbortWithStatus(stored.StatusCode)
+		return
+	}
+	ctx.Data(stored.StatusCode, "application/json; charset=utf-8", stored.Body)
+	ctx.Abort()
+}
+
+var errBodyTooLarge = errors.New("request body is too large")
+
+// requestHash identifies the request by its method, path and body. The body, at most MaxBodySize
+// bytes, is read and put back for the handler.
+func requestHash(ctx *gin.Context) (string, error) {
+	if ctx.Request.ContentLength > MaxBodySize {
+		return "", errBodyTooLarge
+	}
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, MaxBodySize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > MaxBodySize {
+		return "", errBodyTooLarge
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", ctx.Request.Method, ctx.Request.URL.RequestURI())
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// responseRecorder keeps a copy of the response body
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
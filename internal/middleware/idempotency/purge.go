@@ -0,0 +1,29 @@
+package idempotency
+
+import (
+	"context"
+	"expense-api/internal/repository"
+	"log"
+	"time"
+)
+
+// PurgeInterval is how often Purge deletes the expired keys
+const PurgeInterval = time.Hour
+
+// Purge deletes the keys that expired right away and then every interval, until the context is done
+func Purge(ctx context.Context, repo repository.Repository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := repo.IdempotencyKeyPurge(time.Now()); err != nil {
+			log.Printf("couldn't purge the expired idempotency keys: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,12 +3,16 @@ package party
 import (
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+const partyKey = "party"
+
 type PartiesMiddleware interface {
 	ValidateOwnership(*gin.Context)
 }
@@ -21,6 +25,7 @@ func New(repo repository.Repository) PartiesMiddleware {
 	return &partiesMiddleware{repo}
 }
 
+// ValidateOwnership checks that the party is the user's own or belongs to one of their households
 func (p *partiesMiddleware) ValidateOwnership(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -40,10 +45,23 @@ func (p *partiesMiddleware) ValidateOwnership(ctx *gin.Context) {
 		return
 	}
 
-	if wModel.UserID != userID {
+	allowed, err := permissions.Check(p.repo, userID, wModel.UserID, wModel.HouseholdID, permissions.RequiredRole(ctx.Request.Method))
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
 		ctx.AbortWithStatus(http.StatusForbidden)
 		return
 	}
 
+	ctx.Set(partyKey, wModel)
 	ctx.Next()
 }
+
+// GetPartyFromContext returns the party of the request as it was before the handler ran
+func GetPartyFromContext(ctx *gin.Context) *model.Party {
+	p, _ := ctx.Get(partyKey)
+	return p.(*model.Party)
+}
@@ -0,0 +1,60 @@
+package rule
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ruleKey = "rule"
+
+type RulesMiddleware interface {
+	ValidateOwnership(*gin.Context)
+}
+
+type rulesMiddleware struct {
+	repo repository.Repository
+}
+
+func New(repo repository.Repository) RulesMiddleware {
+	return &rulesMiddleware{repo}
+}
+
+// ValidateOwnership only lets users manage their own rules
+func (r *rulesMiddleware) ValidateOwnership(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	rModel, err := r.repo.RuleGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if rModel.UserID != userID {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	ctx.Set(ruleKey, rModel)
+	ctx.Next()
+}
+
+// GetRuleFromContext returns the rule of the request as it was before the handler ran
+func GetRuleFromContext(ctx *gin.Context) *model.Rule {
+	r, _ := ctx.Get(ruleKey)
+	return r.(*model.Rule)
+}
@@ -0,0 +1,50 @@
+package settlement
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettlementsMiddleware interface {
+	ValidateOwnership(*gin.Context)
+}
+
+type settlementsMiddleware struct {
+	repo repository.Repository
+}
+
+func New(repo repository.Repository) SettlementsMiddleware {
+	return &settlementsMiddleware{repo}
+}
+
+// ValidateOwnership only lets the user who got paid by a settlement manage it
+func (s *settlementsMiddleware) ValidateOwnership(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	sModel, err := s.repo.SettlementGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if sModel.ToUserID != userID {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	ctx.Next()
+}
@@ -3,12 +3,16 @@ package transaction
 import (
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+const transactionKey = "transaction"
+
 type TransactionsMiddleware interface {
 	ValidateOwnership(*gin.Context)
 }
@@ -21,6 +25,8 @@ func New(repo repository.Repository) TransactionsMiddleware {
 	return &transactionsMiddleware{repo}
 }
 
+// ValidateOwnership checks that the transaction is the user's own or belongs to a household wallet
+// the user may read or, for anything but reads, edit
 func (t *transactionsMiddleware) ValidateOwnership(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -40,10 +46,23 @@ func (t *transactionsMiddleware) ValidateOwnership(ctx *gin.Context) {
 		return
 	}
 
-	if tModel.UserID != userID {
+	allowed, err := permissions.Check(t.repo, userID, tModel.UserID, tModel.HouseholdID, permissions.RequiredRole(ctx.Request.Method))
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
 		ctx.AbortWithStatus(http.StatusForbidden)
 		return
 	}
 
+	ctx.Set(transactionKey, tModel)
 	ctx.Next()
 }
+
+// GetTransactionFromContext returns the transaction of the request as it was before the handler ran
+func GetTransactionFromContext(ctx *gin.Context) *model.Transaction {
+	t, _ := ctx.Get(transactionKey)
+	return t.(*model.Transaction)
+}
@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"errors"
+	"expense-api/internal/handlers"
+	"expense-api/internal/openapi"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/gin-gonic/gin"
+)
+
+const mimeJSON = "application/json"
+
+type ValidationMiddleware interface {
+	ValidateRequest(*gin.Context)
+}
+
+type validationMiddleware struct{}
+
+// New creates the middleware
+func New() ValidationMiddleware {
+	return &validationMiddleware{}
+}
+
+// ValidateRequest answers with 400 if the parameters or the JSON body of the request don't match the
+// OpenAPI document. Other bodies, such as uploads, are left to the handlers. Authentication isn't
+// checked, that's up to the routes.
+func (v *validationMiddleware) ValidateRequest(ctx *gin.Context) {
+	route := openapi.Route(ctx.Request.Method, ctx.FullPath())
+	if route == nil {
+		ctx.Next()
+		return
+	}
+
+	params := make(map[string]string, len(ctx.Params))
+	for _, param := range ctx.Params {
+		params[param.Key] = param.Value
+	}
+
+	// The handlers bind bodies without a content type as JSON, so they are validated as JSON
+	req := ctx.Request.Clone(ctx.Request.Context())
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", mimeJSON)
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: params,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			ExcludeRequestBody: !validatesBody(route.Operation, req),
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	err := openapi3filter.ValidateRequest(ctx.Request.Context(), input)
+	// The body was read, it is put back for the handlers
+	ctx.Request.Body = req.Body
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, &handlers.ErrorMessage{Message: message(err)})
+		return
+	}
+
+	ctx.Next()
+}
+
+// validatesBody is whether the operation takes a JSON body and the request has one
+func validatesBody(op *openapi3.Operation, req *http.Request) bool {
+	if op.RequestBody == nil || op.RequestBody.Value.Content.Get(mimeJSON) == nil {
+		return false
+	}
+	mime := strings.TrimSpace(strings.Split(req.Header.Get("Content-Type"), ";")[0])
+	return mime == mimeJSON || strings.HasSuffix(mime, "+json")
+}
+
+// message describes what is wrong with the request, like "request body at /amount: value must be a
+// string"
+func message(err error) string {
+	var reqErr *openapi3filter.RequestError
+	if !errors.As(err, &reqErr) {
+		return err.Error()
+	}
+
+	subject := "request body"
+	if reqErr.Parameter != nil {
+		subject = fmt.Sprintf("%s parameter %s", reqErr.Parameter.In, reqErr.Parameter.Name)
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(reqErr.Err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			subject += " at /" + strings.Join(pointer, "/")
+		}
+		return fmt.Sprintf("%s: %s", subject, schemaErr.Reason)
+	}
+	if reqErr.Err != nil {
+		return fmt.Sprintf("%s: %s", subject, reqErr.Err)
+	}
+	return fmt.Sprintf("%s: %s", subject, reqErr.Reason)
+}
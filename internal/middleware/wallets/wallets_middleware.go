@@ -3,12 +3,16 @@ package wallet
 import (
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+const walletKey = "wallet"
+
 type WalletsMiddleware interface {
 	ValidateOwnership(*gin.Context)
 }
@@ -21,6 +25,8 @@ func New(repo repository.Repository) WalletsMiddleware {
 	return &walletsMiddleware{repo}
 }
 
+// ValidateOwnership checks that the wallet is the user's own or belongs to a household in which the
+// user's role allows the request (see permissions.RequiredRole)
 func (w *walletsMiddleware) ValidateOwnership(ctx *gin.Context) {
 	userID, err := auth_middleware.GetUserIDFromContext(ctx)
 	if err != nil {
@@ -40,10 +46,23 @@ func (w *walletsMiddleware) ValidateOwnership(ctx *gin.Context) {
 		return
 	}
 
-	if wModel.UserID != userID {
+	allowed, err := permissions.Check(w.repo, userID, wModel.UserID, wModel.HouseholdID, permissions.RequiredRole(ctx.Request.Method))
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if !allowed {
 		ctx.AbortWithStatus(http.StatusForbidden)
 		return
 	}
 
+	ctx.Set(walletKey, wModel)
 	ctx.Next()
 }
+
+// GetWalletFromContext returns the wallet of the request as it was before the handler ran
+func GetWalletFromContext(ctx *gin.Context) *model.Wallet {
+	w, _ := ctx.Get(walletKey)
+	return w.(*model.Wallet)
+}
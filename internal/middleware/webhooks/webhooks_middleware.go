@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"expense-api/internal/middleware"
+	auth_middleware "expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const webhookKey = "webhook"
+
+type WebhooksMiddleware interface {
+	ValidateOwnership(*gin.Context)
+}
+
+type webhooksMiddleware struct {
+	repo repository.Repository
+}
+
+func New(repo repository.Repository) WebhooksMiddleware {
+	return &webhooksMiddleware{repo}
+}
+
+// ValidateOwnership only lets users manage their own webhooks
+func (w *webhooksMiddleware) ValidateOwnership(ctx *gin.Context) {
+	userID, err := auth_middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	id := middleware.GetIDParamFromContext(ctx)
+
+	wModel, err := w.repo.WebhookGet(id)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			ctx.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	if wModel.UserID != userID {
+		ctx.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	ctx.Set(webhookKey, wModel)
+	ctx.Next()
+}
+
+// GetWebhookFromContext returns the webhook of the request as it was before the handler ran
+func GetWebhookFromContext(ctx *gin.Context) *model.Webhook {
+	w, _ := ctx.Get(webhookKey)
+	return w.(*model.Webhook)
+}
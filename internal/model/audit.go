@@ -0,0 +1,73 @@
+package model
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// AuditChange is the value of a field before and after a change, nil where the record didn't exist
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges are the changed fields of a record by column name, stored in a postgres jsonb column
+type AuditChanges map[string]*AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *AuditChanges) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, c)
+	case string:
+		return json.Unmarshal([]byte(src), c)
+	case nil:
+		*c = AuditChanges{}
+		return nil
+	}
+	return errors.New("unsupported audit changes value")
+}
+
+// ComputeHash hashes the content of the entry together with the hash of the previous entry with
+// SHA-256. The changes are hashed as they read back from the database, so the hash of a stored
+// entry can be computed again.
+func (e *AuditEntry) ComputeHash() (string, error) {
+	changes, err := json.Marshal(e.Changes)
+	if err != nil {
+		return "", err
+	}
+	var stored interface{}
+	if err := json.Unmarshal(changes, &stored); err != nil {
+		return "", err
+	}
+
+	content, err := json.Marshal([]interface{}{
+		e.PrevHash,
+		e.UserID,
+		e.ActorID,
+		e.RequestID,
+		e.IP,
+		e.Resource,
+		e.ResourceID,
+		e.Action,
+		stored,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -4,51 +4,396 @@ import (
 	"time"
 
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 type GormModel interface {
-	User | Wallet | Transaction | Party
+	User | Wallet | Transaction | Party | ExchangeRate | TransactionSplit | TransactionShare | Settlement | Attachment | Rule | PartyAlias | Reconciliation |
+		Household | HouseholdMember | HouseholdInvitation | IdempotencyKey | AuditEntry | Webhook | WebhookDelivery | OutboxEvent
 }
 
+// Model is embedded in every model. Version counts the updates of a record, updates only succeed if
+// nobody else updated the record since it was read.
 type Model struct {
 	ID        uint      `json:"id" gorm:"primarykey"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   uint      `json:"version" gorm:"not null;default:0;"`
+}
+
+// Versioned is implemented by every model through the embedded Model
+type Versioned interface {
+	VersionRef() *uint
+}
+
+// VersionRef points to the version of the model
+func (m *Model) VersionRef() *uint {
+	return &m.Version
 }
 
 type User struct {
 	Model
-	FirstName string `json:"first_name" gorm:"not null;"`
-	LastName  string `json:"last_name" gorm:"not null;"`
-	Email     string `json:"email" gorm:"type:varchar(255);unique;not null;"`
-	Password  string `json:"password" gorm:"not null;"`
-	Salt      string `json:"salt" gorm:"not null;"`
+	FirstName    string `json:"first_name" gorm:"not null;"`
+	LastName     string `json:"last_name" gorm:"not null;"`
+	Email        string `json:"email" gorm:"type:varchar(255);unique;not null;"`
+	Password     string `json:"password" gorm:"not null;"`
+	Salt         string `json:"salt" gorm:"not null;"`
+	BaseCurrency string `json:"base_currency" gorm:"type:char(3);not null;default:EUR;"`
+	// Admin lets the user change what all users share, such as exchange rates
+	Admin bool `json:"admin" gorm:"not null;default:false;"`
+}
+
+// Household is a workspace whose wallets and parties are shared between its members
+type Household struct {
+	Model
+	Name string `json:"name" gorm:"not null;"`
+}
+
+// HouseholdMember gives a user access to a household with one of the roles 'owner', 'editor' or 'viewer'
+type HouseholdMember struct {
+	Model
+	HouseholdID uint      `json:"household_id" gorm:"uniqueIndex:idx_household_member;not null;"`
+	Household   Household `json:"household" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID      uint      `json:"user_id" gorm:"uniqueIndex:idx_household_member;index;not null;"`
+	User        User      `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Role        string    `json:"role" gorm:"not null;"`
+}
+
+// HouseholdInvitation invites whoever registered with the email to join a household
+type HouseholdInvitation struct {
+	Model
+	HouseholdID uint      `json:"household_id" gorm:"index;not null;"`
+	Household   Household `json:"household" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Email       string    `json:"email" gorm:"type:varchar(255);index;not null;"`
+	Role        string    `json:"role" gorm:"not null;"`
+	InvitedByID uint      `json:"invited_by_id" gorm:"not null;"`
+	InvitedBy   User      `json:"invited_by" gorm:"foreignKey:InvitedByID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Status      string    `json:"status" gorm:"not null;default:pending;"`
 }
 
+// Wallets, parties and transactions with a HouseholdID belong to the household; UserID is the user who created them.
+// Deleting them moves them to the trash: DeletedAt is set and gorm leaves them out of every query
+// until they are restored or purged. Names only have to be unique among the ones not in the trash,
+// the personal ones of a user and the ones of a household.
 type Wallet struct {
 	Model
-	Name        string `json:"name" gorm:"uniqueIndex:idx_userid_wallet_name;not null;"`
+	Name        string `json:"name" gorm:"uniqueIndex:idx_wallet_user_name,where:deleted_at IS NULL AND household_id IS NULL;uniqueIndex:idx_wallet_household_name,where:deleted_at IS NULL;not null;"`
 	Description string `json:"description"`
-	UserID      uint   `json:"user_id" gorm:"uniqueIndex:idx_userid_wallet_name;not null;"`
-	User        User   `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Currency    string `json:"currency" gorm:"type:char(3);not null;default:EUR;"`
+	Type        string `json:"type" gorm:"type:varchar(20);not null;default:checking;"`
+	// OpeningBalance is the balance of the account on the opening date, before any of the wallet's transactions
+	OpeningBalance decimal.Decimal  `json:"opening_balance" gorm:"type:numeric;not null;default:0;"`
+	OpeningDate    *time.Time       `json:"opening_date"`
+	CreditLimit    *decimal.Decimal `json:"credit_limit" gorm:"type:numeric;"`
+	// MonthlyBudget is how much may be spent from the wallet in a calendar month, see EventBudgetExceeded
+	MonthlyBudget *decimal.Decimal `json:"monthly_budget" gorm:"type:numeric;"`
+	// Archived wallets are hidden from the wallet list but still count in reports
+	Archived     bool           `json:"archived" gorm:"not null;default:false;"`
+	DisplayOrder int            `json:"display_order" gorm:"not null;default:0;"`
+	UserID       uint           `json:"user_id" gorm:"uniqueIndex:idx_wallet_user_name;not null;"`
+	User         User           `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	HouseholdID  *uint          `json:"household_id" gorm:"index;uniqueIndex:idx_wallet_household_name;"`
+	Household    Household      `json:"household" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	DeletedAt    gorm.DeletedAt `json:"deleted_at" gorm:"index;"`
 }
 
+// Types of wallets. Only credit card wallets have a credit limit.
+const (
+	WalletCash       = "cash"
+	WalletChecking   = "checking"
+	WalletSavings    = "savings"
+	WalletCreditCard = "credit_card"
+	WalletLoan       = "loan"
+	WalletInvestment = "investment"
+)
+
 type Party struct {
 	Model
-	Name   string `json:"name" gorm:"uniqueIndex:idx_userid_party_name;not null;"`
-	UserID uint   `json:"user_id" gorm:"uniqueIndex:idx_userid_party_name;not null;"`
-	User   User   `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Name        string         `json:"name" gorm:"uniqueIndex:idx_party_user_name,where:deleted_at IS NULL AND household_id IS NULL;uniqueIndex:idx_party_household_name,where:deleted_at IS NULL;not null;"`
+	UserID      uint           `json:"user_id" gorm:"uniqueIndex:idx_party_user_name;not null;"`
+	User        User           `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	HouseholdID *uint          `json:"household_id" gorm:"index;uniqueIndex:idx_party_household_name;"`
+	Household   Household      `json:"household" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index;"`
+	// Aliases are the names of the parties merged into this one
+	Aliases []PartyAlias `json:"aliases" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+// PartyAlias is another name of a party, usually how a bank or an import spells it
+type PartyAlias struct {
+	Model
+	PartyID uint   `json:"party_id" gorm:"uniqueIndex:idx_partyid_alias_name;not null;"`
+	Name    string `json:"name" gorm:"uniqueIndex:idx_partyid_alias_name;not null;"`
 }
 
+// Transaction amounts are denominated in the currency of the wallet they belong to.
+// HouseholdID is always the household of the wallet.
 type Transaction struct {
 	Model
 	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Tags        Tags            `json:"tags" gorm:"type:text[];not null;default:'{}';"`
 	Timestamp   time.Time       `json:"timestamp"`
 	Amount      decimal.Decimal `json:"amount" gorm:"type:numeric"`
+	Status      string          `json:"status" gorm:"type:varchar(10);not null;default:uncleared;"`
 	UserID      uint            `json:"user_id" gorm:"not null;"`
 	User        User            `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	WalletID    uint            `json:"wallet_id" gorm:"not null;"`
 	Wallet      Wallet          `json:"wallet" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	PartyID     uint            `json:"party_id" gorm:"not null;"`
 	Party       Party           `json:"party" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	HouseholdID *uint           `json:"household_id" gorm:"index;"`
+	Household   Household       `json:"household" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	DeletedAt   gorm.DeletedAt  `json:"deleted_at" gorm:"index;"`
+}
+
+// Statuses of a transaction, from entered to ticked off against the bank statement. Reconciled
+// transactions can no longer be changed.
+const (
+	TransactionUncleared  = "uncleared"
+	TransactionCleared    = "cleared"
+	TransactionReconciled = "reconciled"
+)
+
+// TransactionSplit is one line of a transaction that is split across several parties or categories.
+// The amounts of all splits of a transaction add up to the transaction's amount.
+// Splits without a party belong to the party of the transaction.
+type TransactionSplit struct {
+	Model
+	TransactionID uint            `json:"transaction_id" gorm:"index;not null;"`
+	Transaction   Transaction     `json:"transaction" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Amount        decimal.Decimal `json:"amount" gorm:"type:numeric;not null;"`
+	PartyID       *uint           `json:"party_id"`
+	Party         Party           `json:"party" gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+	Category      string          `json:"category"`
+	Note          string          `json:"note"`
+}
+
+// Attachment is a file, e.g. a receipt, attached to a transaction. The file itself lives in the
+// blob store under StorageKey; Checksum is its hex encoded SHA-256.
+type Attachment struct {
+	Model
+	TransactionID uint        `json:"transaction_id" gorm:"index;not null;"`
+	Transaction   Transaction `json:"transaction" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID        uint        `json:"user_id" gorm:"index;not null;"`
+	User          User        `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	FileName      string      `json:"file_name" gorm:"not null;"`
+	ContentType   string      `json:"content_type" gorm:"not null;"`
+	Size          int64       `json:"size" gorm:"not null;"`
+	Checksum      string      `json:"checksum" gorm:"type:char(64);not null;"`
+	StorageKey    string      `json:"storage_key" gorm:"uniqueIndex;not null;"`
+}
+
+// TransactionShare is the part of an expense that one user is responsible for. The owner of the
+// transaction paid the whole amount, every other participant owes them their share. Amounts are
+// positive and denominated in the transaction's currency; the shares of a transaction add up to
+// the absolute value of its amount.
+type TransactionShare struct {
+	Model
+	TransactionID uint             `json:"transaction_id" gorm:"uniqueIndex:idx_transaction_share_user;not null;"`
+	Transaction   Transaction      `json:"transaction" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID        uint             `json:"user_id" gorm:"uniqueIndex:idx_transaction_share_user;index;not null;"`
+	User          User             `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Method        string           `json:"method" gorm:"not null;"`
+	Percentage    *decimal.Decimal `json:"percentage" gorm:"type:numeric;"`
+	Amount        decimal.Decimal  `json:"amount" gorm:"type:numeric;not null;"`
+}
+
+// Settlement is a payment from one user to another that pays back shared expenses
+type Settlement struct {
+	Model
+	FromUserID uint            `json:"from_user_id" gorm:"index;not null;"`
+	FromUser   User            `json:"from_user" gorm:"foreignKey:FromUserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	ToUserID   uint            `json:"to_user_id" gorm:"index;not null;"`
+	ToUser     User            `json:"to_user" gorm:"foreignKey:ToUserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Amount     decimal.Decimal `json:"amount" gorm:"type:numeric;not null;"`
+	Currency   string          `json:"currency" gorm:"type:char(3);not null;"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Note       string          `json:"note"`
 }
+
+// Reconciliation records that the cleared transactions of a wallet matched a bank statement
+type Reconciliation struct {
+	Model
+	WalletID         uint            `json:"wallet_id" gorm:"index;not null;"`
+	Wallet           Wallet          `json:"wallet" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	UserID           uint            `json:"user_id" gorm:"not null;"`
+	User             User            `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	StatementDate    time.Time       `json:"statement_date" gorm:"not null;"`
+	StatementBalance decimal.Decimal `json:"statement_balance" gorm:"type:numeric;not null;"`
+	TransactionCount int             `json:"transaction_count" gorm:"not null;"`
+}
+
+// Rule assigns a party, category, tags or description to the transactions of its user that match
+// all of its conditions. Rules with a higher priority are applied first.
+type Rule struct {
+	Model
+	UserID         uint   `json:"user_id" gorm:"index;not null;"`
+	User           User   `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Name           string `json:"name" gorm:"not null;"`
+	Priority       int    `json:"priority" gorm:"not null;default:0;"`
+	Enabled        bool   `json:"enabled" gorm:"not null;"`
+	StopProcessing bool   `json:"stop_processing" gorm:"not null;default:false;"`
+	// Conditions
+	DescriptionContains string           `json:"description_contains"`
+	DescriptionRegex    string           `json:"description_regex"`
+	AmountMin           *decimal.Decimal `json:"amount_min" gorm:"type:numeric;"`
+	AmountMax           *decimal.Decimal `json:"amount_max" gorm:"type:numeric;"`
+	WalletID            *uint            `json:"wallet_id"`
+	Wallet              Wallet           `json:"wallet" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	// Actions
+	SetPartyID     *uint  `json:"set_party_id"`
+	SetParty       Party  `json:"set_party" gorm:"foreignKey:SetPartyID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	SetCategory    string `json:"set_category"`
+	AddTags        Tags   `json:"add_tags" gorm:"type:text[];not null;default:'{}';"`
+	SetDescription string `json:"set_description"`
+	// Statistics
+	HitCount  int64      `json:"hit_count" gorm:"not null;default:0;"`
+	LastHitAt *time.Time `json:"last_hit_at"`
+}
+
+// ExchangeRate states that on Date one unit of Base is worth Rate units of Quote
+type ExchangeRate struct {
+	Model
+	Date   time.Time       `json:"date" gorm:"type:date;uniqueIndex:idx_exchange_rate;not null;"`
+	Base   string          `json:"base" gorm:"type:char(3);uniqueIndex:idx_exchange_rate;not null;"`
+	Quote  string          `json:"quote" gorm:"type:char(3);uniqueIndex:idx_exchange_rate;not null;"`
+	Rate   decimal.Decimal `json:"rate" gorm:"type:numeric;not null;"`
+	Source string          `json:"source" gorm:"not null;"`
+}
+
+// IdempotencyKey stores the response to a request sent with an Idempotency-Key header, so that
+// retries of the request get the same response instead of repeating it. StatusCode is 0 while the
+// first request is still running, the reservation is abandoned once LeaseExpiresAt has passed, e.g.
+// because the server stopped in the middle of the request.
+type IdempotencyKey struct {
+	Model
+	UserID         uint      `json:"user_id" gorm:"uniqueIndex:idx_idempotency_key;not null;"`
+	User           User      `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	Key            string    `json:"key" gorm:"type:varchar(255);uniqueIndex:idx_idempotency_key;not null;"`
+	RequestHash    string    `json:"request_hash" gorm:"not null;"`
+	StatusCode     int       `json:"status_code" gorm:"not null;default:0;"`
+	Body           []byte    `json:"body"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"index;not null;"`
+	LeaseExpiresAt time.Time `json:"lease_expires_at" gorm:"not null;default:CURRENT_TIMESTAMP;"`
+}
+
+// AuditEntry records a create, update or delete of a user, wallet, party or transaction. ActorID is
+// the user who made the change, nil for changes nobody asked for like purging the trash. Changes
+// holds the fields that differ before and after the change. Entries are never changed and outlive
+// the records they are about.
+//
+// The entries about the records of a user, UserID, form a hash chain: Hash covers the content of
+// the entry and PrevHash, the hash of the user's previous entry, so editing or removing an entry
+// breaks the chain. Entries written before the chain existed have no user.
+type AuditEntry struct {
+	Model
+	UserID     uint         `json:"user_id" gorm:"index;not null;default:0;"`
+	PrevHash   string       `json:"prev_hash" gorm:"not null;default:'';"`
+	Hash       string       `json:"hash" gorm:"not null;default:'';"`
+	ActorID    *uint        `json:"actor_id" gorm:"index;"`
+	RequestID  string       `json:"request_id"`
+	IP         string       `json:"ip"`
+	Resource   string       `json:"resource" gorm:"index:idx_audit_resource;not null;"`
+	ResourceID uint         `json:"resource_id" gorm:"index:idx_audit_resource;not null;"`
+	Action     string       `json:"action" gorm:"not null;"`
+	Changes    AuditChanges `json:"changes" gorm:"type:jsonb;not null;"`
+}
+
+// Resources whose changes are audited
+const (
+	AuditResourceUser        = "user"
+	AuditResourceWallet      = "wallet"
+	AuditResourceParty       = "party"
+	AuditResourceTransaction = "transaction"
+)
+
+// Actions of audit entries. Moving a record to the trash is a delete, taking it out a restore and
+// deleting it from the trash for good a purge.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// Webhook sends the events about the wallets and transactions of its user to URL, the ones named
+// in Events. Deliveries are signed with Secret. Disabled webhooks keep their pending deliveries
+// until they are enabled again.
+type Webhook struct {
+	Model
+	UserID  uint   `json:"user_id" gorm:"index;not null;"`
+	User    User   `json:"user" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	URL     string `json:"url" gorm:"not null;"`
+	Events  Tags   `json:"events" gorm:"type:text[];not null;default:'{}';"`
+	Secret  string `json:"secret" gorm:"not null;"`
+	Enabled bool   `json:"enabled" gorm:"not null;"`
+}
+
+// WebhookDelivery is an event on its way to a webhook. Failed attempts are retried at
+// NextAttemptAt until one succeeds or the delivery runs out of attempts. Replaying a delivery sends
+// the same event, with the same EventID, once more; otherwise a webhook gets every event only once.
+type WebhookDelivery struct {
+	Model
+	WebhookID     uint       `json:"webhook_id" gorm:"uniqueIndex:idx_webhook_delivery_event,where:replay_of_id IS NULL;not null;"`
+	Webhook       Webhook    `json:"webhook" gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	EventID       string     `json:"event_id" gorm:"uniqueIndex:idx_webhook_delivery_event;not null;"`
+	Event         string     `json:"event" gorm:"not null;"`
+	Payload       string     `json:"payload" gorm:"not null;"`
+	Status        string     `json:"status" gorm:"index:idx_webhook_delivery_due;not null;"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0;"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index:idx_webhook_delivery_due;not null;"`
+	LastAttemptAt *time.Time `json:"last_attempt_at"`
+	// ResponseStatus and Error are the outcome of the last attempt
+	ResponseStatus int    `json:"response_status" gorm:"not null;default:0;"`
+	Error          string `json:"error"`
+	ReplayOfID     *uint  `json:"replay_of_id"`
+}
+
+// Statuses of webhook deliveries
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// OutboxEvent is a domain event, written in the database transaction of the change it is about so
+// that it exists exactly if the change does. The events are published in the order they were
+// written; PublishedAt stays nil until every subscriber handled the event or it ran out of attempts.
+// Object is the JSON of the record after the change, or before it if it was deleted for good.
+type OutboxEvent struct {
+	Model
+	EventID     string       `json:"event_id" gorm:"uniqueIndex;not null;"`
+	Type        string       `json:"type" gorm:"not null;"`
+	Aggregate   string       `json:"aggregate" gorm:"not null;"`
+	AggregateID uint         `json:"aggregate_id" gorm:"not null;"`
+	UserID      uint         `json:"user_id" gorm:"not null;"`
+	HouseholdID *uint        `json:"household_id"`
+	ActorID     *uint        `json:"actor_id"`
+	Object      string       `json:"object" gorm:"not null;"`
+	Changes     AuditChanges `json:"changes" gorm:"type:jsonb;not null;"`
+	Attempts    int          `json:"attempts" gorm:"not null;default:0;"`
+	Error       string       `json:"error"`
+	PublishedAt *time.Time   `json:"published_at" gorm:"index;"`
+}
+
+// Domain events, named after the resource and what happened to it. Webhooks can subscribe to the
+// events of transactions and wallets one by one, with "wallet.*" to all events about wallets and
+// with "*" to all events about both. EventBudgetExceeded isn't written to the outbox, it is raised by
+// the webhooks when the expenses of a wallet in a month go over its monthly budget.
+const (
+	EventTransactionCreated  = "transaction.created"
+	EventTransactionUpdated  = "transaction.updated"
+	EventTransactionDeleted  = "transaction.deleted"
+	EventTransactionRestored = "transaction.restored"
+	EventWalletCreated       = "wallet.created"
+	EventWalletUpdated       = "wallet.updated"
+	EventWalletDeleted       = "wallet.deleted"
+	EventWalletRestored      = "wallet.restored"
+	EventPartyCreated        = "party.created"
+	EventPartyUpdated        = "party.updated"
+	EventPartyDeleted        = "party.deleted"
+	EventPartyRestored       = "party.restored"
+	EventBudgetExceeded      = "budget.exceeded"
+)
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"database/sql/driver"
+
+	"github.com/jackc/pgtype"
+)
+
+// Tags are free-form labels stored in a postgres text[] column
+type Tags []string
+
+// Value stores missing tags as an empty array, so the column never has to deal with NULL
+func (t Tags) Value() (driver.Value, error) {
+	var array pgtype.TextArray
+	if err := array.Set([]string(t)); err != nil {
+		return nil, err
+	}
+
+	if t == nil {
+		array.Status = pgtype.Present
+	}
+	return array.Value()
+}
+
+func (t *Tags) Scan(src interface{}) error {
+	var array pgtype.TextArray
+	if err := array.Scan(src); err != nil {
+		return err
+	}
+
+	if array.Status != pgtype.Present {
+		*t = Tags{}
+		return nil
+	}
+	return array.AssignTo((*[]string)(t))
+}
+
+// Contains tells whether the tag is one of the tags
+func (t Tags) Contains(tag string) bool {
+	for _, existing := range t {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
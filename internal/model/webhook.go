@@ -0,0 +1,15 @@
+package model
+
+import "strings"
+
+// Subscribes tells whether the webhook wants the event, either by its name, by the wildcard of its
+// resource or by "*"
+func (w *Webhook) Subscribes(event string) bool {
+	resource := strings.SplitN(event, ".", 2)[0]
+	for _, e := range w.Events {
+		if e == event || e == "*" || e == resource+".*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,241 @@
+// Package openapi describes the API in an OpenAPI 3.1 document. The schemas are generated from the
+// request and response types of the handlers, the operations from a table of the routes of the router.
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"expense-api/internal/handlers"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+// BasePath is the path of the server all paths of the document are relative to
+const BasePath = "/api/v1"
+
+const (
+	securitySchemeName = "bearer"
+	mimeJSON           = "application/json"
+	mimeMergePatch     = "application/merge-patch+json"
+)
+
+var (
+	pathParamRegexp = regexp.MustCompile(`\{([a-z_]+)\}`)
+	ginParamRegexp  = regexp.MustCompile(`:([a-z_]+)`)
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+var (
+	once     sync.Once
+	document *openapi3.T
+	encoded  []byte
+	routes   map[string]*routers.Route
+)
+
+// Document is the OpenAPI document of the API. It is generated once, callers must not change it.
+func Document() *openapi3.T {
+	once.Do(func() {
+		document = generate()
+
+		var err error
+		if encoded, err = json.Marshal(document); err != nil {
+			panic(err)
+		}
+
+		routes = make(map[string]*routers.Route, len(operations))
+		for _, op := range operations {
+			item := document.Paths.Value(op.path)
+			routes[op.method+" "+BasePath+strings.TrimSuffix(op.path, "/")] = &routers.Route{
+				Spec:      document,
+				Server:    document.Servers[0],
+				Path:      op.path,
+				PathItem:  item,
+				Method:    op.method,
+				Operation: item.GetOperation(op.method),
+			}
+		}
+	})
+	return document
+}
+
+// Route is the route of the document for a route of the router, such as "/api/v1/wallets/:id". It is
+// nil if the route isn't documented.
+func Route(method, fullPath string) *routers.Route {
+	Document()
+	path := ginParamRegexp.ReplaceAllString(strings.TrimSuffix(fullPath, "/"), "{$1}")
+	return routes[method+" "+path]
+}
+
+// ServeDocument answers with the OpenAPI document
+func ServeDocument(ctx *gin.Context) {
+	Document()
+	ctx.Data(http.StatusOK, mimeJSON, encoded)
+}
+
+// ServeDocs answers with a page to browse the OpenAPI document
+func ServeDocs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
+
+func generate() *openapi3.T {
+	s := newSchemas()
+	errorSchema := s.of(reflect.TypeOf(handlers.ErrorMessage{}))
+
+	doc := &openapi3.T{
+		OpenAPI: "3.1.0",
+		Info: &openapi3.Info{
+			Title:       "xpense API",
+			Version:     "1",
+			Description: "Keeps track of the expenses and incomes of users in wallets, parties and transactions.",
+		},
+		Servers: openapi3.Servers{{URL: BasePath}},
+		Paths:   openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas:   s.components,
+			Responses: openapi3.ResponseBodies{},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				securitySchemeName: &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+			},
+		},
+		Security: *openapi3.NewSecurityRequirements().With(openapi3.SecurityRequirement{securitySchemeName: []string{}}),
+	}
+
+	for _, op := range operations {
+		doc.AddOperation(op.path, op.method, s.operation(doc, op, errorSchema))
+	}
+	return doc
+}
+
+func (s *schemas) operation(doc *openapi3.T, op *operation, errorSchema *openapi3.SchemaRef) *openapi3.Operation {
+	operation := &openapi3.Operation{
+		OperationID: op.id,
+		Summary:     op.summary,
+		Tags:        []string{strings.SplitN(strings.TrimPrefix(op.path, "/"), "/", 2)[0]},
+		Responses:   openapi3.NewResponsesWithCapacity(len(op.responses)),
+	}
+	if op.public {
+		operation.Security = openapi3.NewSecurityRequirements()
+	}
+
+	pathParams := pathParamRegexp.FindAllStringSubmatch(op.path, -1)
+	for _, param := range pathParams {
+		schema := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}}
+		operation.AddParameter(openapi3.NewPathParameter(param[1]).WithSchema(schema.WithMin(1)))
+	}
+	operation.Parameters = append(operation.Parameters, op.query...)
+	operation.Parameters = append(operation.Parameters, op.headers...)
+	if op.method == http.MethodPost && isIdempotent(op.path) {
+		operation.Parameters = append(operation.Parameters, idempotencyKey)
+	}
+	operation.Parameters = append(operation.Parameters, requestID)
+
+	if op.body != nil {
+		operation.RequestBody = &openapi3.RequestBodyRef{Value: s.requestBody(op.body)}
+	}
+
+	for status, body := range op.responses {
+		response := openapi3.NewResponse().WithDescription(http.StatusText(status))
+		if body != nil {
+			response.Content = s.content(body)
+		}
+		if status == http.StatusOK && hasParam(op.headers, ifNoneMatch) {
+			response.Headers = openapi3.Headers{"ETag": &openapi3.HeaderRef{Value: &openapi3.Header{
+				Parameter: openapi3.Parameter{Description: "Version of the resource", Schema: typed(openapi3.TypeString)},
+			}}}
+		}
+		operation.AddResponse(status, response)
+	}
+
+	errors := append([]int{}, op.errors...)
+	if op.body != nil || len(op.query) > 0 || len(pathParams) > 0 {
+		errors = append(errors, http.StatusBadRequest)
+	}
+	if !op.public {
+		errors = append(errors, http.StatusUnauthorized, http.StatusForbidden)
+	}
+	if len(pathParams) > 0 {
+		errors = append(errors, http.StatusNotFound)
+	}
+	for _, status := range errors {
+		name := strings.ReplaceAll(http.StatusText(status), " ", "")
+		if _, ok := doc.Components.Responses[name]; !ok {
+			doc.Components.Responses[name] = &openapi3.ResponseRef{Value: openapi3.NewResponse().
+				WithDescription(http.StatusText(status)).
+				WithJSONSchemaRef(errorSchema)}
+		}
+		if operation.Responses.Value(strconv.Itoa(status)) == nil {
+			operation.Responses.Set(strconv.Itoa(status), &openapi3.ResponseRef{
+				Ref:   "#/components/responses/" + name,
+				Value: doc.Components.Responses[name].Value,
+			})
+		}
+	}
+
+	return operation
+}
+
+func (s *schemas) requestBody(body interface{}) *openapi3.RequestBody {
+	requestBody := openapi3.NewRequestBody().WithRequired(true)
+	switch body := body.(type) {
+	case patchOf:
+		return requestBody.WithSchemaRef(s.patch(reflect.TypeOf(body.of)), []string{mimeJSON, mimeMergePatch})
+	case upload:
+		object := openapi3.NewObjectSchema().
+			WithPropertyRef(body.field, inline(&openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "binary"}))
+		object.Required = []string{body.field}
+		return requestBody.WithFormDataSchema(object)
+	case anyFile:
+		return requestBody.WithContent(openapi3.Content{"*/*": openapi3.NewMediaType()})
+	}
+	return requestBody.WithJSONSchemaRef(s.of(reflect.TypeOf(body)))
+}
+
+func (s *schemas) content(body interface{}) openapi3.Content {
+	switch body := body.(type) {
+	case list:
+		// The entries of a ListResponse are of any type, allOf narrows them down
+		entries := openapi3.NewObjectSchema().WithPropertyRef("entries", inline(&openapi3.Schema{
+			Type:  &openapi3.Types{openapi3.TypeArray},
+			Items: s.of(reflect.TypeOf(body.of)),
+		}))
+		return openapi3.NewContentWithJSONSchema(&openapi3.Schema{AllOf: openapi3.SchemaRefs{
+			s.of(reflect.TypeOf(handlers.ListResponse{})),
+			inline(entries),
+		}})
+	case anyFile:
+		return openapi3.Content{"*/*": openapi3.NewMediaType()}
+	case html:
+		return openapi3.Content{"text/html": openapi3.NewMediaType()}
+	case eventStream:
+		return openapi3.Content{"text/event-stream": &openapi3.MediaType{Schema: s.of(reflect.TypeOf(body.of))}}
+	}
+	return openapi3.NewContentWithJSONSchemaRef(s.of(reflect.TypeOf(body)))
+}
+
+func isIdempotent(path string) bool {
+	for _, prefix := range idempotentPrefixes {
+		if strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasParam(params openapi3.Parameters, param *openapi3.ParameterRef) bool {
+	for _, p := range params {
+		if p == param {
+			return true
+		}
+	}
+	return false
+}
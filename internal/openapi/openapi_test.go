@@ -0,0 +1,181 @@
+package openapi_test
+
+import (
+	"encoding/json"
+	"expense-api/internal/handlers"
+	"expense-api/internal/model"
+	"expense-api/internal/openapi"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func TestDocumentIsValid(t *testing.T) {
+	doc := openapi.Document()
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("expected an OpenAPI 3.1.0 document, got %s", doc.OpenAPI)
+	}
+
+	// kin-openapi validates documents by the rules of OpenAPI 3.0, which has no null type. Loading the
+	// document resolves every reference at least.
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := openapi3.NewLoader().LoadFromData(encoded); err != nil {
+		t.Fatalf("expected the document to load, got %s", err)
+	}
+}
+
+func TestResponsesMatchSchemas(t *testing.T) {
+	schemas := openapi.Document().Components.Schemas
+	amount := decimal.RequireFromString("-12.5")
+	householdID := uint(3)
+
+	testCases := []struct {
+		schema string
+		value  interface{}
+	}{
+		{"Wallet", handlers.WalletModelToResponse(&model.Wallet{Name: "cash", Currency: "EUR", OpeningBalance: amount})},
+		{"Wallet", handlers.WalletModelToResponse(&model.Wallet{Name: "card", CreditLimit: &amount, HouseholdID: &householdID})},
+		{"Transaction", handlers.TransactionModelToResponse(&model.Transaction{Amount: amount, Tags: model.Tags{"food"}, Timestamp: time.Now()})},
+		{"ErrorMessage", handlers.ErrorBadWalletID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.schema, func(t *testing.T) {
+			encoded, err := json.Marshal(tc.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var value interface{}
+			if err := json.Unmarshal(encoded, &value); err != nil {
+				t.Fatal(err)
+			}
+			if err := schemas[tc.schema].Value.VisitJSON(value); err != nil {
+				t.Errorf("expected %s to match the schema, got %s", encoded, err)
+			}
+		})
+	}
+
+	var patch interface{}
+	if err := json.Unmarshal([]byte(`{"description": null, "amount": 0, "tags": null}`), &patch); err != nil {
+		t.Fatal(err)
+	}
+	if err := schemas["TransactionPatch"].Value.VisitJSON(patch); err != nil {
+		t.Errorf("expected the patch to match the schema, got %s", err)
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	schemas := openapi.Document().Components.Schemas
+
+	for _, name := range []string{"Transaction", "Wallet", "Party", "ListResponse", "ErrorMessage", "TransactionPatch"} {
+		if schemas[name] == nil {
+			t.Errorf("expected a %s schema", name)
+		}
+	}
+
+	wallet := schemas["Wallet"].Value
+	if ref := wallet.Properties["opening_balance"].Ref; ref != "#/components/schemas/Decimal" {
+		t.Errorf("expected the opening balance to be a Decimal, got %q", ref)
+	}
+	if id := wallet.Properties["id"].Value; !id.Type.Is(openapi3.TypeInteger) || id.Min == nil || *id.Min != 0 {
+		t.Errorf("expected the ID to be an unsigned integer, got %+v", id)
+	}
+	creditLimit := wallet.Properties["credit_limit"].Value
+	if len(creditLimit.AnyOf) != 2 || !creditLimit.AnyOf[1].Value.Type.Is(openapi3.TypeNull) {
+		t.Errorf("expected the credit limit to be nullable, got %+v", creditLimit)
+	}
+
+	// Every member of a patch may be null, which clears it
+	for name, property := range schemas["TransactionPatch"].Value.Properties {
+		if !permitsNull(property.Value) {
+			t.Errorf("expected %s of the transaction patch to be nullable", name)
+		}
+	}
+
+	// Trashed resources embed the resources
+	trashed := schemas["TrashedWallet"].Value
+	if len(trashed.AllOf) != 2 || trashed.AllOf[0].Ref != "#/components/schemas/Wallet" {
+		t.Errorf("expected the trashed wallet to embed the wallet, got %+v", trashed.AllOf)
+	}
+}
+
+func TestOperations(t *testing.T) {
+	doc := openapi.Document()
+
+	testCases := []struct {
+		path, method string
+		operationID  string
+		responses    []string
+		public       bool
+	}{
+		{"/auth/login", http.MethodPost, "Login", []string{"200", "400", "404"}, true},
+		{"/wallets/{id}", http.MethodGet, "GetWallet", []string{"200", "304", "400", "401", "403", "404"}, false},
+		{"/transactions/{id}", http.MethodPatch, "UpdateTransaction", []string{"200", "400", "401", "403", "404", "409", "412"}, false},
+		{"/openapi.json", http.MethodGet, "ServeDocument", []string{"200"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.operationID, func(t *testing.T) {
+			item := doc.Paths.Value(tc.path)
+			if item == nil {
+				t.Fatalf("expected %s to be documented", tc.path)
+			}
+			op := item.GetOperation(tc.method)
+			if op == nil {
+				t.Fatalf("expected %s %s to be documented", tc.method, tc.path)
+			}
+			if op.OperationID != tc.operationID {
+				t.Errorf("expected operation %s, got %s", tc.operationID, op.OperationID)
+			}
+
+			var responses []string
+			for status := range op.Responses.Map() {
+				responses = append(responses, status)
+			}
+			sort.Strings(responses)
+			if diff := cmp.Diff(tc.responses, responses); diff != "" {
+				t.Errorf("unexpected responses (-want +got):\n%s", diff)
+			}
+			if public := op.Security != nil && len(*op.Security) == 0; public != tc.public {
+				t.Errorf("expected public to be %t", tc.public)
+			}
+		})
+	}
+}
+
+func TestRoute(t *testing.T) {
+	route := openapi.Route(http.MethodDelete, "/api/v1/transactions/:id/attachments/:attachment_id")
+	if route == nil {
+		t.Fatal("expected the route to be documented")
+	}
+	if route.Operation.OperationID != "DeleteAttachment" {
+		t.Errorf("expected DeleteAttachment, got %s", route.Operation.OperationID)
+	}
+
+	if route := openapi.Route(http.MethodGet, "/api/v1/account/"); route == nil || route.Operation.OperationID != "GetAccount" {
+		t.Errorf("expected GetAccount, got %+v", route)
+	}
+	if route := openapi.Route(http.MethodPut, "/api/v1/wallets/:id"); route != nil {
+		t.Errorf("expected no route, got %s", route.Operation.OperationID)
+	}
+}
+
+func permitsNull(schema *openapi3.Schema) bool {
+	if schema.Type != nil && schema.Type.Includes(openapi3.TypeNull) {
+		return true
+	}
+	for _, s := range schema.AnyOf {
+		if permitsNull(s.Value) {
+			return true
+		}
+	}
+	return false
+}
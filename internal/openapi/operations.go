@@ -0,0 +1,565 @@
+package openapi
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware"
+	"expense-api/internal/middleware/idempotency"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// operation documents a route of the router
+type operation struct {
+	// id is the name of the handler
+	id      string
+	method  string
+	path    string
+	summary string
+	// public operations don't need a token
+	public  bool
+	query   openapi3.Parameters
+	headers openapi3.Parameters
+	// body is a value of the type of the JSON body, or one of patchOf, upload or anyFile
+	body interface{}
+	// responses are values of the types of the bodies by status, nil for none
+	responses map[int]interface{}
+	// errors are the statuses of errors besides the ones all operations of the kind can answer with
+	errors []int
+}
+
+type (
+	// list is a handlers.ListResponse with entries of the type
+	list struct{ of interface{} }
+	// patchOf is a JSON Merge Patch of the type
+	patchOf struct{ of interface{} }
+	// upload is a multipart/form-data body with a file
+	upload struct{ field string }
+	// anyFile is a file of any content type, as the body of requests or responses
+	anyFile struct{}
+	// eventStream is a text/event-stream of events with data of the type
+	eventStream struct{ of interface{} }
+)
+
+func queryParam(name, typ, description string) *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{Value: &openapi3.Parameter{
+		In: openapi3.ParameterInQuery, Name: name, Description: description, Schema: typed(typ),
+	}}
+}
+
+func headerParam(name, description string) *openapi3.ParameterRef {
+	return &openapi3.ParameterRef{Value: &openapi3.Parameter{
+		In: openapi3.ParameterInHeader, Name: name, Description: description, Schema: typed(openapi3.TypeString),
+	}}
+}
+
+var (
+	ifMatch        = headerParam("If-Match", "Only change the resource if its ETag is one of these")
+	ifNoneMatch    = headerParam("If-None-Match", "Answer with 304 if the ETag of the resource is one of these")
+	idempotencyKey = headerParam(idempotency.HeaderKey, "Retries of a POST request with the same key get the response of the first one")
+	requestID      = headerParam(middleware.HeaderRequestID, "Identifies the request in the audit log, generated if missing")
+
+	period = openapi3.Parameters{
+		queryParam("from", openapi3.TypeString, "First day of the period, YYYY-MM-DD"),
+		queryParam("to", openapi3.TypeString, "Last day of the period, YYYY-MM-DD"),
+	}
+)
+
+// idempotentPrefixes are the paths whose POST requests may have an Idempotency-Key
+var idempotentPrefixes = []string{
+	"/households", "/wallets", "/parties", "/transactions", "/trash", "/rules", "/webhooks", "/exchange-rates", "/shared",
+}
+
+var operations = []*operation{
+	// Authentication
+	{
+		id: "SignUp", method: http.MethodPost, path: "/auth/signup", summary: "Sign up", public: true,
+		body: handlers.SignUpInfo{}, responses: map[int]interface{}{http.StatusCreated: nil}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "Login", method: http.MethodPost, path: "/auth/login", summary: "Login", public: true,
+		body: handlers.LoginInfo{}, responses: map[int]interface{}{http.StatusOK: handlers.LoginToken{}}, errors: []int{http.StatusNotFound},
+	},
+
+	// Account
+	{
+		id: "GetAccount", method: http.MethodGet, path: "/account/", summary: "Get account information",
+		headers:   openapi3.Parameters{ifNoneMatch},
+		responses: map[int]interface{}{http.StatusOK: handlers.Account{}, http.StatusNotModified: nil},
+	},
+	{
+		id: "UpdateAccount", method: http.MethodPatch, path: "/account/", summary: "Update account information",
+		headers: openapi3.Parameters{ifMatch}, body: patchOf{handlers.Account{}},
+		responses: map[int]interface{}{http.StatusOK: handlers.Account{}}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeleteAccount", method: http.MethodDelete, path: "/account/", summary: "Delete account",
+		headers:   openapi3.Parameters{ifMatch},
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusPreconditionFailed},
+	},
+
+	// Households
+	{
+		id: "ListHouseholds", method: http.MethodGet, path: "/households/", summary: "List households",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Household{}}},
+	},
+	{
+		id: "CreateHousehold", method: http.MethodPost, path: "/households/", summary: "Create household",
+		body: handlers.Household{}, responses: map[int]interface{}{http.StatusCreated: handlers.Household{}},
+	},
+	{
+		id: "GetHousehold", method: http.MethodGet, path: "/households/{id}", summary: "Get household",
+		responses: map[int]interface{}{http.StatusOK: handlers.Household{}},
+	},
+	{
+		id: "UpdateHousehold", method: http.MethodPatch, path: "/households/{id}", summary: "Update household",
+		body: patchOf{handlers.Household{}}, responses: map[int]interface{}{http.StatusOK: handlers.Household{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "DeleteHousehold", method: http.MethodDelete, path: "/households/{id}", summary: "Delete household",
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "ListHouseholdMembers", method: http.MethodGet, path: "/households/{id}/members", summary: "List household members",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.HouseholdMember{}}},
+	},
+	{
+		id: "UpdateHouseholdMember", method: http.MethodPatch, path: "/households/{id}/members/{user_id}", summary: "Change member role",
+		body: patchOf{handlers.HouseholdMember{}}, responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "RemoveHouseholdMember", method: http.MethodDelete, path: "/households/{id}/members/{user_id}", summary: "Remove household member",
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "ListHouseholdInvitations", method: http.MethodGet, path: "/households/{id}/invitations", summary: "List household invitations",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.HouseholdInvitation{}}},
+	},
+	{
+		id: "CreateHouseholdInvitation", method: http.MethodPost, path: "/households/{id}/invitations", summary: "Invite to household",
+		body: handlers.HouseholdInvitation{}, responses: map[int]interface{}{http.StatusCreated: handlers.HouseholdInvitation{}}, errors: []int{http.StatusConflict},
+	},
+
+	// Invitations
+	{
+		id: "ListInvitations", method: http.MethodGet, path: "/invitations/", summary: "List my invitations",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.HouseholdInvitation{}}},
+	},
+	{
+		id: "AcceptInvitation", method: http.MethodPost, path: "/invitations/{id}/accept", summary: "Accept invitation",
+		responses: map[int]interface{}{http.StatusOK: handlers.Household{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "DeclineInvitation", method: http.MethodPost, path: "/invitations/{id}/decline", summary: "Decline invitation",
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict},
+	},
+
+	// Wallets
+	{
+		id: "ListWallets", method: http.MethodGet, path: "/wallets/", summary: "List wallets",
+		query:     openapi3.Parameters{queryParam("include_archived", openapi3.TypeBoolean, "List archived wallets as well")},
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Wallet{}}},
+	},
+	{
+		id: "CreateWallet", method: http.MethodPost, path: "/wallets/", summary: "Create wallet",
+		body: handlers.Wallet{}, responses: map[int]interface{}{http.StatusCreated: handlers.Wallet{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "GetWallet", method: http.MethodGet, path: "/wallets/{id}", summary: "Get wallet",
+		headers:   openapi3.Parameters{ifNoneMatch},
+		responses: map[int]interface{}{http.StatusOK: handlers.Wallet{}, http.StatusNotModified: nil},
+	},
+	{
+		id: "UpdateWallet", method: http.MethodPatch, path: "/wallets/{id}", summary: "Update wallet",
+		headers: openapi3.Parameters{ifMatch}, body: patchOf{handlers.Wallet{}},
+		responses: map[int]interface{}{http.StatusOK: handlers.Wallet{}}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeleteWallet", method: http.MethodDelete, path: "/wallets/{id}", summary: "Delete wallet",
+		headers: openapi3.Parameters{ifMatch},
+		query: openapi3.Parameters{
+			queryParam("cascade", openapi3.TypeBoolean, "Move the transactions of the wallet to the trash as well"),
+			queryParam("reassign_to", openapi3.TypeInteger, "Move the transactions of the wallet to this wallet"),
+		},
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "ListTransactionsByWallet", method: http.MethodGet, path: "/wallets/{id}/transactions", summary: "List transactions by wallet",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Transaction{}}},
+	},
+	{
+		id: "ReconcileWallet", method: http.MethodPost, path: "/wallets/{id}/reconcile", summary: "Reconcile wallet",
+		body: handlers.ReconciliationRequest{},
+		responses: map[int]interface{}{
+			http.StatusCreated: handlers.ReconciliationResult{},
+			http.StatusOK:      handlers.ReconciliationResult{},
+		},
+		errors: []int{http.StatusConflict},
+	},
+	{
+		id: "ListReconciliations", method: http.MethodGet, path: "/wallets/{id}/reconciliations", summary: "List reconciliations",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Reconciliation{}}},
+	},
+
+	// Parties
+	{
+		id: "ListParties", method: http.MethodGet, path: "/parties/", summary: "List parties",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Party{}}},
+	},
+	{
+		id: "CreateParty", method: http.MethodPost, path: "/parties/", summary: "Create party",
+		body: handlers.Party{},
+		responses: map[int]interface{}{
+			http.StatusCreated: handlers.Party{},
+			http.StatusOK:      handlers.Party{},
+		},
+		errors: []int{http.StatusConflict},
+	},
+	{
+		id: "GetParty", method: http.MethodGet, path: "/parties/{id}", summary: "Get party",
+		headers:   openapi3.Parameters{ifNoneMatch},
+		responses: map[int]interface{}{http.StatusOK: handlers.Party{}, http.StatusNotModified: nil},
+	},
+	{
+		id: "UpdateParty", method: http.MethodPatch, path: "/parties/{id}", summary: "Update party",
+		headers: openapi3.Parameters{ifMatch}, body: patchOf{handlers.Party{}},
+		responses: map[int]interface{}{http.StatusOK: handlers.Party{}}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeleteParty", method: http.MethodDelete, path: "/parties/{id}", summary: "Delete party",
+		headers: openapi3.Parameters{ifMatch},
+		query: openapi3.Parameters{
+			queryParam("cascade", openapi3.TypeBoolean, "Move the transactions of the party to the trash as well"),
+			queryParam("reassign_to", openapi3.TypeInteger, "Move the transactions of the party to this party"),
+		},
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "ListTransactionsByParty", method: http.MethodGet, path: "/parties/{id}/transactions", summary: "List transactions by party",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Transaction{}}},
+	},
+	{
+		id: "MergeParties", method: http.MethodPost, path: "/parties/{id}/merge", summary: "Merge parties",
+		headers: openapi3.Parameters{ifMatch},
+		body:    handlers.PartyMerge{}, responses: map[int]interface{}{http.StatusOK: handlers.Party{}},
+		errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeletePartyAlias", method: http.MethodDelete, path: "/parties/{id}/aliases/{alias_id}", summary: "Delete party alias",
+		responses: map[int]interface{}{http.StatusNoContent: nil},
+	},
+
+	// Transactions
+	{
+		id: "ListTransactions", method: http.MethodGet, path: "/transactions/", summary: "List all transactions",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Transaction{}}},
+	},
+	{
+		id: "CreateTransaction", method: http.MethodPost, path: "/transactions/", summary: "Create transaction",
+		query:     openapi3.Parameters{queryParam("strict", openapi3.TypeBoolean, "Reject the transaction if it is likely a duplicate")},
+		body:      handlers.Transaction{},
+		responses: map[int]interface{}{http.StatusCreated: handlers.Transaction{}, http.StatusConflict: handlers.DuplicateWarning{}},
+	},
+	{
+		id: "ListDuplicateTransactions", method: http.MethodGet, path: "/transactions/duplicates", summary: "List duplicate transactions",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.DuplicatePair{}}},
+	},
+	{
+		id: "BulkTransactions", method: http.MethodPost, path: "/transactions/bulk", summary: "Create, update and delete transactions at once",
+		body: handlers.BulkTransactions{},
+		responses: map[int]interface{}{
+			http.StatusOK:                  handlers.BulkTransactionsResponse{},
+			http.StatusUnprocessableEntity: handlers.BulkTransactionsResponse{},
+		},
+	},
+	{
+		id: "GetTransaction", method: http.MethodGet, path: "/transactions/{id}", summary: "Get transaction",
+		headers:   openapi3.Parameters{ifNoneMatch},
+		responses: map[int]interface{}{http.StatusOK: handlers.Transaction{}, http.StatusNotModified: nil},
+	},
+	{
+		id: "UpdateTransaction", method: http.MethodPatch, path: "/transactions/{id}", summary: "Update transaction",
+		headers: openapi3.Parameters{ifMatch}, body: patchOf{handlers.Transaction{}},
+		responses: map[int]interface{}{http.StatusOK: handlers.Transaction{}}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeleteTransaction", method: http.MethodDelete, path: "/transactions/{id}", summary: "Delete transaction",
+		headers:   openapi3.Parameters{ifMatch},
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "MergeTransactions", method: http.MethodPost, path: "/transactions/{id}/merge", summary: "Merge transactions",
+		headers: openapi3.Parameters{ifMatch},
+		body:    handlers.TransactionMerge{}, responses: map[int]interface{}{http.StatusOK: handlers.Transaction{}},
+		errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "ListTransactionSplits", method: http.MethodGet, path: "/transactions/{id}/splits", summary: "List transaction splits",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.TransactionSplit{}}},
+	},
+	{
+		id: "CreateTransactionSplits", method: http.MethodPost, path: "/transactions/{id}/splits", summary: "Split transaction",
+		headers:   openapi3.Parameters{ifMatch},
+		body:      handlers.TransactionSplits{},
+		responses: map[int]interface{}{http.StatusCreated: list{handlers.TransactionSplit{}}},
+		errors:    []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "UpdateTransactionSplits", method: http.MethodPut, path: "/transactions/{id}/splits", summary: "Replace transaction splits",
+		headers:   openapi3.Parameters{ifMatch},
+		body:      handlers.TransactionSplits{},
+		responses: map[int]interface{}{http.StatusOK: list{handlers.TransactionSplit{}}},
+		errors:    []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeleteTransactionSplits", method: http.MethodDelete, path: "/transactions/{id}/splits", summary: "Remove transaction splits",
+		headers:   openapi3.Parameters{ifMatch},
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "GetTransactionShares", method: http.MethodGet, path: "/transactions/{id}/shares", summary: "Get transaction shares",
+		responses: map[int]interface{}{http.StatusOK: handlers.TransactionShares{}},
+	},
+	{
+		id: "UpdateTransactionShares", method: http.MethodPut, path: "/transactions/{id}/shares", summary: "Share transaction",
+		headers: openapi3.Parameters{ifMatch},
+		body:    handlers.TransactionShares{}, responses: map[int]interface{}{http.StatusOK: handlers.TransactionShares{}},
+		errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "DeleteTransactionShares", method: http.MethodDelete, path: "/transactions/{id}/shares", summary: "Stop sharing transaction",
+		headers:   openapi3.Parameters{ifMatch},
+		responses: map[int]interface{}{http.StatusNoContent: nil}, errors: []int{http.StatusConflict, http.StatusPreconditionFailed},
+	},
+	{
+		id: "ListAttachments", method: http.MethodGet, path: "/transactions/{id}/attachments", summary: "List attachments",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Attachment{}}},
+	},
+	{
+		id: "CreateAttachment", method: http.MethodPost, path: "/transactions/{id}/attachments", summary: "Upload attachment",
+		body: upload{"file"}, responses: map[int]interface{}{http.StatusCreated: handlers.Attachment{}}, errors: []int{http.StatusRequestEntityTooLarge},
+	},
+	{
+		id: "GetAttachment", method: http.MethodGet, path: "/transactions/{id}/attachments/{attachment_id}", summary: "Get attachment",
+		responses: map[int]interface{}{http.StatusOK: handlers.Attachment{}},
+	},
+	{
+		id: "DownloadAttachment", method: http.MethodGet, path: "/transactions/{id}/attachments/{attachment_id}/download", summary: "Download attachment",
+		responses: map[int]interface{}{http.StatusOK: anyFile{}},
+	},
+	{
+		id: "DeleteAttachment", method: http.MethodDelete, path: "/transactions/{id}/attachments/{attachment_id}", summary: "Delete attachment",
+		responses: map[int]interface{}{http.StatusNoContent: nil},
+	},
+	{
+		id: "ListTransactionHistory", method: http.MethodGet, path: "/transactions/{id}/history", summary: "Transaction history",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.AuditEntry{}}},
+	},
+
+	// Trash
+	{
+		id: "ListTrash", method: http.MethodGet, path: "/trash/", summary: "List trash",
+		responses: map[int]interface{}{http.StatusOK: handlers.Trash{}},
+	},
+	{
+		id: "RestoreWallet", method: http.MethodPost, path: "/trash/wallets/{id}/restore", summary: "Restore wallet from trash",
+		responses: map[int]interface{}{http.StatusOK: handlers.Wallet{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "RestoreParty", method: http.MethodPost, path: "/trash/parties/{id}/restore", summary: "Restore party from trash",
+		responses: map[int]interface{}{http.StatusOK: handlers.Party{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "RestoreTransaction", method: http.MethodPost, path: "/trash/transactions/{id}/restore", summary: "Restore transaction from trash",
+		responses: map[int]interface{}{http.StatusOK: handlers.Transaction{}}, errors: []int{http.StatusConflict},
+	},
+
+	// Audit
+	{
+		id: "ListAudit", method: http.MethodGet, path: "/audit", summary: "List changes",
+		query: openapi3.Parameters{
+			queryParam("resource", openapi3.TypeString, "One of 'user', 'wallet', 'party' or 'transaction'"),
+			queryParam("id", openapi3.TypeInteger, "The ID of the resource"),
+		},
+		responses: map[int]interface{}{http.StatusOK: list{handlers.AuditEntry{}}},
+	},
+	{
+		id: "VerifyAudit", method: http.MethodGet, path: "/audit/verify", summary: "Verify audit log",
+		responses: map[int]interface{}{http.StatusOK: handlers.AuditVerification{}},
+	},
+
+	// Suggestions
+	{
+		id: "SuggestCategories", method: http.MethodGet, path: "/suggestions/categories", summary: "Suggest categories",
+		query: openapi3.Parameters{
+			queryParam("q", openapi3.TypeString, "Description of the transaction"),
+			queryParam("amount", openapi3.TypeString, "Amount of the transaction"),
+			queryParam("wallet_id", openapi3.TypeInteger, "Wallet of the transaction"),
+			queryParam("k", openapi3.TypeInteger, "How many categories to suggest at most"),
+		},
+		responses: map[int]interface{}{http.StatusOK: list{handlers.CategorySuggestion{}}},
+	},
+
+	// Rules
+	{
+		id: "ListRules", method: http.MethodGet, path: "/rules/", summary: "List rules",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Rule{}}},
+	},
+	{
+		id: "CreateRule", method: http.MethodPost, path: "/rules/", summary: "Create rule",
+		body: handlers.RuleRequest{}, responses: map[int]interface{}{http.StatusCreated: handlers.Rule{}},
+	},
+	{
+		id: "ApplyRules", method: http.MethodPost, path: "/rules/apply", summary: "Re-apply rules",
+		query:     openapi3.Parameters{queryParam("dry_run", openapi3.TypeBoolean, "Only answer with the changes the rules would make")},
+		responses: map[int]interface{}{http.StatusOK: handlers.ApplyRulesResult{}},
+	},
+	{
+		id: "GetRule", method: http.MethodGet, path: "/rules/{id}", summary: "Get rule",
+		responses: map[int]interface{}{http.StatusOK: handlers.Rule{}},
+	},
+	{
+		id: "UpdateRule", method: http.MethodPatch, path: "/rules/{id}", summary: "Update rule",
+		body: patchOf{handlers.RuleRequest{}}, responses: map[int]interface{}{http.StatusOK: handlers.Rule{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "DeleteRule", method: http.MethodDelete, path: "/rules/{id}", summary: "Delete rule",
+		responses: map[int]interface{}{http.StatusNoContent: nil},
+	},
+
+	// Webhooks
+	{
+		id: "ListWebhooks", method: http.MethodGet, path: "/webhooks/", summary: "List webhooks",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Webhook{}}},
+	},
+	{
+		id: "CreateWebhook", method: http.MethodPost, path: "/webhooks/", summary: "Create webhook",
+		body: handlers.WebhookRequest{}, responses: map[int]interface{}{http.StatusCreated: handlers.Webhook{}},
+	},
+	{
+		id: "GetWebhook", method: http.MethodGet, path: "/webhooks/{id}", summary: "Get webhook",
+		responses: map[int]interface{}{http.StatusOK: handlers.Webhook{}},
+	},
+	{
+		id: "UpdateWebhook", method: http.MethodPatch, path: "/webhooks/{id}", summary: "Update webhook",
+		body: patchOf{handlers.WebhookRequest{}}, responses: map[int]interface{}{http.StatusOK: handlers.Webhook{}}, errors: []int{http.StatusConflict},
+	},
+	{
+		id: "DeleteWebhook", method: http.MethodDelete, path: "/webhooks/{id}", summary: "Delete webhook",
+		responses: map[int]interface{}{http.StatusNoContent: nil},
+	},
+	{
+		id: "ListWebhookDeliveries", method: http.MethodGet, path: "/webhooks/{id}/deliveries", summary: "List webhook deliveries",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.WebhookDelivery{}}},
+	},
+	{
+		id: "ReplayWebhookDelivery", method: http.MethodPost, path: "/webhooks/{id}/deliveries/{delivery_id}/replay", summary: "Replay webhook delivery",
+		responses: map[int]interface{}{http.StatusAccepted: handlers.WebhookDelivery{}},
+	},
+
+	// Events
+	{
+		id: "StreamEvents", method: http.MethodGet, path: "/events/stream", summary: "Stream events",
+		headers:   openapi3.Parameters{headerParam("Last-Event-ID", "Send the events after this one first, or a reset event if too many were missed")},
+		responses: map[int]interface{}{http.StatusOK: eventStream{handlers.StreamEvent{}}},
+	},
+
+	// GraphQL
+	{
+		id: "ServeGraphQL", method: http.MethodPost, path: "/graphql", summary: "Execute GraphQL query",
+		body: graphQLRequest{}, responses: map[int]interface{}{http.StatusOK: graphQLResponse{}},
+	},
+
+	// Exchange rates
+	{
+		id: "ListExchangeRates", method: http.MethodGet, path: "/exchange-rates/", summary: "List exchange rates",
+		query: openapi3.Parameters{
+			queryParam("base", openapi3.TypeString, "Only rates from this currency"),
+			queryParam("quote", openapi3.TypeString, "Only rates to this currency"),
+		},
+		responses: map[int]interface{}{http.StatusOK: list{handlers.ExchangeRate{}}},
+	},
+	{
+		id: "CreateExchangeRate", method: http.MethodPost, path: "/exchange-rates/", summary: "Create exchange rate",
+		body: handlers.ExchangeRate{}, responses: map[int]interface{}{http.StatusCreated: handlers.ExchangeRate{}},
+	},
+	{
+		id: "ImportExchangeRates", method: http.MethodPost, path: "/exchange-rates/import", summary: "Import exchange rates",
+		query:     openapi3.Parameters{queryParam("format", openapi3.TypeString, "Either 'xml' or 'csv', instead of the extension or content type of the file")},
+		body:      anyFile{},
+		responses: map[int]interface{}{http.StatusCreated: handlers.ExchangeRateImport{}},
+	},
+
+	// Reports
+	{
+		id: "GetBalanceReport", method: http.MethodGet, path: "/reports/balances", summary: "Balances",
+		query: period, responses: map[int]interface{}{http.StatusOK: handlers.BalanceReport{}}, errors: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		id: "GetPartyReport", method: http.MethodGet, path: "/reports/parties", summary: "Totals by party",
+		query: period, responses: map[int]interface{}{http.StatusOK: handlers.PartyReport{}}, errors: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		id: "GetCategoryReport", method: http.MethodGet, path: "/reports/categories", summary: "Totals by category",
+		query: period, responses: map[int]interface{}{http.StatusOK: handlers.CategoryReport{}}, errors: []int{http.StatusUnprocessableEntity},
+	},
+
+	// Search
+	{
+		id: "Search", method: http.MethodGet, path: "/search", summary: "Search everything",
+		query: openapi3.Parameters{
+			queryParam("q", openapi3.TypeString, "The words to search for"),
+			queryParam("type", openapi3.TypeString, "Only results of these comma separated types"),
+			queryParam("limit", openapi3.TypeInteger, "How many results to answer with at most, between 1 and 100"),
+		},
+		responses: map[int]interface{}{http.StatusOK: list{handlers.SearchResult{}}},
+	},
+
+	// Shared expenses
+	{
+		id: "GetSharedBalances", method: http.MethodGet, path: "/shared/balances", summary: "Balances with other users",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.SharedBalance{}}},
+	},
+	{
+		id: "SimplifySharedDebts", method: http.MethodGet, path: "/shared/simplify", summary: "Simplify debts",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Payment{}}},
+	},
+	{
+		id: "ListSettlements", method: http.MethodGet, path: "/shared/settlements", summary: "List settlements",
+		responses: map[int]interface{}{http.StatusOK: list{handlers.Settlement{}}},
+	},
+	{
+		id: "CreateSettlement", method: http.MethodPost, path: "/shared/settlements", summary: "Record settlement",
+		body: handlers.Settlement{}, responses: map[int]interface{}{http.StatusCreated: handlers.Settlement{}},
+	},
+	{
+		id: "DeleteSettlement", method: http.MethodDelete, path: "/shared/settlements/{id}", summary: "Delete settlement",
+		responses: map[int]interface{}{http.StatusNoContent: nil},
+	},
+
+	// Documentation
+	{
+		id: "ServeDocument", method: http.MethodGet, path: "/openapi.json", summary: "Get this OpenAPI document", public: true,
+		responses: map[int]interface{}{http.StatusOK: map[string]interface{}{}},
+	},
+	{
+		id: "ServeDocs", method: http.MethodGet, path: "/docs", summary: "Browse this OpenAPI document", public: true,
+		responses: map[int]interface{}{http.StatusOK: html{}},
+	},
+}
+
+type (
+	graphQLRequest struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+
+	graphQLResponse struct {
+		Data   map[string]interface{}   `json:"data"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+
+	// html is a web page
+	html struct{}
+)
@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/shopspring/decimal"
+)
+
+const componentsPrefix = "#/components/schemas/"
+
+// decimalSchema is the schema of amounts. They are answered as strings so no precision is lost, but
+// requests may have them as numbers as well.
+var decimalSchema = &openapi3.Schema{
+	Type:        &openapi3.Types{openapi3.TypeString, openapi3.TypeNumber},
+	Pattern:     `^-?[0-9]+(\.[0-9]+)?$`,
+	Description: "Decimal number, answered as a string such as \"-12.50\"",
+	Example:     "-12.50",
+}
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	decimalType = reflect.TypeOf(decimal.Decimal{})
+	rawType     = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas generates the JSON schemas of Go types the way encoding/json marshals them. Named structs
+// become components which are referenced, the others are inlined.
+type schemas struct {
+	components openapi3.Schemas
+	types      map[string]reflect.Type
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: openapi3.Schemas{"Decimal": openapi3.NewSchemaRef("", decimalSchema)},
+		types:      map[string]reflect.Type{"Decimal": decimalType},
+	}
+}
+
+// of is the schema of values of the type. Pointers may be null, slices and maps aren't because the
+// handlers answer with empty ones.
+func (s *schemas) of(t reflect.Type) *openapi3.SchemaRef {
+	switch t {
+	case timeType:
+		return inline(&openapi3.Schema{Type: &openapi3.Types{openapi3.TypeString}, Format: "date-time"})
+	case decimalType:
+		return s.ref("Decimal")
+	case rawType:
+		return inline(&openapi3.Schema{})
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(s.of(t.Elem()))
+	case reflect.Bool:
+		return typed(openapi3.TypeBoolean)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return typed(openapi3.TypeInteger)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema := &openapi3.Schema{Type: &openapi3.Types{openapi3.TypeInteger}}
+		return inline(schema.WithMin(0))
+	case reflect.Float32, reflect.Float64:
+		return typed(openapi3.TypeNumber)
+	case reflect.String:
+		return typed(openapi3.TypeString)
+	case reflect.Slice, reflect.Array:
+		return inline(&openapi3.Schema{Type: &openapi3.Types{openapi3.TypeArray}, Items: s.of(t.Elem())})
+	case reflect.Map:
+		return inline(openapi3.NewObjectSchema().WithAdditionalProperties(s.of(t.Elem()).Value))
+	case reflect.Struct:
+		if t.Name() == "" {
+			return inline(s.object(t))
+		}
+		return s.component(t)
+	}
+	// interface{} can be anything
+	return inline(&openapi3.Schema{})
+}
+
+// component adds the schema of the struct to the components. It is named after the type, prefixed
+// with its package unless it's one of the handlers or of this package.
+func (s *schemas) component(t reflect.Type) *openapi3.SchemaRef {
+	name := title(t.Name())
+	if pkg := path.Base(t.PkgPath()); pkg != "handlers" && pkg != "openapi" {
+		name = title(pkg) + name
+	}
+	if existing, ok := s.types[name]; ok {
+		if existing != t {
+			panic(fmt.Sprintf("openapi: %s and %s have the same name", existing, t))
+		}
+		return s.ref(name)
+	}
+
+	// The type is known before its fields are, so recursive types refer to themselves
+	s.types[name] = t
+	s.components[name] = openapi3.NewSchemaRef("", &openapi3.Schema{})
+	*s.components[name].Value = *s.object(t)
+	return s.ref(name)
+}
+
+func (s *schemas) ref(name string) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef(componentsPrefix+name, s.components[name].Value)
+}
+
+// object is the schema of the fields of the struct. Embedded structs are combined with allOf, like
+// encoding/json lifts their fields.
+func (s *schemas) object(t reflect.Type) *openapi3.Schema {
+	object := openapi3.NewObjectSchema()
+	var embedded []*openapi3.SchemaRef
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+
+		if f.Anonymous && name == "" {
+			embedded = append(embedded, s.of(indirect(f.Type)))
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		object.WithPropertyRef(name, s.of(f.Type))
+	}
+
+	if len(embedded) == 0 {
+		return object
+	}
+	return &openapi3.Schema{AllOf: append(embedded, inline(object))}
+}
+
+// patch is the schema of a JSON Merge Patch of the struct. Any member may be null, which clears it.
+func (s *schemas) patch(t reflect.Type) *openapi3.SchemaRef {
+	name := t.Name() + "Patch"
+	if _, ok := s.components[name]; !ok {
+		object := openapi3.NewObjectSchema()
+		for property, schema := range s.object(t).Properties {
+			object.WithPropertyRef(property, nullable(schema))
+		}
+		s.types[name] = nil
+		s.components[name] = openapi3.NewSchemaRef("", object)
+	}
+	return s.ref(name)
+}
+
+// jsonName is the name of the field in JSON, empty for fields named after the Go field and embedded
+// structs without a tag. Fields that aren't marshaled aren't ok.
+func jsonName(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" && !f.Anonymous {
+		return "", false
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	return strings.Split(tag, ",")[0], true
+}
+
+func title(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+func inline(schema *openapi3.Schema) *openapi3.SchemaRef {
+	return openapi3.NewSchemaRef("", schema)
+}
+
+func typed(typ string) *openapi3.SchemaRef {
+	return inline(&openapi3.Schema{Type: &openapi3.Types{typ}})
+}
+
+// nullable allows null in addition to the values of the schema
+func nullable(schema *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if schema.Ref != "" {
+		return inline(&openapi3.Schema{AnyOf: openapi3.SchemaRefs{schema, typed(openapi3.TypeNull)}})
+	}
+	if schema.Value.Type == nil || schema.Value.Type.Includes(openapi3.TypeNull) {
+		return schema
+	}
+
+	copied := *schema.Value
+	types := append(append(openapi3.Types{}, *copied.Type...), openapi3.TypeNull)
+	copied.Type = &types
+	return inline(&copied)
+}
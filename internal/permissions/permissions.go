@@ -0,0 +1,78 @@
+package permissions
+
+import (
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"net/http"
+)
+
+// Roles of household members, from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+var ranks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// IsValidRole checks if the role is one of the household roles
+func IsValidRole(role string) bool {
+	_, ok := ranks[role]
+	return ok
+}
+
+// Allows checks if the role grants at least the permissions of the required role
+func Allows(role, required string) bool {
+	return ranks[role] >= ranks[required] && ranks[role] > 0
+}
+
+// RequiredRole returns the role needed for a request: reading requires viewer, anything else editor
+func RequiredRole(method string) string {
+	if method == http.MethodGet || method == http.MethodHead {
+		return RoleViewer
+	}
+	return RoleEditor
+}
+
+// Check decides whether the user may access a resource. Resources outside of a household are only
+// accessible by the user they belong to; resources of a household by its members with a sufficient role.
+func Check(repo repository.Repository, userID, ownerID uint, householdID *uint, required string) (bool, error) {
+	if householdID == nil {
+		return ownerID == userID, nil
+	}
+
+	member, err := repo.HouseholdMemberGet(*householdID, userID)
+	if err != nil {
+		if err == repository.ErrorRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return Allows(member.Role, required), nil
+}
+
+// Roles are the roles of a user in the households they are a member of, by household ID. They
+// decide about many resources at once without querying the memberships for every one of them.
+type Roles map[uint]string
+
+// NewRoles collects the roles of the memberships of a user
+func NewRoles(memberships []*model.HouseholdMember) Roles {
+	roles := make(Roles, len(memberships))
+	for _, m := range memberships {
+		roles[m.HouseholdID] = m.Role
+	}
+	return roles
+}
+
+// Check decides like the Check function, the user must be the one the roles belong to
+func (r Roles) Check(userID, ownerID uint, householdID *uint, required string) bool {
+	if householdID == nil {
+		return ownerID == userID
+	}
+	return Allows(r[*householdID], required)
+}
@@ -0,0 +1,75 @@
+package permissions_test
+
+import (
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"net/http"
+	"testing"
+)
+
+func TestAllows(t *testing.T) {
+	testCases := []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{permissions.RoleOwner, permissions.RoleOwner, true},
+		{permissions.RoleOwner, permissions.RoleViewer, true},
+		{permissions.RoleEditor, permissions.RoleEditor, true},
+		{permissions.RoleEditor, permissions.RoleOwner, false},
+		{permissions.RoleViewer, permissions.RoleEditor, false},
+		{permissions.RoleViewer, permissions.RoleViewer, true},
+		{"", permissions.RoleViewer, false},
+		{"admin", permissions.RoleViewer, false},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.role+" as "+tC.required, func(t *testing.T) {
+			if got := permissions.Allows(tC.role, tC.required); got != tC.expected {
+				t.Errorf("got %v, want %v", got, tC.expected)
+			}
+		})
+	}
+}
+
+func TestRequiredRole(t *testing.T) {
+	if got := permissions.RequiredRole(http.MethodGet); got != permissions.RoleViewer {
+		t.Errorf("got %q for GET, want %q", got, permissions.RoleViewer)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		if got := permissions.RequiredRole(method); got != permissions.RoleEditor {
+			t.Errorf("got %q for %s, want %q", got, method, permissions.RoleEditor)
+		}
+	}
+}
+
+func TestRolesCheck(t *testing.T) {
+	household, otherHousehold, unknownHousehold := uint(1), uint(2), uint(3)
+	roles := permissions.NewRoles([]*model.HouseholdMember{
+		{HouseholdID: household, UserID: 1, Role: permissions.RoleEditor},
+		{HouseholdID: otherHousehold, UserID: 1, Role: permissions.RoleViewer},
+	})
+
+	testCases := []struct {
+		desc        string
+		ownerID     uint
+		householdID *uint
+		required    string
+		expected    bool
+	}{
+		{"own resource", 1, nil, permissions.RoleEditor, true},
+		{"resource of another user", 2, nil, permissions.RoleViewer, false},
+		{"editor of the household", 2, &household, permissions.RoleEditor, true},
+		{"viewer of the household", 2, &otherHousehold, permissions.RoleEditor, false},
+		{"not a member of the household", 1, &unknownHousehold, permissions.RoleViewer, false},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if got := roles.Check(1, tC.ownerID, tC.householdID, tC.required); got != tC.expected {
+				t.Errorf("got %v, want %v", got, tC.expected)
+			}
+		})
+	}
+}
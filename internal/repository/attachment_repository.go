@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+)
+
+func (r *repository) AttachmentCreate(a *model.Attachment) error {
+	return genericCreate(r, a)
+}
+
+func (r *repository) AttachmentGet(id uint) (*model.Attachment, error) {
+	return genericGet[model.Attachment](r, map[string]interface{}{"id": id})
+}
+
+func (r *repository) AttachmentDelete(id uint) error {
+	return genericDelete[model.Attachment](r, id)
+}
+
+func (r *repository) AttachmentList(transactionID uint) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	if tx := r.db.Where("transaction_id = ?", transactionID).Order("id").Find(&attachments); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return attachments, nil
+}
+
+// AttachmentListByUser lists the attachments that are deleted together with the user: the ones they
+// uploaded and the ones of transactions in their wallets, of their parties or created by them
+func (r *repository) AttachmentListByUser(userID uint) ([]*model.Attachment, error) {
+	return r.attachmentListByTransactions(
+		"attachments.user_id = ? OR transactions.user_id = ? OR wallets.user_id = ? OR parties.user_id = ?",
+		userID, userID, userID, userID,
+	)
+}
+
+func (r *repository) attachmentListByTransactions(query string, args ...interface{}) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	tx := r.db.
+		Joins("JOIN transactions ON transactions.id = attachments.transaction_id").
+		Joins("JOIN wallets ON wallets.id = transactions.wallet_id").
+		Joins("JOIN parties ON parties.id = transactions.party_id").
+		Where(query, args...).
+		Find(&attachments)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return attachments, nil
+}
@@ -0,0 +1,383 @@
+package repository
+
+import (
+	"context"
+	"expense-api/internal/model"
+	"reflect"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Actor is who changes the records through a repository, see WithActor
+type Actor struct {
+	// UserID is 0 when no user is signed in, e.g. while signing up
+	UserID    uint
+	RequestID string
+	IP        string
+}
+
+type actorKey struct{}
+
+// auditedTables are the tables whose changes are audited, with the resource their records are
+var auditedTables = map[string]string{
+	"users":        model.AuditResourceUser,
+	"wallets":      model.AuditResourceWallet,
+	"parties":      model.AuditResourceParty,
+	"transactions": model.AuditResourceTransaction,
+}
+
+// auditIgnoredColumns change with every create or update, they would only clutter the diffs
+var auditIgnoredColumns = map[string]bool{"id": true, "created_at": true, "updated_at": true, "version": true}
+
+// auditRedactedColumns are secrets, the diffs only tell that they changed
+var auditRedactedColumns = map[string]bool{"password": true, "salt": true}
+
+const auditRedacted = "[redacted]"
+
+// auditChainLock is the class of the advisory locks on the hash chains of the users
+const auditChainLock = 4401
+
+// auditBeforeKey keeps the records an update or delete matched before it ran
+const auditBeforeKey = "audit:before"
+
+// WithActor returns a repository whose changes are audited as changes made by the actor
+func (r *repository) WithActor(actor *Actor) Repository {
+	return &repository{r.db.WithContext(context.WithValue(r.db.Statement.Context, actorKey{}, actor))}
+}
+
+// AuditList lists the audit entries of a record, the oldest first
+func (r *repository) AuditList(resource string, resourceID uint) ([]*model.AuditEntry, error) {
+	var entries []*model.AuditEntry
+	tx := r.db.Where("resource = ? AND resource_id = ?", resource, resourceID).Order("id").Find(&entries)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return entries, nil
+}
+
+// AuditListChain lists up to limit entries of the user's hash chain that come after the entry,
+// oldest first
+func (r *repository) AuditListChain(userID, afterID uint, limit int) ([]*model.AuditEntry, error) {
+	var entries []*model.AuditEntry
+	tx := r.db.Where("user_id = ? AND id > ?", userID, afterID).Order("id").Limit(limit).Find(&entries)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return entries, nil
+}
+
+// AuditChainUsers lists the users who have a hash chain
+func (r *repository) AuditChainUsers() ([]uint, error) {
+	var users []uint
+	tx := r.db.Model(&model.AuditEntry{}).Where("user_id <> 0").Distinct().Order("user_id").Pluck("user_id", &users)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return users, nil
+}
+
+// registerAuditCallbacks makes every create, update and delete of the audited tables write audit
+// entries. The entries are written in the transaction of the change, so they are only kept if the
+// change is. Updates and deletes read the records they match before and after they run, so bulk
+// changes are audited record by record.
+func registerAuditCallbacks(db *gorm.DB) {
+	callbacks := db.Callback()
+	if callbacks.Create().Get("audit:create") != nil {
+		return
+	}
+
+	callbacks.Create().Before("gorm:after_create").Register("audit:create", auditCreate)
+	callbacks.Update().Before("gorm:update").Register("audit:before_update", auditBefore)
+	callbacks.Update().Before("gorm:after_update").Register("audit:update", auditAfter)
+	callbacks.Delete().Before("gorm:delete").Register("audit:before_delete", auditBefore)
+	callbacks.Delete().Before("gorm:after_delete").Register("audit:delete", auditAfter)
+}
+
+func audited(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil && auditedTables[db.Statement.Table] != ""
+}
+
+func auditCreate(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+
+	var created []reflect.Value
+	switch value := reflect.Indirect(db.Statement.ReflectValue); value.Kind() {
+	case reflect.Struct:
+		created = append(created, value)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			created = append(created, reflect.Indirect(value.Index(i)))
+		}
+	}
+	writeAuditEntries(db, nil, created)
+}
+
+// auditBefore reads the records matched by the conditions and the primary key of the statement
+func auditBefore(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+
+	var conds []clause.Expression
+	if where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+		conds = append(conds, where.Exprs...)
+	}
+	_, values := schema.GetIdentityFieldValuesMap(db.Statement.ReflectValue, db.Statement.Schema.PrimaryFields)
+	if len(values) > 0 {
+		column, queryValues := schema.ToQueryValues(db.Statement.Table, db.Statement.Schema.PrimaryFieldDBNames, values)
+		conds = append(conds, clause.IN{Column: column, Values: queryValues})
+	}
+	if len(conds) == 0 {
+		// gorm refuses to update or delete without conditions
+		return
+	}
+
+	before, err := readAuditedRecords(db, clause.Where{Exprs: conds})
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	db.InstanceSet(auditBeforeKey, before)
+}
+
+// auditAfter reads the records auditBefore read again and audits the ones that changed
+func auditAfter(db *gorm.DB) {
+	if !audited(db) {
+		return
+	}
+
+	value, ok := db.InstanceGet(auditBeforeKey)
+	if !ok {
+		return
+	}
+	before := value.([]reflect.Value)
+	if len(before) == 0 {
+		return
+	}
+
+	ids := make([]interface{}, len(before))
+	for i, record := range before {
+		ids[i], _ = db.Statement.Schema.PrioritizedPrimaryField.ValueOf(record)
+	}
+	after, err := readAuditedRecords(db, clause.IN{Column: clause.PrimaryColumn, Values: ids})
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	writeAuditEntries(db, before, after)
+}
+
+// readAuditedRecords reads the records of the statement's table that match the condition, including
+// the ones in the trash
+func readAuditedRecords(db *gorm.DB, cond clause.Expression) ([]reflect.Value, error) {
+	records := reflect.New(reflect.SliceOf(reflect.PtrTo(db.Statement.Schema.ModelType)))
+	if err := db.Session(&gorm.Session{}).Unscoped().Clauses(cond).Find(records.Interface()).Error; err != nil {
+		return nil, err
+	}
+
+	values := make([]reflect.Value, records.Elem().Len())
+	for i := range values {
+		values[i] = records.Elem().Index(i).Elem()
+	}
+	return values, nil
+}
+
+// writeAuditEntries writes an entry for every record whose fields differ before and after, and
+// the domain events of the changes. Records missing from before were created, the ones
+// missing from after were deleted.
+func writeAuditEntries(db *gorm.DB, before, after []reflect.Value) {
+	s := db.Statement.Schema
+	id := func(record reflect.Value) uint {
+		value, _ := s.PrioritizedPrimaryField.ValueOf(record)
+		return value.(uint)
+	}
+
+	records := map[uint]*[2]reflect.Value{}
+	var order []uint
+	for i, side := range [][]reflect.Value{before, after} {
+		for _, record := range side {
+			pair, ok := records[id(record)]
+			if !ok {
+				pair = &[2]reflect.Value{}
+				records[id(record)] = pair
+				order = append(order, id(record))
+			}
+			pair[i] = record
+		}
+	}
+
+	actor, _ := db.Statement.Context.Value(actorKey{}).(*Actor)
+	if actor == nil {
+		actor = &Actor{}
+	}
+	var actorID *uint
+	if actor.UserID != 0 {
+		actorID = &actor.UserID
+	}
+
+	// Postgres keeps microseconds, the hash has to cover the time as it is stored
+	now := db.NowFunc().Truncate(time.Microsecond)
+
+	var entries []*model.AuditEntry
+	var changed []reflect.Value
+	for _, recordID := range order {
+		pair := records[recordID]
+		changes := auditDiff(s, pair[0], pair[1])
+		if len(changes) == 0 {
+			continue
+		}
+
+		record := pair[1]
+		if !record.IsValid() {
+			record = pair[0]
+		}
+		entry := &model.AuditEntry{
+			UserID:     auditOwner(s, recordID, record),
+			ActorID:    actorID,
+			RequestID:  actor.RequestID,
+			IP:         actor.IP,
+			Resource:   auditedTables[db.Statement.Table],
+			ResourceID: recordID,
+			Action:     auditAction(pair[0], pair[1], changes),
+			Changes:    changes,
+		}
+		entry.CreatedAt = now
+		entries = append(entries, entry)
+		changed = append(changed, record)
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+	if err := chainAuditEntries(db, entries); err != nil {
+		db.AddError(err)
+		return
+	}
+	if err := db.Session(&gorm.Session{}).Create(&entries).Error; err != nil {
+		db.AddError(err)
+		return
+	}
+	db.AddError(writeOutboxEvents(db, entries, changed))
+}
+
+// auditOwner is the user whose hash chain the entries about the record go to: the user the record
+// belongs to, users are their own
+func auditOwner(s *schema.Schema, id uint, record reflect.Value) uint {
+	if field := s.LookUpField("user_id"); field != nil {
+		owner, _ := field.ValueOf(record)
+		return owner.(uint)
+	}
+	return id
+}
+
+// chainAuditEntries links the entries to the last entries of their users' hash chains. The chains
+// stay locked until the transaction ends, so concurrent changes can't link to the same entry.
+func chainAuditEntries(db *gorm.DB, entries []*model.AuditEntry) error {
+	var users []uint
+	last := map[uint]string{}
+	for _, entry := range entries {
+		if _, ok := last[entry.UserID]; !ok {
+			last[entry.UserID] = ""
+			users = append(users, entry.UserID)
+		}
+	}
+	// Locking in the same order everywhere avoids deadlocks
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+
+	for _, userID := range users {
+		tx := db.Session(&gorm.Session{})
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", auditChainLock, userID).Error; err != nil {
+			return err
+		}
+
+		var hashes []string
+		err := tx.Model(&model.AuditEntry{}).
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Limit(1).
+			Pluck("hash", &hashes).Error
+		if err != nil {
+			return err
+		}
+		if len(hashes) > 0 {
+			last[userID] = hashes[0]
+		}
+	}
+
+	for _, entry := range entries {
+		entry.PrevHash = last[entry.UserID]
+		hash, err := entry.ComputeHash()
+		if err != nil {
+			return err
+		}
+		entry.Hash = hash
+		last[entry.UserID] = hash
+	}
+	return nil
+}
+
+// auditDiff lists the columns whose values differ, before or after is invalid if the record doesn't
+// exist on that side of the change
+func auditDiff(s *schema.Schema, before, after reflect.Value) model.AuditChanges {
+	changes := model.AuditChanges{}
+	for _, field := range s.Fields {
+		if field.DBName == "" || auditIgnoredColumns[field.DBName] {
+			continue
+		}
+
+		change := &model.AuditChange{}
+		if before.IsValid() {
+			change.Before, _ = field.ValueOf(before)
+		}
+		if after.IsValid() {
+			change.After, _ = field.ValueOf(after)
+		}
+		if before.IsValid() && after.IsValid() && reflect.DeepEqual(change.Before, change.After) {
+			continue
+		}
+
+		if auditRedactedColumns[field.DBName] {
+			if change.Before != nil {
+				change.Before = auditRedacted
+			}
+			if change.After != nil {
+				change.After = auditRedacted
+			}
+		}
+		changes[field.DBName] = change
+	}
+	return changes
+}
+
+// auditAction tells what happened to the record. Records are deleted by moving them to the trash,
+// deleting them when they are in the trash already purges them.
+func auditAction(before, after reflect.Value, changes model.AuditChanges) string {
+	switch {
+	case !before.IsValid():
+		return model.AuditCreate
+	case !after.IsValid():
+		if change, ok := changes["deleted_at"]; ok && trashed(change.Before) {
+			return model.AuditPurge
+		}
+		return model.AuditDelete
+	}
+
+	if change, ok := changes["deleted_at"]; ok {
+		if trashed(change.After) {
+			return model.AuditDelete
+		}
+		return model.AuditRestore
+	}
+	return model.AuditUpdate
+}
+
+func trashed(deletedAt interface{}) bool {
+	d, ok := deletedAt.(gorm.DeletedAt)
+	return ok && d.Valid
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// exchangeRateBatchSize keeps the bind parameters of an insert well below the 65535 Postgres allows,
+// historical ECB files have far more rates than that
+const exchangeRateBatchSize = 1000
+
+// ExchangeRateUpsert creates the rates, overwriting existing rates for the same date and currency pair.
+// If the rates have the same date and currency pair several times, the last one wins.
+func (r *repository) ExchangeRateUpsert(rates []*model.ExchangeRate) error {
+	rates = uniqueExchangeRates(rates)
+	if len(rates) == 0 {
+		return nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		upsert := clause.OnConflict{
+			Columns:   []clause.Column{{Name: "date"}, {Name: "base"}, {Name: "quote"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+		}
+		for start := 0; start < len(rates); start += exchangeRateBatchSize {
+			end := start + exchangeRateBatchSize
+			if end > len(rates) {
+				end = len(rates)
+			}
+			batch := rates[start:end]
+			if err := tx.Clauses(upsert).Create(&batch).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// ExchangeRateList lists rates for the given pair; an empty base or quote matches every currency
+func (r *repository) ExchangeRateList(base, quote string) ([]*model.ExchangeRate, error) {
+	query := map[string]interface{}{}
+	if base != "" {
+		query["base"] = base
+	}
+	if quote != "" {
+		query["quote"] = quote
+	}
+
+	var rates []*model.ExchangeRate
+	if tx := r.db.Where(query).Order("date desc").Find(&rates); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return rates, nil
+}
+
+// ExchangeRateListByCurrencies lists all rates between any of the given currencies
+func (r *repository) ExchangeRateListByCurrencies(currencies []string) ([]*model.ExchangeRate, error) {
+	var rates []*model.ExchangeRate
+	if tx := r.db.Where("base IN ? AND quote IN ?", currencies, currencies).Find(&rates); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return rates, nil
+}
+
+// uniqueExchangeRates drops all but the last rate for every date and currency pair. Postgres refuses
+// to update the same row twice in one insert.
+func uniqueExchangeRates(rates []*model.ExchangeRate) []*model.ExchangeRate {
+	type key struct {
+		date, base, quote string
+	}
+
+	index := make(map[key]int, len(rates))
+	unique := make([]*model.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		k := key{rate.Date.Format("2006-01-02"), rate.Base, rate.Quote}
+		if i, ok := index[k]; ok {
+			unique[i] = rate
+			continue
+		}
+		index[k] = len(unique)
+		unique = append(unique, rate)
+	}
+	return unique
+}
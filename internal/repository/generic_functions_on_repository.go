@@ -2,8 +2,32 @@ package repository
 
 import (
 	"expense-api/internal/model"
+
+	"gorm.io/gorm"
 )
 
+// nextVersion bumps the version of the records changed by a bulk update
+var nextVersion = gorm.Expr("version + 1")
+
+// bumpVersion gives the record a new version when something that belongs to it changed, like the
+// aliases of a party
+func bumpVersion(tx *gorm.DB, m interface{}, id uint) error {
+	return tx.Model(m).Where("id = ?", id).Updates(map[string]interface{}{"version": nextVersion}).Error
+}
+
+// bumpVersionFrom gives the record a new version like bumpVersion, but only if it still has the version
+// it was read at. Otherwise the record was updated since then and it fails with ErrorVersionConflict.
+func bumpVersionFrom(tx *gorm.DB, m interface{}, id, version uint) error {
+	bumped := tx.Model(m).Where("id = ? AND version = ?", id, version).Updates(map[string]interface{}{"version": nextVersion})
+	if bumped.Error != nil {
+		return bumped.Error
+	}
+	if bumped.RowsAffected == 0 {
+		return ErrorVersionConflict
+	}
+	return nil
+}
+
 func genericCreate[M model.GormModel](r *repository, model *M) error {
 	if tx := r.db.Create(model); tx.Error != nil {
 		return checkError(tx.Error)
@@ -11,10 +35,26 @@ func genericCreate[M model.GormModel](r *repository, model *M) error {
 	return nil
 }
 
-func genericSave[M model.GormModel](r *repository, model *M) error {
-	if tx := r.db.Save(model); tx.Error != nil {
+// genericSave updates all columns of the model and bumps its version. The version of the model is the
+// version it was read at, if the record was updated since then it is left alone.
+func genericSave[M model.GormModel](r *repository, m *M) error {
+	return save(r.db, any(m).(model.Versioned))
+}
+
+func save(db *gorm.DB, m model.Versioned) error {
+	version := m.VersionRef()
+	read := *version
+
+	*version++
+	tx := db.Select("*").Where("version = ?", read).Save(m)
+	if tx.Error != nil {
+		*version = read
 		return checkError(tx.Error)
 	}
+	if tx.RowsAffected == 0 {
+		*version = read
+		return ErrorVersionConflict
+	}
 	return nil
 }
 
@@ -37,6 +77,30 @@ func genericDelete[M model.GormModel](r *repository, id uint) error {
 	return nil
 }
 
+// genericGetTrashed gets a record that is in the trash
+func genericGetTrashed[M model.GormModel](r *repository, id uint) (*M, error) {
+	var model M
+	if tx := r.db.Unscoped().Where("deleted_at IS NOT NULL").First(&model, id); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return &model, nil
+}
+
+// genericListTrashed lists the records of the table in the trash that the user can see, the most
+// recently trashed first
+func genericListTrashed[M model.GormModel](r *repository, table string, userID uint) ([]*M, error) {
+	var models []*M
+	tx := r.db.Unscoped().
+		Scopes(r.visibleTo(table, userID)).
+		Where(table + ".deleted_at IS NOT NULL").
+		Order(table + ".deleted_at DESC, " + table + ".id").
+		Find(&models)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return models, nil
+}
+
 func genericList[M model.GormModel](r *repository, query map[string]interface{}) ([]*M, error) {
 	var models []*M
 	if tx := r.db.Where(query).Find(&models); tx.Error != nil {
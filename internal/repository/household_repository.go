@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+)
+
+// HouseholdCreate creates the household and makes the user its owner
+func (r *repository) HouseholdCreate(h *model.Household, ownerID uint, ownerRole string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(h).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.HouseholdMember{HouseholdID: h.ID, UserID: ownerID, Role: ownerRole}).Error
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+func (r *repository) HouseholdUpdate(id uint, updated *model.Household) (*model.Household, error) {
+	household, err := r.HouseholdGet(id)
+	if err != nil {
+		return nil, err
+	}
+
+	household.Version = updated.Version
+	household.Name = updated.Name
+
+	err = genericSave(r, household)
+	return household, err
+}
+
+func (r *repository) HouseholdGet(id uint) (*model.Household, error) {
+	return genericGet[model.Household](r, map[string]interface{}{"id": id})
+}
+
+// HouseholdDelete deletes a household that has no other members than the one deleting it. Everything
+// in it goes to that member first, also what former members created, so that no records of other users
+// stay behind in the wallets that become theirs.
+func (r *repository) HouseholdDelete(id uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Locking the household keeps invitations from being accepted in the meantime
+		var household model.Household
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&household, id).Error; err != nil {
+			return err
+		}
+
+		var members []*model.HouseholdMember
+		if err := tx.Where("household_id = ?", id).Find(&members).Error; err != nil {
+			return err
+		}
+		if len(members) > 1 {
+			return ErrorHouseholdHasMembers
+		}
+		if len(members) == 1 {
+			if err := handOver(tx, id, members[0].UserID, "user_id <> ?", members[0].UserID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Delete(&household).Error
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// HouseholdListByUser lists the memberships of the user together with their households
+func (r *repository) HouseholdListByUser(userID uint) ([]*model.HouseholdMember, error) {
+	var members []*model.HouseholdMember
+	if tx := r.db.Preload("Household").Where("user_id = ?", userID).Order("household_id").Find(&members); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return members, nil
+}
+
+// HouseholdHandOver makes another member the creator of everything a member created in the household,
+// trashed records included, so that it stays in the household when the member's account is deleted
+func (r *repository) HouseholdHandOver(householdID, fromUserID, toUserID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		return handOver(tx, householdID, toUserID, "user_id = ?", fromUserID)
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// handOver makes toUserID the creator of the records in the household whose creators match the query,
+// trashed records included
+func handOver(tx *gorm.DB, householdID, toUserID uint, query string, args ...interface{}) error {
+	wallets := tx.Unscoped().Model(&model.Wallet{}).Select("id").Where("household_id = ?", householdID)
+	transactions := tx.Unscoped().Model(&model.Transaction{}).Select("id").Where("household_id = ?", householdID)
+
+	moves := []struct {
+		model interface{}
+		query string
+		arg   interface{}
+	}{
+		{&model.Wallet{}, "household_id = ?", householdID},
+		{&model.Party{}, "household_id = ?", householdID},
+		{&model.Transaction{}, "household_id = ?", householdID},
+		{&model.Attachment{}, "transaction_id IN (?)", transactions},
+		{&model.Reconciliation{}, "wallet_id IN (?)", wallets},
+	}
+	for _, m := range moves {
+		err := tx.Unscoped().Model(m.model).Where(query, args...).Where(m.query, m.arg).Updates(map[string]interface{}{
+			"user_id": toUserID,
+			"version": nextVersion,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *repository) HouseholdMemberGet(householdID, userID uint) (*model.HouseholdMember, error) {
+	return genericGet[model.HouseholdMember](r, map[string]interface{}{"household_id": householdID, "user_id": userID})
+}
+
+// HouseholdMemberList lists the members of a household together with their users
+func (r *repository) HouseholdMemberList(householdID uint) ([]*model.HouseholdMember, error) {
+	var members []*model.HouseholdMember
+	if tx := r.db.Preload("User").Where("household_id = ?", householdID).Order("id").Find(&members); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return members, nil
+}
+
+func (r *repository) HouseholdMemberUpdate(householdID, userID uint, role string) (*model.HouseholdMember, error) {
+	member, err := r.HouseholdMemberGet(householdID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Role = role
+
+	err = genericSave(r, member)
+	return member, err
+}
+
+func (r *repository) HouseholdMemberDelete(householdID, userID uint) error {
+	member, err := r.HouseholdMemberGet(householdID, userID)
+	if err != nil {
+		return err
+	}
+	return genericDelete[model.HouseholdMember](r, member.ID)
+}
+
+// HouseholdSharedByUsers is whether both users are members of at least one common household
+func (r *repository) HouseholdSharedByUsers(userID, otherID uint) (bool, error) {
+	memberships := r.db.Model(&model.HouseholdMember{}).Select("household_id").Where("user_id = ?", userID)
+
+	var count int64
+	tx := r.db.Model(&model.HouseholdMember{}).
+		Where("user_id = ? AND household_id IN (?)", otherID, memberships).
+		Count(&count)
+	if tx.Error != nil {
+		return false, checkError(tx.Error)
+	}
+	return count > 0, nil
+}
+
+func (r *repository) HouseholdInvitationCreate(i *model.HouseholdInvitation) error {
+	if i.Status == "" {
+		i.Status = InvitationPending
+	}
+	return genericCreate(r, i)
+}
+
+func (r *repository) HouseholdInvitationGet(id uint) (*model.HouseholdInvitation, error) {
+	return genericGet[model.HouseholdInvitation](r, map[string]interface{}{"id": id})
+}
+
+func (r *repository) HouseholdInvitationList(householdID uint) ([]*model.HouseholdInvitation, error) {
+	var invitations []*model.HouseholdInvitation
+	if tx := r.db.Where("household_id = ?", householdID).Order("id desc").Find(&invitations); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return invitations, nil
+}
+
+// HouseholdInvitationListByEmail lists the pending invitations sent to the email together with their households
+func (r *repository) HouseholdInvitationListByEmail(email string) ([]*model.HouseholdInvitation, error) {
+	var invitations []*model.HouseholdInvitation
+	tx := r.db.
+		Preload("Household").
+		Where("email = ? AND status = ?", email, InvitationPending).
+		Order("id desc").
+		Find(&invitations)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return invitations, nil
+}
+
+// HouseholdInvitationAccept marks the invitation as accepted and adds the user to the household with the invited role
+func (r *repository) HouseholdInvitationAccept(id, userID uint) (*model.HouseholdMember, error) {
+	invitation, err := r.HouseholdInvitationGet(id)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &model.HouseholdMember{HouseholdID: invitation.HouseholdID, UserID: userID, Role: invitation.Role}
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(invitation).Update("status", InvitationAccepted).Error; err != nil {
+			return err
+		}
+		return tx.Create(member).Error
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return member, nil
+}
+
+func (r *repository) HouseholdInvitationDecline(id uint) error {
+	invitation, err := r.HouseholdInvitationGet(id)
+	if err != nil {
+		return err
+	}
+
+	if tx := r.db.Model(invitation).Update("status", InvitationDeclined); tx.Error != nil {
+		return checkError(tx.Error)
+	}
+	return nil
+}
+
+// visibleTo limits a query to the rows of the table the user can see: their own rows outside of any
+// household and the rows of every household they are a member of
+func (r *repository) visibleTo(table string, userID uint) func(*gorm.DB) *gorm.DB {
+	memberships := r.db.Model(&model.HouseholdMember{}).Select("household_id").Where("user_id = ?", userID)
+
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(
+			"(("+table+".user_id = ? AND "+table+".household_id IS NULL) OR "+table+".household_id IN (?))",
+			userID, memberships,
+		)
+	}
+}
+
+// checkPartyHousehold fails with ErrorPartyHousehold if any of the transactions would have a party of
+// another household than householdID once they are in it. Transactions only use the parties of their
+// household, or personal parties if they are personal, so everybody who sees them sees their party.
+func checkPartyHousehold(transactions *gorm.DB, householdID *uint) error {
+	var count int64
+	err := transactions.
+		Joins("JOIN parties ON parties.id = transactions.party_id").
+		Where("parties.household_id IS DISTINCT FROM ?", householdID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrorPartyHousehold
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyCreate reserves the key for its user. Expired keys and abandoned reservations are
+// replaced, a key which is still valid results in ErrorUniqueConstaintViolation.
+func (r *repository) IdempotencyKeyCreate(k *model.IdempotencyKey) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.
+			Where("user_id = ? AND key = ?", k.UserID, k.Key).
+			Where("expires_at <= ? OR (status_code = 0 AND lease_expires_at <= ?)", now, now).
+			Delete(&model.IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(k).Error
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// IdempotencyKeyGet returns the key of the user, unless it expired or its reservation was abandoned
+func (r *repository) IdempotencyKeyGet(userID uint, key string) (*model.IdempotencyKey, error) {
+	var k model.IdempotencyKey
+	now := time.Now()
+	tx := r.db.
+		Where("user_id = ? AND key = ? AND expires_at > ?", userID, key, now).
+		Where("status_code <> 0 OR lease_expires_at > ?", now).
+		First(&k)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return &k, nil
+}
+
+// IdempotencyKeyComplete stores the response to the request the key was reserved for
+func (r *repository) IdempotencyKeyComplete(id uint, statusCode int, body []byte) error {
+	tx := r.db.Model(&model.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status_code": statusCode,
+		"body":        body,
+		"version":     nextVersion,
+	})
+	if tx.Error != nil {
+		return checkError(tx.Error)
+	}
+	return nil
+}
+
+func (r *repository) IdempotencyKeyDelete(id uint) error {
+	return genericDelete[model.IdempotencyKey](r, id)
+}
+
+// IdempotencyKeyPurge deletes the keys which expired before the given time
+func (r *repository) IdempotencyKeyPurge(before time.Time) error {
+	tx := r.db.Where("expires_at <= ?", before).Delete(&model.IdempotencyKey{})
+	if tx.Error != nil {
+		return checkError(tx.Error)
+	}
+	return nil
+}
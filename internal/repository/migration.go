@@ -2,19 +2,96 @@ package repository
 
 import (
 	"expense-api/internal/model"
+	"expense-api/internal/search"
 
 	"gorm.io/gorm"
 )
 
 var models = []interface{}{
 	model.User{},
+	model.Household{},
+	model.HouseholdMember{},
+	model.HouseholdInvitation{},
 	model.Wallet{},
 	model.Party{},
+	model.PartyAlias{},
 	model.Transaction{},
+	model.TransactionSplit{},
+	model.TransactionShare{},
+	model.Attachment{},
+	model.Reconciliation{},
+	model.Rule{},
+	model.Settlement{},
+	model.ExchangeRate{},
+	model.IdempotencyKey{},
+	model.AuditEntry{},
+	model.Webhook{},
+	model.WebhookDelivery{},
+	model.OutboxEvent{},
+}
+
+// searchMigrations add the full-text search columns and their GIN indexes. The columns are generated
+// by postgres, so they are always in sync with the columns they are built from.
+var searchMigrations = []string{
+	`CREATE EXTENSION IF NOT EXISTS unaccent`,
+	`DO $$
+	BEGIN
+		IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = '` + search.TextSearchConfig + `') THEN
+			CREATE TEXT SEARCH CONFIGURATION ` + search.TextSearchConfig + ` (COPY = simple);
+			ALTER TEXT SEARCH CONFIGURATION ` + search.TextSearchConfig + `
+				ALTER MAPPING FOR hword, hword_part, word WITH unaccent, simple;
+		END IF;
+	END
+	$$`,
+	// array_to_string is only stable, generated columns need immutable functions
+	`CREATE OR REPLACE FUNCTION xpense_tags_text(text[]) RETURNS text
+		AS $$ SELECT array_to_string($1, ' ') $$
+		LANGUAGE sql IMMUTABLE`,
+	`ALTER TABLE transactions ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('` + search.TextSearchConfig + `', coalesce(description, '')), 'A') ||
+		setweight(to_tsvector('` + search.TextSearchConfig + `', xpense_tags_text(tags)), 'B') ||
+		setweight(to_tsvector('` + search.TextSearchConfig + `', coalesce(category, '')), 'C')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_transactions_search_vector ON transactions USING GIN (search_vector)`,
+	`ALTER TABLE parties ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('` + search.TextSearchConfig + `', coalesce(name, '')), 'A')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_parties_search_vector ON parties USING GIN (search_vector)`,
+	`ALTER TABLE wallets ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('` + search.TextSearchConfig + `', coalesce(name, '')), 'A') ||
+		setweight(to_tsvector('` + search.TextSearchConfig + `', coalesce(description, '')), 'B')
+	) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_wallets_search_vector ON wallets USING GIN (search_vector)`,
+}
+
+// trashMigrations drop the unique indexes on the names of wallets and parties that included the
+// trashed ones. Their replacements only cover the wallets and parties that aren't in the trash.
+var trashMigrations = []string{
+	`DROP INDEX IF EXISTS idx_userid_wallet_name`,
+	`DROP INDEX IF EXISTS idx_userid_party_name`,
+}
+
+// householdMigrations drop the unique indexes on the names of wallets and parties per user, which
+// let the members of a household create wallets and parties with the same name. Their replacements
+// are unique per household for the wallets and parties of households.
+var householdMigrations = []string{
+	`DROP INDEX IF EXISTS idx_wallet_name`,
+	`DROP INDEX IF EXISTS idx_party_name`,
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(models...)
+	if err := db.AutoMigrate(models...); err != nil {
+		return err
+	}
+
+	migrations := append(append(searchMigrations, trashMigrations...), householdMigrations...)
+	for _, migration := range migrations {
+		if err := db.Exec(migration).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func Cleanup(db *gorm.DB) error {
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"expense-api/internal/model"
+	"reflect"
+	"strconv"
+
+	"github.com/jackc/pgx/v4/stdlib"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// outboxLock is the advisory lock of the instance that dispatches the outbox
+	outboxLock = 4601
+	// outboxChannel is the channel of the notifications about published events
+	outboxChannel = "outbox_events"
+)
+
+// outboxEvents name the events of the audited actions. Purges aren't events, the records left when
+// they went to the trash.
+var outboxEvents = map[string]map[string]string{
+	model.AuditResourceTransaction: {
+		model.AuditCreate:  model.EventTransactionCreated,
+		model.AuditUpdate:  model.EventTransactionUpdated,
+		model.AuditDelete:  model.EventTransactionDeleted,
+		model.AuditRestore: model.EventTransactionRestored,
+	},
+	model.AuditResourceWallet: {
+		model.AuditCreate:  model.EventWalletCreated,
+		model.AuditUpdate:  model.EventWalletUpdated,
+		model.AuditDelete:  model.EventWalletDeleted,
+		model.AuditRestore: model.EventWalletRestored,
+	},
+	model.AuditResourceParty: {
+		model.AuditCreate:  model.EventPartyCreated,
+		model.AuditUpdate:  model.EventPartyUpdated,
+		model.AuditDelete:  model.EventPartyDeleted,
+		model.AuditRestore: model.EventPartyRestored,
+	},
+}
+
+// OutboxDispatch hands the oldest limit events that weren't published yet to dispatch, which marks
+// the ones it published, and stores what dispatch changed. Only one instance dispatches at a time,
+// so the events are published in order; the others dispatch nothing. It returns how many events
+// dispatch got.
+func (r *repository) OutboxDispatch(limit int, dispatch func(events []*model.OutboxEvent)) (int, error) {
+	var events []*model.OutboxEvent
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLock).Row().Scan(&locked); err != nil || !locked {
+			return err
+		}
+
+		if err := tx.Where("published_at IS NULL").Order("id").Limit(limit).Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		dispatch(events)
+
+		for _, e := range events {
+			err := tx.Model(&model.OutboxEvent{}).Where("id = ?", e.ID).Updates(map[string]interface{}{
+				"attempts":     e.Attempts,
+				"error":        e.Error,
+				"published_at": e.PublishedAt,
+				"version":      nextVersion,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, checkError(err)
+	}
+	return len(events), nil
+}
+
+func (r *repository) OutboxGet(id uint) (*model.OutboxEvent, error) {
+	return genericGet[model.OutboxEvent](r, map[string]interface{}{"id": id})
+}
+
+// OutboxLatestID returns the ID of the latest event in the outbox, or 0 if it's empty
+func (r *repository) OutboxLatestID() (uint, error) {
+	var id uint
+	if tx := r.db.Model(&model.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id); tx.Error != nil {
+		return 0, checkError(tx.Error)
+	}
+	return id, nil
+}
+
+// OutboxList returns the events after the one with ID afterID about the records of the user and
+// of the households, oldest first
+func (r *repository) OutboxList(userID uint, householdIDs []uint, afterID uint, limit int) ([]*model.OutboxEvent, error) {
+	var events []*model.OutboxEvent
+	tx := r.db.Where("id > ?", afterID)
+	if len(householdIDs) > 0 {
+		tx = tx.Where("(user_id = ? OR household_id IN ?)", userID, householdIDs)
+	} else {
+		tx = tx.Where("user_id = ?", userID)
+	}
+	if tx := tx.Order("id").Limit(limit).Find(&events); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return events, nil
+}
+
+// OutboxNotify tells every instance listening with OutboxListen that the event with the ID was
+// published
+func (r *repository) OutboxNotify(id uint) error {
+	if tx := r.db.Exec("SELECT pg_notify(?, ?)", outboxChannel, strconv.FormatUint(uint64(id), 10)); tx.Error != nil {
+		return checkError(tx.Error)
+	}
+	return nil
+}
+
+// OutboxListen calls notify with the ID of every event OutboxNotify tells about, on any instance,
+// until the context is done or the connection fails. It holds on to a connection of the pool while
+// it listens.
+func (r *repository) OutboxListen(ctx context.Context, notify func(id uint)) error {
+	db, err := r.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		c := driverConn.(*stdlib.Conn).Conn()
+		if _, err := c.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+			return err
+		}
+
+		for {
+			n, err := c.WaitForNotification(ctx)
+			if err != nil {
+				// Stopping to wait closes the connection, keep it out of the pool
+				if c.IsClosed() {
+					return driver.ErrBadConn
+				}
+				return err
+			}
+			if id, err := strconv.ParseUint(n.Payload, 10, 64); err == nil {
+				notify(uint(id))
+			}
+		}
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// writeOutboxEvents writes the domain events of the audited changes to the outbox, in the
+// transaction of the changes
+func writeOutboxEvents(db *gorm.DB, entries []*model.AuditEntry, records []reflect.Value) error {
+	types := outboxEvents[auditedTables[db.Statement.Table]]
+	if types == nil {
+		return nil
+	}
+
+	var events []*model.OutboxEvent
+	for i, entry := range entries {
+		eventType := types[entry.Action]
+		if eventType == "" {
+			continue
+		}
+
+		id, err := newEventID()
+		if err != nil {
+			return err
+		}
+		object, err := json.Marshal(outboxObject(db.Statement.Schema, records[i]))
+		if err != nil {
+			return err
+		}
+
+		event := &model.OutboxEvent{
+			EventID:     id,
+			Type:        eventType,
+			Aggregate:   entry.Resource,
+			AggregateID: entry.ResourceID,
+			UserID:      entry.UserID,
+			HouseholdID: outboxHousehold(db.Statement.Schema, records[i]),
+			ActorID:     entry.ActorID,
+			Object:      string(object),
+			Changes:     entry.Changes,
+		}
+		event.CreatedAt = entry.CreatedAt
+		events = append(events, event)
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	return db.Session(&gorm.Session{}).Create(&events).Error
+}
+
+// outboxObject lists the columns of the record, without the secret ones
+func outboxObject(s *schema.Schema, record reflect.Value) map[string]interface{} {
+	object := map[string]interface{}{}
+	for _, field := range s.Fields {
+		if field.DBName == "" || auditRedactedColumns[field.DBName] {
+			continue
+		}
+		object[field.DBName], _ = field.ValueOf(record)
+	}
+	return object
+}
+
+func outboxHousehold(s *schema.Schema, record reflect.Value) *uint {
+	field := s.LookUpField("household_id")
+	if field == nil {
+		return nil
+	}
+	householdID, _ := field.ValueOf(record)
+	return householdID.(*uint)
+}
+
+func newEventID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"expense-api/internal/model"
+	"strings"
+
+	"gorm.io/gorm"
 )
 
 func (r *repository) PartyCreate(p *model.Party) error {
@@ -14,22 +17,261 @@ func (r *repository) PartyUpdate(id uint, updated *model.Party) (*model.Party, e
 		return nil, err
 	}
 
-	if updated.Name != "" {
-		party.Name = updated.Name
+	party.Version = updated.Version
+	party.Name = updated.Name
+
+	if sameHousehold(party.HouseholdID, updated.HouseholdID) {
+		err = genericSave(r, party)
+		return party, err
 	}
 
-	err = genericSave(r, party)
-	return party, err
+	// The transactions using the party have to be in the household it moves to
+	party.HouseholdID = updated.HouseholdID
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Unscoped().Model(&model.Transaction{}).
+			Where("party_id = ? AND household_id IS DISTINCT FROM ?", id, party.HouseholdID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrorPartyHousehold
+		}
+		return save(tx, party)
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return party, nil
 }
 
 func (r *repository) PartyGet(id uint) (*model.Party, error) {
-	return genericGet[model.Party](r, map[string]interface{}{"id": id})
+	var party model.Party
+	if tx := r.db.Scopes(preloadAliases).First(&party, id); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return &party, nil
 }
 
-func (r *repository) PartyDelete(id uint) error {
-	return genericDelete[model.Party](r, id)
+// PartyGetByAlias finds the party the user can see that has the name as an alias, ignoring case
+func (r *repository) PartyGetByAlias(userID uint, name string) (*model.Party, error) {
+	var party model.Party
+	tx := r.db.
+		Scopes(r.visibleTo("parties", userID), preloadAliases).
+		Where("parties.id IN (?)", r.db.Model(&model.PartyAlias{}).Select("party_id").Where("LOWER(name) = LOWER(?)", name)).
+		Order("parties.id").
+		First(&party)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return &party, nil
 }
 
+// PartyMerge atomically merges the sources into the target: their transactions, splits and rules move
+// to the target, their names and aliases become aliases of the target and the sources are deleted
+func (r *repository) PartyMerge(targetID uint, sourceIDs []uint) (*model.Party, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var target model.Party
+		if err := tx.Scopes(preloadAliases).First(&target, targetID).Error; err != nil {
+			return err
+		}
+
+		var sources []*model.Party
+		if err := tx.Scopes(preloadAliases).Where("id IN ?", sourceIDs).Order("id").Find(&sources).Error; err != nil {
+			return err
+		}
+		if len(sources) != len(sourceIDs) {
+			return gorm.ErrRecordNotFound
+		}
+
+		// The transactions of the sources have to be in the household of the target
+		var count int64
+		err := tx.Unscoped().Model(&model.Transaction{}).
+			Where("party_id IN ? AND household_id IS DISTINCT FROM ?", sourceIDs, target.HouseholdID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrorPartyHousehold
+		}
+
+		moves := []struct {
+			model  interface{}
+			column string
+		}{
+			{&model.Transaction{}, "party_id"},
+			{&model.TransactionSplit{}, "party_id"},
+			{&model.Rule{}, "set_party_id"},
+		}
+		for _, m := range moves {
+			err = tx.Model(m.model).Where(m.column+" IN ?", sourceIDs).Updates(map[string]interface{}{
+				m.column:  targetID,
+				"version": nextVersion,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		known := map[string]bool{strings.ToLower(target.Name): true}
+		for _, a := range target.Aliases {
+			known[strings.ToLower(a.Name)] = true
+		}
+
+		var aliases []*model.PartyAlias
+		for _, source := range sources {
+			names := []string{source.Name}
+			for _, a := range source.Aliases {
+				names = append(names, a.Name)
+			}
+
+			for _, name := range names {
+				if !known[strings.ToLower(name)] {
+					known[strings.ToLower(name)] = true
+					aliases = append(aliases, &model.PartyAlias{PartyID: targetID, Name: name})
+				}
+			}
+		}
+
+		if len(aliases) > 0 {
+			if err := tx.Create(&aliases).Error; err != nil {
+				return err
+			}
+		}
+
+		// The aliases are part of the target, it gets a new version
+		if err := bumpVersion(tx, &model.Party{}, targetID); err != nil {
+			return err
+		}
+
+		// The sources live on as aliases of the target, they don't go to the trash
+		return tx.Unscoped().Delete(&model.Party{}, sourceIDs).Error
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return r.PartyGet(targetID)
+}
+
+// PartyAliasDelete deletes the alias of the party, which gets a new version
+func (r *repository) PartyAliasDelete(partyID, aliasID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		deleted := tx.Where("party_id = ?", partyID).Delete(&model.PartyAlias{}, aliasID)
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return bumpVersion(tx, &model.Party{}, partyID)
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+func preloadAliases(db *gorm.DB) *gorm.DB {
+	return db.Preload("Aliases", func(db *gorm.DB) *gorm.DB {
+		return db.Order("party_aliases.name")
+	})
+}
+
+// PartyDelete moves the party to the trash. Parties with transactions are only deleted with cascade,
+// their transactions go to the trash along with them.
+func (r *repository) PartyDelete(id uint, cascade bool) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var party model.Party
+		if err := tx.First(&party, id).Error; err != nil {
+			return err
+		}
+
+		if !cascade {
+			var count int64
+			if err := tx.Model(&model.Transaction{}).Where("party_id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrorPartyNotEmpty
+			}
+		}
+		if err := checkNotReconciled(tx, id, "party_id"); err != nil {
+			return err
+		}
+
+		return trash(tx, &party, id, "party_id")
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// PartyReassign moves the transactions, splits and rules of the party to the target party and the party
+// to the trash. Parties with reconciled transactions can't be reassigned.
+func (r *repository) PartyReassign(id, targetID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var party, target model.Party
+		if err := tx.First(&party, id).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&target, targetID).Error; err != nil {
+			return err
+		}
+		if err := checkNotReconciled(tx, id, "party_id"); err != nil {
+			return err
+		}
+
+		// The transactions of the party have to be in the household of the target
+		var count int64
+		err := tx.Model(&model.Transaction{}).
+			Where("party_id = ? AND household_id IS DISTINCT FROM ?", id, target.HouseholdID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrorPartyHousehold
+		}
+
+		moves := []struct {
+			model  interface{}
+			column string
+		}{
+			{&model.Transaction{}, "party_id"},
+			{&model.TransactionSplit{}, "party_id"},
+			{&model.Rule{}, "set_party_id"},
+		}
+		for _, m := range moves {
+			err = tx.Model(m.model).Where(m.column+" = ?", id).Updates(map[string]interface{}{
+				m.column:  targetID,
+				"version": nextVersion,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return trash(tx, &party, id, "party_id")
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// PartyList lists the user's own parties and the parties of every household they are a member of
 func (r *repository) PartyList(userID uint) ([]*model.Party, error) {
-	return genericList[model.Party](r, map[string]interface{}{"user_id": userID})
+	var parties []*model.Party
+	if tx := r.db.Scopes(r.visibleTo("parties", userID), preloadAliases).Find(&parties); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return parties, nil
+}
+
+// PartyListByIDs lists the parties without their aliases
+func (r *repository) PartyListByIDs(ids []uint) ([]*model.Party, error) {
+	return genericList[model.Party](r, map[string]interface{}{"id": ids})
 }
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// clearedUntil scopes to the cleared and reconciled transactions of the wallet up to and including until
+func clearedUntil(walletID uint, until time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Model(&model.Transaction{}).
+			Where("wallet_id = ? AND timestamp <= ?", walletID, until).
+			Where("status IN ?", []string{model.TransactionCleared, model.TransactionReconciled})
+	}
+}
+
+// clearedBalance adds the opening balance of the wallet to its cleared transactions up to until,
+// unless the wallet was opened later
+func clearedBalance(db *gorm.DB, walletID uint, until time.Time) (decimal.Decimal, error) {
+	var wallet model.Wallet
+	if err := db.First(&wallet, walletID).Error; err != nil {
+		return decimal.Zero, err
+	}
+
+	var balance decimal.Decimal
+	if err := db.Scopes(clearedUntil(walletID, until)).Select("COALESCE(SUM(amount), 0)").Row().Scan(&balance); err != nil {
+		return decimal.Zero, err
+	}
+
+	if wallet.OpeningDate == nil || !wallet.OpeningDate.After(until) {
+		balance = balance.Add(wallet.OpeningBalance)
+	}
+	return balance, nil
+}
+
+// WalletClearedBalance is the balance of the wallet that the bank statement of until should show:
+// its opening balance plus its cleared and reconciled transactions up to and including until
+func (r *repository) WalletClearedBalance(walletID uint, until time.Time) (decimal.Decimal, error) {
+	balance, err := clearedBalance(r.db, walletID, until)
+	if err != nil {
+		return decimal.Zero, checkError(err)
+	}
+	return balance, nil
+}
+
+// ReconciliationCreate locks the cleared transactions of the wallet up to the statement date by
+// marking them reconciled and records the reconciliation. Nothing changes when the cleared balance
+// doesn't match the statement balance (anymore).
+func (r *repository) ReconciliationCreate(rec *model.Reconciliation) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		balance, err := clearedBalance(tx, rec.WalletID, rec.StatementDate)
+		if err != nil {
+			return err
+		}
+		if !balance.Equal(rec.StatementBalance) {
+			return ErrorStatementOutOfBalance
+		}
+
+		res := tx.Scopes(clearedUntil(rec.WalletID, rec.StatementDate)).
+			Where("status = ?", model.TransactionCleared).
+			Updates(map[string]interface{}{
+				"status":  model.TransactionReconciled,
+				"version": nextVersion,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		rec.TransactionCount = int(res.RowsAffected)
+
+		return tx.Create(rec).Error
+	})
+	if err == ErrorStatementOutOfBalance {
+		return err
+	}
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// ReconciliationList lists the reconciliations of the wallet, latest statement first
+func (r *repository) ReconciliationList(walletID uint) ([]*model.Reconciliation, error) {
+	var reconciliations []*model.Reconciliation
+	tx := r.db.
+		Where("wallet_id = ?", walletID).
+		Order("statement_date desc, id desc").
+		Find(&reconciliations)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return reconciliations, nil
+}
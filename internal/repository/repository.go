@@ -1,37 +1,156 @@
 package repository
 
 import (
+	"context"
 	"expense-api/internal/model"
+	"expense-api/internal/search"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
 	UserCreate(firstName, LastName, Email, Password, Salt string) (*model.User, error)
-	UserUpdate(id uint, firstName, LastName, Email string) (*model.User, error)
+	UserUpdate(id uint, u *model.User) (*model.User, error)
 	UserDelete(id uint) error
 	UserGet(id uint) (*model.User, error)
 	UserGetWithEmail(email string) (*model.User, error)
+	UserListByIDs(ids []uint) ([]*model.User, error)
+
+	HouseholdCreate(h *model.Household, ownerID uint, ownerRole string) error
+	HouseholdUpdate(id uint, h *model.Household) (*model.Household, error)
+	HouseholdGet(id uint) (*model.Household, error)
+	HouseholdDelete(id uint) error
+	HouseholdListByUser(userID uint) ([]*model.HouseholdMember, error)
+	HouseholdHandOver(householdID, fromUserID, toUserID uint) error
+
+	HouseholdMemberGet(householdID, userID uint) (*model.HouseholdMember, error)
+	HouseholdMemberList(householdID uint) ([]*model.HouseholdMember, error)
+	HouseholdMemberUpdate(householdID, userID uint, role string) (*model.HouseholdMember, error)
+	HouseholdMemberDelete(householdID, userID uint) error
+	HouseholdSharedByUsers(userID, otherID uint) (bool, error)
+
+	HouseholdInvitationCreate(i *model.HouseholdInvitation) error
+	HouseholdInvitationGet(id uint) (*model.HouseholdInvitation, error)
+	HouseholdInvitationList(householdID uint) ([]*model.HouseholdInvitation, error)
+	HouseholdInvitationListByEmail(email string) ([]*model.HouseholdInvitation, error)
+	HouseholdInvitationAccept(id, userID uint) (*model.HouseholdMember, error)
+	HouseholdInvitationDecline(id uint) error
 
 	WalletCreate(w *model.Wallet) error
 	WalletUpdate(id uint, w *model.Wallet) (*model.Wallet, error)
 	WalletGet(id uint) (*model.Wallet, error)
-	WalletDelete(id uint) error
+	WalletDelete(id uint, cascade bool) error
+	WalletReassign(id, targetID uint) error
+	WalletGetTrashed(id uint) (*model.Wallet, error)
+	WalletListTrashed(userID uint) ([]*model.Wallet, error)
+	WalletRestore(id uint) (*model.Wallet, error)
 	WalletList(userID uint) ([]*model.Wallet, error)
+	WalletListByIDs(ids []uint) ([]*model.Wallet, error)
+	WalletClearedBalance(walletID uint, until time.Time) (decimal.Decimal, error)
+	TransactionSumByWallet(walletIDs []uint) (map[uint]decimal.Decimal, error)
+	TransactionSumExpenses(walletID uint, from, to time.Time) (decimal.Decimal, error)
 
 	PartyCreate(w *model.Party) error
 	PartyUpdate(id uint, w *model.Party) (*model.Party, error)
 	PartyGet(id uint) (*model.Party, error)
-	PartyDelete(id uint) error
+	PartyDelete(id uint, cascade bool) error
+	PartyReassign(id, targetID uint) error
+	PartyGetTrashed(id uint) (*model.Party, error)
+	PartyListTrashed(userID uint) ([]*model.Party, error)
+	PartyRestore(id uint) (*model.Party, error)
 	PartyList(userID uint) ([]*model.Party, error)
+	PartyListByIDs(ids []uint) ([]*model.Party, error)
+	PartyGetByAlias(userID uint, name string) (*model.Party, error)
+	PartyMerge(targetID uint, sourceIDs []uint) (*model.Party, error)
+	PartyAliasDelete(partyID, aliasID uint) error
 
 	TransactionCreate(t *model.Transaction) error
 	TransactionUpdate(id uint, t *model.Transaction) (*model.Transaction, error)
 	TransactionGet(id uint) (*model.Transaction, error)
 	TransactionDelete(id uint) error
+	TransactionGetTrashed(id uint) (*model.Transaction, error)
+	TransactionListTrashed(userID uint) ([]*model.Transaction, error)
+	TransactionRestore(id uint) (*model.Transaction, error)
 	TransactionList(userID uint) ([]*model.Transaction, error)
+	TransactionStamp(userID uint) (string, error)
+	TransactionListByIDs(ids []uint) ([]*model.Transaction, error)
 	TransactionListByWallet(userID, walletID uint) ([]*model.Transaction, error)
 	TransactionListByParty(userID, partyID uint) ([]*model.Transaction, error)
+	TransactionListFiltered(userID uint, filter *TransactionFilter) ([]*model.Transaction, error)
+	TransactionListDuplicateCandidates(t *model.Transaction, window time.Duration) ([]*model.Transaction, error)
+	TransactionMerge(survivorID, duplicateID uint) (*model.Transaction, error)
+	TransactionBulk(ops []*TransactionOperation) (int, error)
+
+	ReconciliationCreate(rec *model.Reconciliation) error
+	ReconciliationList(walletID uint) ([]*model.Reconciliation, error)
+
+	TransactionSplitList(transactionID uint) ([]*model.TransactionSplit, error)
+	TransactionSplitListByUser(userID uint) ([]*model.TransactionSplit, error)
+	TransactionSplitReplace(transactionID, version uint, splits []*model.TransactionSplit) error
+
+	TransactionShareList(transactionID uint) ([]*model.TransactionShare, error)
+	TransactionShareListByUsers(userIDs []uint) ([]*model.TransactionShare, error)
+	TransactionShareReplace(transactionID, version uint, shares []*model.TransactionShare) error
+
+	AttachmentCreate(a *model.Attachment) error
+	AttachmentGet(id uint) (*model.Attachment, error)
+	AttachmentDelete(id uint) error
+	AttachmentList(transactionID uint) ([]*model.Attachment, error)
+	AttachmentListByUser(userID uint) ([]*model.Attachment, error)
+
+	SettlementCreate(s *model.Settlement) error
+	SettlementGet(id uint) (*model.Settlement, error)
+	SettlementDelete(id uint) error
+	SettlementListByUsers(userIDs []uint) ([]*model.Settlement, error)
+
+	RuleCreate(rule *model.Rule) error
+	RuleUpdate(id uint, updated *model.Rule) (*model.Rule, error)
+	RuleGet(id uint) (*model.Rule, error)
+	RuleDelete(id uint) error
+	RuleList(userID uint) ([]*model.Rule, error)
+	RuleRecordHits(hits map[uint]uint) error
+	TransactionApplyRules(transactions []*model.Transaction, hits map[uint]uint) error
+
+	Search(userID uint, query *search.Query) ([]*search.Result, error)
+
+	ExchangeRateUpsert(rates []*model.ExchangeRate) error
+	ExchangeRateList(base, quote string) ([]*model.ExchangeRate, error)
+	ExchangeRateListByCurrencies(currencies []string) ([]*model.ExchangeRate, error)
+
+	TrashPurge(before time.Time) ([]*model.Attachment, error)
+
+	IdempotencyKeyCreate(k *model.IdempotencyKey) error
+	IdempotencyKeyGet(userID uint, key string) (*model.IdempotencyKey, error)
+	IdempotencyKeyComplete(id uint, statusCode int, body []byte) error
+	IdempotencyKeyDelete(id uint) error
+	IdempotencyKeyPurge(before time.Time) error
+
+	AuditList(resource string, resourceID uint) ([]*model.AuditEntry, error)
+	AuditListChain(userID, afterID uint, limit int) ([]*model.AuditEntry, error)
+	AuditChainUsers() ([]uint, error)
+
+	WebhookCreate(w *model.Webhook) error
+	WebhookUpdate(id uint, updated *model.Webhook) (*model.Webhook, error)
+	WebhookGet(id uint) (*model.Webhook, error)
+	WebhookDelete(id uint) error
+	WebhookList(userID uint) ([]*model.Webhook, error)
+	WebhookDeliveryCreate(d *model.WebhookDelivery) error
+	WebhookDeliveryEnqueue(deliveries []*model.WebhookDelivery) error
+	WebhookDeliveryGet(id uint) (*model.WebhookDelivery, error)
+	WebhookDeliveryList(webhookID uint) ([]*model.WebhookDelivery, error)
+	WebhookDeliveryClaim(now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error)
+	WebhookDeliveryRecordAttempt(d *model.WebhookDelivery) error
+
+	OutboxDispatch(limit int, dispatch func([]*model.OutboxEvent)) (int, error)
+	OutboxGet(id uint) (*model.OutboxEvent, error)
+	OutboxLatestID() (uint, error)
+	OutboxList(userID uint, householdIDs []uint, afterID uint, limit int) ([]*model.OutboxEvent, error)
+	OutboxNotify(id uint) error
+	OutboxListen(ctx context.Context, notify func(uint)) error
+
+	WithActor(actor *Actor) Repository
 }
 
 type repository struct {
@@ -39,5 +158,6 @@ type repository struct {
 }
 
 func New(db *gorm.DB) Repository {
+	registerAuditCallbacks(db)
 	return &repository{db}
 }
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) RuleCreate(rule *model.Rule) error {
+	return genericCreate(r, rule)
+}
+
+// RuleUpdate replaces the definition of the rule, keeping its statistics
+func (r *repository) RuleUpdate(id uint, updated *model.Rule) (*model.Rule, error) {
+	rule, err := r.RuleGet(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.Version = updated.Version
+	rule.Name = updated.Name
+	rule.Priority = updated.Priority
+	rule.Enabled = updated.Enabled
+	rule.StopProcessing = updated.StopProcessing
+	rule.DescriptionContains = updated.DescriptionContains
+	rule.DescriptionRegex = updated.DescriptionRegex
+	rule.AmountMin = updated.AmountMin
+	rule.AmountMax = updated.AmountMax
+	rule.WalletID = updated.WalletID
+	rule.SetPartyID = updated.SetPartyID
+	rule.SetCategory = updated.SetCategory
+	rule.AddTags = updated.AddTags
+	rule.SetDescription = updated.SetDescription
+
+	err = genericSave(r, rule)
+	return rule, err
+}
+
+func (r *repository) RuleGet(id uint) (*model.Rule, error) {
+	return genericGet[model.Rule](r, map[string]interface{}{"id": id})
+}
+
+func (r *repository) RuleDelete(id uint) error {
+	return genericDelete[model.Rule](r, id)
+}
+
+// RuleList lists the user's rules in the order they are applied
+func (r *repository) RuleList(userID uint) ([]*model.Rule, error) {
+	var rules []*model.Rule
+	if tx := r.db.Where("user_id = ?", userID).Order("priority DESC").Order("id").Find(&rules); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return rules, nil
+}
+
+// RuleRecordHits adds the number of transactions each rule matched to its hit count
+func (r *repository) RuleRecordHits(hits map[uint]uint) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error { return recordRuleHits(tx, hits) }); err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// TransactionApplyRules atomically stores the fields rules can change on the transactions and the rule hits
+func (r *repository) TransactionApplyRules(transactions []*model.Transaction, hits map[uint]uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, t := range transactions {
+			err := tx.Model(&model.Transaction{}).Where("id = ?", t.ID).Updates(map[string]interface{}{
+				"party_id":    t.PartyID,
+				"category":    t.Category,
+				"tags":        t.Tags,
+				"description": t.Description,
+				"version":     nextVersion,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return recordRuleHits(tx, hits)
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+func recordRuleHits(tx *gorm.DB, hits map[uint]uint) error {
+	now := time.Now()
+	for id, count := range hits {
+		err := tx.Model(&model.Rule{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"hit_count":   gorm.Expr("hit_count + ?", count),
+			"last_hit_at": now,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"expense-api/internal/search"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// headlineOptions configure the excerpts of search results. The matches are marked with characters
+// which aren't HTML, the excerpt is only turned into HTML once it's escaped (see search.Headline).
+var headlineOptions = fmt.Sprintf(
+	`StartSel="%s", StopSel="%s", MaxWords=20, MinWords=5, MaxFragments=2`,
+	search.MarkStart, search.MarkStop,
+)
+
+// searchSources describe how results of each type are selected from their table
+var searchSources = map[string]struct {
+	table    string
+	title    string
+	document string
+	date     string
+}{
+	search.TypeTransaction: {
+		table:    "transactions",
+		title:    "transactions.description",
+		document: "concat_ws(' ', transactions.description, xpense_tags_text(transactions.tags), transactions.category)",
+		date:     "transactions.timestamp",
+	},
+	search.TypeParty: {
+		table:    "parties",
+		title:    "parties.name",
+		document: "parties.name",
+		date:     "parties.created_at",
+	},
+	search.TypeWallet: {
+		table:    "wallets",
+		title:    "wallets.name",
+		document: "concat_ws(' ', wallets.name, wallets.description)",
+		date:     "wallets.created_at",
+	},
+}
+
+// Search ranks the transactions, parties and wallets the user can see against the query
+func (r *repository) Search(userID uint, query *search.Query) ([]*search.Result, error) {
+	requested := make(map[string]bool, len(query.Types))
+	for _, t := range query.Types {
+		requested[t] = true
+	}
+
+	var subqueries []interface{}
+	for _, t := range search.Types {
+		if requested[t] {
+			subqueries = append(subqueries, r.searchSubquery(userID, t, query))
+		}
+	}
+
+	var results []*search.Result
+	if len(subqueries) == 0 {
+		return results, nil
+	}
+
+	sql := "?"
+	for i := 1; i < len(subqueries); i++ {
+		sql += " UNION ALL ?"
+	}
+	sql += " ORDER BY rank DESC, date DESC, id LIMIT ?"
+
+	if tx := r.db.Raw(sql, append(subqueries, query.Limit)...).Scan(&results); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+
+	for _, result := range results {
+		result.Headline = search.Headline(result.Headline)
+	}
+	return results, nil
+}
+
+func (r *repository) searchSubquery(userID uint, resultType string, query *search.Query) *gorm.DB {
+	source := searchSources[resultType]
+
+	tx := r.db.
+		Table(source.table).
+		Select(
+			"?::text AS type, "+source.table+".id AS id, "+source.title+" AS title, "+
+				"ts_headline(?::regconfig, translate("+source.document+", ?, ''), query, ?) AS headline, "+
+				"ts_rank("+source.table+".search_vector, query) AS rank, "+source.date+" AS date",
+			resultType, search.TextSearchConfig, search.MarkStart+search.MarkStop, headlineOptions,
+		).
+		Joins("CROSS JOIN to_tsquery(?::regconfig, ?) AS query", search.TextSearchConfig, query.TSQuery).
+		Scopes(r.visibleTo(source.table, userID)).
+		Where(source.table + ".deleted_at IS NULL").
+		Where(source.table + ".search_vector @@ query")
+
+	if !query.From.IsZero() {
+		tx = tx.Where(source.date+" >= ?", query.From)
+	}
+
+	if !query.To.IsZero() {
+		tx = tx.Where(source.date+" < ?", query.To)
+	}
+
+	return tx
+}
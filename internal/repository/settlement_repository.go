@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+	"time"
+)
+
+func (r *repository) SettlementCreate(s *model.Settlement) error {
+	if s.Timestamp.IsZero() {
+		s.Timestamp = time.Now()
+	}
+	return genericCreate(r, s)
+}
+
+func (r *repository) SettlementGet(id uint) (*model.Settlement, error) {
+	return genericGet[model.Settlement](r, map[string]interface{}{"id": id})
+}
+
+func (r *repository) SettlementDelete(id uint) error {
+	return genericDelete[model.Settlement](r, id)
+}
+
+// SettlementListByUsers lists the settlements paid or received by any of the users, newest first
+func (r *repository) SettlementListByUsers(userIDs []uint) ([]*model.Settlement, error) {
+	var settlements []*model.Settlement
+	tx := r.db.
+		Where("from_user_id IN ? OR to_user_id IN ?", userIDs, userIDs).
+		Order("timestamp desc").
+		Find(&settlements)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return settlements, nil
+}
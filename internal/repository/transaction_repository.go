@@ -2,9 +2,11 @@ package repository
 
 import (
 	"expense-api/internal/model"
+	"fmt"
 	"time"
 
 	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 func (r *repository) TransactionCreate(t *model.Transaction) error {
@@ -20,21 +22,25 @@ func (r *repository) TransactionUpdate(id uint, updated *model.Transaction) (*mo
 		return nil, err
 	}
 
-	if !updated.Timestamp.IsZero() {
-		transaction.Timestamp = updated.Timestamp
+	if !updated.Amount.Equal(transaction.Amount) {
+		if err := r.assertSplitsBalance(id, updated.Amount); err != nil {
+			return nil, err
+		}
+		if err := r.assertSharesBalance(id, updated.Amount); err != nil {
+			return nil, err
+		}
 	}
 
-	if updated.Amount.Cmp(decimal.Zero) != 0 {
-		transaction.Amount = updated.Amount
-	}
-
-	if updated.Description != "" {
-		transaction.Description = updated.Description
-	}
-
-	if updated.WalletID != 0 {
-		transaction.WalletID = updated.WalletID
-	}
+	transaction.Version = updated.Version
+	transaction.Timestamp = updated.Timestamp
+	transaction.Amount = updated.Amount
+	transaction.Description = updated.Description
+	transaction.Category = updated.Category
+	transaction.Tags = updated.Tags
+	transaction.Status = updated.Status
+	transaction.WalletID = updated.WalletID
+	transaction.PartyID = updated.PartyID
+	transaction.HouseholdID = updated.HouseholdID
 
 	err = genericSave(r, transaction)
 	return transaction, err
@@ -44,28 +50,292 @@ func (r *repository) TransactionGet(id uint) (*model.Transaction, error) {
 	return genericGet[model.Transaction](r, map[string]interface{}{"id": id})
 }
 
+// TransactionDelete moves the transaction to the trash
 func (r *repository) TransactionDelete(id uint) error {
 	return genericDelete[model.Transaction](r, id)
 }
 
+// TransactionList lists the user's own transactions and the transactions of every household they are a member of
 func (r *repository) TransactionList(userID uint) ([]*model.Transaction, error) {
-	return r.transactionList(map[string]interface{}{"user_id": userID})
+	return r.transactionList(userID, map[string]interface{}{})
+}
+
+// TransactionStamp sums up the transactions the user created, including the ones in the trash. The
+// stamp changes whenever one of them is created, changed, trashed, restored or purged.
+func (r *repository) TransactionStamp(userID uint) (string, error) {
+	var stamp struct {
+		Count     int64
+		Trashed   int64
+		Versions  int64
+		UpdatedAt *time.Time
+	}
+	tx := r.db.Unscoped().Model(&model.Transaction{}).
+		Select("count(*) AS count, count(deleted_at) AS trashed, coalesce(sum(version), 0) AS versions, max(updated_at) AS updated_at").
+		Where("user_id = ?", userID).
+		Scan(&stamp)
+	if tx.Error != nil {
+		return "", checkError(tx.Error)
+	}
+
+	var updatedAt int64
+	if stamp.UpdatedAt != nil {
+		updatedAt = stamp.UpdatedAt.UnixNano()
+	}
+	return fmt.Sprintf("%d-%d-%d-%d", stamp.Count, stamp.Trashed, stamp.Versions, updatedAt), nil
+}
+
+func (r *repository) TransactionListByIDs(ids []uint) ([]*model.Transaction, error) {
+	return genericList[model.Transaction](r, map[string]interface{}{"id": ids})
 }
 
 func (r *repository) TransactionListByWallet(userID, walletID uint) ([]*model.Transaction, error) {
-	return r.transactionList(map[string]interface{}{
-		"user_id":   userID,
+	return r.transactionList(userID, map[string]interface{}{
 		"wallet_id": walletID,
 	})
 }
 
 func (r *repository) TransactionListByParty(userID, partyID uint) ([]*model.Transaction, error) {
-	return r.transactionList(map[string]interface{}{
-		"user_id":  userID,
+	return r.transactionList(userID, map[string]interface{}{
 		"party_id": partyID,
 	})
 }
 
-func (r *repository) transactionList(query map[string]interface{}) ([]*model.Transaction, error) {
-	return genericList[model.Transaction](r, query)
+// TransactionFilter narrows down the transactions of TransactionListFiltered, zero fields don't.
+// From and To include the transactions at their time, Description matches parts of descriptions
+// regardless of case.
+type TransactionFilter struct {
+	WalletIDs   []uint
+	PartyIDs    []uint
+	Category    string
+	Tag         string
+	Status      string
+	Description string
+	From        *time.Time
+	To          *time.Time
+	MinAmount   *decimal.Decimal
+	MaxAmount   *decimal.Decimal
+}
+
+// TransactionListFiltered lists the transactions the user can see that match the filter, newest first
+func (r *repository) TransactionListFiltered(userID uint, filter *TransactionFilter) ([]*model.Transaction, error) {
+	tx := r.db.Scopes(r.visibleTo("transactions", userID))
+	if len(filter.WalletIDs) > 0 {
+		tx = tx.Where("wallet_id IN ?", filter.WalletIDs)
+	}
+	if len(filter.PartyIDs) > 0 {
+		tx = tx.Where("party_id IN ?", filter.PartyIDs)
+	}
+	if filter.Category != "" {
+		tx = tx.Where("category = ?", filter.Category)
+	}
+	if filter.Tag != "" {
+		tx = tx.Where("? = ANY(tags)", filter.Tag)
+	}
+	if filter.Status != "" {
+		tx = tx.Where("status = ?", filter.Status)
+	}
+	if filter.Description != "" {
+		tx = tx.Where("description ILIKE ?", "%"+escapeLike(filter.Description)+"%")
+	}
+	if filter.From != nil {
+		tx = tx.Where("timestamp >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		tx = tx.Where("timestamp <= ?", *filter.To)
+	}
+	if filter.MinAmount != nil {
+		tx = tx.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		tx = tx.Where("amount <= ?", *filter.MaxAmount)
+	}
+
+	var transactions []*model.Transaction
+	if tx := tx.Order("timestamp DESC, id DESC").Find(&transactions); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return transactions, nil
+}
+
+// TransactionListDuplicateCandidates lists the other transactions in the wallet of t with the same
+// amount whose timestamps are at most window away from it
+func (r *repository) TransactionListDuplicateCandidates(t *model.Transaction, window time.Duration) ([]*model.Transaction, error) {
+	var transactions []*model.Transaction
+	tx := r.db.
+		Where("wallet_id = ? AND amount = ? AND id <> ?", t.WalletID, t.Amount, t.ID).
+		Where("timestamp BETWEEN ? AND ?", t.Timestamp.Add(-window), t.Timestamp.Add(window)).
+		Order("timestamp").
+		Find(&transactions)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return transactions, nil
+}
+
+// TransactionMerge atomically merges the duplicate into the survivor: the attachments of the
+// duplicate are moved to the survivor, which gets the tags of both, and the duplicate is deleted.
+// Both must be in the same wallet (ErrorDifferentWallets), and the duplicate can't have splits or
+// shares (ErrorHasSplitsOrShares), they would be deleted with it.
+func (r *repository) TransactionMerge(survivorID, duplicateID uint) (*model.Transaction, error) {
+	var survivor, duplicate model.Transaction
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&survivor, survivorID).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&duplicate, duplicateID).Error; err != nil {
+			return err
+		}
+
+		if survivor.WalletID != duplicate.WalletID {
+			return ErrorDifferentWallets
+		}
+
+		for _, m := range []interface{}{&model.TransactionSplit{}, &model.TransactionShare{}} {
+			var count int64
+			if err := tx.Model(m).Where("transaction_id = ?", duplicateID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrorHasSplitsOrShares
+			}
+		}
+
+		err := tx.Model(&model.Attachment{}).
+			Where("transaction_id = ?", duplicateID).
+			Update("transaction_id", survivorID).Error
+		if err != nil {
+			return err
+		}
+
+		for _, tag := range duplicate.Tags {
+			if !survivor.Tags.Contains(tag) {
+				survivor.Tags = append(survivor.Tags, tag)
+			}
+		}
+		err = tx.Model(&survivor).Updates(map[string]interface{}{
+			"tags":    survivor.Tags,
+			"version": nextVersion,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		// The duplicate lives on in the survivor, it doesn't go to the trash
+		if err := tx.Unscoped().Delete(&duplicate).Error; err != nil {
+			return err
+		}
+
+		// The survivor was updated with an expression, read it again for its new version
+		return tx.First(&survivor, survivorID).Error
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return &survivor, nil
+}
+
+// Kinds of TransactionOperation
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// TransactionOperation is one of the changes of TransactionBulk. Transaction is the transaction to
+// create or the updated transaction with the given ID, deletions only need the ID.
+type TransactionOperation struct {
+	Kind        string
+	ID          uint
+	Transaction *model.Transaction
+}
+
+// TransactionBulk applies all operations in one database transaction, so either all of them or none
+// succeed. Updated transactions replace the ones in the operations. When an operation fails, its
+// index is returned together with the error, otherwise the index is -1.
+func (r *repository) TransactionBulk(ops []*TransactionOperation) (int, error) {
+	failed := -1
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		txRepo := &repository{tx}
+
+		for i, op := range ops {
+			var err error
+			switch op.Kind {
+			case OperationCreate:
+				err = txRepo.TransactionCreate(op.Transaction)
+			case OperationUpdate:
+				var updated *model.Transaction
+				if updated, err = txRepo.TransactionUpdate(op.ID, op.Transaction); err == nil {
+					op.Transaction = updated
+				}
+			case OperationDelete:
+				err = txRepo.TransactionDelete(op.ID)
+			}
+
+			if err != nil {
+				failed = i
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if failed >= 0 {
+			// The repository methods already translated the error
+			return failed, err
+		}
+		return failed, checkError(err)
+	}
+	return failed, nil
+}
+
+func (r *repository) transactionList(userID uint, query map[string]interface{}) ([]*model.Transaction, error) {
+	var transactions []*model.Transaction
+	if tx := r.db.Scopes(r.visibleTo("transactions", userID)).Where(query).Find(&transactions); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return transactions, nil
+}
+
+// assertSplitsBalance makes sure a split transaction's amount isn't changed without changing its splits
+func (r *repository) assertSplitsBalance(id uint, amount decimal.Decimal) error {
+	splits, err := r.TransactionSplitList(id)
+	if err != nil {
+		return err
+	}
+
+	if len(splits) == 0 {
+		return nil
+	}
+
+	sum := decimal.Zero
+	for _, s := range splits {
+		sum = sum.Add(s.Amount)
+	}
+
+	if !sum.Equal(amount) {
+		return ErrorSplitsOutOfBalance
+	}
+	return nil
+}
+
+// assertSharesBalance makes sure a shared transaction's amount isn't changed without changing its shares
+func (r *repository) assertSharesBalance(id uint, amount decimal.Decimal) error {
+	shares, err := r.TransactionShareList(id)
+	if err != nil {
+		return err
+	}
+
+	if len(shares) == 0 {
+		return nil
+	}
+
+	sum := decimal.Zero
+	for _, s := range shares {
+		sum = sum.Add(s.Amount)
+	}
+
+	if !sum.Equal(amount.Abs()) {
+		return ErrorSharesOutOfBalance
+	}
+	return nil
 }
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) TransactionShareList(transactionID uint) ([]*model.TransactionShare, error) {
+	var shares []*model.TransactionShare
+	if tx := r.db.Preload("User").Where("transaction_id = ?", transactionID).Order("id").Find(&shares); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return shares, nil
+}
+
+// TransactionShareListByUsers lists the shares in which any of the users is either the participant or
+// the one who paid, together with the transaction and its wallet to know the payer and the currency
+func (r *repository) TransactionShareListByUsers(userIDs []uint) ([]*model.TransactionShare, error) {
+	var shares []*model.TransactionShare
+	tx := r.db.
+		Preload("Transaction.Wallet").
+		Joins("JOIN transactions ON transactions.id = transaction_shares.transaction_id").
+		Where("transaction_shares.user_id IN ? OR transactions.user_id IN ?", userIDs, userIDs).
+		Where("transactions.deleted_at IS NULL").
+		Order("transaction_shares.id").
+		Find(&shares)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return shares, nil
+}
+
+// TransactionShareReplace atomically replaces all shares of a transaction; passing no shares stops sharing it.
+// Like its splits, the shares belong to the transaction, see TransactionSplitReplace.
+func (r *repository) TransactionShareReplace(transactionID, version uint, shares []*model.TransactionShare) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionFrom(tx, &model.Transaction{}, transactionID, version); err != nil {
+			return err
+		}
+
+		if err := tx.Where("transaction_id = ?", transactionID).Delete(&model.TransactionShare{}).Error; err != nil {
+			return err
+		}
+
+		if len(shares) == 0 {
+			return nil
+		}
+
+		for _, s := range shares {
+			s.TransactionID = transactionID
+		}
+		return tx.Create(&shares).Error
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
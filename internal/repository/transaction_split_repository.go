@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) TransactionSplitList(transactionID uint) ([]*model.TransactionSplit, error) {
+	var splits []*model.TransactionSplit
+	if tx := r.db.Where("transaction_id = ?", transactionID).Order("id").Find(&splits); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return splits, nil
+}
+
+// TransactionSplitListByUser lists the splits of all transactions the user can see
+func (r *repository) TransactionSplitListByUser(userID uint) ([]*model.TransactionSplit, error) {
+	var splits []*model.TransactionSplit
+	tx := r.db.
+		Joins("JOIN transactions ON transactions.id = transaction_splits.transaction_id").
+		Scopes(r.visibleTo("transactions", userID)).
+		Where("transactions.deleted_at IS NULL").
+		Order("transaction_splits.id").
+		Find(&splits)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return splits, nil
+}
+
+// TransactionSplitReplace atomically replaces all splits of a transaction; passing no splits removes the split.
+// The splits belong to the transaction, which gets a new version. The version is the one the transaction
+// was read at, if it was updated since then the splits are left alone.
+func (r *repository) TransactionSplitReplace(transactionID, version uint, splits []*model.TransactionSplit) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersionFrom(tx, &model.Transaction{}, transactionID, version); err != nil {
+			return err
+		}
+
+		if err := tx.Where("transaction_id = ?", transactionID).Delete(&model.TransactionSplit{}).Error; err != nil {
+			return err
+		}
+
+		if len(splits) == 0 {
+			return nil
+		}
+
+		for _, s := range splits {
+			s.TransactionID = transactionID
+		}
+		return tx.Create(&splits).Error
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (r *repository) WalletGetTrashed(id uint) (*model.Wallet, error) {
+	return genericGetTrashed[model.Wallet](r, id)
+}
+
+func (r *repository) WalletListTrashed(userID uint) ([]*model.Wallet, error) {
+	return genericListTrashed[model.Wallet](r, "wallets", userID)
+}
+
+// WalletRestore takes the wallet out of the trash together with the transactions that were trashed
+// with it
+func (r *repository) WalletRestore(id uint) (*model.Wallet, error) {
+	var wallet *model.Wallet
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if wallet, err = (&repository{tx}).WalletGetTrashed(id); err != nil {
+			return err
+		}
+		return restore(tx, wallet, id, wallet.DeletedAt, "wallet_id", "party_id", &model.Party{})
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return r.WalletGet(id)
+}
+
+func (r *repository) PartyGetTrashed(id uint) (*model.Party, error) {
+	return genericGetTrashed[model.Party](r, id)
+}
+
+func (r *repository) PartyListTrashed(userID uint) ([]*model.Party, error) {
+	return genericListTrashed[model.Party](r, "parties", userID)
+}
+
+// PartyRestore takes the party out of the trash together with the transactions that were trashed
+// with it
+func (r *repository) PartyRestore(id uint) (*model.Party, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		party, err := (&repository{tx}).PartyGetTrashed(id)
+		if err != nil {
+			return err
+		}
+		return restore(tx, party, id, party.DeletedAt, "party_id", "wallet_id", &model.Wallet{})
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return r.PartyGet(id)
+}
+
+func (r *repository) TransactionGetTrashed(id uint) (*model.Transaction, error) {
+	return genericGetTrashed[model.Transaction](r, id)
+}
+
+func (r *repository) TransactionListTrashed(userID uint) ([]*model.Transaction, error) {
+	return genericListTrashed[model.Transaction](r, "transactions", userID)
+}
+
+// TransactionRestore takes the transaction out of the trash. Transactions whose wallet or party is
+// still in the trash can't be restored on their own.
+func (r *repository) TransactionRestore(id uint) (*model.Transaction, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		transaction, err := (&repository{tx}).TransactionGetTrashed(id)
+		if err != nil {
+			return err
+		}
+
+		var wallets, parties int64
+		if err := tx.Model(&model.Wallet{}).Where("id = ?", transaction.WalletID).Count(&wallets).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.Party{}).Where("id = ?", transaction.PartyID).Count(&parties).Error; err != nil {
+			return err
+		}
+		if wallets == 0 || parties == 0 {
+			return ErrorParentTrashed
+		}
+
+		return tx.Model(transaction).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return r.TransactionGet(id)
+}
+
+// TrashPurge permanently deletes the wallets, parties and transactions that were trashed before the
+// time. The attachments deleted along with them are returned, their files are left to the caller.
+func (r *repository) TrashPurge(before time.Time) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		attachments, err = (&repository{tx}).attachmentListByTransactions(
+			"transactions.deleted_at < ? OR wallets.deleted_at < ? OR parties.deleted_at < ?",
+			before, before, before,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Deleting wallets and parties also deletes their transactions, which are in the trash too
+		for _, m := range []interface{}{&model.Transaction{}, &model.Party{}, &model.Wallet{}} {
+			if err := tx.Unscoped().Where("deleted_at < ?", before).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return attachments, nil
+}
+
+// trash moves the record and the transactions that reference it through column to the trash. They
+// are trashed at the same time, which is how restoring the record finds its transactions again.
+func trash(tx *gorm.DB, record interface{}, id uint, column string) error {
+	now := tx.NowFunc()
+
+	err := tx.Model(&model.Transaction{}).
+		Where(column+" = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", now).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(record).Update("deleted_at", now).Error
+}
+
+// checkNotReconciled refuses to move or trash the transactions of a wallet or party if any of them is
+// reconciled. Reconciled transactions have to stay as they were ticked off against the statement.
+func checkNotReconciled(tx *gorm.DB, id uint, column string) error {
+	var count int64
+	err := tx.Model(&model.Transaction{}).
+		Where(column+" = ? AND status = ?", id, model.TransactionReconciled).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrorHasReconciled
+	}
+	return nil
+}
+
+// restore takes the record out of the trash together with the transactions that were trashed with
+// it. Transactions whose other parent, referenced through otherColumn, is still in the trash stay
+// there.
+func restore(tx *gorm.DB, record interface{}, id uint, deletedAt gorm.DeletedAt, column, otherColumn string, other interface{}) error {
+	err := tx.Model(&model.Transaction{}).
+		Where(column+" = ? AND deleted_at = ?", id, deletedAt.Time).
+		Where(otherColumn+" IN (?)", tx.Model(other).Select("id")).
+		Update("deleted_at", nil).Error
+	if err != nil {
+		return err
+	}
+	return tx.Model(record).Update("deleted_at", nil).Error
+}
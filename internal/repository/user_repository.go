@@ -16,23 +16,17 @@ func (r *repository) UserCreate(firstName, lastName, email, password, salt strin
 	return &user, err
 }
 
-func (r *repository) UserUpdate(id uint, firstName, lastName, email string) (*model.User, error) {
+func (r *repository) UserUpdate(id uint, updated *model.User) (*model.User, error) {
 	user, err := r.UserGet(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if user.FirstName != firstName {
-		user.FirstName = firstName
-	}
-
-	if user.LastName != lastName {
-		user.LastName = lastName
-	}
-
-	if user.Email != email {
-		user.Email = email
-	}
+	user.Version = updated.Version
+	user.FirstName = updated.FirstName
+	user.LastName = updated.LastName
+	user.Email = updated.Email
+	user.BaseCurrency = updated.BaseCurrency
 
 	err = genericSave(r, user)
 	return user, err
@@ -49,3 +43,7 @@ func (r *repository) UserGet(id uint) (*model.User, error) {
 func (r *repository) UserGetWithEmail(email string) (*model.User, error) {
 	return genericGet[model.User](r, map[string]interface{}{"email": email})
 }
+
+func (r *repository) UserListByIDs(ids []uint) ([]*model.User, error) {
+	return genericList[model.User](r, map[string]interface{}{"id": ids})
+}
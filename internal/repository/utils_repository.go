@@ -2,6 +2,7 @@ package repository
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/jackc/pgconn"
 	"gorm.io/gorm"
@@ -11,6 +12,18 @@ var (
 	ErrorRecordNotFound           = errors.New("resource not found")
 	ErrorOther                    = errors.New("an error occurred")
 	ErrorUniqueConstaintViolation = errors.New("record already exists (duplicate unique key)")
+	ErrorSplitsOutOfBalance       = errors.New("the transaction's splits don't add up to its amount")
+	ErrorSharesOutOfBalance       = errors.New("the transaction's shares don't add up to its amount")
+	ErrorStatementOutOfBalance    = errors.New("the wallet's cleared transactions don't add up to the statement balance")
+	ErrorVersionConflict          = errors.New("the record was updated since it was read")
+	ErrorWalletNotEmpty           = errors.New("the wallet still has transactions")
+	ErrorPartyNotEmpty            = errors.New("the party still has transactions")
+	ErrorParentTrashed            = errors.New("the wallet or party of the transaction is in the trash")
+	ErrorHasReconciled            = errors.New("the wallet or party has reconciled transactions")
+	ErrorDifferentWallets         = errors.New("the transactions are in different wallets")
+	ErrorHasSplitsOrShares        = errors.New("the transaction has splits or shares")
+	ErrorPartyHousehold           = errors.New("a transaction would have a party of another household")
+	ErrorHouseholdHasMembers      = errors.New("the household has other members")
 )
 
 var PGuniqueConstraintCode = "23505"
@@ -23,10 +36,21 @@ func isUniqueConstaintViolationError(err error) bool {
 }
 
 func checkError(err error) error {
-	if isUniqueConstaintViolationError(err) {
+	if err == ErrorVersionConflict || err == ErrorWalletNotEmpty || err == ErrorPartyNotEmpty || err == ErrorParentTrashed || err == ErrorHasReconciled ||
+		err == ErrorDifferentWallets || err == ErrorHasSplitsOrShares || err == ErrorPartyHousehold ||
+		err == ErrorHouseholdHasMembers {
+		return err
+	} else if isUniqueConstaintViolationError(err) {
 		return ErrorUniqueConstaintViolation
 	} else if err == gorm.ErrRecordNotFound {
 		return ErrorRecordNotFound
 	}
 	return ErrorOther
 }
+
+// likeEscaper makes the wildcards of LIKE patterns match themselves
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -2,38 +2,186 @@ package repository
 
 import (
 	"expense-api/internal/model"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
 )
 
 func (r *repository) WalletCreate(w *model.Wallet) error {
 	return genericCreate(r, w)
 }
 
+// WalletUpdate replaces everything but the currency of the wallet, which its transactions depend on
 func (r *repository) WalletUpdate(id uint, updated *model.Wallet) (*model.Wallet, error) {
 	wallet, err := r.WalletGet(id)
 	if err != nil {
 		return nil, err
 	}
 
-	if updated.Name != "" {
-		wallet.Name = updated.Name
+	wallet.Version = updated.Version
+	wallet.Name = updated.Name
+	wallet.Description = updated.Description
+	wallet.Type = updated.Type
+	wallet.OpeningBalance = updated.OpeningBalance
+	wallet.OpeningDate = updated.OpeningDate
+	wallet.CreditLimit = updated.CreditLimit
+	wallet.MonthlyBudget = updated.MonthlyBudget
+	wallet.Archived = updated.Archived
+	wallet.DisplayOrder = updated.DisplayOrder
+
+	if sameHousehold(wallet.HouseholdID, updated.HouseholdID) {
+		err = genericSave(r, wallet)
+		return wallet, err
 	}
 
-	if updated.Description != "" {
-		wallet.Description = updated.Description
+	// Moving a wallet into or out of a household moves its transactions along
+	wallet.HouseholdID = updated.HouseholdID
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		transactions := tx.Unscoped().Model(&model.Transaction{}).Where("transactions.wallet_id = ?", id)
+		if err := checkPartyHousehold(transactions, wallet.HouseholdID); err != nil {
+			return err
+		}
+		if err := save(tx, wallet); err != nil {
+			return err
+		}
+		return tx.Model(&model.Transaction{}).Where("wallet_id = ?", id).Updates(map[string]interface{}{
+			"household_id": wallet.HouseholdID,
+			"version":      nextVersion,
+		}).Error
+	})
+	if err != nil {
+		return nil, checkError(err)
 	}
+	return wallet, nil
+}
 
-	err = genericSave(r, wallet)
-	return wallet, err
+func sameHousehold(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
 func (r *repository) WalletGet(id uint) (*model.Wallet, error) {
 	return genericGet[model.Wallet](r, map[string]interface{}{"id": id})
 }
 
-func (r *repository) WalletDelete(id uint) error {
-	return genericDelete[model.Wallet](r, id)
+// WalletDelete moves the wallet to the trash. Wallets that still have transactions are only trashed
+// with cascade, their transactions go to the trash along with them unless any of them is reconciled.
+func (r *repository) WalletDelete(id uint, cascade bool) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var wallet model.Wallet
+		if err := tx.First(&wallet, id).Error; err != nil {
+			return err
+		}
+
+		if !cascade {
+			var count int64
+			if err := tx.Model(&model.Transaction{}).Where("wallet_id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrorWalletNotEmpty
+			}
+		}
+		if err := checkNotReconciled(tx, id, "wallet_id"); err != nil {
+			return err
+		}
+
+		return trash(tx, &wallet, id, "wallet_id")
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
+}
+
+// WalletReassign moves the transactions of the wallet to the target wallet and the wallet to the trash.
+// Wallets with reconciled transactions can't be reassigned.
+func (r *repository) WalletReassign(id, targetID uint) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var wallet, target model.Wallet
+		if err := tx.First(&wallet, id).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&target, targetID).Error; err != nil {
+			return err
+		}
+		if err := checkNotReconciled(tx, id, "wallet_id"); err != nil {
+			return err
+		}
+		transactions := tx.Model(&model.Transaction{}).Where("transactions.wallet_id = ?", id)
+		if err := checkPartyHousehold(transactions, target.HouseholdID); err != nil {
+			return err
+		}
+
+		err := tx.Model(&model.Transaction{}).Where("wallet_id = ? AND deleted_at IS NULL", id).Updates(map[string]interface{}{
+			"wallet_id":    targetID,
+			"household_id": target.HouseholdID,
+			"version":      nextVersion,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return trash(tx, &wallet, id, "wallet_id")
+	})
+	if err != nil {
+		return checkError(err)
+	}
+	return nil
 }
 
+// WalletList lists the user's own wallets and the wallets of every household they are a member of,
+// archived ones included, in display order
 func (r *repository) WalletList(userID uint) ([]*model.Wallet, error) {
-	return genericList[model.Wallet](r, map[string]interface{}{"user_id": userID})
+	var wallets []*model.Wallet
+	if tx := r.db.Scopes(r.visibleTo("wallets", userID)).Order("display_order, id").Find(&wallets); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return wallets, nil
+}
+
+func (r *repository) WalletListByIDs(ids []uint) ([]*model.Wallet, error) {
+	return genericList[model.Wallet](r, map[string]interface{}{"id": ids})
+}
+
+// TransactionSumByWallet sums the amounts of the transactions of each of the wallets. Wallets
+// without transactions are missing from the result.
+func (r *repository) TransactionSumByWallet(walletIDs []uint) (map[uint]decimal.Decimal, error) {
+	var rows []struct {
+		WalletID uint
+		Sum      decimal.Decimal
+	}
+	tx := r.db.Model(&model.Transaction{}).
+		Select("wallet_id, SUM(amount) AS sum").
+		Where("wallet_id IN ?", walletIDs).
+		Group("wallet_id").
+		Scan(&rows)
+	if tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+
+	sums := make(map[uint]decimal.Decimal, len(rows))
+	for _, row := range rows {
+		sums[row.WalletID] = row.Sum
+	}
+	return sums, nil
+}
+
+// TransactionSumExpenses adds up how much was spent from the wallet from from until before to, as a
+// positive amount
+func (r *repository) TransactionSumExpenses(walletID uint, from, to time.Time) (decimal.Decimal, error) {
+	var row struct {
+		Sum decimal.NullDecimal
+	}
+	tx := r.db.Model(&model.Transaction{}).
+		Select("-SUM(amount) AS sum").
+		Where("wallet_id = ? AND amount < 0 AND timestamp >= ? AND timestamp < ?", walletID, from, to).
+		Scan(&row)
+	if tx.Error != nil {
+		return decimal.Zero, checkError(tx.Error)
+	}
+	return row.Sum.Decimal, nil
 }
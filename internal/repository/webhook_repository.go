@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"expense-api/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *repository) WebhookCreate(w *model.Webhook) error {
+	return genericCreate(r, w)
+}
+
+func (r *repository) WebhookUpdate(id uint, updated *model.Webhook) (*model.Webhook, error) {
+	w, err := r.WebhookGet(id)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Version = updated.Version
+	w.URL = updated.URL
+	w.Events = updated.Events
+	w.Secret = updated.Secret
+	w.Enabled = updated.Enabled
+
+	err = genericSave(r, w)
+	return w, err
+}
+
+func (r *repository) WebhookGet(id uint) (*model.Webhook, error) {
+	return genericGet[model.Webhook](r, map[string]interface{}{"id": id})
+}
+
+func (r *repository) WebhookDelete(id uint) error {
+	return genericDelete[model.Webhook](r, id)
+}
+
+func (r *repository) WebhookList(userID uint) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	if tx := r.db.Where("user_id = ?", userID).Order("id").Find(&webhooks); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return webhooks, nil
+}
+
+func (r *repository) WebhookDeliveryCreate(d *model.WebhookDelivery) error {
+	return genericCreate(r, d)
+}
+
+// WebhookDeliveryEnqueue queues the deliveries of an event, leaving out the webhooks that got the
+// event already
+func (r *repository) WebhookDeliveryEnqueue(deliveries []*model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if tx := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries); tx.Error != nil {
+		return checkError(tx.Error)
+	}
+	return nil
+}
+
+func (r *repository) WebhookDeliveryGet(id uint) (*model.WebhookDelivery, error) {
+	return genericGet[model.WebhookDelivery](r, map[string]interface{}{"id": id})
+}
+
+// WebhookDeliveryList lists the deliveries of the webhook, the newest first
+func (r *repository) WebhookDeliveryList(webhookID uint) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	if tx := r.db.Where("webhook_id = ?", webhookID).Order("id DESC").Find(&deliveries); tx.Error != nil {
+		return nil, checkError(tx.Error)
+	}
+	return deliveries, nil
+}
+
+// WebhookDeliveryClaim takes up to limit pending deliveries of enabled webhooks that are due at now,
+// with their webhooks. They aren't due again until the lease ran out, so other instances leave them
+// alone while they are being sent.
+func (r *repository) WebhookDeliveryClaim(now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Where("webhook_id IN (?)", tx.Model(&model.Webhook{}).Select("id").Where("enabled")).
+			Order("next_attempt_at, id").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(deliveries))
+		webhookIDs := make([]uint, len(deliveries))
+		for i, d := range deliveries {
+			ids[i] = d.ID
+			webhookIDs[i] = d.WebhookID
+			d.NextAttemptAt = now.Add(lease)
+		}
+		err = tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).UpdateColumn("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return err
+		}
+
+		var webhooks []*model.Webhook
+		if err := tx.Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*model.Webhook, len(webhooks))
+		for _, w := range webhooks {
+			byID[w.ID] = w
+		}
+		for _, d := range deliveries {
+			d.Webhook = *byID[d.WebhookID]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, checkError(err)
+	}
+	return deliveries, nil
+}
+
+// WebhookDeliveryRecordAttempt stores the outcome of an attempt to send the delivery
+func (r *repository) WebhookDeliveryRecordAttempt(d *model.WebhookDelivery) error {
+	tx := r.db.Model(&model.WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]interface{}{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"last_attempt_at": d.LastAttemptAt,
+		"response_status": d.ResponseStatus,
+		"error":           d.Error,
+		"version":         nextVersion,
+	})
+	if tx.Error != nil {
+		return checkError(tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return ErrorRecordNotFound
+	}
+	return nil
+}
@@ -1,30 +1,53 @@
 package router
 
 import (
+	"expense-api/internal/blobstore"
+	"expense-api/internal/gql"
 	"expense-api/internal/handlers"
 	"expense-api/internal/middleware"
 	auth_middleware "expense-api/internal/middleware/auth"
+	exchange_rates_middleware "expense-api/internal/middleware/exchangerates"
+	households_middleware "expense-api/internal/middleware/households"
+	idempotency_middleware "expense-api/internal/middleware/idempotency"
 	parties_middleware "expense-api/internal/middleware/parties"
+	rules_middleware "expense-api/internal/middleware/rules"
+	settlements_middleware "expense-api/internal/middleware/settlements"
 	transactions_middleware "expense-api/internal/middleware/transactions"
+	validation_middleware "expense-api/internal/middleware/validation"
 	wallets_middleware "expense-api/internal/middleware/wallets"
+	webhooks_middleware "expense-api/internal/middleware/webhooks"
+	"expense-api/internal/openapi"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"expense-api/internal/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Config struct {
 	withDefaultMiddleware bool
+	// IdempotencyKeyTTL is how long responses to requests with an Idempotency-Key are replayed
+	IdempotencyKeyTTL time.Duration
+	// ValidateRequests answers requests that don't match the OpenAPI document with 400
+	ValidateRequests bool
+	// TrustedProxies are the IP addresses and CIDR ranges of the reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers name the client. Without any, the client is always the
+	// address the request came from.
+	TrustedProxies []string
 }
 
-var DefaultConfig = &Config{withDefaultMiddleware: true}
-var TestConfig = &Config{}
+const DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+var DefaultConfig = &Config{withDefaultMiddleware: true, IdempotencyKeyTTL: DefaultIdempotencyKeyTTL}
+var TestConfig = &Config{IdempotencyKeyTTL: DefaultIdempotencyKeyTTL}
 
 // Setup creates a new gin router
 func Setup(
 	repo repository.Repository,
 	jwtService auth_middleware.JWTService,
 	hasher utils.PasswordHasher,
+	blobs blobstore.BlobStore,
 	config *Config,
 ) *gin.Engine {
 
@@ -35,10 +58,22 @@ func Setup(
 		gin.SetMode(gin.ReleaseMode)
 		router = gin.New()
 	}
+	// Headers naming the client are only believed from the proxies, everybody else could make up
+	// the IP address recorded in the audit log
+	router.TrustedProxies = config.TrustedProxies
+
+	handler := handlers.New(repo, jwtService, hasher, blobs)
+	commonM := middleware.NewCommonMiddleware()
 
-	handler := handlers.New(repo, jwtService, hasher)
+	// The request ID identifies the request in the audit log
+	router.Use(commonM.SetRequestID)
+	if config.ValidateRequests {
+		router.Use(validation_middleware.New().ValidateRequest)
+	}
 
-	v1 := router.Group("/api/v1")
+	v1 := router.Group(openapi.BasePath)
+	v1.GET("/openapi.json", openapi.ServeDocument)
+	v1.GET("/docs", openapi.ServeDocs)
 
 	auth := v1.Group("/auth")
 	{
@@ -46,8 +81,8 @@ func Setup(
 		auth.POST("/login", handler.Login)
 	}
 
-	commonM := middleware.NewCommonMiddleware()
 	authM := auth_middleware.New(jwtService)
+	idempotencyM := idempotency_middleware.New(repo, config.IdempotencyKeyTTL)
 
 	account := v1.Group("/account").Use(authM.IsAuthenticated)
 	{
@@ -56,7 +91,32 @@ func Setup(
 		account.DELETE("/", handler.DeleteAccount)
 	}
 
-	wallets := v1.Group("/wallets").Use(authM.IsAuthenticated)
+	households := v1.Group("/households").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
+	{
+		householdsM := households_middleware.New(repo)
+		viewer := householdsM.ValidateMembership(permissions.RoleViewer)
+		owner := householdsM.ValidateMembership(permissions.RoleOwner)
+
+		households.GET("/", handler.ListHouseholds)
+		households.POST("/", handler.CreateHousehold)
+		households.GET("/:id", commonM.SetIDParamToContext, viewer, handler.GetHousehold)
+		households.PATCH("/:id", commonM.SetIDParamToContext, owner, handler.UpdateHousehold)
+		households.DELETE("/:id", commonM.SetIDParamToContext, owner, handler.DeleteHousehold)
+		households.GET("/:id/members", commonM.SetIDParamToContext, viewer, handler.ListHouseholdMembers)
+		households.PATCH("/:id/members/:user_id", commonM.SetIDParamToContext, owner, handler.UpdateHouseholdMember)
+		households.DELETE("/:id/members/:user_id", commonM.SetIDParamToContext, viewer, handler.RemoveHouseholdMember)
+		households.GET("/:id/invitations", commonM.SetIDParamToContext, owner, handler.ListHouseholdInvitations)
+		households.POST("/:id/invitations", commonM.SetIDParamToContext, owner, handler.CreateHouseholdInvitation)
+	}
+
+	invitations := v1.Group("/invitations").Use(authM.IsAuthenticated)
+	{
+		invitations.GET("/", handler.ListInvitations)
+		invitations.POST("/:id/accept", commonM.SetIDParamToContext, handler.AcceptInvitation)
+		invitations.POST("/:id/decline", commonM.SetIDParamToContext, handler.DeclineInvitation)
+	}
+
+	wallets := v1.Group("/wallets").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
 	{
 		walletsM := wallets_middleware.New(repo)
 
@@ -66,9 +126,11 @@ func Setup(
 		wallets.PATCH("/:id", commonM.SetIDParamToContext, walletsM.ValidateOwnership, handler.UpdateWallet)
 		wallets.DELETE("/:id", commonM.SetIDParamToContext, walletsM.ValidateOwnership, handler.DeleteWallet)
 		wallets.GET("/:id/transactions", commonM.SetIDParamToContext, walletsM.ValidateOwnership, handler.ListTransactionsByWallet)
+		wallets.POST("/:id/reconcile", commonM.SetIDParamToContext, walletsM.ValidateOwnership, handler.ReconcileWallet)
+		wallets.GET("/:id/reconciliations", commonM.SetIDParamToContext, walletsM.ValidateOwnership, handler.ListReconciliations)
 	}
 
-	parties := v1.Group("/parties").Use(authM.IsAuthenticated)
+	parties := v1.Group("/parties").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
 	{
 		partiesM := parties_middleware.New(repo)
 
@@ -78,17 +140,120 @@ func Setup(
 		parties.PATCH("/:id", commonM.SetIDParamToContext, partiesM.ValidateOwnership, handler.UpdateParty)
 		parties.DELETE("/:id", commonM.SetIDParamToContext, partiesM.ValidateOwnership, handler.DeleteParty)
 		parties.GET("/:id/transactions", commonM.SetIDParamToContext, partiesM.ValidateOwnership, handler.ListTransactionsByParty)
+		parties.POST("/:id/merge", commonM.SetIDParamToContext, partiesM.ValidateOwnership, handler.MergeParties)
+		parties.DELETE("/:id/aliases/:alias_id", commonM.SetIDParamToContext, partiesM.ValidateOwnership, handler.DeletePartyAlias)
 	}
 
-	transactions := v1.Group("/transactions").Use(authM.IsAuthenticated)
+	transactions := v1.Group("/transactions").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
 	{
 		txM := transactions_middleware.New(repo)
 
 		transactions.GET("/", handler.ListTransactions)
 		transactions.POST("/", handler.CreateTransaction)
+		transactions.GET("/duplicates", handler.ListDuplicateTransactions)
+		transactions.POST("/bulk", handler.BulkTransactions)
 		transactions.GET("/:id", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.GetTransaction)
 		transactions.PATCH("/:id", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.UpdateTransaction)
 		transactions.DELETE("/:id", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.DeleteTransaction)
+		transactions.POST("/:id/merge", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.MergeTransactions)
+		transactions.GET("/:id/splits", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.ListTransactionSplits)
+		transactions.POST("/:id/splits", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.CreateTransactionSplits)
+		transactions.PUT("/:id/splits", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.UpdateTransactionSplits)
+		transactions.DELETE("/:id/splits", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.DeleteTransactionSplits)
+		transactions.GET("/:id/shares", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.GetTransactionShares)
+		transactions.PUT("/:id/shares", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.UpdateTransactionShares)
+		transactions.DELETE("/:id/shares", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.DeleteTransactionShares)
+		transactions.GET("/:id/attachments", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.ListAttachments)
+		transactions.POST("/:id/attachments", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.CreateAttachment)
+		transactions.GET("/:id/attachments/:attachment_id", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.GetAttachment)
+		transactions.GET("/:id/attachments/:attachment_id/download", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.DownloadAttachment)
+		transactions.DELETE("/:id/attachments/:attachment_id", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.DeleteAttachment)
+		transactions.GET("/:id/history", commonM.SetIDParamToContext, txM.ValidateOwnership, handler.ListTransactionHistory)
+	}
+
+	trash := v1.Group("/trash").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
+	{
+		trash.GET("/", handler.ListTrash)
+		trash.POST("/wallets/:id/restore", commonM.SetIDParamToContext, handler.RestoreWallet)
+		trash.POST("/parties/:id/restore", commonM.SetIDParamToContext, handler.RestoreParty)
+		trash.POST("/transactions/:id/restore", commonM.SetIDParamToContext, handler.RestoreTransaction)
+	}
+
+	audit := v1.Group("/audit").Use(authM.IsAuthenticated)
+	{
+		audit.GET("", handler.ListAudit)
+		audit.GET("/verify", handler.VerifyAudit)
+	}
+
+	suggestions := v1.Group("/suggestions").Use(authM.IsAuthenticated)
+	{
+		suggestions.GET("/categories", handler.SuggestCategories)
+	}
+
+	rules := v1.Group("/rules").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
+	{
+		rulesM := rules_middleware.New(repo)
+
+		rules.GET("/", handler.ListRules)
+		rules.POST("/", handler.CreateRule)
+		rules.POST("/apply", handler.ApplyRules)
+		rules.GET("/:id", commonM.SetIDParamToContext, rulesM.ValidateOwnership, handler.GetRule)
+		rules.PATCH("/:id", commonM.SetIDParamToContext, rulesM.ValidateOwnership, handler.UpdateRule)
+		rules.DELETE("/:id", commonM.SetIDParamToContext, rulesM.ValidateOwnership, handler.DeleteRule)
+	}
+
+	webhooks := v1.Group("/webhooks").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
+	{
+		webhooksM := webhooks_middleware.New(repo)
+
+		webhooks.GET("/", handler.ListWebhooks)
+		webhooks.POST("/", handler.CreateWebhook)
+		webhooks.GET("/:id", commonM.SetIDParamToContext, webhooksM.ValidateOwnership, handler.GetWebhook)
+		webhooks.PATCH("/:id", commonM.SetIDParamToContext, webhooksM.ValidateOwnership, handler.UpdateWebhook)
+		webhooks.DELETE("/:id", commonM.SetIDParamToContext, webhooksM.ValidateOwnership, handler.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", commonM.SetIDParamToContext, webhooksM.ValidateOwnership, handler.ListWebhookDeliveries)
+		webhooks.POST("/:id/deliveries/:delivery_id/replay", commonM.SetIDParamToContext, webhooksM.ValidateOwnership, handler.ReplayWebhookDelivery)
+	}
+
+	events := v1.Group("/events").Use(authM.IsAuthenticated)
+	{
+		events.GET("/stream", handler.StreamEvents)
+	}
+
+	// GraphQL mutations are served by the REST routes of the router
+	graphQL := gql.New(repo, router)
+	v1.POST("/graphql", authM.IsAuthenticated, graphQL.ServeGraphQL)
+
+	exchangeRates := v1.Group("/exchange-rates").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
+	{
+		exchangeRatesM := exchange_rates_middleware.New(repo)
+
+		exchangeRates.GET("/", handler.ListExchangeRates)
+		exchangeRates.POST("/", exchangeRatesM.ValidateAdmin, handler.CreateExchangeRate)
+		exchangeRates.POST("/import", exchangeRatesM.ValidateAdmin, handler.ImportExchangeRates)
+	}
+
+	reports := v1.Group("/reports").Use(authM.IsAuthenticated)
+	{
+		reports.GET("/balances", handler.GetBalanceReport)
+		reports.GET("/parties", handler.GetPartyReport)
+		reports.GET("/categories", handler.GetCategoryReport)
+	}
+
+	search := v1.Group("/search").Use(authM.IsAuthenticated)
+	{
+		search.GET("", handler.Search)
+	}
+
+	shared := v1.Group("/shared").Use(authM.IsAuthenticated, idempotencyM.HandleIdempotencyKey)
+	{
+		settlementsM := settlements_middleware.New(repo)
+
+		shared.GET("/balances", handler.GetSharedBalances)
+		shared.GET("/simplify", handler.SimplifySharedDebts)
+		shared.GET("/settlements", handler.ListSettlements)
+		shared.POST("/settlements", handler.CreateSettlement)
+		shared.DELETE("/settlements/:id", commonM.SetIDParamToContext, settlementsM.ValidateOwnership, handler.DeleteSettlement)
 	}
 
 	return router
@@ -0,0 +1,162 @@
+package rules
+
+import (
+	"errors"
+	"expense-api/internal/model"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrorNoCondition  = errors.New("a rule needs at least one condition")
+	ErrorNoAction     = errors.New("a rule needs at least one action")
+	ErrorInvalidRegex = errors.New("description regex is not a valid regular expression")
+	ErrorAmountRange  = errors.New("minimum amount must not be greater than the maximum amount")
+)
+
+// Validate makes sure a rule can be applied
+func Validate(r *model.Rule) error {
+	if r.DescriptionContains == "" && r.DescriptionRegex == "" &&
+		r.AmountMin == nil && r.AmountMax == nil && r.WalletID == nil {
+		return ErrorNoCondition
+	}
+
+	if r.SetPartyID == nil && r.SetCategory == "" && len(r.AddTags) == 0 && r.SetDescription == "" {
+		return ErrorNoAction
+	}
+
+	if r.DescriptionRegex != "" {
+		if _, err := regexp.Compile(r.DescriptionRegex); err != nil {
+			return ErrorInvalidRegex
+		}
+	}
+
+	if r.AmountMin != nil && r.AmountMax != nil && r.AmountMin.GreaterThan(*r.AmountMax) {
+		return ErrorAmountRange
+	}
+
+	return nil
+}
+
+// Sort orders rules the way they are applied: highest priority first, older rules first on equal priority
+func Sort(rules []*model.Rule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
+	})
+}
+
+// compiledRule is a rule with its regex compiled once per Apply
+type compiledRule struct {
+	*model.Rule
+	regex *regexp.Regexp
+}
+
+func compile(r *model.Rule) (*compiledRule, bool) {
+	if r.DescriptionRegex == "" {
+		return &compiledRule{Rule: r}, true
+	}
+
+	regex, err := regexp.Compile(r.DescriptionRegex)
+	if err != nil {
+		return nil, false
+	}
+	return &compiledRule{r, regex}, true
+}
+
+// Matches tells whether the transaction satisfies all conditions of the rule. Disabled rules never match.
+func Matches(r *model.Rule, t *model.Transaction) bool {
+	c, ok := compile(r)
+	return ok && c.matches(t)
+}
+
+func (r *compiledRule) matches(t *model.Transaction) bool {
+	if !r.Enabled {
+		return false
+	}
+
+	if r.DescriptionContains != "" &&
+		!strings.Contains(strings.ToLower(t.Description), strings.ToLower(r.DescriptionContains)) {
+		return false
+	}
+
+	if r.regex != nil && !r.regex.MatchString(t.Description) {
+		return false
+	}
+
+	if r.AmountMin != nil && t.Amount.LessThan(*r.AmountMin) {
+		return false
+	}
+
+	if r.AmountMax != nil && t.Amount.GreaterThan(*r.AmountMax) {
+		return false
+	}
+
+	if r.WalletID != nil && *r.WalletID != t.WalletID {
+		return false
+	}
+
+	return true
+}
+
+// description rewrites the description. With a regex condition, $1, ${name}, ... in the new
+// description are replaced by the groups of the first match.
+func (r *compiledRule) description(original string) string {
+	if r.regex == nil {
+		return r.SetDescription
+	}
+
+	match := r.regex.FindStringSubmatchIndex(original)
+	return string(r.regex.ExpandString(nil, r.SetDescription, original, match))
+}
+
+// Apply applies the rules to the transaction in the order given (see Sort) and returns the IDs of
+// the rules that matched. Conditions are checked against the transaction as it was passed in.
+// Every field is set by the first matching rule that sets it; tags of all matching rules are added.
+// Unless overwrite is set, a party, category or description the transaction already has is kept.
+func Apply(rules []*model.Rule, t *model.Transaction, overwrite bool) []uint {
+	original := *t
+
+	var matched []uint
+	setParty := !overwrite && t.PartyID != 0
+	setCategory := !overwrite && t.Category != ""
+	setDescription := false
+
+	for _, rule := range rules {
+		r, ok := compile(rule)
+		if !ok || !r.matches(&original) {
+			continue
+		}
+		matched = append(matched, r.ID)
+
+		if r.SetPartyID != nil && !setParty {
+			t.PartyID = *r.SetPartyID
+			setParty = true
+		}
+
+		if r.SetCategory != "" && !setCategory {
+			t.Category = r.SetCategory
+			setCategory = true
+		}
+
+		if r.SetDescription != "" && !setDescription {
+			t.Description = r.description(original.Description)
+			setDescription = true
+		}
+
+		for _, tag := range r.AddTags {
+			if !t.Tags.Contains(tag) {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+
+		if r.StopProcessing {
+			break
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,150 @@
+package rules_test
+
+import (
+	"expense-api/internal/model"
+	"expense-api/internal/rules"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+)
+
+func uintPtr(v uint) *uint { return &v }
+
+func decimalPtr(v string) *decimal.Decimal {
+	d := decimal.RequireFromString(v)
+	return &d
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		rule     *model.Rule
+		expected error
+	}{
+		{"No condition", &model.Rule{SetCategory: "food"}, rules.ErrorNoCondition},
+		{"No action", &model.Rule{DescriptionContains: "uber"}, rules.ErrorNoAction},
+		{"Invalid regex", &model.Rule{DescriptionRegex: "(", SetCategory: "food"}, rules.ErrorInvalidRegex},
+		{"Inverted amount range", &model.Rule{AmountMin: decimalPtr("10"), AmountMax: decimalPtr("1"), SetCategory: "food"}, rules.ErrorAmountRange},
+		{"Valid", &model.Rule{DescriptionRegex: `^uber\b`, AddTags: model.Tags{"transport"}}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := rules.Validate(tc.rule); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	transaction := &model.Transaction{
+		Description: "UBER *TRIP Lisbon",
+		Amount:      decimal.RequireFromString("-12.50"),
+		WalletID:    3,
+	}
+
+	testCases := []struct {
+		desc     string
+		rule     *model.Rule
+		expected bool
+	}{
+		{"Contains is case-insensitive", &model.Rule{Enabled: true, DescriptionContains: "uber"}, true},
+		{"Contains does not match", &model.Rule{Enabled: true, DescriptionContains: "bolt"}, false},
+		{"Regex matches", &model.Rule{Enabled: true, DescriptionRegex: `^UBER \*(\w+)`}, true},
+		{"Regex is case-sensitive", &model.Rule{Enabled: true, DescriptionRegex: `^uber`}, false},
+		{"Amount within inclusive range", &model.Rule{Enabled: true, AmountMin: decimalPtr("-12.50"), AmountMax: decimalPtr("0")}, true},
+		{"Amount outside range", &model.Rule{Enabled: true, AmountMin: decimalPtr("0")}, false},
+		{"Wallet matches", &model.Rule{Enabled: true, WalletID: uintPtr(3)}, true},
+		{"Wallet does not match", &model.Rule{Enabled: true, WalletID: uintPtr(4)}, false},
+		{"All conditions must hold", &model.Rule{Enabled: true, DescriptionContains: "uber", WalletID: uintPtr(4)}, false},
+		{"Disabled rule", &model.Rule{DescriptionContains: "uber"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := rules.Matches(tc.rule, transaction); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	newRules := func() []*model.Rule {
+		return []*model.Rule{
+			{Model: model.Model{ID: 1}, Enabled: true, DescriptionContains: "uber", AddTags: model.Tags{"transport"}},
+			{Model: model.Model{ID: 2}, Enabled: true, Priority: 10, DescriptionRegex: `^UBER \*(\w+)`, SetDescription: "Uber ${1}", SetPartyID: uintPtr(7), SetCategory: "travel"},
+			{Model: model.Model{ID: 3}, Enabled: true, Priority: 5, DescriptionContains: "lisbon", SetCategory: "holidays", AddTags: model.Tags{"lisbon", "transport"}},
+			{Model: model.Model{ID: 4}, Enabled: true, DescriptionContains: "bolt", SetCategory: "never"},
+		}
+	}
+
+	t.Run("Sort by priority then id", func(t *testing.T) {
+		rs := newRules()
+		rules.Sort(rs)
+
+		var got []uint
+		for _, r := range rs {
+			got = append(got, r.ID)
+		}
+		if diff := cmp.Diff([]uint{2, 3, 1, 4}, got); diff != "" {
+			t.Errorf("unexpected order (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("First rule setting a field wins, tags accumulate", func(t *testing.T) {
+		rs := newRules()
+		rules.Sort(rs)
+		transaction := &model.Transaction{Description: "UBER *TRIP Lisbon"}
+
+		matched := rules.Apply(rs, transaction, false)
+
+		if diff := cmp.Diff([]uint{2, 3, 1}, matched); diff != "" {
+			t.Errorf("unexpected matches (-want +got):\n%s", diff)
+		}
+		want := model.Transaction{
+			Description: "Uber TRIP",
+			PartyID:     7,
+			Category:    "travel",
+			Tags:        model.Tags{"lisbon", "transport"},
+		}
+		if diff := cmp.Diff(want, *transaction); diff != "" {
+			t.Errorf("unexpected transaction (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Existing party and category are kept unless overwriting", func(t *testing.T) {
+		rs := newRules()
+		rules.Sort(rs)
+
+		kept := &model.Transaction{Description: "UBER *TRIP", PartyID: 1, Category: "work"}
+		rules.Apply(rs, kept, false)
+		if kept.PartyID != 1 || kept.Category != "work" {
+			t.Errorf("expected party and category to be kept, got %d and %q", kept.PartyID, kept.Category)
+		}
+
+		overwritten := &model.Transaction{Description: "UBER *TRIP", PartyID: 1, Category: "work"}
+		rules.Apply(rs, overwritten, true)
+		if overwritten.PartyID != 7 || overwritten.Category != "travel" {
+			t.Errorf("expected party and category to be overwritten, got %d and %q", overwritten.PartyID, overwritten.Category)
+		}
+	})
+
+	t.Run("Stop processing", func(t *testing.T) {
+		rs := newRules()
+		rs[1].StopProcessing = true
+		rules.Sort(rs)
+		transaction := &model.Transaction{Description: "UBER *TRIP Lisbon"}
+
+		matched := rules.Apply(rs, transaction, false)
+
+		if diff := cmp.Diff([]uint{2}, matched); diff != "" {
+			t.Errorf("unexpected matches (-want +got):\n%s", diff)
+		}
+		if transaction.Tags != nil {
+			t.Errorf("expected no tags, got %v", transaction.Tags)
+		}
+	})
+}
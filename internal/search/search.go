@@ -0,0 +1,101 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Types of search results
+const (
+	TypeTransaction = "transaction"
+	TypeParty       = "party"
+	TypeWallet      = "wallet"
+)
+
+// Types are all types that can be searched, in the order results of equal rank are listed
+var Types = []string{TypeTransaction, TypeParty, TypeWallet}
+
+// TextSearchConfig is the postgres text search configuration the search columns are built with.
+// It is 'simple' with accents removed, so no language specific stemming gets in the way of names.
+const TextSearchConfig = "xpense"
+
+// maxTerms limits how many words of a query are searched for
+const maxTerms = 10
+
+// Query is a search over everything a user can see
+type Query struct {
+	// TSQuery is the query in the postgres tsquery syntax, see ToTSQuery
+	TSQuery string
+	Types   []string
+	// From and To limit results to the period [From, To); zero values leave the period open
+	From  time.Time
+	To    time.Time
+	Limit int
+}
+
+// Result is a transaction, party or wallet matching a query.
+// Headline is an HTML-escaped excerpt with the matching words wrapped in HighlightStart and
+// HighlightStop, see Headline.
+type Result struct {
+	Type     string
+	ID       uint
+	Title    string
+	Headline string
+	Rank     float64
+	Date     time.Time
+}
+
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// MarkStart and MarkStop wrap the matching words in the excerpts postgres builds. They are from the
+// Unicode private use area and are removed from the searched text, so they only appear as markers.
+const (
+	MarkStart = "\uE000"
+	MarkStop  = "\uE001"
+)
+
+var highlighter = strings.NewReplacer(MarkStart, HighlightStart, MarkStop, HighlightStop)
+
+// Headline turns an excerpt with the matches wrapped in MarkStart and MarkStop into HTML: the text
+// is escaped and the matches are wrapped in HighlightStart and HighlightStop
+func Headline(marked string) string {
+	return highlighter.Replace(html.EscapeString(marked))
+}
+
+func IsValidType(t string) bool {
+	for _, valid := range Types {
+		if t == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// ToTSQuery turns what a user typed into a tsquery that matches everything containing all words,
+// with the words matched as prefixes ("lisb hot" finds "Hotel in Lisbon"). Everything but letters
+// and digits separates words, so user input can't break the tsquery syntax. It returns false if
+// the text has no words.
+func ToTSQuery(text string) (string, bool) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(words) == 0 {
+		return "", false
+	}
+
+	if len(words) > maxTerms {
+		words = words[:maxTerms]
+	}
+
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, w+":*")
+	}
+	return strings.Join(terms, " & "), true
+}
@@ -0,0 +1,69 @@
+package search_test
+
+import (
+	"expense-api/internal/search"
+	"testing"
+)
+
+func TestToTSQuery(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		text     string
+		expected string
+		ok       bool
+	}{
+		{"Single word", "hotel", "hotel:*", true},
+		{"Several words", "Hotel  Lisbon", "hotel:* & lisbon:*", true},
+		{"Accents are kept for the dictionary", "Café São Bento", "café:* & são:* & bento:*", true},
+		{"Operators are separators", "rent & !(flat) | 'x':*", "rent:* & flat:* & x:*", true},
+		{"Digits", "invoice 2021-11", "invoice:* & 2021:* & 11:*", true},
+		{"Too many words", "a b c d e f g h i j k l", "a:* & b:* & c:* & d:* & e:* & f:* & g:* & h:* & i:* & j:*", true},
+		{"Empty", "", "", false},
+		{"Only punctuation", " &|!:* ", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, ok := search.ToTSQuery(tc.text)
+			if got != tc.expected || ok != tc.ok {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tc.expected, tc.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestHeadline(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		marked   string
+		expected string
+	}{
+		{"Matches", search.MarkStart + "Hotel" + search.MarkStop + " in Lisbon", "<mark>Hotel</mark> in Lisbon"},
+		{"Text is escaped", `<img src=x onerror="alert(1)"> ` + search.MarkStart + "Café" + search.MarkStop,
+			"&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>Café</mark>"},
+		{"Tags in the text aren't highlights", "<mark>rent</mark> & bills", "&lt;mark&gt;rent&lt;/mark&gt; &amp; bills"},
+		{"Empty", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := search.Headline(tc.marked); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsValidType(t *testing.T) {
+	for _, valid := range []string{"transaction", "party", "wallet"} {
+		if !search.IsValidType(valid) {
+			t.Errorf("expected %q to be valid", valid)
+		}
+	}
+
+	for _, invalid := range []string{"", "tag", "Transaction"} {
+		if search.IsValidType(invalid) {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
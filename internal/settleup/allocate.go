@@ -0,0 +1,123 @@
+package settleup
+
+import (
+	"errors"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	MethodEqual      = "equal"
+	MethodPercentage = "percentage"
+	MethodExact      = "exact"
+)
+
+var (
+	ErrorNoParticipants      = errors.New("at least one participant is required")
+	ErrorPercentagesNot100   = errors.New("percentages must add up to 100")
+	ErrorNegativePercentage  = errors.New("percentages must not be negative")
+	ErrorExactSharesMismatch = errors.New("exact shares must add up to the total")
+	ErrorTotalPrecision      = errors.New("the total has more decimal places than the shares may have")
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// IsValidMethod checks if the method is one of the supported ways to share an expense
+func IsValidMethod(method string) bool {
+	return method == MethodEqual || method == MethodPercentage || method == MethodExact
+}
+
+// SplitEqually divides the total into n shares rounded to the given number of decimal places.
+// Rounding leftovers are handed out one minor unit at a time, starting with the first share,
+// so the shares always add up to the total.
+func SplitEqually(total decimal.Decimal, places int32, n int) ([]decimal.Decimal, error) {
+	if n <= 0 {
+		return nil, ErrorNoParticipants
+	}
+
+	weights := make([]decimal.Decimal, n)
+	for i := range weights {
+		weights[i] = decimal.NewFromInt(1)
+	}
+	return allocate(total, places, weights)
+}
+
+// SplitByPercentage divides the total according to percentages that add up to 100, with
+// rounding leftovers handed out the same way as in SplitEqually
+func SplitByPercentage(total decimal.Decimal, places int32, percentages []decimal.Decimal) ([]decimal.Decimal, error) {
+	if len(percentages) == 0 {
+		return nil, ErrorNoParticipants
+	}
+
+	sum := decimal.Zero
+	for _, p := range percentages {
+		if p.IsNegative() {
+			return nil, ErrorNegativePercentage
+		}
+		sum = sum.Add(p)
+	}
+
+	if !sum.Equal(hundred) {
+		return nil, ErrorPercentagesNot100
+	}
+	return allocate(total, places, percentages)
+}
+
+// CheckExact makes sure exact shares add up to the total
+func CheckExact(total decimal.Decimal, shares []decimal.Decimal) error {
+	if len(shares) == 0 {
+		return ErrorNoParticipants
+	}
+
+	sum := decimal.Zero
+	for _, s := range shares {
+		sum = sum.Add(s)
+	}
+
+	if !sum.Equal(total) {
+		return ErrorExactSharesMismatch
+	}
+	return nil
+}
+
+// allocate divides the total proportionally to the weights, truncating every share to the
+// given number of decimal places and distributing what's left in minor units. A total with
+// more decimal places can't be divided into such shares.
+func allocate(total decimal.Decimal, places int32, weights []decimal.Decimal) ([]decimal.Decimal, error) {
+	if !total.Equal(total.Truncate(places)) {
+		return nil, ErrorTotalPrecision
+	}
+
+	weightSum := decimal.Zero
+	for _, w := range weights {
+		weightSum = weightSum.Add(w)
+	}
+
+	shares := make([]decimal.Decimal, len(weights))
+	allocated := decimal.Zero
+	for i, w := range weights {
+		shares[i] = total.Mul(w).DivRound(weightSum, places+8).Truncate(places)
+		allocated = allocated.Add(shares[i])
+	}
+
+	unit := decimal.New(1, -places)
+	if total.IsNegative() {
+		unit = unit.Neg()
+	}
+
+	// Every truncated share lacks less than a unit, so fewer units than shares are left over
+	leftover := total.Sub(allocated).Div(unit)
+	if leftover.IsNegative() || leftover.GreaterThanOrEqual(decimal.NewFromInt(int64(len(shares)))) {
+		return nil, ErrorTotalPrecision
+	}
+
+	for i, left := 0, leftover.IntPart(); left > 0; i = (i + 1) % len(shares) {
+		if weights[i].IsZero() {
+			continue
+		}
+		shares[i] = shares[i].Add(unit)
+		left--
+	}
+
+	return shares, nil
+}
@@ -0,0 +1,135 @@
+package settleup_test
+
+import (
+	"expense-api/internal/settleup"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func decimals(values ...string) []decimal.Decimal {
+	result := make([]decimal.Decimal, 0, len(values))
+	for _, v := range values {
+		result = append(result, decimal.RequireFromString(v))
+	}
+	return result
+}
+
+func assertDecimals(t *testing.T, got, want []decimal.Decimal) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSplitEqually(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		total    string
+		places   int32
+		n        int
+		expected []decimal.Decimal
+	}{
+		{
+			desc:     "Divides evenly",
+			total:    "30",
+			places:   2,
+			n:        3,
+			expected: decimals("10", "10", "10"),
+		},
+		{
+			desc:     "Hands out leftover cents starting with the first share",
+			total:    "100",
+			places:   2,
+			n:        3,
+			expected: decimals("33.34", "33.33", "33.33"),
+		},
+		{
+			desc:     "Negative total",
+			total:    "-0.05",
+			places:   2,
+			n:        2,
+			expected: decimals("-0.03", "-0.02"),
+		},
+		{
+			desc:     "Currency without minor units",
+			total:    "1000",
+			places:   0,
+			n:        3,
+			expected: decimals("334", "333", "333"),
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := settleup.SplitEqually(decimal.RequireFromString(tC.total), tC.places, tC.n)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertDecimals(t, got, tC.expected)
+		})
+	}
+
+	t.Run("No participants", func(t *testing.T) {
+		if _, err := settleup.SplitEqually(decimal.NewFromInt(10), 2, 0); err != settleup.ErrorNoParticipants {
+			t.Errorf("got error %v, want %v", err, settleup.ErrorNoParticipants)
+		}
+	})
+
+	t.Run("Total with more decimal places than the shares", func(t *testing.T) {
+		if _, err := settleup.SplitEqually(decimal.RequireFromString("10.005"), 2, 2); err != settleup.ErrorTotalPrecision {
+			t.Errorf("got error %v, want %v", err, settleup.ErrorTotalPrecision)
+		}
+	})
+}
+
+func TestSplitByPercentage(t *testing.T) {
+	t.Run("Splits by percentage", func(t *testing.T) {
+		got, err := settleup.SplitByPercentage(decimal.RequireFromString("99.99"), 2, decimals("50", "25", "25"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertDecimals(t, got, decimals("50", "25", "24.99"))
+	})
+
+	t.Run("Skips participants with 0 percent when handing out leftovers", func(t *testing.T) {
+		got, err := settleup.SplitByPercentage(decimal.RequireFromString("10"), 2, decimals("0", "33.3", "66.7"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		assertDecimals(t, got, decimals("0", "3.33", "6.67"))
+	})
+
+	t.Run("Percentages that don't add up to 100", func(t *testing.T) {
+		_, err := settleup.SplitByPercentage(decimal.NewFromInt(10), 2, decimals("50", "40"))
+		if err != settleup.ErrorPercentagesNot100 {
+			t.Errorf("got error %v, want %v", err, settleup.ErrorPercentagesNot100)
+		}
+	})
+
+	t.Run("Negative percentage", func(t *testing.T) {
+		_, err := settleup.SplitByPercentage(decimal.NewFromInt(10), 2, decimals("110", "-10"))
+		if err != settleup.ErrorNegativePercentage {
+			t.Errorf("got error %v, want %v", err, settleup.ErrorNegativePercentage)
+		}
+	})
+}
+
+func TestCheckExact(t *testing.T) {
+	if err := settleup.CheckExact(decimal.NewFromInt(10), decimals("7.5", "2.5")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := settleup.CheckExact(decimal.NewFromInt(10), decimals("7.5", "2")); err != settleup.ErrorExactSharesMismatch {
+		t.Errorf("got error %v, want %v", err, settleup.ErrorExactSharesMismatch)
+	}
+}
@@ -0,0 +1,135 @@
+package settleup
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Debt states that From owes To the amount in the given currency
+type Debt struct {
+	From     uint
+	To       uint
+	Currency string
+	Amount   decimal.Decimal
+}
+
+// Balance is what a counterpart owes a user in one currency; a negative amount means the user owes the counterpart
+type Balance struct {
+	UserID   uint
+	Currency string
+	Amount   decimal.Decimal
+}
+
+// Balances nets all debts between the user and each counterpart, leaving out settled pairs
+func Balances(userID uint, debts []Debt) []Balance {
+	type key struct {
+		userID   uint
+		currency string
+	}
+
+	totals := map[key]decimal.Decimal{}
+	for _, d := range debts {
+		switch {
+		case d.To == userID && d.From != userID:
+			k := key{d.From, d.Currency}
+			totals[k] = totals[k].Add(d.Amount)
+		case d.From == userID && d.To != userID:
+			k := key{d.To, d.Currency}
+			totals[k] = totals[k].Sub(d.Amount)
+		}
+	}
+
+	balances := make([]Balance, 0, len(totals))
+	for k, amount := range totals {
+		if amount.IsZero() {
+			continue
+		}
+		balances = append(balances, Balance{UserID: k.userID, Currency: k.currency, Amount: amount})
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].UserID != balances[j].UserID {
+			return balances[i].UserID < balances[j].UserID
+		}
+		return balances[i].Currency < balances[j].Currency
+	})
+	return balances
+}
+
+// Simplify replaces the debts with as few payments as possible that leave everybody with the
+// same net balance. Each currency is settled on its own. Debtors and creditors are matched
+// greedily, largest amounts first, which needs at most one payment less than the number of
+// people with an open balance.
+func Simplify(debts []Debt) []Debt {
+	net := map[string]map[uint]decimal.Decimal{}
+	for _, d := range debts {
+		if d.From == d.To {
+			continue
+		}
+		if net[d.Currency] == nil {
+			net[d.Currency] = map[uint]decimal.Decimal{}
+		}
+		net[d.Currency][d.From] = net[d.Currency][d.From].Sub(d.Amount)
+		net[d.Currency][d.To] = net[d.Currency][d.To].Add(d.Amount)
+	}
+
+	currencies := make([]string, 0, len(net))
+	for c := range net {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+
+	payments := []Debt{}
+	for _, c := range currencies {
+		payments = append(payments, settle(c, net[c])...)
+	}
+	return payments
+}
+
+type position struct {
+	userID uint
+	amount decimal.Decimal
+}
+
+func settle(currency string, net map[uint]decimal.Decimal) []Debt {
+	var debtors, creditors []*position
+	for userID, amount := range net {
+		if amount.IsNegative() {
+			debtors = append(debtors, &position{userID, amount.Neg()})
+		} else if amount.IsPositive() {
+			creditors = append(creditors, &position{userID, amount})
+		}
+	}
+
+	var payments []Debt
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sortPositions(debtors)
+		sortPositions(creditors)
+
+		debtor, creditor := debtors[0], creditors[0]
+		amount := decimal.Min(debtor.amount, creditor.amount)
+
+		payments = append(payments, Debt{From: debtor.userID, To: creditor.userID, Currency: currency, Amount: amount})
+
+		debtor.amount = debtor.amount.Sub(amount)
+		creditor.amount = creditor.amount.Sub(amount)
+		if debtor.amount.IsZero() {
+			debtors = debtors[1:]
+		}
+		if creditor.amount.IsZero() {
+			creditors = creditors[1:]
+		}
+	}
+	return payments
+}
+
+// sortPositions orders by amount, largest first, and by user ID to keep the result deterministic
+func sortPositions(positions []*position) {
+	sort.Slice(positions, func(i, j int) bool {
+		if c := positions[i].amount.Cmp(positions[j].amount); c != 0 {
+			return c > 0
+		}
+		return positions[i].userID < positions[j].userID
+	})
+}
@@ -0,0 +1,121 @@
+package settleup_test
+
+import (
+	"expense-api/internal/settleup"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func debt(from, to uint, currency, amount string) settleup.Debt {
+	return settleup.Debt{From: from, To: to, Currency: currency, Amount: decimal.RequireFromString(amount)}
+}
+
+func assertDebts(t *testing.T, got, want []settleup.Debt) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].From != want[i].From || got[i].To != want[i].To ||
+			got[i].Currency != want[i].Currency || !got[i].Amount.Equal(want[i].Amount) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestBalances(t *testing.T) {
+	got := settleup.Balances(1, []settleup.Debt{
+		debt(2, 1, "EUR", "30"),
+		debt(1, 2, "EUR", "10"),
+		debt(1, 3, "EUR", "5"),
+		debt(3, 1, "USD", "7"),
+		debt(1, 4, "EUR", "8"),
+		debt(4, 1, "EUR", "8"),
+		debt(2, 3, "EUR", "100"),
+	})
+
+	want := []settleup.Balance{
+		{UserID: 2, Currency: "EUR", Amount: decimal.RequireFromString("20")},
+		{UserID: 3, Currency: "EUR", Amount: decimal.RequireFromString("-5")},
+		{UserID: 3, Currency: "USD", Amount: decimal.RequireFromString("7")},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i].UserID != want[i].UserID || got[i].Currency != want[i].Currency || !got[i].Amount.Equal(want[i].Amount) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		debts    []settleup.Debt
+		expected []settleup.Debt
+	}{
+		{
+			desc: "Chain of debts collapses into a single payment",
+			debts: []settleup.Debt{
+				debt(1, 2, "EUR", "10"),
+				debt(2, 3, "EUR", "10"),
+			},
+			expected: []settleup.Debt{
+				debt(1, 3, "EUR", "10"),
+			},
+		},
+		{
+			desc: "Debts in both directions are netted",
+			debts: []settleup.Debt{
+				debt(1, 2, "EUR", "10"),
+				debt(2, 1, "EUR", "4"),
+			},
+			expected: []settleup.Debt{
+				debt(1, 2, "EUR", "6"),
+			},
+		},
+		{
+			desc: "Flatmates",
+			debts: []settleup.Debt{
+				debt(2, 1, "EUR", "30"),
+				debt(3, 1, "EUR", "30"),
+				debt(1, 2, "EUR", "15"),
+				debt(3, 2, "EUR", "15"),
+				debt(4, 3, "EUR", "20"),
+			},
+			expected: []settleup.Debt{
+				debt(3, 1, "EUR", "25"),
+				debt(4, 1, "EUR", "20"),
+			},
+		},
+		{
+			desc: "Currencies are settled separately",
+			debts: []settleup.Debt{
+				debt(1, 2, "USD", "5"),
+				debt(2, 1, "EUR", "5"),
+			},
+			expected: []settleup.Debt{
+				debt(2, 1, "EUR", "5"),
+				debt(1, 2, "USD", "5"),
+			},
+		},
+		{
+			desc: "Everything settled",
+			debts: []settleup.Debt{
+				debt(1, 2, "EUR", "5"),
+				debt(2, 1, "EUR", "5"),
+			},
+			expected: []settleup.Debt{},
+		},
+	}
+
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			assertDebts(t, settleup.Simplify(tC.debts), tC.expected)
+		})
+	}
+}
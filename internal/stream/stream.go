@@ -0,0 +1,125 @@
+package stream
+
+import (
+	"context"
+	"expense-api/internal/events"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// bufferSize is how many events a subscriber may fall behind before it is dropped
+	bufferSize = 64
+	// relistenDelay is how long the hub waits before listening again after the connection failed
+	relistenDelay = time.Second
+)
+
+// Subscribe notifies every instance through the database about the published events, so their hubs
+// can stream them
+func Subscribe(bus *events.Bus, repo repository.Repository) {
+	bus.Subscribe("stream", func(ctx context.Context, e *events.Event) error {
+		return repo.OutboxNotify(e.Sequence)
+	}, "*")
+}
+
+// Hub hands the events published on any instance to the streams of this one. It only listens to the
+// database while it has subscribers.
+type Hub struct {
+	repo repository.Repository
+
+	mu          sync.Mutex
+	subscribers map[chan *model.OutboxEvent]bool
+	stop        context.CancelFunc
+}
+
+func NewHub(repo repository.Repository) *Hub {
+	return &Hub{repo: repo, subscribers: map[chan *model.OutboxEvent]bool{}}
+}
+
+// Subscribe returns a channel with all events published from now on, and a function to unsubscribe.
+// The channel is closed when the subscriber falls behind or events may have been missed, so the
+// subscriber should catch up from the outbox.
+func (h *Hub) Subscribe() (<-chan *model.OutboxEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan *model.OutboxEvent, bufferSize)
+	h.subscribers[ch] = true
+	if h.stop == nil {
+		var ctx context.Context
+		ctx, h.stop = context.WithCancel(context.Background())
+		go h.listen(ctx)
+	}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.drop(ch)
+		if len(h.subscribers) == 0 && h.stop != nil {
+			h.stop()
+			h.stop = nil
+		}
+	}
+}
+
+func (h *Hub) listen(ctx context.Context) {
+	for {
+		err := h.repo.OutboxListen(ctx, func(id uint) { h.publish(ctx, id) })
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("couldn't listen for events: %v", err)
+		h.dropAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(relistenDelay):
+		}
+	}
+}
+
+func (h *Hub) publish(ctx context.Context, id uint) {
+	e, err := h.repo.OutboxGet(id)
+	if err != nil {
+		log.Printf("couldn't read event %d: %v", id, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// A new listener may have taken over in the meantime
+	if ctx.Err() != nil {
+		return
+	}
+	for ch := range h.subscribers {
+		select {
+		case ch <- e:
+		default:
+			h.drop(ch)
+		}
+	}
+}
+
+// dropAll drops the subscribers, as they missed the events published while the hub wasn't listening
+func (h *Hub) dropAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		h.drop(ch)
+	}
+}
+
+func (h *Hub) drop(ch chan *model.OutboxEvent) {
+	if h.subscribers[ch] {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
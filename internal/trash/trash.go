@@ -0,0 +1,58 @@
+package trash
+
+import (
+	"context"
+	"expense-api/internal/blobstore"
+	"expense-api/internal/repository"
+	"log"
+	"time"
+)
+
+// PurgeInterval is how often Run empties the trash
+const PurgeInterval = time.Hour
+
+// Purger permanently deletes the wallets, parties and transactions that have been in the trash for
+// longer than the retention period, together with the files of their attachments
+type Purger struct {
+	repo      repository.Repository
+	blobs     blobstore.BlobStore
+	retention time.Duration
+}
+
+func NewPurger(repo repository.Repository, blobs blobstore.BlobStore, retention time.Duration) *Purger {
+	return &Purger{repo, blobs, retention}
+}
+
+// Purge deletes everything that was trashed longer than the retention period before now. Files that
+// can't be deleted are only logged, their records are already gone.
+func (p *Purger) Purge(ctx context.Context, now time.Time) error {
+	attachments, err := p.repo.TrashPurge(now.Add(-p.retention))
+	if err != nil {
+		return err
+	}
+
+	for _, a := range attachments {
+		if err := p.blobs.Delete(ctx, a.StorageKey); err != nil {
+			log.Printf("couldn't delete blob %s: %v", a.StorageKey, err)
+		}
+	}
+	return nil
+}
+
+// Run purges the trash right away and then every interval, until the context is done
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Purge(ctx, time.Now()); err != nil {
+			log.Printf("couldn't purge the trash: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package trash_test
+
+import (
+	"context"
+	"errors"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/trash"
+	"expense-api/test/spies"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurge(t *testing.T) {
+	now := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	retention := 30 * 24 * time.Hour
+
+	t.Run("Delete the files of the purged attachments", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		blobStoreSpy := &spies.BlobStoreSpy{}
+
+		repoSpy.On("TrashPurge", now.Add(-retention)).Return([]*model.Attachment{
+			{StorageKey: "transactions/1/a"},
+			{StorageKey: "transactions/2/b"},
+		}, nil).Once()
+		blobStoreSpy.On("Delete", mock.Anything, "transactions/1/a").Return(errors.New("unavailable")).Once()
+		blobStoreSpy.On("Delete", mock.Anything, "transactions/2/b").Return(nil).Once()
+
+		if err := trash.NewPurger(repoSpy, blobStoreSpy, retention).Purge(context.Background(), now); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		repoSpy.AssertExpectations(t)
+		blobStoreSpy.AssertExpectations(t)
+	})
+
+	t.Run("Keep the files if the records couldn't be purged", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		blobStoreSpy := &spies.BlobStoreSpy{}
+
+		repoSpy.On("TrashPurge", now.Add(-retention)).Return(nil, repository.ErrorOther).Once()
+
+		if err := trash.NewPurger(repoSpy, blobStoreSpy, retention).Purge(context.Background(), now); err != repository.ErrorOther {
+			t.Errorf("expected %v, got %v", repository.ErrorOther, err)
+		}
+		blobStoreSpy.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,104 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"expense-api/internal/events"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BudgetExceeded is the object of budget.exceeded events: the expenses of the wallet in the month
+// went over its monthly budget
+type BudgetExceeded struct {
+	WalletID uint            `json:"wallet_id"`
+	Month    string          `json:"month"`
+	Currency string          `json:"currency"`
+	Budget   decimal.Decimal `json:"budget"`
+	Spent    decimal.Decimal `json:"spent"`
+}
+
+// budgetMonthLayout formats the calendar month of a budget.exceeded event
+const budgetMonthLayout = "2006-01"
+
+// subscribeBudgets raises a budget.exceeded event for the webhooks when a change of a transaction
+// makes the expenses of its wallet in the month of the transaction go over the wallet's budget
+func subscribeBudgets(bus *events.Bus, repo repository.Repository) {
+	bus.Subscribe("budgets", func(ctx context.Context, e *events.Event) error {
+		return checkBudget(repo, e)
+	}, model.EventTransactionCreated, model.EventTransactionUpdated, model.EventTransactionRestored)
+}
+
+// checkBudget queues the deliveries of a budget.exceeded event if the transaction of the event is an
+// expense and the wallet's expenses in its month are over the budget. The event has the same ID for a
+// wallet and a month, so webhooks get it only once a month, however often the budget is exceeded.
+func checkBudget(repo repository.Repository, e *events.Event) error {
+	var t struct {
+		WalletID  uint            `json:"wallet_id"`
+		Timestamp time.Time       `json:"timestamp"`
+		Amount    decimal.Decimal `json:"amount"`
+	}
+	if err := json.Unmarshal(e.Object, &t); err != nil {
+		return err
+	}
+	if !t.Amount.IsNegative() {
+		return nil
+	}
+
+	wallet, err := repo.WalletGet(t.WalletID)
+	if err == repository.ErrorRecordNotFound {
+		// The wallet went to the trash in the meantime
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if wallet.MonthlyBudget == nil {
+		return nil
+	}
+
+	timestamp := t.Timestamp.UTC()
+	month := time.Date(timestamp.Year(), timestamp.Month(), 1, 0, 0, 0, 0, time.UTC)
+	spent, err := repo.TransactionSumExpenses(wallet.ID, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return err
+	}
+	if spent.LessThanOrEqual(*wallet.MonthlyBudget) {
+		return nil
+	}
+
+	object, err := json.Marshal(&BudgetExceeded{
+		WalletID: wallet.ID,
+		Month:    month.Format(budgetMonthLayout),
+		Currency: wallet.Currency,
+		Budget:   *wallet.MonthlyBudget,
+		Spent:    spent,
+	})
+	if err != nil {
+		return err
+	}
+
+	return enqueue(repo, &events.Event{
+		ID:          budgetEventID(wallet.ID, month),
+		Type:        model.EventBudgetExceeded,
+		Aggregate:   model.AuditResourceWallet,
+		AggregateID: wallet.ID,
+		UserID:      wallet.UserID,
+		HouseholdID: wallet.HouseholdID,
+		OccurredAt:  e.OccurredAt,
+		Object:      object,
+	})
+}
+
+// budgetEventID is the ID of the budget.exceeded event of the wallet in the month, as long as the IDs
+// of the other events
+func budgetEventID(walletID uint, month time.Time) string {
+	id := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/%s", model.EventBudgetExceeded, walletID, month.Format(budgetMonthLayout))))
+	return hex.EncodeToString(id[:16])
+}
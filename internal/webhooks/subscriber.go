@@ -0,0 +1,69 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"expense-api/internal/events"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"time"
+)
+
+// Payload is the body of a webhook delivery. Object is the record after the change, or before it
+// if it was deleted for good, Changes the fields that changed.
+type Payload struct {
+	ID        string             `json:"id"`
+	Event     string             `json:"event"`
+	CreatedAt time.Time          `json:"created_at"`
+	Object    json.RawMessage    `json:"object"`
+	Changes   model.AuditChanges `json:"changes"`
+}
+
+// Subscribe queues a delivery of the wallet and transaction events to the enabled webhooks of the
+// owner of the record that subscribe to them, and of the budget.exceeded events the transaction
+// events raise
+func Subscribe(bus *events.Bus, repo repository.Repository) {
+	bus.Subscribe("webhooks", func(ctx context.Context, e *events.Event) error {
+		return enqueue(repo, e)
+	}, "transaction.*", "wallet.*")
+	subscribeBudgets(bus, repo)
+}
+
+// enqueue queues the deliveries of the event. Webhooks get an event once however often it is
+// published.
+func enqueue(repo repository.Repository, e *events.Event) error {
+	webhooks, err := repo.WebhookList(e.UserID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	var deliveries []*model.WebhookDelivery
+	for _, w := range webhooks {
+		if !w.Enabled || !w.Subscribes(e.Type) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(&Payload{
+				ID:        e.ID,
+				Event:     e.Type,
+				CreatedAt: e.OccurredAt,
+				Object:    e.Object,
+				Changes:   e.Changes,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			WebhookID:     w.ID,
+			EventID:       e.ID,
+			Event:         e.Type,
+			Payload:       string(payload),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: e.OccurredAt,
+		})
+	}
+	return repo.WebhookDeliveryEnqueue(deliveries)
+}
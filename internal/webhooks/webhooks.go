@@ -0,0 +1,256 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Headers of webhook deliveries
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-ID"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+const (
+	// DeliveryInterval is how often Run looks for due deliveries
+	DeliveryInterval = 5 * time.Second
+	// MaxAttempts is how often a delivery is tried before it fails for good
+	MaxAttempts = 8
+	// Timeout is how long a webhook may take to answer
+	Timeout = 10 * time.Second
+
+	batchSize = 10
+	// lease keeps other instances from sending the deliveries of a batch while they are being sent,
+	// which takes up to Timeout for each of them
+	lease          = (batchSize + 1) * Timeout
+	initialBackoff = 30 * time.Second
+	maxBackoff     = 6 * time.Hour
+	// maxErrorLength keeps the delivery log short when connections fail with long errors
+	maxErrorLength = 512
+)
+
+// ErrorAddressNotPublic is the error of deliveries to webhooks that resolve to an address that isn't
+// public, see IsPublicAddress
+var ErrorAddressNotPublic = errors.New("webhook address is not public")
+
+// deniedPrefixes are the networks webhooks may not reach: the server itself, the networks it may run
+// in and the ones that aren't routed on the internet
+var deniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // this network, 0.0.0.0 reaches the server itself
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space of carrier-grade NAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local, cloud metadata services
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, broadcast
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, reaches any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use NAT64
+	netip.MustParsePrefix("100::/64"),        // discard
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// IsPublicAddress is whether webhooks may be sent to the address. Webhooks mustn't reach the server
+// itself or the network it runs in.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() {
+		return false
+	}
+	for _, prefix := range deniedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewClient creates the client to send deliveries with. It checks the address of every connection
+// after the host was resolved, so a webhook can't point at a public name that resolves to a private
+// address, and it doesn't follow redirects, which could lead anywhere.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{Timeout: Timeout, Control: controlPublic}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   Timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// controlPublic refuses to connect to addresses that aren't public
+func controlPublic(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublicAddress(addrPort.Addr()) {
+		return ErrorAddressNotPublic
+	}
+	return nil
+}
+
+// Sign computes the signature of a delivery: the hex encoded HMAC-SHA256 of the timestamp, a dot and
+// the payload, keyed with the secret of the webhook. Receivers compute it the same way and compare.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff is how long to wait after the attempt-th failed attempt, doubling from 30 seconds up to 6
+// hours
+func Backoff(attempt int) time.Duration {
+	backoff := initialBackoff
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
+
+// Dispatcher sends the pending deliveries of the webhooks
+type Dispatcher struct {
+	repo   repository.Repository
+	client *http.Client
+}
+
+func NewDispatcher(repo repository.Repository, client *http.Client) *Dispatcher {
+	return &Dispatcher{repo, client}
+}
+
+// Deliver sends the deliveries that are due, until none is left. Failed attempts are retried with
+// exponential backoff. The clock is read for every claim and every attempt, a batch takes a while to
+// send and receivers reject deliveries with old timestamps.
+func (d *Dispatcher) Deliver(ctx context.Context, clock func() time.Time) error {
+	for {
+		deliveries, err := d.repo.WebhookDeliveryClaim(clock(), lease, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			d.attempt(ctx, delivery, clock())
+			if err := d.repo.WebhookDeliveryRecordAttempt(delivery); err != nil && err != repository.ErrorRecordNotFound {
+				return err
+			}
+		}
+
+		if len(deliveries) < batchSize {
+			return nil
+		}
+	}
+}
+
+// attempt sends the delivery once and updates it with the outcome. Webhooks accept a delivery by
+// answering with a 2xx status; redirects count as failures.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *model.WebhookDelivery, now time.Time) {
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+	delivery.Error = ""
+
+	status, err := d.send(ctx, delivery, now)
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		return
+	}
+
+	delivery.Error = truncate(err.Error())
+	if delivery.Attempts >= MaxAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+		return
+	}
+	delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *model.WebhookDelivery, now time.Time) (int, error) {
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Webhook.Secret, timestamp, payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	// The body isn't kept, the delivery log would show the user whatever the URL answers with
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, errors.New(res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// Run delivers right away and then every interval, until the context is done
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Deliver(ctx, time.Now); err != nil {
+			log.Printf("couldn't deliver webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func truncate(s string) string {
+	if len(s) > maxErrorLength {
+		return s[:maxErrorLength]
+	}
+	return s
+}
@@ -0,0 +1,490 @@
+package webhooks_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"expense-api/internal/events"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/webhooks"
+	"expense-api/test/spies"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+const secret = "s3cr3t"
+
+// receiver stands in for the server behind a webhook. It answers with the statuses in order and
+// keeps the requests it got.
+type receiver struct {
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	status := r.statuses[0]
+	r.statuses = r.statuses[1:]
+	w.WriteHeader(status)
+	w.Write([]byte("not today"))
+}
+
+func delivery(url string, attempts int) *model.WebhookDelivery {
+	d := &model.WebhookDelivery{
+		WebhookID: 2,
+		Webhook:   model.Webhook{URL: url, Secret: secret, Enabled: true},
+		EventID:   "0123456789abcdef",
+		Event:     model.EventTransactionCreated,
+		Payload:   `{"id":"0123456789abcdef","event":"transaction.created"}`,
+		Status:    model.WebhookDeliveryPending,
+		Attempts:  attempts,
+	}
+	d.ID = 7
+	return d
+}
+
+// recorded returns what the dispatcher stored about the delivery
+func recorded(repoSpy *spies.RepositorySpy) *model.WebhookDelivery {
+	for _, call := range repoSpy.Calls {
+		if call.Method == "WebhookDeliveryRecordAttempt" {
+			return call.Arguments.Get(0).(*model.WebhookDelivery)
+		}
+	}
+	return nil
+}
+
+func TestDeliver(t *testing.T) {
+	now := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	t.Run("Send a signed delivery", func(t *testing.T) {
+		stub := &receiver{statuses: []int{http.StatusNoContent}}
+		server := httptest.NewServer(stub)
+		defer server.Close()
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{delivery(server.URL, 0)}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, server.Client()).Deliver(context.Background(), clock); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(stub.requests) != 1 {
+			t.Fatalf("expected 1 request, got %d", len(stub.requests))
+		}
+		req := stub.requests[0]
+		if got := req.Header.Get(webhooks.HeaderEvent); got != model.EventTransactionCreated {
+			t.Errorf("expected event %s, got %s", model.EventTransactionCreated, got)
+		}
+		if got := req.Header.Get(webhooks.HeaderDelivery); got != "7" {
+			t.Errorf("expected delivery 7, got %s", got)
+		}
+		timestamp, _ := strconv.ParseInt(req.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+		if timestamp != now.Unix() {
+			t.Errorf("expected timestamp %d, got %d", now.Unix(), timestamp)
+		}
+		signature := webhooks.Sign(secret, timestamp, stub.bodies[0])
+		if !hmac.Equal([]byte(req.Header.Get(webhooks.HeaderSignature)), []byte(signature)) {
+			t.Errorf("expected signature %s, got %s", signature, req.Header.Get(webhooks.HeaderSignature))
+		}
+
+		d := recorded(repoSpy)
+		if d.Status != model.WebhookDeliverySucceeded || d.Attempts != 1 || d.ResponseStatus != http.StatusNoContent {
+			t.Errorf("expected a successful first attempt, got %+v", d)
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Sign every delivery of a batch when it is sent", func(t *testing.T) {
+		stub := &receiver{statuses: []int{http.StatusNoContent, http.StatusNoContent}}
+		server := httptest.NewServer(stub)
+		defer server.Close()
+
+		// Every reading of the clock is a minute later than the one before
+		ticks := 0
+		ticking := func() time.Time {
+			ticks++
+			return now.Add(time.Duration(ticks) * time.Minute)
+		}
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now.Add(time.Minute), mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{
+			delivery(server.URL, 0), delivery(server.URL, 0),
+		}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Twice()
+
+		if err := webhooks.NewDispatcher(repoSpy, server.Client()).Deliver(context.Background(), ticking); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(stub.requests) != 2 {
+			t.Fatalf("expected 2 requests, got %d", len(stub.requests))
+		}
+		for i, req := range stub.requests {
+			expected := now.Add(time.Duration(i+2) * time.Minute).Unix()
+			timestamp, _ := strconv.ParseInt(req.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+			if timestamp != expected {
+				t.Errorf("expected timestamp %d for delivery %d, got %d", expected, i, timestamp)
+			}
+			signature := webhooks.Sign(secret, timestamp, stub.bodies[i])
+			if req.Header.Get(webhooks.HeaderSignature) != signature {
+				t.Errorf("expected signature %s for delivery %d, got %s", signature, i, req.Header.Get(webhooks.HeaderSignature))
+			}
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Retry a failed delivery later", func(t *testing.T) {
+		stub := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+		server := httptest.NewServer(stub)
+		defer server.Close()
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{delivery(server.URL, 2)}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, server.Client()).Deliver(context.Background(), clock); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		d := recorded(repoSpy)
+		if d.Status != model.WebhookDeliveryPending || d.Attempts != 3 || d.ResponseStatus != http.StatusServiceUnavailable {
+			t.Errorf("expected a pending delivery after the third attempt, got %+v", d)
+		}
+		if expected := now.Add(webhooks.Backoff(3)); !d.NextAttemptAt.Equal(expected) {
+			t.Errorf("expected the next attempt at %v, got %v", expected, d.NextAttemptAt)
+		}
+		if d.Error != "503 Service Unavailable" {
+			t.Errorf("expected the status without the body in the error, got %q", d.Error)
+		}
+	})
+
+	t.Run("Give up after the last attempt", func(t *testing.T) {
+		server := httptest.NewServer(&receiver{statuses: []int{http.StatusInternalServerError}})
+		defer server.Close()
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{delivery(server.URL, webhooks.MaxAttempts-1)}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, server.Client()).Deliver(context.Background(), clock); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if d := recorded(repoSpy); d.Status != model.WebhookDeliveryFailed || d.Attempts != webhooks.MaxAttempts {
+			t.Errorf("expected a failed delivery, got %+v", d)
+		}
+	})
+
+	t.Run("Unreachable webhook", func(t *testing.T) {
+		server := httptest.NewServer(&receiver{})
+		server.Close()
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{delivery(server.URL, 0)}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, server.Client()).Deliver(context.Background(), clock); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if d := recorded(repoSpy); d.Status != model.WebhookDeliveryPending || d.ResponseStatus != 0 || d.Error == "" {
+			t.Errorf("expected a pending delivery with the connection error, got %+v", d)
+		}
+	})
+
+	t.Run("Webhook at a private address", func(t *testing.T) {
+		stub := &receiver{statuses: []int{http.StatusNoContent}}
+		server := httptest.NewServer(stub)
+		defer server.Close()
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{delivery(server.URL, 0)}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, webhooks.NewClient()).Deliver(context.Background(), clock); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if len(stub.requests) != 0 {
+			t.Errorf("expected the loopback server not to be called, got %d requests", len(stub.requests))
+		}
+		if d := recorded(repoSpy); d.Status != model.WebhookDeliveryPending || !strings.Contains(d.Error, webhooks.ErrorAddressNotPublic.Error()) {
+			t.Errorf("expected a pending delivery refused for its address, got %+v", d)
+		}
+	})
+
+	t.Run("Redirects aren't followed", func(t *testing.T) {
+		server := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest/meta-data", http.StatusFound))
+		defer server.Close()
+
+		client := server.Client()
+		client.CheckRedirect = webhooks.NewClient().CheckRedirect
+
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return([]*model.WebhookDelivery{delivery(server.URL, 0)}, nil).Once()
+		repoSpy.On("WebhookDeliveryRecordAttempt", mock.Anything).Return(nil).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, client).Deliver(context.Background(), clock); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if d := recorded(repoSpy); d.Status != model.WebhookDeliveryPending || d.ResponseStatus != http.StatusFound {
+			t.Errorf("expected a pending delivery that wasn't redirected, got %+v", d)
+		}
+	})
+
+	t.Run("Deliveries can't be claimed", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookDeliveryClaim", now, mock.Anything, mock.Anything).Return(nil, repository.ErrorOther).Once()
+
+		if err := webhooks.NewDispatcher(repoSpy, http.DefaultClient).Deliver(context.Background(), clock); err != repository.ErrorOther {
+			t.Errorf("expected %v, got %v", repository.ErrorOther, err)
+		}
+	})
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	testCases := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"100.128.0.1", true},
+		{"0.1.2.3", false},
+		{"198.18.0.1", false},
+		{"198.19.255.255", false},
+		{"192.0.2.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"64:ff9b::7f00:1", false},
+		{"64:ff9b::a00:1", false},
+		{"ff02::1", false},
+	}
+
+	for _, tc := range testCases {
+		if got := webhooks.IsPublicAddress(netip.MustParseAddr(tc.addr)); got != tc.public {
+			t.Errorf("expected %s to be public: %t, got %t", tc.addr, tc.public, got)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	occurredAt := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	event := &events.Event{
+		ID:          "0123456789abcdef",
+		Type:        model.EventWalletUpdated,
+		Aggregate:   model.AuditResourceWallet,
+		AggregateID: 3,
+		UserID:      1,
+		OccurredAt:  occurredAt,
+		Object:      json.RawMessage(`{"id":3,"name":"cash"}`),
+		Changes:     model.AuditChanges{"name": {Before: "wallet", After: "cash"}},
+	}
+	webhook := func(id uint, enabled bool, events ...string) *model.Webhook {
+		w := &model.Webhook{UserID: 1, Events: events, Enabled: enabled}
+		w.ID = id
+		return w
+	}
+
+	t.Run("Queue a delivery to the subscribed webhooks", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookList", uint(1)).Return([]*model.Webhook{
+			webhook(2, true, "wallet.*"),
+			webhook(3, true, model.EventTransactionCreated),
+			webhook(4, false, "*"),
+			webhook(5, true, model.EventWalletUpdated),
+		}, nil).Once()
+		repoSpy.On("WebhookDeliveryEnqueue", mock.Anything).Return(nil).Once()
+
+		bus := events.NewBus()
+		webhooks.Subscribe(bus, repoSpy)
+		if err := bus.Publish(context.Background(), event); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		deliveries := repoSpy.Calls[1].Arguments.Get(0).([]*model.WebhookDelivery)
+		var got []uint
+		for _, d := range deliveries {
+			got = append(got, d.WebhookID)
+			if d.EventID != event.ID || d.Event != event.Type || d.Status != model.WebhookDeliveryPending || !d.NextAttemptAt.Equal(occurredAt) {
+				t.Errorf("expected a pending delivery of the event, got %+v", d)
+			}
+		}
+		if diff := cmp.Diff([]uint{2, 5}, got); diff != "" {
+			t.Errorf("unexpected webhooks (-expected +got):\n%s", diff)
+		}
+
+		expected := `{"id":"0123456789abcdef","event":"wallet.updated","created_at":"2021-03-31T12:00:00Z",` +
+			`"object":{"id":3,"name":"cash"},"changes":{"name":{"before":"wallet","after":"cash"}}}`
+		if deliveries[0].Payload != expected {
+			t.Errorf("expected payload %s, got %s", expected, deliveries[0].Payload)
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Leave out party events", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+
+		bus := events.NewBus()
+		webhooks.Subscribe(bus, repoSpy)
+		if err := bus.Publish(context.Background(), &events.Event{Type: model.EventPartyCreated, UserID: 1}); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		repoSpy.AssertNotCalled(t, "WebhookList", mock.Anything)
+	})
+
+	t.Run("Webhooks can't be listed", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookList", uint(1)).Return(nil, repository.ErrorOther).Once()
+
+		bus := events.NewBus()
+		webhooks.Subscribe(bus, repoSpy)
+		if err := bus.Publish(context.Background(), event); !errors.Is(err, repository.ErrorOther) {
+			t.Errorf("expected %v, got %v", repository.ErrorOther, err)
+		}
+	})
+}
+
+func TestSubscribeBudgets(t *testing.T) {
+	occurredAt := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	march := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	budget := decimal.RequireFromString("100")
+	wallet := &model.Wallet{Currency: "EUR", MonthlyBudget: &budget, UserID: 1}
+	wallet.ID = 3
+	transaction := func(amount string) *events.Event {
+		return &events.Event{
+			ID:          "0123456789abcdef",
+			Type:        model.EventTransactionCreated,
+			Aggregate:   model.AuditResourceTransaction,
+			AggregateID: 9,
+			UserID:      1,
+			OccurredAt:  occurredAt,
+			Object:      json.RawMessage(`{"id":9,"wallet_id":3,"timestamp":"2021-03-20T18:30:00+01:00","amount":"` + amount + `"}`),
+		}
+	}
+	budgetWebhook := &model.Webhook{UserID: 1, Events: []string{model.EventBudgetExceeded}, Enabled: true}
+	budgetWebhook.ID = 2
+
+	t.Run("Raise budget.exceeded when the expenses of the month go over the budget", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookList", uint(1)).Return([]*model.Webhook{budgetWebhook}, nil).Twice()
+		repoSpy.On("WebhookDeliveryEnqueue", mock.Anything).Return(nil).Twice()
+		repoSpy.On("WalletGet", uint(3)).Return(wallet, nil).Once()
+		repoSpy.On("TransactionSumExpenses", uint(3), march, march.AddDate(0, 1, 0)).Return(decimal.RequireFromString("120.5"), nil).Once()
+
+		bus := events.NewBus()
+		webhooks.Subscribe(bus, repoSpy)
+		if err := bus.Publish(context.Background(), transaction("-30")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		var deliveries []*model.WebhookDelivery
+		for _, call := range repoSpy.Calls {
+			if call.Method == "WebhookDeliveryEnqueue" {
+				deliveries = append(deliveries, call.Arguments.Get(0).([]*model.WebhookDelivery)...)
+			}
+		}
+		if len(deliveries) != 1 || deliveries[0].Event != model.EventBudgetExceeded || deliveries[0].WebhookID != 2 {
+			t.Fatalf("expected one budget.exceeded delivery, got %+v", deliveries)
+		}
+		if len(deliveries[0].EventID) != 32 {
+			t.Errorf("expected an event ID of 32 characters, got %q", deliveries[0].EventID)
+		}
+
+		var payload struct {
+			Event  string                  `json:"event"`
+			Object webhooks.BudgetExceeded `json:"object"`
+		}
+		if err := json.Unmarshal([]byte(deliveries[0].Payload), &payload); err != nil {
+			t.Fatalf("expected a JSON payload, got %v", err)
+		}
+		if payload.Object.WalletID != 3 || payload.Object.Month != "2021-03" || !payload.Object.Budget.Equal(budget) ||
+			!payload.Object.Spent.Equal(decimal.RequireFromString("120.5")) || payload.Object.Currency != "EUR" {
+			t.Errorf("unexpected budget in the payload: %+v", payload.Object)
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Expenses within the budget", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookList", uint(1)).Return([]*model.Webhook{budgetWebhook}, nil).Once()
+		repoSpy.On("WebhookDeliveryEnqueue", mock.Anything).Return(nil).Once()
+		repoSpy.On("WalletGet", uint(3)).Return(wallet, nil).Once()
+		repoSpy.On("TransactionSumExpenses", uint(3), march, march.AddDate(0, 1, 0)).Return(budget, nil).Once()
+
+		bus := events.NewBus()
+		webhooks.Subscribe(bus, repoSpy)
+		if err := bus.Publish(context.Background(), transaction("-30")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Incomes don't count", func(t *testing.T) {
+		repoSpy := &spies.RepositorySpy{}
+		repoSpy.On("WebhookList", uint(1)).Return([]*model.Webhook{budgetWebhook}, nil).Once()
+		repoSpy.On("WebhookDeliveryEnqueue", mock.Anything).Return(nil).Once()
+
+		bus := events.NewBus()
+		webhooks.Subscribe(bus, repoSpy)
+		if err := bus.Publish(context.Background(), transaction("30")); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		repoSpy.AssertNotCalled(t, "WalletGet", mock.Anything)
+	})
+}
+
+func TestSign(t *testing.T) {
+	// Computed with: printf '1617192000.{"id":"1"}' | openssl dgst -sha256 -hmac s3cr3t
+	expected := "sha256=9563ac34cb27dde9d3745d49c621c9f111e4733016a77c87e694c7bbfd60fc60"
+	if got := webhooks.Sign(secret, 1617192000, []byte(`{"id":"1"}`)); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	var got []time.Duration
+	for attempt := 1; attempt <= 11; attempt++ {
+		got = append(got, webhooks.Backoff(attempt))
+	}
+
+	expected := []time.Duration{
+		30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 16 * time.Minute,
+		32 * time.Minute, 64 * time.Minute, 128 * time.Minute, 256 * time.Minute, 6 * time.Hour,
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("unexpected backoffs (-expected +got):\n%s", diff)
+	}
+}
@@ -1,10 +1,13 @@
 package integration
 
 import (
+	"context"
 	"expense-api/internal/handlers"
 	router_test "expense-api/test/router"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/shopspring/decimal"
@@ -15,7 +18,7 @@ func TestIntegration(t *testing.T) {
 		t.Skip("skipping integration test in short mode")
 	}
 
-	r := Setup()
+	r, dispatcher := Setup()
 
 	var authToken string
 
@@ -23,6 +26,7 @@ func TestIntegration(t *testing.T) {
 		walletID      uint
 		partyID       uint
 		transactionID uint
+		webhookID     uint
 	)
 
 	var (
@@ -67,6 +71,24 @@ func TestIntegration(t *testing.T) {
 		authToken = loginTokenResponseBody.Token
 	})
 
+	t.Run("Creates a webhook for the wallet events", func(t *testing.T) {
+		webhook := &handlers.WebhookRequest{
+			URL:    "http://localhost:8123/api/webhook/expenses",
+			Events: []string{"wallet.*"},
+		}
+
+		createWebhookReq := router_test.NewCreateWebhookRequest(webhook, authToken)
+		createWebhookRes := httptest.NewRecorder()
+
+		r.ServeHTTP(createWebhookRes, createWebhookReq)
+		router_test.AssertStatusCode(t, createWebhookRes, http.StatusCreated)
+
+		var createWebhookResponseBody handlers.Webhook
+		router_test.ParseJSONtoResponse(t, createWebhookRes, &createWebhookResponseBody)
+
+		webhookID = createWebhookResponseBody.ID
+	})
+
 	t.Run("Creates, updates, and lists wallets", func(t *testing.T) {
 		{
 			// Create wallet
@@ -103,8 +125,8 @@ func TestIntegration(t *testing.T) {
 
 		{
 			// Update wallet
-			updateWallet := &handlers.Wallet{
-				Name: "groceries",
+			updateWallet := router_test.Patch{
+				"name": "groceries",
 			}
 
 			updateWalletReq := router_test.NewUpdateWalletRequest(walletID, updateWallet, authToken)
@@ -116,8 +138,8 @@ func TestIntegration(t *testing.T) {
 			var updateWalletResponseBody handlers.Wallet
 			router_test.ParseJSONtoResponse(t, updateWalletRes, &updateWalletResponseBody)
 
-			if updateWallet.Name != updateWalletResponseBody.Name {
-				t.Errorf("Expected wallet name: %s, got: %s", updateWallet.Name, updateWalletResponseBody.Name)
+			if updateWallet["name"] != updateWalletResponseBody.Name {
+				t.Errorf("Expected wallet name: %s, got: %s", updateWallet["name"], updateWalletResponseBody.Name)
 			}
 		}
 
@@ -138,6 +160,32 @@ func TestIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("Queues the wallet events for the webhook", func(t *testing.T) {
+		if err := dispatcher.Dispatch(context.Background()); err != nil {
+			t.Fatalf("Couldn't dispatch the events: %v", err)
+		}
+
+		listDeliveriesReq := router_test.NewListWebhookDeliveriesRequest(webhookID, authToken)
+		listDeliveriesRes := httptest.NewRecorder()
+
+		r.ServeHTTP(listDeliveriesRes, listDeliveriesReq)
+		router_test.AssertStatusCode(t, listDeliveriesRes, http.StatusOK)
+
+		var deliveries router_test.WebhookDeliveryListResponse
+		router_test.ParseJSONtoResponse(t, listDeliveriesRes, &deliveries)
+
+		var events []string
+		for _, d := range deliveries.Entries {
+			events = append(events, d.Event)
+			if d.Status != "pending" {
+				t.Errorf("Expected the delivery of %s to be pending, got: %s", d.Event, d.Status)
+			}
+		}
+		if strings.Join(events, ",") != "wallet.updated,wallet.created" {
+			t.Errorf("Expected the wallet to be created and updated, got: %v", events)
+		}
+	})
+
 	t.Run("Creates, updates, and lists parties", func(t *testing.T) {
 		{
 			// Create party
@@ -174,8 +222,8 @@ func TestIntegration(t *testing.T) {
 
 		{
 			// Update party
-			updateParty := &handlers.Party{
-				Name: "groceries",
+			updateParty := router_test.Patch{
+				"name": "groceries",
 			}
 
 			updatePartyReq := router_test.NewUpdatePartyRequest(partyID, updateParty, authToken)
@@ -187,8 +235,8 @@ func TestIntegration(t *testing.T) {
 			var updatePartyResponseBody handlers.Party
 			router_test.ParseJSONtoResponse(t, updatePartyRes, &updatePartyResponseBody)
 
-			if updateParty.Name != updatePartyResponseBody.Name {
-				t.Errorf("Expected wallet name: %s, got: %s", updateParty.Name, updatePartyResponseBody.Name)
+			if updateParty["name"] != updatePartyResponseBody.Name {
+				t.Errorf("Expected wallet name: %s, got: %s", updateParty["name"], updatePartyResponseBody.Name)
 			}
 		}
 
@@ -247,8 +295,9 @@ func TestIntegration(t *testing.T) {
 
 		{
 			// Update transaction
-			updateTransaction := &handlers.Transaction{
-				Amount: decimal.NewFromFloat(99.99),
+			amount := decimal.NewFromFloat(99.99)
+			updateTransaction := router_test.Patch{
+				"amount": amount,
 			}
 
 			updateTransactionReq := router_test.NewUpdateTransactionRequest(transactionID, updateTransaction, authToken)
@@ -260,8 +309,8 @@ func TestIntegration(t *testing.T) {
 			var updateTransactionResponseBody handlers.Transaction
 			router_test.ParseJSONtoResponse(t, updateTransactionRes, &updateTransactionResponseBody)
 
-			if updateTransaction.Amount.Cmp(updateTransactionResponseBody.Amount) != 0 {
-				t.Errorf("Expected transaction amount: %v, got: %v", updateTransaction.Amount, updateTransactionResponseBody.Amount)
+			if amount.Cmp(updateTransactionResponseBody.Amount) != 0 {
+				t.Errorf("Expected transaction amount: %v, got: %v", amount, updateTransactionResponseBody.Amount)
 			}
 		}
 
@@ -345,6 +394,49 @@ func TestIntegration(t *testing.T) {
 			router_test.AssertStatusCode(t, getTransactionRes, http.StatusNotFound)
 		}
 
+		{
+			// Restore transaction
+			restoreTransactionReq := router_test.NewRestoreTransactionRequest(transactionID, authToken)
+			restoreTransactionRes := httptest.NewRecorder()
+
+			r.ServeHTTP(restoreTransactionRes, restoreTransactionReq)
+			router_test.AssertStatusCode(t, restoreTransactionRes, http.StatusOK)
+
+			// The wallet can't be deleted while it has transactions
+			deleteWalletReq := router_test.NewDeleteWalletRequest(walletID, authToken)
+			deleteWalletRes := httptest.NewRecorder()
+
+			r.ServeHTTP(deleteWalletRes, deleteWalletReq)
+			router_test.AssertStatusCode(t, deleteWalletRes, http.StatusConflict)
+
+			// Delete transaction again
+			deleteTransactionReq := router_test.NewDeleteTransactionRequest(transactionID, authToken)
+			deleteTransactionRes := httptest.NewRecorder()
+
+			r.ServeHTTP(deleteTransactionRes, deleteTransactionReq)
+			router_test.AssertStatusCode(t, deleteTransactionRes, http.StatusNoContent)
+		}
+
+		{
+			// The audit log has every change of the transaction, also while it is in the trash
+			listAuditReq := router_test.NewListAuditRequest(fmt.Sprintf("resource=transaction&id=%d", transactionID), authToken)
+			listAuditRes := httptest.NewRecorder()
+
+			r.ServeHTTP(listAuditRes, listAuditReq)
+			router_test.AssertStatusCode(t, listAuditRes, http.StatusOK)
+
+			var entries router_test.AuditEntryListResponse
+			router_test.ParseJSONtoResponse(t, listAuditRes, &entries)
+
+			var actions []string
+			for _, entry := range entries.Entries {
+				actions = append(actions, entry.Action)
+			}
+			if n := len(actions); n < 4 || actions[0] != "create" || strings.Join(actions[n-3:], ",") != "delete,restore,delete" {
+				t.Errorf("Expected the transaction to be created, deleted, restored and deleted, got: %v", actions)
+			}
+		}
+
 		{
 			// Delete wallet
 			deleteWalletReq := router_test.NewDeleteWalletRequest(walletID, authToken)
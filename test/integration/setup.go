@@ -2,11 +2,16 @@ package integration
 
 import (
 	"expense-api/internal/app"
+	"expense-api/internal/blobstore"
+	"expense-api/internal/events"
 	"expense-api/internal/middleware/auth"
 	"expense-api/internal/repository"
 	"expense-api/internal/router"
+	"expense-api/internal/stream"
 	"expense-api/internal/utils"
+	"expense-api/internal/webhooks"
 	"fmt"
+	"os"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,7 +36,9 @@ func NewTestingEnvironment() *app.Environment {
 	}
 }
 
-func Setup() *gin.Engine {
+// Setup returns the router and the dispatcher of the domain events, which the tests run themselves
+// when they need the events published
+func Setup() (*gin.Engine, *events.Dispatcher) {
 	env := NewTestingEnvironment()
 	env.LoadVariables()
 
@@ -52,5 +59,19 @@ func Setup() *gin.Engine {
 	jwtService := auth.NewJWTService(env.Issuer.Value, env.Secret.Value)
 	hasher := utils.NewPasswordHasher()
 
-	return router.Setup(repository, jwtService, hasher, router.TestConfig)
+	blobDir, err := os.MkdirTemp("", "xpense-blobs-")
+	if err != nil {
+		panic(fmt.Sprintf("couldn't create blob directory: %v", err))
+	}
+
+	blobs, err := blobstore.NewLocal(blobDir)
+	if err != nil {
+		panic(fmt.Sprintf("couldn't set up blob store: %v", err))
+	}
+
+	bus := events.NewBus()
+	webhooks.Subscribe(bus, repository)
+	stream.Subscribe(bus, repository)
+
+	return router.Setup(repository, jwtService, hasher, blobs, router.TestConfig), events.NewDispatcher(repository, bus)
 }
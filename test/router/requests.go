@@ -4,18 +4,39 @@ import (
 	"bytes"
 	"expense-api/internal/handlers"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strings"
 )
 
 var (
-	BasePath             = "/api/v1"
-	BaseAccountPath      = BasePath + "/account/"
-	BaseAuthPath         = BasePath + "/auth"
-	BasePartiesPath      = BasePath + "/parties/"
-	BaseTransactionsPath = BasePath + "/transactions/"
-	BaseWalletsPath      = BasePath + "/wallets/"
+	BasePath              = "/api/v1"
+	BaseAccountPath       = BasePath + "/account/"
+	BaseAuthPath          = BasePath + "/auth"
+	BasePartiesPath       = BasePath + "/parties/"
+	BaseTransactionsPath  = BasePath + "/transactions/"
+	BaseWalletsPath       = BasePath + "/wallets/"
+	BaseExchangeRatesPath = BasePath + "/exchange-rates/"
+	BaseReportsPath       = BasePath + "/reports"
+	BaseSharedPath        = BasePath + "/shared"
+	BaseSearchPath        = BasePath + "/search"
+	BaseRulesPath         = BasePath + "/rules/"
+	BaseWebhooksPath      = BasePath + "/webhooks/"
+	BaseSuggestionsPath   = BasePath + "/suggestions"
+	BaseHouseholdsPath    = BasePath + "/households/"
+	BaseInvitationsPath   = BasePath + "/invitations/"
+	BaseTrashPath         = BasePath + "/trash/"
+	BaseAuditPath         = BasePath + "/audit"
+	BaseEventsPath        = BasePath + "/events"
+	GraphQLPath           = BasePath + "/graphql"
+	OpenAPIPath           = BasePath + "/openapi.json"
+	DocsPath              = BasePath + "/docs"
 )
 
+// Patch is the body of a PATCH request, a JSON Merge Patch in which nil clears a field
+type Patch map[string]interface{}
+
 func NewRequest(method, path, token string, handler interface{}) *http.Request {
 	body := createRequestBody(handler)
 	req, _ := http.NewRequest(method, path, bytes.NewReader(body))
@@ -29,8 +50,8 @@ func NewGetAccountRequest(token string) *http.Request {
 	return NewRequest(http.MethodGet, BaseAccountPath, token, nil)
 }
 
-func NewUpdateAccountRequest(user *handlers.Account, token string) *http.Request {
-	return NewRequest(http.MethodPatch, BaseAccountPath, token, user)
+func NewUpdateAccountRequest(patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, BaseAccountPath, token, patch)
 }
 
 func NewDeleteAccountRequest(token string) *http.Request {
@@ -55,14 +76,18 @@ func NewGetPartyRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d", BasePartiesPath, id), token, nil)
 }
 
-func NewUpdatePartyRequest(id uint, party *handlers.Party, token string) *http.Request {
-	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BasePartiesPath, id), token, party)
+func NewUpdatePartyRequest(id uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BasePartiesPath, id), token, patch)
 }
 
 func NewDeletePartyRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d", BasePartiesPath, id), token, nil)
 }
 
+func NewDeletePartyWithQueryRequest(id uint, query, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d?%s", BasePartiesPath, id, query), token, nil)
+}
+
 func NewListPartiesRequest(token string) *http.Request {
 	return NewRequest(http.MethodGet, BasePartiesPath, token, nil)
 }
@@ -71,17 +96,41 @@ func NewListTransactionsByPartyRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/transactions", BasePartiesPath, id), token, nil)
 }
 
+func NewMergePartiesRequest(id uint, merge *handlers.PartyMerge, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/merge", BasePartiesPath, id), token, merge)
+}
+
+func NewDeletePartyAliasRequest(id, aliasID uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d/aliases/%d", BasePartiesPath, id, aliasID), token, nil)
+}
+
 // Transactions
 func NewCreateTransactionRequest(transaction *handlers.Transaction, token string) *http.Request {
 	return NewRequest(http.MethodPost, BaseTransactionsPath, token, transaction)
 }
 
+func NewCreateTransactionStrictRequest(transaction *handlers.Transaction, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseTransactionsPath+"?strict=true", token, transaction)
+}
+
+func NewListDuplicateTransactionsRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseTransactionsPath+"duplicates", token, nil)
+}
+
+func NewBulkTransactionsRequest(bulk map[string]interface{}, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseTransactionsPath+"bulk", token, bulk)
+}
+
+func NewMergeTransactionsRequest(id uint, merge *handlers.TransactionMerge, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/merge", BaseTransactionsPath, id), token, merge)
+}
+
 func NewGetTransactionRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d", BaseTransactionsPath, id), token, nil)
 }
 
-func NewUpdateTransactionRequest(id uint, transaction *handlers.Transaction, token string) *http.Request {
-	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseTransactionsPath, id), token, transaction)
+func NewUpdateTransactionRequest(id uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseTransactionsPath, id), token, patch)
 }
 
 func NewDeleteTransactionRequest(id uint, token string) *http.Request {
@@ -92,6 +141,104 @@ func NewListTransactionsRequest(token string) *http.Request {
 	return NewRequest(http.MethodGet, BaseTransactionsPath, token, nil)
 }
 
+func NewListTransactionSplitsRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/splits", BaseTransactionsPath, id), token, nil)
+}
+
+func NewCreateTransactionSplitsRequest(id uint, splits *handlers.TransactionSplits, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/splits", BaseTransactionsPath, id), token, splits)
+}
+
+func NewUpdateTransactionSplitsRequest(id uint, splits *handlers.TransactionSplits, token string) *http.Request {
+	return NewRequest(http.MethodPut, fmt.Sprintf("%s%d/splits", BaseTransactionsPath, id), token, splits)
+}
+
+func NewGetTransactionSharesRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/shares", BaseTransactionsPath, id), token, nil)
+}
+
+func NewUpdateTransactionSharesRequest(id uint, shares *handlers.TransactionShares, token string) *http.Request {
+	return NewRequest(http.MethodPut, fmt.Sprintf("%s%d/shares", BaseTransactionsPath, id), token, shares)
+}
+
+func NewDeleteTransactionSharesRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d/shares", BaseTransactionsPath, id), token, nil)
+}
+
+func NewDeleteTransactionSplitsRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d/splits", BaseTransactionsPath, id), token, nil)
+}
+
+func NewListAttachmentsRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/attachments", BaseTransactionsPath, id), token, nil)
+}
+
+// NewCreateAttachmentRequest uploads the content as a multipart form, like a browser would
+func NewCreateAttachmentRequest(id uint, fileName, contentType string, content []byte, token string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, fileName))
+	header.Set("Content-Type", contentType)
+
+	part, _ := writer.CreatePart(header)
+	part.Write(content)
+	writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%d/attachments", BaseTransactionsPath, id), body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func NewGetAttachmentRequest(id, attachmentID uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/attachments/%d", BaseTransactionsPath, id, attachmentID), token, nil)
+}
+
+func NewDownloadAttachmentRequest(id, attachmentID uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/attachments/%d/download", BaseTransactionsPath, id, attachmentID), token, nil)
+}
+
+func NewDeleteAttachmentRequest(id, attachmentID uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d/attachments/%d", BaseTransactionsPath, id, attachmentID), token, nil)
+}
+
+// Search
+func NewSearchRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseSearchPath+"?"+query, token, nil)
+}
+
+// Suggestions
+func NewSuggestCategoriesRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseSuggestionsPath+"/categories?"+query, token, nil)
+}
+
+// Rules
+func NewCreateRuleRequest(rule *handlers.RuleRequest, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseRulesPath, token, rule)
+}
+
+func NewGetRuleRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d", BaseRulesPath, id), token, nil)
+}
+
+func NewUpdateRuleRequest(id uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseRulesPath, id), token, patch)
+}
+
+func NewDeleteRuleRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d", BaseRulesPath, id), token, nil)
+}
+
+func NewListRulesRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseRulesPath, token, nil)
+}
+
+func NewApplyRulesRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseRulesPath+"apply?"+query, token, nil)
+}
+
 // Wallets
 func NewCreateWalletRequest(wallet *handlers.Wallet, token string) *http.Request {
 	return NewRequest(http.MethodPost, BaseWalletsPath, token, wallet)
@@ -101,14 +248,22 @@ func NewGetWalletRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d", BaseWalletsPath, id), token, nil)
 }
 
-func NewUpdateWalletRequest(id uint, wallet *handlers.Wallet, token string) *http.Request {
-	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseWalletsPath, id), token, wallet)
+func NewUpdateWalletRequest(id uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseWalletsPath, id), token, patch)
 }
 
 func NewDeleteWalletRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d", BaseWalletsPath, id), token, nil)
 }
 
+func NewDeleteWalletWithQueryRequest(id uint, query, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d?%s", BaseWalletsPath, id, query), token, nil)
+}
+
+func NewListAllWalletsRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseWalletsPath+"?include_archived=true", token, nil)
+}
+
 func NewListWalletsRequest(token string) *http.Request {
 	return NewRequest(http.MethodGet, BaseWalletsPath, token, nil)
 }
@@ -116,3 +271,178 @@ func NewListWalletsRequest(token string) *http.Request {
 func NewListTransactionsByWalletRequest(id uint, token string) *http.Request {
 	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/transactions", BaseWalletsPath, id), token, nil)
 }
+
+func NewReconcileWalletRequest(id uint, statement *handlers.ReconciliationRequest, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/reconcile", BaseWalletsPath, id), token, statement)
+}
+
+func NewListReconciliationsRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/reconciliations", BaseWalletsPath, id), token, nil)
+}
+
+// Exchange Rates
+func NewCreateExchangeRateRequest(rate *handlers.ExchangeRate, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseExchangeRatesPath, token, rate)
+}
+
+func NewListExchangeRatesRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseExchangeRatesPath+query, token, nil)
+}
+
+func NewImportExchangeRatesRequest(format, contentType, body, token string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, BaseExchangeRatesPath+"import?format="+format, strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+// Reports
+func NewGetBalanceReportRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseReportsPath+"/balances"+query, token, nil)
+}
+
+func NewGetPartyReportRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseReportsPath+"/parties"+query, token, nil)
+}
+
+func NewGetCategoryReportRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseReportsPath+"/categories"+query, token, nil)
+}
+
+// Shared expenses
+func NewGetSharedBalancesRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseSharedPath+"/balances", token, nil)
+}
+
+func NewSimplifySharedDebtsRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseSharedPath+"/simplify", token, nil)
+}
+
+func NewCreateSettlementRequest(settlement *handlers.Settlement, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseSharedPath+"/settlements", token, settlement)
+}
+
+func NewDeleteSettlementRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s/settlements/%d", BaseSharedPath, id), token, nil)
+}
+
+// Households
+func NewCreateHouseholdRequest(household *handlers.Household, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseHouseholdsPath, token, household)
+}
+
+func NewGetHouseholdRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d", BaseHouseholdsPath, id), token, nil)
+}
+
+func NewUpdateHouseholdRequest(id uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseHouseholdsPath, id), token, patch)
+}
+
+func NewDeleteHouseholdRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d", BaseHouseholdsPath, id), token, nil)
+}
+
+func NewUpdateHouseholdMemberRequest(id, userID uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d/members/%d", BaseHouseholdsPath, id, userID), token, patch)
+}
+
+func NewRemoveHouseholdMemberRequest(id, userID uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d/members/%d", BaseHouseholdsPath, id, userID), token, nil)
+}
+
+func NewCreateHouseholdInvitationRequest(id uint, invitation *handlers.HouseholdInvitation, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/invitations", BaseHouseholdsPath, id), token, invitation)
+}
+
+func NewAcceptInvitationRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/accept", BaseInvitationsPath, id), token, nil)
+}
+
+func NewDeclineInvitationRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/decline", BaseInvitationsPath, id), token, nil)
+}
+
+// Trash
+func NewListTrashRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseTrashPath, token, nil)
+}
+
+func NewRestoreWalletRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%swallets/%d/restore", BaseTrashPath, id), token, nil)
+}
+
+func NewRestorePartyRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%sparties/%d/restore", BaseTrashPath, id), token, nil)
+}
+
+func NewRestoreTransactionRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%stransactions/%d/restore", BaseTrashPath, id), token, nil)
+}
+
+// Webhooks
+func NewCreateWebhookRequest(webhook *handlers.WebhookRequest, token string) *http.Request {
+	return NewRequest(http.MethodPost, BaseWebhooksPath, token, webhook)
+}
+
+func NewGetWebhookRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d", BaseWebhooksPath, id), token, nil)
+}
+
+func NewUpdateWebhookRequest(id uint, patch Patch, token string) *http.Request {
+	return NewRequest(http.MethodPatch, fmt.Sprintf("%s%d", BaseWebhooksPath, id), token, patch)
+}
+
+func NewDeleteWebhookRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodDelete, fmt.Sprintf("%s%d", BaseWebhooksPath, id), token, nil)
+}
+
+func NewListWebhooksRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseWebhooksPath, token, nil)
+}
+
+func NewListWebhookDeliveriesRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/deliveries", BaseWebhooksPath, id), token, nil)
+}
+
+func NewReplayWebhookDeliveryRequest(id uint, deliveryID string, token string) *http.Request {
+	return NewRequest(http.MethodPost, fmt.Sprintf("%s%d/deliveries/%s/replay", BaseWebhooksPath, id, deliveryID), token, nil)
+}
+
+// Audit
+func NewListAuditRequest(query, token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseAuditPath+"?"+query, token, nil)
+}
+
+func NewVerifyAuditRequest(token string) *http.Request {
+	return NewRequest(http.MethodGet, BaseAuditPath+"/verify", token, nil)
+}
+
+func NewListTransactionHistoryRequest(id uint, token string) *http.Request {
+	return NewRequest(http.MethodGet, fmt.Sprintf("%s%d/history", BaseTransactionsPath, id), token, nil)
+}
+
+// Events
+func NewStreamEventsRequest(lastEventID, token string) *http.Request {
+	req := NewRequest(http.MethodGet, BaseEventsPath+"/stream", token, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	return req
+}
+
+// GraphQL
+func NewGraphQLRequest(query string, variables map[string]interface{}, token string) *http.Request {
+	return NewRequest(http.MethodPost, GraphQLPath, token, map[string]interface{}{"query": query, "variables": variables})
+}
+
+// OpenAPI
+func NewGetOpenAPIDocumentRequest() *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, OpenAPIPath, nil)
+	return req
+}
+
+func NewGetDocsRequest() *http.Request {
+	req, _ := http.NewRequest(http.MethodGet, DocsPath, nil)
+	return req
+}
@@ -4,20 +4,24 @@ import (
 	"expense-api/internal/handlers"
 	auth_middleware "expense-api/internal/middleware/auth"
 	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"expense-api/internal/router"
 	"expense-api/test/spies"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/stretchr/testify/mock"
 )
 
 func TestGetAccount(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		token := "invalid-token"
@@ -61,23 +65,41 @@ func TestGetAccount(t *testing.T) {
 
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, &handlers.Account{})
+			AssertHeader(t, res, "ETag", `"0"`)
+		})
+
+		t.Run("Get existing user that is not modified", func(t *testing.T) {
+			user := &model.User{}
+			user.Version = 3
+
+			repoSpy.On("UserGet", claims.ID).Return(user, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetAccountRequest(token)
+			req.Header.Set("If-None-Match", `"2", W/"3"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNotModified)
+			AssertHeader(t, res, "ETag", `"3"`)
 		})
 	})
 }
 
 func TestUpdateAccount(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
-		account := &handlers.Account{}
+		patch := Patch{}
 		token := "invalid-token"
 
-		missingTokenReq := NewUpdateAccountRequest(account, token)
-		invalidTokenReq := NewUpdateAccountRequest(account, token)
+		missingTokenReq := NewUpdateAccountRequest(patch, token)
+		invalidTokenReq := NewUpdateAccountRequest(patch, token)
 
 		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
 		t.Run("Unauthorized test cases", unauthorizedTestCases)
@@ -92,74 +114,138 @@ func TestUpdateAccount(t *testing.T) {
 
 		jwtServiceSpy.On("ValidateJWT", token).Return(claims, nil)
 
-		t.Run("Update non-existent user", func(t *testing.T) {
-			account := &handlers.Account{
-				FirstName: "Updated First Name",
-				LastName:  "Last Name",
-				Email:     "john@doe.com",
-			}
+		newUser := func() *model.User {
+			user := &model.User{FirstName: "John", LastName: "Doe", Email: "john@doe.com", BaseCurrency: "EUR"}
+			user.ID = claims.ID
+			return user
+		}
 
-			repoSpy.On("UserUpdate", claims.ID, account.FirstName, account.LastName, account.Email).Return(nil, repository.ErrorRecordNotFound).Once()
+		t.Run("Update non-existent user", func(t *testing.T) {
+			repoSpy.On("UserGet", claims.ID).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateAccountRequest(account, token)
+			req := NewUpdateAccountRequest(Patch{"first_name": "Jane"}, token)
 
 			r.ServeHTTP(res, req)
 
 			AssertStatusCode(t, res, http.StatusNotFound)
 		})
 
-		t.Run("Update existing user with empty body", func(t *testing.T) {
-			account := &handlers.Account{}
+		badPatches := []struct {
+			desc    string
+			patch   Patch
+			message string
+		}{
+			{desc: "Remove the first name", patch: Patch{"first_name": nil}, message: handlers.ErrorName.Message},
+			{desc: "Set an invalid email", patch: Patch{"email": "@"}, message: handlers.ErrorEmail.Message},
+			{desc: "Remove the email", patch: Patch{"email": nil}, message: handlers.ErrorEmail.Message},
+			{desc: "Set an invalid base currency", patch: Patch{"base_currency": "XYZ"}, message: handlers.ErrorInvalidCurrency.Message},
+			{desc: "Set a field to a value of the wrong type", patch: Patch{"last_name": 1}, message: handlers.ErrorInvalidPatch.Message},
+		}
+
+		for _, tc := range badPatches {
+			t.Run(tc.desc, func(t *testing.T) {
+				repoSpy.On("UserGet", claims.ID).Return(newUser(), nil).Once()
+
+				res := httptest.NewRecorder()
+				req := NewUpdateAccountRequest(tc.patch, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Update existing user with an outdated If-Match", func(t *testing.T) {
+			current := newUser()
+			current.Version = 2
+
+			repoSpy.On("UserGet", claims.ID).Return(current, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateAccountRequest(account, token)
+			req := NewUpdateAccountRequest(Patch{"email": "jane@doe.com"}, token)
+			req.Header.Set("If-Match", `"1"`)
 
 			r.ServeHTTP(res, req)
 
-			wantErrorMessage := handlers.ErrorEmptyBody.Message
-
-			AssertStatusCode(t, res, http.StatusBadRequest)
-			AssertErrorMessage(t, res, wantErrorMessage)
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
 		})
 
-		t.Run("Update existing user with invalid email", func(t *testing.T) {
-			account := &handlers.Account{Email: "@"}
+		t.Run("Update existing user that is changed at the same time", func(t *testing.T) {
+			current := newUser()
+			current.Version = 2
+			expected := &model.User{FirstName: "John", LastName: "Doe", Email: "jane@doe.com", BaseCurrency: "EUR"}
+			expected.Version = 2
+
+			repoSpy.On("UserGet", claims.ID).Return(current, nil).Once()
+			repoSpy.On("UserUpdate", claims.ID, expected).Return(nil, repository.ErrorVersionConflict).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateAccountRequest(account, token)
+			req := NewUpdateAccountRequest(Patch{"email": "jane@doe.com"}, token)
+			req.Header.Set("If-Match", `"2"`)
 
 			r.ServeHTTP(res, req)
 
-			wantErrorMessage := handlers.ErrorEmail.Message
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorConcurrentUpdate.Message)
+		})
+
+		t.Run("Update existing user with an email that is taken", func(t *testing.T) {
+			repoSpy.On("UserGet", claims.ID).Return(newUser(), nil).Once()
+			repoSpy.On("UserUpdate", claims.ID, &model.User{FirstName: "John", LastName: "Doe", Email: "jane@doe.com", BaseCurrency: "EUR"}).Return(nil, repository.ErrorUniqueConstaintViolation).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateAccountRequest(Patch{"email": "jane@doe.com"}, token)
+
+			r.ServeHTTP(res, req)
 
-			AssertStatusCode(t, res, http.StatusBadRequest)
-			AssertErrorMessage(t, res, wantErrorMessage)
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorEmailConflict.Message)
 		})
 
 		t.Run("Update existing user with valid email", func(t *testing.T) {
-			user := &model.User{Email: "john@doe.com"}
-			account := &handlers.Account{Email: user.Email}
+			updated := newUser()
+			updated.Email = "jane@doe.com"
+
+			repoSpy.On("UserGet", claims.ID).Return(newUser(), nil).Once()
+			repoSpy.On("UserUpdate", claims.ID, &model.User{FirstName: "John", LastName: "Doe", Email: "jane@doe.com", BaseCurrency: "EUR"}).Return(updated, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateAccountRequest(Patch{"email": "jane@doe.com"}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.UserModelToAccountResponse(updated))
+		})
+
+		t.Run("Update existing user with valid base currency", func(t *testing.T) {
+			updated := newUser()
+			updated.BaseCurrency = "USD"
 
-			repoSpy.On("UserUpdate", claims.ID, user.FirstName, user.LastName, user.Email).Return(user, nil).Once()
+			repoSpy.On("UserGet", claims.ID).Return(newUser(), nil).Once()
+			repoSpy.On("UserUpdate", claims.ID, &model.User{FirstName: "John", LastName: "Doe", Email: "john@doe.com", BaseCurrency: "USD"}).Return(updated, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateAccountRequest(account, token)
+			req := NewUpdateAccountRequest(Patch{"base_currency": "usd"}, token)
 
 			r.ServeHTTP(res, req)
 
 			AssertStatusCode(t, res, http.StatusOK)
-			AssertResponseBody(t, res, account)
+			AssertResponseBody(t, res, handlers.UserModelToAccountResponse(updated))
 		})
 	})
 }
 
 func TestDeleteAccount(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		token := "invalid-token"
@@ -181,6 +267,8 @@ func TestDeleteAccount(t *testing.T) {
 		jwtServiceSpy.On("ValidateJWT", token).Return(claims, nil)
 
 		t.Run("Delete non-existent user", func(t *testing.T) {
+			repoSpy.On("HouseholdListByUser", claims.ID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("AttachmentListByUser", claims.ID).Return([]*model.Attachment{}, nil).Once()
 			repoSpy.On("UserDelete", claims.ID).Return(repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
@@ -192,6 +280,70 @@ func TestDeleteAccount(t *testing.T) {
 		})
 
 		t.Run("Delete existing user", func(t *testing.T) {
+			attachments := []*model.Attachment{
+				{StorageKey: "transactions/1/a"},
+				{StorageKey: "transactions/2/b"},
+			}
+
+			repoSpy.On("HouseholdListByUser", claims.ID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("AttachmentListByUser", claims.ID).Return(attachments, nil).Once()
+			repoSpy.On("UserDelete", claims.ID).Return(nil).Once()
+			blobStoreSpy.On("Delete", mock.Anything, "transactions/1/a").Return(nil).Once()
+			blobStoreSpy.On("Delete", mock.Anything, "transactions/2/b").Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteAccountRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNoContent)
+			blobStoreSpy.AssertExpectations(t)
+		})
+
+		member := func(householdID, userID uint, role string) *model.HouseholdMember {
+			return &model.HouseholdMember{HouseholdID: householdID, UserID: userID, Role: role}
+		}
+
+		t.Run("Delete the last owner of a household with other members", func(t *testing.T) {
+			repoSpy.On("HouseholdListByUser", claims.ID).Return([]*model.HouseholdMember{
+				member(1, claims.ID, permissions.RoleEditor),
+				member(2, claims.ID, permissions.RoleOwner),
+			}, nil).Once()
+			repoSpy.On("HouseholdMemberList", uint(1)).Return([]*model.HouseholdMember{
+				member(1, 11, permissions.RoleOwner),
+				member(1, claims.ID, permissions.RoleEditor),
+			}, nil).Once()
+			repoSpy.On("HouseholdMemberList", uint(2)).Return([]*model.HouseholdMember{
+				member(2, claims.ID, permissions.RoleOwner),
+				member(2, 12, permissions.RoleViewer),
+			}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteAccountRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorLastOwner.Message)
+			repoSpy.AssertNotCalled(t, "HouseholdHandOver", mock.Anything, mock.Anything, mock.Anything)
+		})
+
+		t.Run("Delete a user who is a member of households", func(t *testing.T) {
+			repoSpy.On("HouseholdListByUser", claims.ID).Return([]*model.HouseholdMember{
+				member(1, claims.ID, permissions.RoleOwner),
+				member(2, claims.ID, permissions.RoleOwner),
+			}, nil).Once()
+			repoSpy.On("HouseholdMemberList", uint(1)).Return([]*model.HouseholdMember{
+				member(1, claims.ID, permissions.RoleOwner),
+				member(1, 12, permissions.RoleViewer),
+				member(1, 11, permissions.RoleOwner),
+			}, nil).Once()
+			repoSpy.On("HouseholdMemberList", uint(2)).Return([]*model.HouseholdMember{
+				member(2, claims.ID, permissions.RoleOwner),
+			}, nil).Once()
+			repoSpy.On("HouseholdHandOver", uint(1), claims.ID, uint(11)).Return(nil).Once()
+			repoSpy.On("HouseholdDelete", uint(2)).Return(nil).Once()
+			repoSpy.On("AttachmentListByUser", claims.ID).Return([]*model.Attachment{}, nil).Once()
 			repoSpy.On("UserDelete", claims.ID).Return(nil).Once()
 
 			res := httptest.NewRecorder()
@@ -200,6 +352,7 @@ func TestDeleteAccount(t *testing.T) {
 			r.ServeHTTP(res, req)
 
 			AssertStatusCode(t, res, http.StatusNoContent)
+			repoSpy.AssertExpectations(t)
 		})
 	})
 }
@@ -0,0 +1,382 @@
+package router
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+var receipt = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF")
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+func newAttachment(id, transactionID uint, content []byte) *model.Attachment {
+	a := &model.Attachment{
+		TransactionID: transactionID,
+		UserID:        1,
+		FileName:      "receipt.pdf",
+		ContentType:   "application/pdf",
+		Size:          int64(len(content)),
+		Checksum:      checksum(content),
+		StorageKey:    "transactions/1/abc",
+	}
+	a.ID = id
+	return a
+}
+
+func TestCreateAttachment(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		id := uint(1)
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", receipt, token)
+		invalidTokenReq := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", receipt, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		id := uint(1)
+		transaction := &model.Transaction{
+			Amount: decimal.NewFromInt(-20),
+			UserID: userID,
+		}
+
+		t.Run("Attach to another user's transaction", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(&model.Transaction{UserID: userID + 1}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", receipt, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+		})
+
+		t.Run("Attach without a file", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRequest(http.MethodPost, BaseTransactionsPath+"1/attachments", token, nil)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorAttachmentMissing.Message)
+		})
+
+		t.Run("Attach an empty file", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", []byte{}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorAttachmentEmpty.Message)
+		})
+
+		t.Run("Attach a file that is too large", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			content := bytes.Repeat([]byte("a"), handlers.MaxAttachmentSize+1)
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "scan.txt", "text/plain", content, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusRequestEntityTooLarge)
+			AssertErrorMessage(t, res, handlers.ErrorAttachmentTooLarge.Message)
+		})
+
+		t.Run("Attach an unsupported file type", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "receipts.zip", "application/zip", []byte("PK\x03\x04archive"), token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusUnsupportedMediaType)
+			AssertErrorMessage(t, res, handlers.ErrorAttachmentType.Message)
+		})
+
+		t.Run("Attach a file disguised as a PDF", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", []byte("<html><body>hi</body></html>"), token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusUnsupportedMediaType)
+		})
+
+		t.Run("Blob store failure", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			blobStoreSpy.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(len(receipt)), "application/pdf").
+				Return(errors.New("disk full")).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", receipt, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusInternalServerError)
+		})
+
+		t.Run("Database failure removes the blob", func(t *testing.T) {
+			var key string
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("AttachmentCreate", mock.AnythingOfType("*model.Attachment")).Return(errors.New("db down")).Once()
+			blobStoreSpy.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(len(receipt)), "application/pdf").
+				Run(func(args mock.Arguments) { key = args.String(1) }).
+				Return(nil).Once()
+			blobStoreSpy.On("Delete", mock.Anything, mock.AnythingOfType("string")).
+				Run(func(args mock.Arguments) {
+					if args.String(1) != key {
+						t.Errorf("expected blob %s to be deleted, got %s", key, args.String(1))
+					}
+				}).
+				Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, "receipt.pdf", "application/pdf", receipt, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusInternalServerError)
+			blobStoreSpy.AssertExpectations(t)
+		})
+
+		t.Run("Attach a receipt", func(t *testing.T) {
+			var stored []byte
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("AttachmentCreate", mock.AnythingOfType("*model.Attachment")).Return(nil).Once()
+			blobStoreSpy.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, int64(len(receipt)), "application/pdf").
+				Run(func(args mock.Arguments) {
+					stored, _ = io.ReadAll(args.Get(2).(io.Reader))
+					if key := args.String(1); !strings.HasPrefix(key, "transactions/1/") {
+						t.Errorf("expected key below transactions/1/, got %s", key)
+					}
+				}).
+				Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateAttachmentRequest(id, `C:\Users\john\receipt.pdf`, "application/octet-stream", receipt, token)
+
+			r.ServeHTTP(res, req)
+
+			want := &handlers.Attachment{
+				TransactionID: id,
+				UserID:        userID,
+				FileName:      "receipt.pdf",
+				ContentType:   "application/pdf",
+				Size:          int64(len(receipt)),
+				Checksum:      checksum(receipt),
+			}
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, want)
+			AssertEqual(t, stored, receipt)
+		})
+	})
+}
+
+func TestListAttachments(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	id := uint(1)
+	repoSpy.On("TransactionGet", id).Return(&model.Transaction{UserID: userID}, nil).Once()
+	repoSpy.On("AttachmentList", id).Return([]*model.Attachment{newAttachment(3, id, receipt)}, nil).Once()
+
+	res := httptest.NewRecorder()
+	req := NewListAttachmentsRequest(id, token)
+
+	r.ServeHTTP(res, req)
+
+	want := &AttachmentListResponse{
+		Count:   1,
+		Entries: []*handlers.Attachment{handlers.AttachmentModelToResponse(newAttachment(3, id, receipt))},
+	}
+
+	AssertStatusCode(t, res, http.StatusOK)
+	AssertResponseBody(t, res, want)
+}
+
+func TestDownloadAttachment(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	id := uint(1)
+	attachmentID := uint(3)
+	transaction := &model.Transaction{UserID: userID}
+
+	t.Run("Download non-existent attachment", func(t *testing.T) {
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+		repoSpy.On("AttachmentGet", attachmentID).Return(nil, repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDownloadAttachmentRequest(id, attachmentID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	t.Run("Download attachment of another transaction", func(t *testing.T) {
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+		repoSpy.On("AttachmentGet", attachmentID).Return(newAttachment(attachmentID, id+1, receipt), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDownloadAttachmentRequest(id, attachmentID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	t.Run("Download corrupted attachment", func(t *testing.T) {
+		attachment := newAttachment(attachmentID, id, receipt)
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+		repoSpy.On("AttachmentGet", attachmentID).Return(attachment, nil).Once()
+		blobStoreSpy.On("Get", mock.Anything, attachment.StorageKey).
+			Return(io.NopCloser(bytes.NewReader([]byte("%PDF-1.4 tampered"))), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDownloadAttachmentRequest(id, attachmentID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusInternalServerError)
+		AssertErrorMessage(t, res, handlers.ErrorChecksumMismatch.Message)
+	})
+
+	t.Run("Download attachment", func(t *testing.T) {
+		attachment := newAttachment(attachmentID, id, receipt)
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+		repoSpy.On("AttachmentGet", attachmentID).Return(attachment, nil).Once()
+		blobStoreSpy.On("Get", mock.Anything, attachment.StorageKey).
+			Return(io.NopCloser(bytes.NewReader(receipt)), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDownloadAttachmentRequest(id, attachmentID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertEqual(t, res.Body.Bytes(), receipt)
+		AssertEqual(t, res.Header().Get("Content-Type"), "application/pdf")
+		AssertEqual(t, res.Header().Get("Content-Disposition"), `attachment; filename=receipt.pdf`)
+		AssertEqual(t, res.Header().Get("ETag"), `"`+checksum(receipt)+`"`)
+	})
+}
+
+func TestDeleteAttachment(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	id := uint(1)
+	attachment := newAttachment(3, id, receipt)
+	transaction := &model.Transaction{UserID: userID}
+
+	t.Run("Delete with a malformed attachment ID", func(t *testing.T) {
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewRequest(http.MethodDelete, BaseTransactionsPath+"1/attachments/abc", token, nil)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorBadAttachmentID.Message)
+	})
+
+	t.Run("Blob store failure keeps the attachment", func(t *testing.T) {
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+		repoSpy.On("AttachmentGet", attachment.ID).Return(attachment, nil).Once()
+		blobStoreSpy.On("Delete", mock.Anything, attachment.StorageKey).Return(errors.New("unreachable")).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteAttachmentRequest(id, attachment.ID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusInternalServerError)
+	})
+
+	t.Run("Delete attachment", func(t *testing.T) {
+		repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+		repoSpy.On("AttachmentGet", attachment.ID).Return(attachment, nil).Once()
+		repoSpy.On("AttachmentDelete", attachment.ID).Return(nil).Once()
+		blobStoreSpy.On("Delete", mock.Anything, attachment.StorageKey).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteAttachmentRequest(id, attachment.ID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+		repoSpy.AssertExpectations(t)
+	})
+}
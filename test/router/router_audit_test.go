@@ -0,0 +1,463 @@
+package router
+
+import (
+	"expense-api/internal/audit"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestListAudit(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewListAuditRequest("resource=wallet&id=1", token)
+		invalidTokenReq := NewListAuditRequest("resource=wallet&id=1", token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		createdAt := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+		entry := &model.AuditEntry{
+			ActorID:    &userID,
+			RequestID:  "request-1",
+			IP:         "192.0.2.1",
+			Resource:   model.AuditResourceWallet,
+			ResourceID: 2,
+			Action:     model.AuditUpdate,
+			Changes:    model.AuditChanges{"name": {Before: "cash", After: "pocket money"}},
+		}
+		entry.ID = 7
+		entry.CreatedAt = createdAt
+
+		expected := &AuditEntryListResponse{
+			Count: 1,
+			Entries: []*handlers.AuditEntry{{
+				ID:         7,
+				CreatedAt:  createdAt,
+				ActorID:    &userID,
+				RequestID:  "request-1",
+				IP:         "192.0.2.1",
+				Resource:   model.AuditResourceWallet,
+				ResourceID: 2,
+				Action:     model.AuditUpdate,
+				Changes:    map[string]*handlers.AuditChange{"name": {Before: "cash", After: "pocket money"}},
+			}},
+		}
+
+		invalidQueryTestCases := []struct {
+			name    string
+			query   string
+			message string
+		}{
+			{"Missing resource", "id=2", handlers.ErrorAuditResource.Message},
+			{"Unknown resource", "resource=rule&id=2", handlers.ErrorAuditResource.Message},
+			{"Missing id", "resource=wallet", handlers.ErrorAuditID.Message},
+			{"Invalid id", "resource=wallet&id=two", handlers.ErrorAuditID.Message},
+			{"Zero id", "resource=wallet&id=0", handlers.ErrorAuditID.Message},
+		}
+
+		for _, tc := range invalidQueryTestCases {
+			t.Run(tc.name, func(t *testing.T) {
+				res := httptest.NewRecorder()
+				req := NewListAuditRequest(tc.query, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("List the changes of a wallet", func(t *testing.T) {
+			wallet := &model.Wallet{UserID: userID}
+			wallet.ID = 2
+
+			repoSpy.On("WalletGet", wallet.ID).Return(wallet, nil).Once()
+			repoSpy.On("AuditList", model.AuditResourceWallet, wallet.ID).Return([]*model.AuditEntry{entry}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=wallet&id=2", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("List the changes of a party in the trash", func(t *testing.T) {
+			party := &model.Party{UserID: userID, DeletedAt: trashedAt(createdAt)}
+			party.ID = 3
+
+			repoSpy.On("PartyGet", party.ID).Return(nil, repository.ErrorRecordNotFound).Once()
+			repoSpy.On("PartyGetTrashed", party.ID).Return(party, nil).Once()
+			repoSpy.On("AuditList", model.AuditResourceParty, party.ID).Return([]*model.AuditEntry{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=party&id=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &AuditEntryListResponse{Entries: []*handlers.AuditEntry{}})
+		})
+
+		t.Run("List the changes of a transaction that doesn't exist", func(t *testing.T) {
+			id := uint(4)
+
+			repoSpy.On("TransactionGet", id).Return(nil, repository.ErrorRecordNotFound).Once()
+			repoSpy.On("TransactionGetTrashed", id).Return(nil, repository.ErrorRecordNotFound).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=transaction&id=4", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNotFound)
+		})
+
+		t.Run("List the changes of another user's wallet", func(t *testing.T) {
+			wallet := &model.Wallet{UserID: userID + 1}
+			wallet.ID = 5
+
+			repoSpy.On("WalletGet", wallet.ID).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=wallet&id=5", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+		})
+
+		t.Run("List the changes of a household member's wallet", func(t *testing.T) {
+			householdID := uint(9)
+			wallet := &model.Wallet{UserID: userID + 1, HouseholdID: &householdID}
+			wallet.ID = 6
+
+			repoSpy.On("WalletGet", wallet.ID).Return(wallet, nil).Once()
+			repoSpy.On("HouseholdMemberGet", householdID, userID).Return(&model.HouseholdMember{Role: "viewer"}, nil).Once()
+			repoSpy.On("AuditList", model.AuditResourceWallet, wallet.ID).Return([]*model.AuditEntry{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=wallet&id=6", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+		})
+
+		t.Run("List the changes of the own account", func(t *testing.T) {
+			repoSpy.On("AuditList", model.AuditResourceUser, userID).Return([]*model.AuditEntry{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=user&id=1", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+		})
+
+		t.Run("List the changes of another account", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewListAuditRequest("resource=user&id=2", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+		})
+
+		repoSpy.AssertExpectations(t)
+	})
+}
+
+func TestListTransactionHistory(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewListTransactionHistoryRequest(1, token)
+		invalidTokenReq := NewListTransactionHistoryRequest(1, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		t.Run("List the changes of a transaction", func(t *testing.T) {
+			transaction := &model.Transaction{UserID: userID}
+			transaction.ID = 4
+			created := &model.AuditEntry{
+				ActorID:    &userID,
+				Resource:   model.AuditResourceTransaction,
+				ResourceID: transaction.ID,
+				Action:     model.AuditCreate,
+				Changes:    model.AuditChanges{"amount": {Before: nil, After: "10"}},
+			}
+			created.ID = 1
+			updated := &model.AuditEntry{
+				ActorID:    &userID,
+				Resource:   model.AuditResourceTransaction,
+				ResourceID: transaction.ID,
+				Action:     model.AuditUpdate,
+				Changes:    model.AuditChanges{"amount": {Before: "10", After: "12.5"}},
+			}
+			updated.ID = 2
+
+			repoSpy.On("TransactionGet", transaction.ID).Return(transaction, nil).Once()
+			repoSpy.On("AuditList", model.AuditResourceTransaction, transaction.ID).Return([]*model.AuditEntry{created, updated}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListTransactionHistoryRequest(transaction.ID, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &AuditEntryListResponse{
+				Count: 2,
+				Entries: []*handlers.AuditEntry{
+					handlers.AuditEntryModelToResponse(created),
+					handlers.AuditEntryModelToResponse(updated),
+				},
+			})
+		})
+
+		t.Run("List the changes of another user's transaction", func(t *testing.T) {
+			transaction := &model.Transaction{UserID: userID + 1}
+			transaction.ID = 5
+
+			repoSpy.On("TransactionGet", transaction.ID).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListTransactionHistoryRequest(transaction.ID, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+		})
+
+		repoSpy.AssertExpectations(t)
+	})
+}
+
+func TestVerifyAudit(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewVerifyAuditRequest(token)
+		invalidTokenReq := NewVerifyAuditRequest(token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		chain := func() []*model.AuditEntry {
+			var entries []*model.AuditEntry
+			prevHash := ""
+			for i, amount := range []string{"10", "12.5", "15"} {
+				entry := &model.AuditEntry{
+					UserID:     userID,
+					ActorID:    &userID,
+					Resource:   model.AuditResourceTransaction,
+					ResourceID: 4,
+					Action:     model.AuditUpdate,
+					Changes:    model.AuditChanges{"amount": {After: amount}},
+					PrevHash:   prevHash,
+				}
+				entry.ID = uint(i + 1)
+				entry.CreatedAt = time.Date(2021, 3, 4, 10, i, 0, 0, time.UTC)
+				hash, err := entry.ComputeHash()
+				assert.NoError(t, err)
+				entry.Hash = hash
+				prevHash = hash
+				entries = append(entries, entry)
+			}
+			return entries
+		}
+
+		t.Run("Verify an intact chain", func(t *testing.T) {
+			repoSpy.On("AuditListChain", userID, uint(0), audit.BatchSize).Return(chain(), nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewVerifyAuditRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.AuditVerification{UserID: userID, Checked: 3, Valid: true})
+		})
+
+		t.Run("Verify a chain with an edited entry", func(t *testing.T) {
+			entries := chain()
+			entries[1].Changes["amount"].After = "1000"
+			repoSpy.On("AuditListChain", userID, uint(0), audit.BatchSize).Return(entries, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewVerifyAuditRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.AuditVerification{
+				UserID:     userID,
+				Checked:    1,
+				BrokenLink: &handlers.AuditBrokenLink{EntryID: 2, Reason: audit.ReasonHashMismatch},
+			})
+		})
+
+		t.Run("Verify a chain with a removed entry", func(t *testing.T) {
+			entries := chain()
+			entries = append(entries[:1], entries[2:]...)
+			repoSpy.On("AuditListChain", userID, uint(0), audit.BatchSize).Return(entries, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewVerifyAuditRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.AuditVerification{
+				UserID:     userID,
+				Checked:    1,
+				BrokenLink: &handlers.AuditBrokenLink{EntryID: 3, Reason: audit.ReasonPrevMismatch},
+			})
+		})
+
+		repoSpy.AssertExpectations(t)
+	})
+}
+
+func TestAuditActor(t *testing.T) {
+	repoSpy := &spies.RepositorySpy{}
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	t.Run("The repository serves the request as its user", func(t *testing.T) {
+		repoSpy.On("WithActor", &repository.Actor{UserID: userID, RequestID: "request-1", IP: "192.0.2.1"}).Return(repoSpy).Once()
+		repoSpy.On("UserGet", userID).Return(&model.User{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetAccountRequest(token)
+		req.Header.Set(middleware.HeaderRequestID, "request-1")
+		req.RemoteAddr = "192.0.2.1:4321"
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertHeader(t, res, middleware.HeaderRequestID, "request-1")
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Clients can't name their own address", func(t *testing.T) {
+		repoSpy.On("WithActor", &repository.Actor{UserID: userID, RequestID: "request-2", IP: "192.0.2.1"}).Return(repoSpy).Once()
+		repoSpy.On("UserGet", userID).Return(&model.User{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetAccountRequest(token)
+		req.Header.Set(middleware.HeaderRequestID, "request-2")
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		req.Header.Set("X-Real-IP", "203.0.113.9")
+		req.RemoteAddr = "192.0.2.1:4321"
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Trusted proxies name the client", func(t *testing.T) {
+		config := *router.TestConfig
+		config.TrustedProxies = []string{"10.0.0.0/8"}
+		r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, &config)
+
+		repoSpy.On("WithActor", &repository.Actor{UserID: userID, RequestID: "request-3", IP: "203.0.113.9"}).Return(repoSpy).Once()
+		repoSpy.On("UserGet", userID).Return(&model.User{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetAccountRequest(token)
+		req.Header.Set(middleware.HeaderRequestID, "request-3")
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		req.RemoteAddr = "10.1.2.3:4321"
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Requests without an ID get a random one", func(t *testing.T) {
+		repoSpy.On("WithActor", mock.Anything).Return(repoSpy).Once()
+		repoSpy.On("UserGet", userID).Return(&model.User{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetAccountRequest(token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		assert.Len(t, res.Header().Get(middleware.HeaderRequestID), 32)
+		repoSpy.AssertExpectations(t)
+	})
+}
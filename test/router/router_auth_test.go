@@ -14,11 +14,12 @@ import (
 )
 
 func TestSignUp(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Shouldn't sign up with missing 'first_name'", func(t *testing.T) {
 		res := httptest.NewRecorder()
@@ -140,11 +141,12 @@ func TestSignUp(t *testing.T) {
 }
 
 func TestLogin(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Invalid request body", func(t *testing.T) {
 		testCases := []struct {
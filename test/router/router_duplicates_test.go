@@ -0,0 +1,282 @@
+package router
+
+import (
+	"expense-api/internal/duplicates"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCreateDuplicateTransaction(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	walletID, partyID := uint(1), uint(2)
+	timestamp := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+	transaction := &model.Transaction{
+		Timestamp:   timestamp,
+		Amount:      decimal.RequireFromString("-23.4"),
+		Description: "Lidl",
+		Category:    "groceries",
+		Status:      model.TransactionUncleared,
+		UserID:      userID,
+		WalletID:    walletID,
+		PartyID:     partyID,
+	}
+	candidates := []*model.Transaction{
+		{Model: model.Model{ID: 7}, WalletID: walletID, Amount: transaction.Amount, Timestamp: timestamp.Add(26 * time.Hour), Description: "POS 1234 LIDL SAGT 56"},
+		{Model: model.Model{ID: 8}, WalletID: walletID, Amount: transaction.Amount, Timestamp: timestamp, Description: "Rewe"},
+	}
+	request := &handlers.Transaction{
+		Timestamp:   timestamp,
+		Amount:      transaction.Amount,
+		Description: transaction.Description,
+		Category:    transaction.Category,
+		WalletID:    walletID,
+		PartyID:     partyID,
+	}
+	warning := &handlers.DuplicateWarning{
+		Message:        handlers.ErrorDuplicateTransaction.Message,
+		TransactionIDs: []uint{7},
+	}
+
+	validate := func() {
+		repoSpy.On("WalletGet", walletID).Return(&model.Wallet{UserID: userID}, nil).Once()
+		repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+		repoSpy.On("PartyGet", partyID).Return(&model.Party{UserID: userID}, nil).Once()
+		repoSpy.On("TransactionListDuplicateCandidates", transaction, duplicates.Window).Return(candidates, nil).Once()
+	}
+
+	t.Run("Strict mode refuses duplicates", func(t *testing.T) {
+		validate()
+
+		res := httptest.NewRecorder()
+		req := NewCreateTransactionStrictRequest(request, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertResponseBody(t, res, warning)
+	})
+
+	t.Run("Duplicates are created with a warning", func(t *testing.T) {
+		validate()
+		repoSpy.On("TransactionCreate", transaction).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateTransactionRequest(request, token)
+
+		r.ServeHTTP(res, req)
+
+		want := handlers.TransactionModelToResponse(transaction)
+		want.Warning = warning
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		AssertResponseBody(t, res, want)
+		repoSpy.AssertExpectations(t)
+	})
+}
+
+func TestListDuplicateTransactions(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewListDuplicateTransactionsRequest(token)
+		invalidTokenReq := NewListDuplicateTransactionsRequest(token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		timestamp := time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC)
+		transactions := []*model.Transaction{
+			{Model: model.Model{ID: 3}, WalletID: 1, Amount: decimal.RequireFromString("-23.40"), Timestamp: timestamp.Add(time.Hour), Description: "POS 1234 LIDL SAGT 56"},
+			{Model: model.Model{ID: 4}, WalletID: 1, Amount: decimal.RequireFromString("-9.99"), Timestamp: timestamp, Description: "Netflix"},
+			{Model: model.Model{ID: 5}, WalletID: 1, Amount: decimal.RequireFromString("-23.40"), Timestamp: timestamp, Description: "Lidl"},
+		}
+
+		repoSpy.On("TransactionList", userID).Return(transactions, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewListDuplicateTransactionsRequest(token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &DuplicatePairListResponse{
+			Count: 1,
+			Entries: []*handlers.DuplicatePair{{
+				Transaction: handlers.TransactionModelToResponse(transactions[2]),
+				Duplicate:   handlers.TransactionModelToResponse(transactions[0]),
+				Similarity:  1,
+			}},
+		})
+	})
+}
+
+func TestMergeTransactions(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewMergeTransactionsRequest(1, &handlers.TransactionMerge{DuplicateID: 2}, token)
+		invalidTokenReq := NewMergeTransactionsRequest(1, &handlers.TransactionMerge{DuplicateID: 2}, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		survivorID, duplicateID := uint(1), uint(2)
+		survivor := &model.Transaction{Model: model.Model{ID: survivorID}, UserID: userID, Tags: model.Tags{"food"}}
+
+		t.Run("Merge without a duplicate", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorRequiredDuplicateID.Message)
+		})
+
+		t.Run("Merge into a transaction with an outdated If-Match", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: duplicateID}, token)
+			req.Header.Set("If-Match", `"1"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
+		})
+
+		t.Run("Merge a transaction with itself", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: survivorID}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorMergeWithItself.Message)
+		})
+
+		t.Run("Merge a non-existent duplicate", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+			repoSpy.On("TransactionGet", duplicateID).Return(nil, repository.ErrorRecordNotFound).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: duplicateID}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorDuplicateNotFound.Message)
+		})
+
+		t.Run("Merge another user's transaction", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+			repoSpy.On("TransactionGet", duplicateID).Return(&model.Transaction{UserID: userID + 1}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: duplicateID}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+			AssertErrorMessage(t, res, handlers.ErrorBadDuplicateID.Message)
+		})
+
+		t.Run("Merge a duplicate in another wallet", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+			repoSpy.On("TransactionGet", duplicateID).Return(&model.Transaction{Model: model.Model{ID: duplicateID}, UserID: userID, WalletID: 3}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: duplicateID}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorDuplicateWallet.Message)
+			repoSpy.AssertNotCalled(t, "TransactionMerge", survivorID, duplicateID)
+		})
+
+		t.Run("Merge a duplicate with splits or shares", func(t *testing.T) {
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+			repoSpy.On("TransactionGet", duplicateID).Return(&model.Transaction{Model: model.Model{ID: duplicateID}, UserID: userID}, nil).Once()
+			repoSpy.On("TransactionMerge", survivorID, duplicateID).Return(nil, repository.ErrorHasSplitsOrShares).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: duplicateID}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorDuplicateSplit.Message)
+		})
+
+		t.Run("Merge duplicate", func(t *testing.T) {
+			duplicate := &model.Transaction{Model: model.Model{ID: duplicateID}, UserID: userID, Tags: model.Tags{"lidl"}}
+			merged := &model.Transaction{Model: model.Model{ID: survivorID, Version: 1}, UserID: userID, Tags: model.Tags{"food", "lidl"}}
+
+			repoSpy.On("TransactionGet", survivorID).Return(survivor, nil).Once()
+			repoSpy.On("TransactionGet", duplicateID).Return(duplicate, nil).Once()
+			repoSpy.On("TransactionMerge", survivorID, duplicateID).Return(merged, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergeTransactionsRequest(survivorID, &handlers.TransactionMerge{DuplicateID: duplicateID}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertEqual(t, res.Header().Get("ETag"), `"1"`)
+			AssertResponseBody(t, res, handlers.TransactionModelToResponse(merged))
+			repoSpy.AssertExpectations(t)
+		})
+	})
+}
@@ -0,0 +1,202 @@
+package router
+
+import (
+	"context"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// streamRecorder ends the stream once the body contains until
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+	until string
+	stop  context.CancelFunc
+}
+
+func (r *streamRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	if strings.Contains(r.Body.String(), r.until) {
+		r.stop()
+	}
+}
+
+// serveStream serves the stream request until the body contains until, or a few seconds passed
+func serveStream(r http.Handler, req *http.Request, until string) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithTimeout(req.Context(), 5*time.Second)
+	defer cancel()
+
+	res := &streamRecorder{httptest.NewRecorder(), until, cancel}
+	r.ServeHTTP(res, req.WithContext(ctx))
+	return res.ResponseRecorder
+}
+
+// notifying makes the database notify the hub about the events with the IDs, and then wait until
+// the hub stops listening
+func notifying(repoSpy *spies.RepositorySpy, ids ...uint) {
+	repoSpy.On("OutboxListen", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		notify := args.Get(1).(func(uint))
+		for _, id := range ids {
+			notify(id)
+		}
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil).Once()
+}
+
+func streamEvent(id uint, eventType string, userID uint, householdID *uint) *model.OutboxEvent {
+	e := &model.OutboxEvent{
+		EventID:     "event-" + eventType,
+		Type:        eventType,
+		Aggregate:   strings.Split(eventType, ".")[0],
+		AggregateID: 3,
+		UserID:      userID,
+		HouseholdID: householdID,
+		Object:      `{"id":3}`,
+	}
+	e.ID = id
+	return e
+}
+
+func TestStreamEvents(t *testing.T) {
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		r := router.Setup(NewRepositorySpy(), jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+		token := "invalid-token"
+
+		missingTokenReq := NewStreamEventsRequest("", token)
+		invalidTokenReq := NewStreamEventsRequest("", token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		household, otherHousehold := uint(4), uint(9)
+		members := []*model.HouseholdMember{{HouseholdID: household, UserID: userID}}
+
+		t.Run("Stream the events of the user and their households", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("HouseholdListByUser", userID).Return(members, nil).Once()
+			notifying(repoSpy, 7, 8, 9)
+			repoSpy.On("OutboxGet", uint(7)).Return(streamEvent(7, model.EventTransactionCreated, userID, nil), nil).Once()
+			repoSpy.On("OutboxGet", uint(8)).Return(streamEvent(8, model.EventWalletUpdated, 2, &otherHousehold), nil).Once()
+			repoSpy.On("OutboxGet", uint(9)).Return(streamEvent(9, model.EventPartyDeleted, 2, &household), nil).Once()
+
+			res := serveStream(r, NewStreamEventsRequest("", token), "id: 9\n")
+
+			AssertStatusCode(t, res, http.StatusOK)
+			if contentType := res.Header().Get("Content-Type"); contentType != "text/event-stream" {
+				t.Errorf("expected an event stream, got %s", contentType)
+			}
+			expected := "id: 7\nevent: transaction.created\n" +
+				`data: {"id":"event-transaction.created","event":"transaction.created","resource":"transaction","resource_id":3,` +
+				`"household_id":null,"actor_id":null,"created_at":"0001-01-01T00:00:00Z","object":{"id":3},"changes":null}` + "\n\n" +
+				"id: 9\nevent: party.deleted\n" +
+				`data: {"id":"event-party.deleted","event":"party.deleted","resource":"party","resource_id":3,` +
+				`"household_id":4,"actor_id":null,"created_at":"0001-01-01T00:00:00Z","object":{"id":3},"changes":null}` + "\n\n"
+			if body := res.Body.String(); body != expected {
+				t.Errorf("expected the events of the user and their household:\n%s\ngot:\n%s", expected, body)
+			}
+		})
+
+		t.Run("Resume after the last event", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("HouseholdListByUser", userID).Return(members, nil).Once()
+			repoSpy.On("OutboxList", userID, []uint{household}, uint(5), mock.Anything).Return([]*model.OutboxEvent{
+				streamEvent(6, model.EventWalletCreated, userID, nil),
+			}, nil).Once()
+			// Nothing was published while the stream caught up
+			repoSpy.On("OutboxList", userID, []uint{household}, uint(6), mock.Anything).Return([]*model.OutboxEvent{}, nil).Once()
+			// The missed event is also notified after the stream subscribed
+			notifying(repoSpy, 6, 10)
+			repoSpy.On("OutboxGet", uint(6)).Return(streamEvent(6, model.EventWalletCreated, userID, nil), nil).Maybe()
+			repoSpy.On("OutboxGet", uint(10)).Return(streamEvent(10, model.EventWalletUpdated, userID, nil), nil).Once()
+
+			res := serveStream(r, NewStreamEventsRequest("5", token), "id: 10\n")
+
+			AssertStatusCode(t, res, http.StatusOK)
+			if body := res.Body.String(); strings.Count(body, "id: 6\n") != 1 || strings.Index(body, "id: 6\n") > strings.Index(body, "id: 10\n") {
+				t.Errorf("expected the missed event once before the new one, got:\n%s", body)
+			}
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Reset a stream that missed too many events", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			batch := func(afterID uint) []*model.OutboxEvent {
+				events := make([]*model.OutboxEvent, 100)
+				for i := range events {
+					events[i] = streamEvent(afterID+uint(i)+1, model.EventTransactionCreated, userID, nil)
+				}
+				return events
+			}
+
+			repoSpy.On("HouseholdListByUser", userID).Return(members, nil).Once()
+			for afterID := uint(5); afterID < 1005; afterID += 100 {
+				repoSpy.On("OutboxList", userID, []uint{household}, afterID, mock.Anything).Return(batch(afterID), nil).Once()
+			}
+			repoSpy.On("OutboxLatestID").Return(uint(5000), nil).Once()
+			notifying(repoSpy, 5001)
+			repoSpy.On("OutboxList", userID, []uint{household}, uint(5000), mock.Anything).Return([]*model.OutboxEvent{}, nil).Once()
+			repoSpy.On("OutboxGet", uint(5001)).Return(streamEvent(5001, model.EventWalletUpdated, userID, nil), nil).Once()
+
+			res := serveStream(r, NewStreamEventsRequest("5", token), "id: 5001\n")
+
+			AssertStatusCode(t, res, http.StatusOK)
+			body := res.Body.String()
+			if strings.Count(body, "event: transaction.created\n") != 1000 || strings.Contains(body, "id: 1006\n") {
+				t.Errorf("expected the first 1000 missed events only")
+			}
+			if reset := strings.Index(body, "id: 5000\nevent: reset\ndata: {}\n\n"); reset < 0 || reset < strings.Index(body, "id: 1005\n") || reset > strings.Index(body, "id: 5001\n") {
+				t.Errorf("expected a reset after the replayed events and before the new one, got:\n%s", body[len(body)-300:])
+			}
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Invalid Last-Event-ID", func(t *testing.T) {
+			r := router.Setup(NewRepositorySpy(), jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, NewStreamEventsRequest("event-5", token))
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorBadLastEventID.Message)
+		})
+
+		t.Run("Missed events can't be read", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("HouseholdListByUser", userID).Return(members, nil).Once()
+			repoSpy.On("OutboxList", userID, []uint{household}, uint(5), mock.Anything).Return(nil, repository.ErrorOther).Once()
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, NewStreamEventsRequest("5", token))
+
+			AssertStatusCode(t, res, http.StatusInternalServerError)
+		})
+	})
+}
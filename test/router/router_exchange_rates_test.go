@@ -0,0 +1,233 @@
+package router
+
+import (
+	"expense-api/internal/currency"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateExchangeRate(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		rate := &handlers.ExchangeRate{}
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateExchangeRateRequest(rate, token)
+		invalidTokenReq := NewCreateExchangeRateRequest(rate, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Non-admin user", func(t *testing.T) {
+		token := "user-token"
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: 2}, nil)
+		repoSpy.On("UserGet", uint(2)).Return(&model.User{Email: "jane@doe.com"}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateExchangeRateRequest(&handlers.ExchangeRate{
+			Date:  "2021-11-19",
+			Base:  "EUR",
+			Quote: "USD",
+			Rate:  decimal.RequireFromString("1.137"),
+		}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		claims := auth.CustomClaims{
+			ID: 1,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+		repoSpy.On("UserGet", uint(1)).Return(&model.User{Email: "admin@doe.com", Admin: true}, nil)
+
+		t.Run("Create exchange rate with invalid date", func(t *testing.T) {
+			rate := &handlers.ExchangeRate{
+				Date:  "19.11.2021",
+				Base:  "EUR",
+				Quote: "USD",
+				Rate:  decimal.RequireFromString("1.137"),
+			}
+
+			res := httptest.NewRecorder()
+			req := NewCreateExchangeRateRequest(rate, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidDate.Message)
+		})
+
+		t.Run("Create exchange rate with unknown currency", func(t *testing.T) {
+			rate := &handlers.ExchangeRate{
+				Date:  "2021-11-19",
+				Base:  "EUR",
+				Quote: "XYZ",
+				Rate:  decimal.RequireFromString("1.137"),
+			}
+
+			res := httptest.NewRecorder()
+			req := NewCreateExchangeRateRequest(rate, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidCurrency.Message)
+		})
+
+		t.Run("Create exchange rate with a negative rate", func(t *testing.T) {
+			rate := &handlers.ExchangeRate{
+				Date:  "2021-11-19",
+				Base:  "EUR",
+				Quote: "USD",
+				Rate:  decimal.RequireFromString("-1.137"),
+			}
+
+			res := httptest.NewRecorder()
+			req := NewCreateExchangeRateRequest(rate, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidRate.Message)
+		})
+
+		t.Run("Create exchange rate with valid data", func(t *testing.T) {
+			rateModel := &model.ExchangeRate{
+				Date:   time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC),
+				Base:   "EUR",
+				Quote:  "USD",
+				Rate:   decimal.RequireFromString("1.137"),
+				Source: currency.SourceManual,
+			}
+
+			repoSpy.On("ExchangeRateUpsert", []*model.ExchangeRate{rateModel}).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateExchangeRateRequest(&handlers.ExchangeRate{
+				Date:  "2021-11-19",
+				Base:  "eur",
+				Quote: "usd",
+				Rate:  rateModel.Rate,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, handlers.ExchangeRateModelToResponse(rateModel))
+		})
+	})
+}
+
+func TestListExchangeRates(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	claims := auth.CustomClaims{
+		ID: 1,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("List exchange rates filtered by currency pair", func(t *testing.T) {
+		rates := []*model.ExchangeRate{
+			{
+				Date:  time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC),
+				Base:  "EUR",
+				Quote: "USD",
+				Rate:  decimal.RequireFromString("1.137"),
+			},
+		}
+
+		repoSpy.On("ExchangeRateList", "EUR", "USD").Return(rates, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewListExchangeRatesRequest("?base=eur&quote=usd", token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &ExchangeRateListResponse{
+			Count:   1,
+			Entries: []*handlers.ExchangeRate{handlers.ExchangeRateModelToResponse(rates[0])},
+		}
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, expected)
+	})
+}
+
+func TestImportExchangeRates(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	claims := auth.CustomClaims{
+		ID: 1,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+	repoSpy.On("UserGet", uint(1)).Return(&model.User{Email: "admin@doe.com", Admin: true}, nil)
+
+	t.Run("Import file with unknown format", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewImportExchangeRatesRequest("", "application/octet-stream", "", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorUnknownRateFormat.Message)
+	})
+
+	t.Run("Import malformed CSV file", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewImportExchangeRatesRequest("csv", "text/csv", "not,an\necb,file", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorMalformedRateFile.Message)
+	})
+
+	t.Run("Import ECB CSV file", func(t *testing.T) {
+		body := "Date,USD,GBP,\n2021-11-19,1.1370,0.84108,\n"
+
+		repoSpy.On("ExchangeRateUpsert", mock.MatchedBy(func(rates []*model.ExchangeRate) bool {
+			return len(rates) == 2 && rates[0].Quote == "USD" && rates[1].Quote == "GBP"
+		})).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewImportExchangeRatesRequest("", "text/csv", body, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		AssertResponseBody(t, res, &handlers.ExchangeRateImport{Imported: 2})
+	})
+}
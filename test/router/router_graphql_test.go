@@ -0,0 +1,292 @@
+package router
+
+import (
+	"encoding/json"
+	"expense-api/internal/gql"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func serveGraphQL(t *testing.T, r http.Handler, query string, variables map[string]interface{}, token string) *graphQLResponse {
+	t.Helper()
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, NewGraphQLRequest(query, variables, token))
+	AssertStatusCode(t, res, http.StatusOK)
+
+	var body graphQLResponse
+	if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected a GraphQL response, got %s", res.Body.String())
+	}
+	return &body
+}
+
+// assertData compares the data of the response to the expected JSON
+func assertData(t *testing.T, res *graphQLResponse, expected string) {
+	t.Helper()
+	if len(res.Errors) > 0 {
+		t.Fatalf("expected no errors, got %+v", res.Errors)
+	}
+
+	var got, want interface{}
+	_ = json.Unmarshal(res.Data, &got)
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("invalid expected data: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected data (-expected +got):\n%s", diff)
+	}
+}
+
+// assertGraphQLError checks that the first error of the response has the message and code
+func assertGraphQLError(t *testing.T, res *graphQLResponse, message, code string) {
+	t.Helper()
+	if len(res.Errors) == 0 {
+		t.Fatalf("expected an error, got data %s", res.Data)
+	}
+	if got := res.Errors[0]; got.Message != message || got.Extensions["code"] != code {
+		t.Errorf("expected error %q with code %s, got %q with %v", message, code, got.Message, got.Extensions)
+	}
+}
+
+func graphQLTransaction(id, walletID, partyID, userID uint, amount string) *model.Transaction {
+	t := &model.Transaction{WalletID: walletID, PartyID: partyID, UserID: userID, Amount: decimal.RequireFromString(amount)}
+	t.ID = id
+	return t
+}
+
+func TestGraphQL(t *testing.T) {
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		r := router.Setup(NewRepositorySpy(), jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+		token := "invalid-token"
+
+		missingTokenReq := NewGraphQLRequest("{ me { id } }", nil, token)
+		invalidTokenReq := NewGraphQLRequest("{ me { id } }", nil, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		user := &model.User{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"}
+		user.ID = userID
+		cash := &model.Wallet{Name: "Cash", UserID: userID}
+		cash.ID = 1
+		bank := &model.Wallet{Name: "Bank", UserID: userID}
+		bank.ID = 2
+		shop := &model.Party{Name: "Shop", UserID: userID}
+		shop.ID = 5
+
+		t.Run("Missing query", func(t *testing.T) {
+			r := router.Setup(NewRepositorySpy(), jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, NewRequest(http.MethodPost, GraphQLPath, token, map[string]string{"operationName": "q"}))
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+		})
+
+		t.Run("Resolve the records of transactions in one query per type", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("TransactionListFiltered", userID, mock.Anything).Return([]*model.Transaction{
+				graphQLTransaction(10, 1, 5, userID, "-4.5"),
+				graphQLTransaction(11, 2, 5, userID, "100"),
+				graphQLTransaction(12, 1, 5, userID, "-12"),
+			}, nil).Once()
+			repoSpy.On("HouseholdListByUser", userID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("WalletListByIDs", []uint{1, 2}).Return([]*model.Wallet{cash, bank}, nil).Once()
+			repoSpy.On("PartyListByIDs", []uint{5}).Return([]*model.Party{shop}, nil).Once()
+			repoSpy.On("UserListByIDs", []uint{userID}).Return([]*model.User{user}, nil).Once()
+
+			res := serveGraphQL(t, r, `{
+				transactions {
+					totalCount
+					nodes { id amount wallet { name user { firstName } } party { name } }
+				}
+			}`, nil, token)
+
+			assertData(t, res, `{"transactions": {"totalCount": 3, "nodes": [
+				{"id": 10, "amount": "-4.5", "wallet": {"name": "Cash", "user": {"firstName": "Ada"}}, "party": {"name": "Shop"}},
+				{"id": 11, "amount": "100", "wallet": {"name": "Bank", "user": {"firstName": "Ada"}}, "party": {"name": "Shop"}},
+				{"id": 12, "amount": "-12", "wallet": {"name": "Cash", "user": {"firstName": "Ada"}}, "party": {"name": "Shop"}}
+			]}}`)
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Resolve the transactions of all wallets in one query", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("WalletList", userID).Return([]*model.Wallet{cash, bank}, nil).Once()
+			repoSpy.On("TransactionListFiltered", userID, mock.MatchedBy(func(f *repository.TransactionFilter) bool {
+				return cmp.Equal(f.WalletIDs, []uint{1, 2}) && f.Status == model.TransactionCleared
+			})).Return([]*model.Transaction{
+				graphQLTransaction(10, 1, 5, userID, "-4.5"),
+				graphQLTransaction(12, 1, 5, userID, "-12"),
+			}, nil).Once()
+
+			res := serveGraphQL(t, r, `{
+				wallets {
+					nodes { name transactions(filter: {status: "cleared"}, first: 1) { totalCount nodes { id } } }
+				}
+			}`, nil, token)
+
+			assertData(t, res, `{"wallets": {"nodes": [
+				{"name": "Cash", "transactions": {"totalCount": 2, "nodes": [{"id": 10}]}},
+				{"name": "Bank", "transactions": {"totalCount": 0, "nodes": []}}
+			]}}`)
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Filter and paginate transactions", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("TransactionListFiltered", userID, mock.MatchedBy(func(f *repository.TransactionFilter) bool {
+				return cmp.Equal(f.WalletIDs, []uint{1}) && f.Tag == "food" && f.MinAmount != nil && f.MinAmount.Equal(decimal.NewFromInt(-20))
+			})).Return([]*model.Transaction{
+				graphQLTransaction(10, 1, 5, userID, "-4.5"),
+				graphQLTransaction(12, 1, 5, userID, "-12"),
+				graphQLTransaction(13, 1, 5, userID, "-1"),
+			}, nil).Twice()
+
+			query := `query Page($after: String) {
+				transactions(filter: {walletIds: [1], tag: "food", minAmount: "-20"}, first: 2, after: $after) {
+					totalCount
+					nodes { id }
+					pageInfo { endCursor hasNextPage }
+				}
+			}`
+
+			first := serveGraphQL(t, r, query, nil, token)
+			var page struct {
+				Transactions struct {
+					Nodes    []struct{ ID uint }
+					PageInfo struct {
+						EndCursor   string
+						HasNextPage bool
+					}
+				}
+			}
+			_ = json.Unmarshal(first.Data, &page)
+			if len(page.Transactions.Nodes) != 2 || !page.Transactions.PageInfo.HasNextPage {
+				t.Fatalf("expected the first two transactions and a next page, got %s", first.Data)
+			}
+
+			second := serveGraphQL(t, r, query, map[string]interface{}{"after": page.Transactions.PageInfo.EndCursor}, token)
+			if !strings.Contains(string(second.Data), `"nodes":[{"id":13}]`) || !strings.Contains(string(second.Data), `"hasNextPage":false`) {
+				t.Errorf("expected the last transaction, got %s", second.Data)
+			}
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Records of others are forbidden", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			other := &model.Wallet{Name: "Other", UserID: 2}
+			other.ID = 3
+			repoSpy.On("WalletGet", uint(3)).Return(other, nil).Once()
+			repoSpy.On("HouseholdListByUser", userID).Return([]*model.HouseholdMember{}, nil).Once()
+
+			res := serveGraphQL(t, r, `{ wallet(id: 3) { name } }`, nil, token)
+
+			assertGraphQLError(t, res, "forbidden", "FORBIDDEN")
+			if string(res.Data) != `{"wallet":null}` {
+				t.Errorf("expected no wallet, got %s", res.Data)
+			}
+		})
+
+		t.Run("Reject queries nested too deep", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			query := "{ transaction(id: 1) " + strings.Repeat("{ wallet { transactions { nodes ", 3) + "{ id }" + strings.Repeat(" } } }", 3) + " }"
+			res := serveGraphQL(t, r, query, nil, token)
+
+			assertGraphQLError(t, res, gql.ErrorQueryTooDeep.Message, "QUERY_TOO_DEEP")
+			repoSpy.AssertNotCalled(t, "TransactionGet", mock.Anything)
+		})
+
+		t.Run("Reject queries asking for too many fields", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			res := serveGraphQL(t, r, `query Big($n: Int = 100) {
+				transactions(first: $n) { nodes { wallet { transactions(first: 100) { nodes { id amount } } } } }
+			}`, nil, token)
+
+			assertGraphQLError(t, res, gql.ErrorQueryTooComplex.Message, "QUERY_TOO_COMPLEX")
+			repoSpy.AssertNotCalled(t, "TransactionListFiltered", mock.Anything, mock.Anything)
+		})
+
+		t.Run("Mutations are validated like the REST API", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			res := serveGraphQL(t, r, `mutation { createWallet(input: {description: "no name"}) { id } }`, nil, token)
+
+			assertGraphQLError(t, res, handlers.ErrorWalletName.Message, "BAD_REQUEST")
+			repoSpy.AssertNotCalled(t, "WalletCreate", mock.Anything)
+		})
+
+		t.Run("Create a party", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			repoSpy.On("PartyGetByAlias", userID, "Shop").Return(nil, repository.ErrorRecordNotFound).Once()
+			repoSpy.On("PartyCreate", mock.MatchedBy(func(p *model.Party) bool { return p.Name == "Shop" && p.UserID == userID })).
+				Run(func(args mock.Arguments) { args.Get(0).(*model.Party).ID = shop.ID }).Return(nil).Once()
+			repoSpy.On("PartyGet", shop.ID).Return(shop, nil).Once()
+			repoSpy.On("UserListByIDs", []uint{userID}).Return([]*model.User{user}, nil).Once()
+
+			res := serveGraphQL(t, r, `mutation { createParty(input: {name: "Shop"}) { id name user { email } } }`, nil, token)
+
+			assertData(t, res, `{"createParty": {"id": 5, "name": "Shop", "user": {"email": "ada@example.com"}}}`)
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Update with an outdated version", func(t *testing.T) {
+			repoSpy := NewRepositorySpy()
+			r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+			current := *shop
+			current.Version = 2
+			repoSpy.On("PartyGet", shop.ID).Return(&current, nil).Once()
+
+			res := serveGraphQL(t, r, `mutation { updateParty(id: 5, input: {name: "Store"}, version: 1) { id } }`, nil, token)
+
+			assertGraphQLError(t, res, handlers.ErrorPreconditionFailed.Message, "PRECONDITION_FAILED")
+			repoSpy.AssertNotCalled(t, "PartyUpdate", mock.Anything, mock.Anything)
+		})
+	})
+}
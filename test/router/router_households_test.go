@@ -0,0 +1,715 @@
+package router
+
+import (
+	"expense-api/internal/duplicates"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/permissions"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func newMember(householdID, userID uint, role string) *model.HouseholdMember {
+	return &model.HouseholdMember{HouseholdID: householdID, UserID: userID, Role: role}
+}
+
+func TestCreateHousehold(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		household := &handlers.Household{}
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateHouseholdRequest(household, token)
+		invalidTokenReq := NewCreateHouseholdRequest(household, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		t.Run("Create household without a name", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewCreateHouseholdRequest(&handlers.Household{}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorHouseholdName.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Create household with valid arguments", func(t *testing.T) {
+			household := &model.Household{Name: "Flat"}
+
+			repoSpy.On("HouseholdCreate", household, userID, permissions.RoleOwner).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateHouseholdRequest(&handlers.Household{Name: household.Name}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, &handlers.Household{Name: household.Name, Role: permissions.RoleOwner})
+		})
+	})
+}
+
+func TestGetHousehold(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Get household the user isn't a member of", func(t *testing.T) {
+		id := uint(3)
+
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(nil, repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetHouseholdRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	t.Run("Get household as a viewer", func(t *testing.T) {
+		id := uint(3)
+
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleViewer), nil).Once()
+		repoSpy.On("HouseholdGet", id).Return(&model.Household{Name: "Flat"}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetHouseholdRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.Household{Name: "Flat", Role: permissions.RoleViewer})
+	})
+}
+
+func TestUpdateHousehold(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	id := uint(3)
+
+	t.Run("Rename a household as an editor", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleEditor), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdRequest(id, Patch{"name": "Home"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Remove the name of a household", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdGet", id).Return(&model.Household{Name: "Flat"}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdRequest(id, Patch{"name": nil}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorHouseholdName.Message)
+	})
+
+	t.Run("Rename a household as an owner", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdGet", id).Return(&model.Household{Name: "Flat"}, nil).Once()
+		repoSpy.On("HouseholdUpdate", id, &model.Household{Name: "Home"}).Return(&model.Household{Name: "Home"}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdRequest(id, Patch{"name": "Home", "role": permissions.RoleViewer}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.Household{Name: "Home", Role: permissions.RoleOwner})
+	})
+}
+
+func TestDeleteHousehold(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	id := uint(3)
+
+	t.Run("Delete a household as an editor", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleEditor), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteHouseholdRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+		repoSpy.AssertNotCalled(t, "HouseholdDelete", id)
+	})
+
+	t.Run("Delete a household with other members", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdDelete", id).Return(repository.ErrorHouseholdHasMembers).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteHouseholdRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, handlers.ErrorHouseholdMembers.Message)
+	})
+
+	t.Run("Delete a household whose parties have the names of the member's own", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdDelete", id).Return(repository.ErrorUniqueConstaintViolation).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteHouseholdRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, handlers.ErrorHouseholdNameTaken.Message)
+	})
+
+	t.Run("Delete a household as its only member", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdDelete", id).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteHouseholdRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+}
+
+func TestHouseholdMembers(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	id := uint(3)
+
+	t.Run("Change a role as an editor", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleEditor), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdMemberRequest(id, 2, Patch{"role": permissions.RoleViewer}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Demote the last owner", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Twice()
+		repoSpy.On("HouseholdMemberList", id).Return([]*model.HouseholdMember{
+			newMember(id, userID, permissions.RoleOwner),
+			newMember(id, 2, permissions.RoleEditor),
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdMemberRequest(id, userID, Patch{"role": permissions.RoleEditor}, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorLastOwner.Message
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+
+	t.Run("Change the role of a user who isn't a member", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdMemberGet", id, uint(4)).Return(nil, repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdMemberRequest(id, 4, Patch{"role": permissions.RoleViewer}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	for _, patch := range []Patch{{"role": "admin"}, {"role": nil}} {
+		t.Run("Change a role with an invalid role", func(t *testing.T) {
+			repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+			repoSpy.On("HouseholdMemberGet", id, uint(2)).Return(newMember(id, 2, permissions.RoleEditor), nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateHouseholdMemberRequest(id, 2, patch, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorInvalidRole.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+	}
+
+	t.Run("Promote a member to owner", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdMemberGet", id, uint(2)).Return(newMember(id, 2, permissions.RoleEditor), nil).Once()
+		repoSpy.On("HouseholdMemberUpdate", id, uint(2), permissions.RoleOwner).Return(newMember(id, 2, permissions.RoleOwner), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateHouseholdMemberRequest(id, 2, Patch{"role": permissions.RoleOwner}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+
+	t.Run("Remove another member as a viewer", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleViewer), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewRemoveHouseholdMemberRequest(id, 2, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Leave a household as a viewer", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleViewer), nil).Once()
+		repoSpy.On("HouseholdMemberList", id).Return([]*model.HouseholdMember{
+			newMember(id, 2, permissions.RoleOwner),
+			newMember(id, userID, permissions.RoleViewer),
+		}, nil).Once()
+		repoSpy.On("HouseholdMemberDelete", id, userID).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewRemoveHouseholdMemberRequest(id, userID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+
+	t.Run("Leave a household as its only owner", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("HouseholdMemberList", id).Return([]*model.HouseholdMember{
+			newMember(id, userID, permissions.RoleOwner),
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewRemoveHouseholdMemberRequest(id, userID, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorLastOwner.Message
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+}
+
+func TestHouseholdInvitations(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	id := uint(3)
+	invitee := newUser(2, "jane@doe.com")
+
+	t.Run("Invite with an invalid role", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateHouseholdInvitationRequest(id, &handlers.HouseholdInvitation{Email: invitee.Email, Role: "admin"}, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorInvalidRole.Message
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+
+	t.Run("Invite somebody who is already a member", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("UserGetWithEmail", invitee.Email).Return(invitee, nil).Once()
+		repoSpy.On("HouseholdMemberGet", id, invitee.ID).Return(newMember(id, invitee.ID, permissions.RoleViewer), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateHouseholdInvitationRequest(id, &handlers.HouseholdInvitation{Email: invitee.Email, Role: permissions.RoleEditor}, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorAlreadyMember.Message
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+
+	t.Run("Invite somebody who was already invited", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("UserGetWithEmail", "new@doe.com").Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("HouseholdInvitationList", id).Return([]*model.HouseholdInvitation{
+			{Email: "new@doe.com", Status: repository.InvitationPending},
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateHouseholdInvitationRequest(id, &handlers.HouseholdInvitation{Email: "new@doe.com", Role: permissions.RoleEditor}, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorInvitationPending.Message
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+
+	t.Run("Invite as an editor", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleEditor), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateHouseholdInvitationRequest(id, &handlers.HouseholdInvitation{Email: invitee.Email, Role: permissions.RoleEditor}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Invite with valid arguments", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", id, userID).Return(newMember(id, userID, permissions.RoleOwner), nil).Once()
+		repoSpy.On("UserGetWithEmail", invitee.Email).Return(invitee, nil).Once()
+		repoSpy.On("HouseholdMemberGet", id, invitee.ID).Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("HouseholdInvitationList", id).Return([]*model.HouseholdInvitation{
+			{Email: invitee.Email, Status: repository.InvitationDeclined},
+		}, nil).Once()
+		repoSpy.On("HouseholdInvitationCreate", mock.Anything).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateHouseholdInvitationRequest(id, &handlers.HouseholdInvitation{Email: invitee.Email, Role: permissions.RoleEditor}, token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &handlers.HouseholdInvitation{
+			HouseholdID: id,
+			Email:       invitee.Email,
+			Role:        permissions.RoleEditor,
+			InvitedByID: userID,
+		}
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		AssertResponseBody(t, res, expected)
+	})
+}
+
+func TestAnswerInvitation(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(2)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	user := newUser(userID, "jane@doe.com")
+	householdID := uint(3)
+	id := uint(7)
+
+	t.Run("Accept an invitation sent to somebody else", func(t *testing.T) {
+		repoSpy.On("HouseholdInvitationGet", id).Return(&model.HouseholdInvitation{Email: "alex@doe.com", Status: repository.InvitationPending}, nil).Once()
+		repoSpy.On("UserGet", userID).Return(user, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewAcceptInvitationRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	t.Run("Accept an invitation that was declined", func(t *testing.T) {
+		repoSpy.On("HouseholdInvitationGet", id).Return(&model.HouseholdInvitation{Email: user.Email, Status: repository.InvitationDeclined}, nil).Once()
+		repoSpy.On("UserGet", userID).Return(user, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewAcceptInvitationRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorInvitationNotPending.Message
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+
+	t.Run("Accept an invitation", func(t *testing.T) {
+		invitation := &model.HouseholdInvitation{HouseholdID: householdID, Email: user.Email, Role: permissions.RoleEditor, Status: repository.InvitationPending}
+		invitation.ID = id
+
+		repoSpy.On("HouseholdInvitationGet", id).Return(invitation, nil).Once()
+		repoSpy.On("UserGet", userID).Return(user, nil).Once()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("HouseholdInvitationAccept", id, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Once()
+		repoSpy.On("HouseholdGet", householdID).Return(&model.Household{Name: "Flat"}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewAcceptInvitationRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.Household{Name: "Flat", Role: permissions.RoleEditor})
+	})
+
+	t.Run("Decline an invitation", func(t *testing.T) {
+		invitation := &model.HouseholdInvitation{HouseholdID: householdID, Email: user.Email, Status: repository.InvitationPending}
+		invitation.ID = id
+
+		repoSpy.On("HouseholdInvitationGet", id).Return(invitation, nil).Once()
+		repoSpy.On("UserGet", userID).Return(user, nil).Once()
+		repoSpy.On("HouseholdInvitationDecline", id).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeclineInvitationRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+}
+
+func TestHouseholdWallets(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	householdID := uint(3)
+	walletID := uint(4)
+	wallet := &model.Wallet{Name: "groceries", UserID: userID + 1, HouseholdID: &householdID}
+
+	t.Run("Get a household wallet as a viewer", func(t *testing.T) {
+		repoSpy.On("WalletGet", walletID).Return(wallet, nil).Twice()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleViewer), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetWalletRequest(walletID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.Wallet{Name: wallet.Name, HouseholdID: householdID})
+	})
+
+	t.Run("Update a household wallet as a viewer", func(t *testing.T) {
+		repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleViewer), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateWalletRequest(walletID, Patch{"name": "food"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Move a household wallet whose transactions have household parties", func(t *testing.T) {
+		current := &model.Wallet{Name: "groceries", Type: model.WalletChecking, UserID: userID + 1, HouseholdID: &householdID}
+
+		repoSpy.On("WalletGet", walletID).Return(current, nil).Once()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Once()
+		repoSpy.On("WalletUpdate", walletID, mock.MatchedBy(func(w *model.Wallet) bool {
+			return w.HouseholdID == nil
+		})).Return(nil, repository.ErrorPartyHousehold).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateWalletRequest(walletID, Patch{"household_id": nil}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, handlers.ErrorWalletForeignParties.Message)
+	})
+
+	t.Run("Get a household wallet as a non-member", func(t *testing.T) {
+		repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(nil, repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetWalletRequest(walletID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Create a wallet in a household as a viewer", func(t *testing.T) {
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleViewer), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateWalletRequest(&handlers.Wallet{Name: "rent", HouseholdID: householdID}, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorBadHouseholdID.Message
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+
+	t.Run("Create a wallet in a household as an editor", func(t *testing.T) {
+		created := &model.Wallet{Name: "rent", Type: model.WalletChecking, OpeningBalance: decimal.RequireFromString("0"), UserID: userID, HouseholdID: &householdID}
+
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Once()
+		repoSpy.On("WalletCreate", created).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateWalletRequest(&handlers.Wallet{Name: "rent", HouseholdID: householdID}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		AssertResponseBody(t, res, &handlers.Wallet{Name: "rent", Type: model.WalletChecking, HouseholdID: householdID})
+	})
+
+	t.Run("Create a transaction with a personal party in a household wallet", func(t *testing.T) {
+		partyID := uint(6)
+
+		repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Once()
+		repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+		repoSpy.On("PartyGet", partyID).Return(&model.Party{UserID: userID}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateTransactionRequest(&handlers.Transaction{
+			Amount:   decimal.RequireFromString("-12.5"),
+			WalletID: walletID,
+			PartyID:  partyID,
+		}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorPartyHousehold.Message)
+		repoSpy.AssertNotCalled(t, "TransactionCreate", mock.Anything)
+	})
+
+	t.Run("Create a transaction in a household wallet as an editor", func(t *testing.T) {
+		partyID := uint(6)
+		party := &model.Party{UserID: userID + 1, HouseholdID: &householdID}
+
+		repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+		repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Twice()
+		repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+		repoSpy.On("PartyGet", partyID).Return(party, nil).Once()
+		repoSpy.On("TransactionListDuplicateCandidates", mock.Anything, duplicates.Window).Return([]*model.Transaction{}, nil).Once()
+		repoSpy.On("TransactionCreate", mock.MatchedBy(func(t *model.Transaction) bool {
+			return t.HouseholdID != nil && *t.HouseholdID == householdID
+		})).Return(nil).Once()
+		repoSpy.On("TransactionList", userID).Return([]*model.Transaction{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateTransactionRequest(&handlers.Transaction{
+			Amount:   decimal.RequireFromString("-12.5"),
+			WalletID: walletID,
+			PartyID:  partyID,
+		}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusCreated)
+	})
+}
@@ -0,0 +1,183 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/middleware/idempotency"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	newRequest := func(key string) *http.Request {
+		req := NewCreateWalletRequest(&handlers.Wallet{Name: "cash"}, token)
+		req.Header.Set(idempotency.HeaderKey, key)
+		return req
+	}
+
+	wallet := &model.Wallet{
+		Name:           "cash",
+		Type:           model.WalletChecking,
+		OpeningBalance: decimal.RequireFromString("0"),
+		UserID:         userID,
+	}
+
+	t.Run("First request with a key", func(t *testing.T) {
+		key := &model.IdempotencyKey{}
+		key.ID = 7
+
+		repoSpy.On("IdempotencyKeyGet", userID, "key-1").Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("IdempotencyKeyCreate", mock.MatchedBy(func(k *model.IdempotencyKey) bool {
+			return k.UserID == userID && k.Key == "key-1" && k.RequestHash != "" &&
+				k.LeaseExpiresAt.After(time.Now()) && k.ExpiresAt.After(k.LeaseExpiresAt)
+		})).Run(func(args mock.Arguments) {
+			args.Get(0).(*model.IdempotencyKey).ID = key.ID
+		}).Return(nil).Once()
+		repoSpy.On("WalletCreate", wallet).Return(nil).Once()
+		repoSpy.On("IdempotencyKeyComplete", key.ID, http.StatusCreated, mock.Anything).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, newRequest("key-1"))
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		AssertResponseBody(t, res, handlers.WalletModelToResponse(wallet))
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Retry a request with the same key", func(t *testing.T) {
+		stored := &model.IdempotencyKey{}
+
+		repoSpy.On("IdempotencyKeyGet", userID, "key-2").Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("IdempotencyKeyCreate", mock.Anything).Run(func(args mock.Arguments) {
+			stored.RequestHash = args.Get(0).(*model.IdempotencyKey).RequestHash
+		}).Return(nil).Once()
+		repoSpy.On("WalletCreate", wallet).Return(nil).Once()
+		repoSpy.On("IdempotencyKeyComplete", uint(0), http.StatusCreated, mock.Anything).Run(func(args mock.Arguments) {
+			stored.StatusCode = args.Int(1)
+			stored.Body = args.Get(2).([]byte)
+		}).Return(nil).Once()
+
+		first := httptest.NewRecorder()
+		r.ServeHTTP(first, newRequest("key-2"))
+
+		repoSpy.On("IdempotencyKeyGet", userID, "key-2").Return(stored, nil).Once()
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, newRequest("key-2"))
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		AssertEqual(t, res.Body.String(), first.Body.String())
+		AssertHeader(t, res, idempotency.HeaderReplayed, "true")
+		repoSpy.AssertNumberOfCalls(t, "WalletCreate", 2)
+	})
+
+	t.Run("Reuse a key for a different request", func(t *testing.T) {
+		repoSpy.On("IdempotencyKeyGet", userID, "key-1").Return(&model.IdempotencyKey{
+			RequestHash: "hash of another request",
+			StatusCode:  http.StatusCreated,
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, newRequest("key-1"))
+
+		AssertStatusCode(t, res, http.StatusUnprocessableEntity)
+		AssertErrorMessage(t, res, idempotency.ErrMsgKeyReused)
+	})
+
+	t.Run("Retry a request which is still running", func(t *testing.T) {
+		repoSpy.On("IdempotencyKeyGet", userID, "key-3").Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("IdempotencyKeyCreate", mock.Anything).Return(repository.ErrorUniqueConstaintViolation).Once()
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, newRequest("key-3"))
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, idempotency.ErrMsgInProgress)
+	})
+
+	t.Run("Forget the key when the request fails", func(t *testing.T) {
+		repoSpy.On("IdempotencyKeyGet", userID, "key-4").Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("IdempotencyKeyCreate", mock.Anything).Return(nil).Once()
+		repoSpy.On("WalletCreate", wallet).Return(repository.ErrorOther).Once()
+		repoSpy.On("IdempotencyKeyDelete", uint(0)).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, newRequest("key-4"))
+
+		AssertStatusCode(t, res, http.StatusInternalServerError)
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Forget the key when the handler panics", func(t *testing.T) {
+		repoSpy.On("IdempotencyKeyGet", userID, "key-5").Return(nil, repository.ErrorRecordNotFound).Once()
+		repoSpy.On("IdempotencyKeyCreate", mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*model.IdempotencyKey).ID = 9
+		}).Return(nil).Once()
+		repoSpy.On("WalletCreate", wallet).Run(func(args mock.Arguments) {
+			panic("unexpected")
+		}).Once()
+		repoSpy.On("IdempotencyKeyDelete", uint(9)).Return(nil).Once()
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected the panic to be passed on")
+				}
+			}()
+			r.ServeHTTP(httptest.NewRecorder(), newRequest("key-5"))
+		}()
+
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Body that is too large", func(t *testing.T) {
+		req := NewCreateWalletRequest(&handlers.Wallet{
+			Name: strings.Repeat("w", idempotency.MaxBodySize),
+		}, token)
+		req.Header.Set(idempotency.HeaderKey, "key-6")
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusRequestEntityTooLarge)
+		AssertErrorMessage(t, res, idempotency.ErrMsgTooLarge)
+		repoSpy.AssertNotCalled(t, "IdempotencyKeyGet", userID, "key-6")
+	})
+
+	t.Run("Key that is too long", func(t *testing.T) {
+		key := make([]byte, idempotency.MaxKeyLength+1)
+		for i := range key {
+			key[i] = 'k'
+		}
+
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, newRequest(string(key)))
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, idempotency.ErrMsgInvalidKey)
+	})
+}
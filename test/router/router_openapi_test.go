@@ -0,0 +1,161 @@
+package router
+
+import (
+	"encoding/json"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/openapi"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	r := router.Setup(NewRepositorySpy(), &spies.JWTServiceSpy{}, &spies.PasswordHasherSpy{}, &spies.BlobStoreSpy{}, router.TestConfig)
+
+	routed := make(map[string]bool)
+	for _, route := range r.Routes() {
+		documented := openapi.Route(route.Method, route.Path)
+		if documented == nil {
+			t.Errorf("%s %s is missing from the OpenAPI document", route.Method, route.Path)
+			continue
+		}
+		routed[documented.Operation.OperationID] = true
+
+		// Operations are named after their handlers
+		if !strings.HasSuffix(strings.TrimSuffix(route.Handler, "-fm"), "."+documented.Operation.OperationID) {
+			t.Errorf("expected %s %s to be handled by %s, got %s", route.Method, route.Path, documented.Operation.OperationID, route.Handler)
+		}
+	}
+
+	for path, item := range openapi.Document().Paths.Map() {
+		for method, op := range item.Operations() {
+			if !routed[op.OperationID] {
+				t.Errorf("%s %s is documented but not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestServeOpenAPIDocument(t *testing.T) {
+	r := router.Setup(NewRepositorySpy(), &spies.JWTServiceSpy{}, &spies.PasswordHasherSpy{}, &spies.BlobStoreSpy{}, router.TestConfig)
+
+	t.Run("Get the document without a token", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, NewGetOpenAPIDocumentRequest())
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertHeader(t, res, "Content-Type", "application/json")
+
+		var doc map[string]interface{}
+		if err := json.Unmarshal(res.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("expected a JSON document, got %s", err)
+		}
+		AssertEqual(t, doc["openapi"], "3.1.0")
+	})
+
+	t.Run("Get the docs page without a token", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, NewGetDocsRequest())
+
+		AssertStatusCode(t, res, http.StatusOK)
+		if !strings.Contains(res.Body.String(), `url: "openapi.json"`) {
+			t.Errorf("expected the docs page to load the document, got %s", res.Body.String())
+		}
+	})
+}
+
+func TestRequestValidation(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	config := *router.TestConfig
+	config.ValidateRequests = true
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, &config)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Create wallet with a name that isn't a string", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewRequest(http.MethodPost, BaseWalletsPath, token, map[string]interface{}{"name": 5})
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, `request body at /name: value must be a string`)
+	})
+
+	t.Run("Create wallet with an amount that isn't a decimal number", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewRequest(http.MethodPost, BaseWalletsPath, token, map[string]interface{}{"name": "cash", "opening_balance": "12,50"})
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, `request body at /opening_balance: string doesn't match the regular expression "^-?[0-9]+(\.[0-9]+)?$"`)
+	})
+
+	t.Run("List wallets with a flag that isn't a boolean", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewRequest(http.MethodGet, BaseWalletsPath+"?include_archived=maybe", token, nil)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, `query parameter include_archived: value maybe: an invalid boolean: invalid syntax`)
+	})
+
+	t.Run("Get wallet with an ID that isn't a number", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewRequest(http.MethodGet, BaseWalletsPath+"cash", token, nil)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, `path parameter id: value cash: an invalid integer: invalid syntax`)
+	})
+
+	t.Run("Create valid wallet", func(t *testing.T) {
+		wallet := &model.Wallet{
+			Name:           "cash",
+			Type:           model.WalletChecking,
+			OpeningBalance: decimal.RequireFromString("12.5"),
+			UserID:         userID,
+		}
+		repoSpy.On("WalletCreate", wallet).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewCreateWalletRequest(&handlers.Wallet{
+			Name:           wallet.Name,
+			OpeningBalance: wallet.OpeningBalance,
+		}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusCreated)
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Update wallet with a patch of the wrong type", func(t *testing.T) {
+		res := httptest.NewRecorder()
+		req := NewUpdateWalletRequest(2, Patch{"display_order": "first"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, `request body at /display_order: value must be one of integer, null`)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"expense-api/internal/handlers"
 	"expense-api/internal/middleware/auth"
 	"expense-api/internal/model"
+	"expense-api/internal/permissions"
 	"expense-api/internal/repository"
 	"expense-api/internal/router"
 	"expense-api/test/spies"
@@ -13,11 +14,12 @@ import (
 )
 
 func TestCreateParty(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		party := &handlers.Party{}
@@ -44,6 +46,7 @@ func TestCreateParty(t *testing.T) {
 				UserID: userID,
 			}
 
+			repoSpy.On("PartyGetByAlias", userID, party.Name).Return(nil, repository.ErrorRecordNotFound).Once()
 			repoSpy.On("PartyCreate", party).Return(repository.ErrorUniqueConstaintViolation).Once()
 
 			res := httptest.NewRecorder()
@@ -63,6 +66,7 @@ func TestCreateParty(t *testing.T) {
 				UserID: userID,
 			}
 
+			repoSpy.On("PartyGetByAlias", userID, party.Name).Return(nil, repository.ErrorRecordNotFound).Once()
 			repoSpy.On("PartyCreate", party).Return(nil).Once()
 
 			res := httptest.NewRecorder()
@@ -75,15 +79,35 @@ func TestCreateParty(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusCreated)
 			AssertResponseBody(t, res, resBody)
 		})
+
+		t.Run("Create party with the alias of another party", func(t *testing.T) {
+			canonical := &model.Party{
+				Model:   model.Model{ID: 3},
+				Name:    "Amazon",
+				UserID:  userID,
+				Aliases: []model.PartyAlias{{Model: model.Model{ID: 1}, PartyID: 3, Name: "AMAZON EU SARL"}},
+			}
+
+			repoSpy.On("PartyGetByAlias", userID, "amazon eu sarl").Return(canonical, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreatePartyRequest(&handlers.Party{Name: "amazon eu sarl"}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.PartyModelToResponse(canonical))
+		})
 	})
 }
 
 func TestGetParty(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
@@ -164,24 +188,47 @@ func TestGetParty(t *testing.T) {
 
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, resBody)
+			AssertHeader(t, res, "ETag", `"0"`)
+		})
+
+		t.Run("Get party that changed since the client read it", func(t *testing.T) {
+			id := uint(1)
+			party := &model.Party{
+				Name:   "new party",
+				UserID: userID,
+			}
+			party.Version = 2
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Twice()
+
+			res := httptest.NewRecorder()
+			req := NewGetPartyRequest(id, token)
+			req.Header.Set("If-None-Match", `"1"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertHeader(t, res, "ETag", `"2"`)
+			AssertResponseBody(t, res, handlers.PartyModelToResponse(party))
 		})
 	})
 }
 
 func TestUpdateParty(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
-		party := &handlers.Party{}
+		patch := Patch{}
 		token := "invalid-token"
 
-		missingTokenReq := NewUpdatePartyRequest(id, party, token)
-		invalidTokenReq := NewUpdatePartyRequest(id, party, token)
+		missingTokenReq := NewUpdatePartyRequest(id, patch, token)
+		invalidTokenReq := NewUpdatePartyRequest(id, patch, token)
 
 		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
 		t.Run("Unauthorized test cases", unauthorizedTestCases)
@@ -197,14 +244,11 @@ func TestUpdateParty(t *testing.T) {
 
 		t.Run("Update non-existent party", func(t *testing.T) {
 			id := uint(1)
-			party := &handlers.Party{
-				Name: "new party",
-			}
 
 			repoSpy.On("PartyGet", id).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdatePartyRequest(id, party, token)
+			req := NewUpdatePartyRequest(id, Patch{"name": "new party"}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -222,13 +266,27 @@ func TestUpdateParty(t *testing.T) {
 			repoSpy.On("PartyGet", id).Return(party, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdatePartyRequest(id, &handlers.Party{Name: party.Name}, token)
+			req := NewUpdatePartyRequest(id, Patch{"name": party.Name}, token)
 
 			r.ServeHTTP(res, req)
 
 			AssertStatusCode(t, res, http.StatusForbidden)
 		})
 
+		t.Run("Remove the name of a party", func(t *testing.T) {
+			id := uint(1)
+
+			repoSpy.On("PartyGet", id).Return(&model.Party{Name: "Lidl", UserID: userID}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdatePartyRequest(id, Patch{"name": nil}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorPartyName.Message)
+		})
+
 		t.Run("Try to update a party with already existing name, belonging to the same user", func(t *testing.T) {
 			id := uint(1)
 			party := &model.Party{
@@ -236,11 +294,11 @@ func TestUpdateParty(t *testing.T) {
 				UserID: userID,
 			}
 
-			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyGet", id).Return(&model.Party{Name: "Lidl", UserID: userID}, nil).Once()
 			repoSpy.On("PartyUpdate", id, party).Return(nil, repository.ErrorUniqueConstaintViolation).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdatePartyRequest(id, &handlers.Party{Name: party.Name}, token)
+			req := NewUpdatePartyRequest(id, Patch{"name": party.Name}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -257,11 +315,11 @@ func TestUpdateParty(t *testing.T) {
 				UserID: userID,
 			}
 
-			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyGet", id).Return(&model.Party{Name: "old party", UserID: userID}, nil).Once()
 			repoSpy.On("PartyUpdate", id, party).Return(party, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdatePartyRequest(id, &handlers.Party{Name: party.Name}, token)
+			req := NewUpdatePartyRequest(id, Patch{"name": party.Name}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -270,15 +328,54 @@ func TestUpdateParty(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, resBody)
 		})
+
+		t.Run("Move a party out of its household", func(t *testing.T) {
+			id := uint(3)
+			householdID := uint(2)
+			current := &model.Party{Name: "Lidl", UserID: userID, HouseholdID: &householdID}
+			party := &model.Party{Name: "Lidl", UserID: userID}
+
+			repoSpy.On("PartyGet", id).Return(current, nil).Once()
+			repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Once()
+			repoSpy.On("PartyUpdate", id, party).Return(party, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdatePartyRequest(id, Patch{"household_id": nil}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.PartyModelToResponse(party))
+		})
+
+		t.Run("Move a party used by household transactions out of its household", func(t *testing.T) {
+			id := uint(3)
+			householdID := uint(2)
+			current := &model.Party{Name: "Lidl", UserID: userID, HouseholdID: &householdID}
+			party := &model.Party{Name: "Lidl", UserID: userID}
+
+			repoSpy.On("PartyGet", id).Return(current, nil).Once()
+			repoSpy.On("HouseholdMemberGet", householdID, userID).Return(newMember(householdID, userID, permissions.RoleEditor), nil).Once()
+			repoSpy.On("PartyUpdate", id, party).Return(nil, repository.ErrorPartyHousehold).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdatePartyRequest(id, Patch{"household_id": nil}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorPartyInUse.Message)
+		})
 	})
 }
 
 func TestDeleteParty(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
@@ -330,6 +427,25 @@ func TestDeleteParty(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusForbidden)
 		})
 
+		t.Run("Delete party with reconciled transactions", func(t *testing.T) {
+			id := uint(2)
+			party := &model.Party{
+				Name:   "new party",
+				UserID: userID,
+			}
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyDelete", id, false).Return(repository.ErrorHasReconciled).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyRequest(id, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorPartyReconciled.Message)
+		})
+
 		t.Run("Delete existing party", func(t *testing.T) {
 			id := uint(2)
 			party := &model.Party{
@@ -338,7 +454,7 @@ func TestDeleteParty(t *testing.T) {
 			}
 
 			repoSpy.On("PartyGet", id).Return(party, nil).Once()
-			repoSpy.On("PartyDelete", id).Return(nil).Once()
+			repoSpy.On("PartyDelete", id, false).Return(nil).Once()
 
 			res := httptest.NewRecorder()
 			req := NewDeletePartyRequest(id, token)
@@ -347,15 +463,140 @@ func TestDeleteParty(t *testing.T) {
 
 			AssertStatusCode(t, res, http.StatusNoContent)
 		})
+
+		party := &model.Party{
+			Name:   "new party",
+			UserID: userID,
+		}
+
+		t.Run("Try to delete party with transactions", func(t *testing.T) {
+			id := uint(2)
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyDelete", id, false).Return(repository.ErrorPartyNotEmpty).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyRequest(id, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorPartyTransactions.Message)
+		})
+
+		t.Run("Delete party along with its transactions", func(t *testing.T) {
+			id := uint(2)
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyDelete", id, true).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyWithQueryRequest(id, "cascade=true", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNoContent)
+		})
+
+		reassignBadRequestCases := []struct {
+			desc    string
+			query   string
+			message string
+		}{
+			{"Try to cascade and reassign at once", "cascade=true&reassign_to=3", handlers.ErrorCascadeAndReassign.Message},
+			{"Try to reassign to the party itself", "reassign_to=2", handlers.ErrorPartyReassignID.Message},
+			{"Try to reassign to an invalid id", "reassign_to=abc", handlers.ErrorPartyReassignID.Message},
+		}
+		for _, tc := range reassignBadRequestCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				id := uint(2)
+
+				repoSpy.On("PartyGet", id).Return(party, nil).Once()
+
+				res := httptest.NewRecorder()
+				req := NewDeletePartyWithQueryRequest(id, tc.query, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Try to reassign to a party that doesn't exist", func(t *testing.T) {
+			id := uint(2)
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyGet", uint(3)).Return(nil, repository.ErrorRecordNotFound).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyWithQueryRequest(id, "reassign_to=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorPartyNotFound.Message)
+		})
+
+		t.Run("Try to reassign to a party of another user", func(t *testing.T) {
+			id := uint(2)
+			foreign := &model.Party{Name: "foreign party", UserID: userID + 1}
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyGet", uint(3)).Return(foreign, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyWithQueryRequest(id, "reassign_to=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+			AssertErrorMessage(t, res, handlers.ErrorBadPartyID.Message)
+		})
+
+		t.Run("Try to reassign to a party of another household", func(t *testing.T) {
+			id := uint(2)
+			target := &model.Party{Name: "other party", UserID: userID}
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyGet", uint(3)).Return(target, nil).Once()
+			repoSpy.On("PartyReassign", id, uint(3)).Return(repository.ErrorPartyHousehold).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyWithQueryRequest(id, "reassign_to=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorReassignHousehold.Message)
+		})
+
+		t.Run("Reassign the transactions of a party and delete it", func(t *testing.T) {
+			id := uint(2)
+			target := &model.Party{Name: "other party", UserID: userID}
+
+			repoSpy.On("PartyGet", id).Return(party, nil).Once()
+			repoSpy.On("PartyGet", uint(3)).Return(target, nil).Once()
+			repoSpy.On("PartyReassign", id, uint(3)).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeletePartyWithQueryRequest(id, "reassign_to=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNoContent)
+			repoSpy.AssertExpectations(t)
+		})
 	})
 }
 
 func TestListParties(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	newPartyListResponse := func(slice []*handlers.Party) *PartyListResponse {
 		return &PartyListResponse{
@@ -416,11 +657,12 @@ func TestListParties(t *testing.T) {
 }
 
 func TestListTransactionsByParty(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	newTransactionListResponse := func(slice []*handlers.Transaction) *TransactionListResponse {
 		return &TransactionListResponse{
@@ -508,10 +750,180 @@ func TestListTransactionsByParty(t *testing.T) {
 
 			r.ServeHTTP(res, req)
 
-			expected := newTransactionListResponse([]*handlers.Transaction{{}})
+			expected := newTransactionListResponse([]*handlers.Transaction{{Tags: []string{}}})
 
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, expected)
 		})
 	})
 }
+
+func TestMergeParties(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+		merge := &handlers.PartyMerge{SourceIDs: []uint{2}}
+
+		missingTokenReq := NewMergePartiesRequest(1, merge, token)
+		invalidTokenReq := NewMergePartiesRequest(1, merge, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		targetID := uint(1)
+		target := &model.Party{Model: model.Model{ID: targetID}, Name: "Amazon", UserID: userID}
+
+		badRequests := []struct {
+			desc    string
+			merge   *handlers.PartyMerge
+			message string
+		}{
+			{"Merge no parties", &handlers.PartyMerge{}, handlers.ErrorRequiredSourceIDs.Message},
+			{"Merge a party into itself", &handlers.PartyMerge{SourceIDs: []uint{targetID}}, handlers.ErrorMergeIntoItself.Message},
+		}
+
+		for _, tc := range badRequests {
+			t.Run(tc.desc, func(t *testing.T) {
+				repoSpy.On("PartyGet", targetID).Return(target, nil).Once()
+
+				res := httptest.NewRecorder()
+				req := NewMergePartiesRequest(targetID, tc.merge, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Merge into a party with an outdated If-Match", func(t *testing.T) {
+			repoSpy.On("PartyGet", targetID).Return(target, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergePartiesRequest(targetID, &handlers.PartyMerge{SourceIDs: []uint{2}}, token)
+			req.Header.Set("If-Match", `"1"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
+		})
+
+		t.Run("Merge a non-existent party", func(t *testing.T) {
+			repoSpy.On("PartyGet", targetID).Return(target, nil).Once()
+			repoSpy.On("PartyGet", uint(2)).Return(nil, repository.ErrorRecordNotFound).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergePartiesRequest(targetID, &handlers.PartyMerge{SourceIDs: []uint{2}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorPartyNotFound.Message)
+		})
+
+		t.Run("Merge another user's party", func(t *testing.T) {
+			repoSpy.On("PartyGet", targetID).Return(target, nil).Once()
+			repoSpy.On("PartyGet", uint(2)).Return(&model.Party{UserID: userID + 1}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergePartiesRequest(targetID, &handlers.PartyMerge{SourceIDs: []uint{2}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+			AssertErrorMessage(t, res, handlers.ErrorBadPartyID.Message)
+		})
+
+		t.Run("Merge parties", func(t *testing.T) {
+			merged := &model.Party{
+				Model:  model.Model{ID: targetID},
+				Name:   "Amazon",
+				UserID: userID,
+				Aliases: []model.PartyAlias{
+					{Model: model.Model{ID: 1}, PartyID: targetID, Name: "AMAZON EU SARL"},
+					{Model: model.Model{ID: 2}, PartyID: targetID, Name: "amzn mktp"},
+				},
+			}
+
+			repoSpy.On("PartyGet", targetID).Return(target, nil).Once()
+			repoSpy.On("PartyGet", uint(2)).Return(&model.Party{Name: "AMAZON EU SARL", UserID: userID}, nil).Once()
+			repoSpy.On("PartyGet", uint(3)).Return(&model.Party{Name: "amzn mktp", UserID: userID}, nil).Once()
+			repoSpy.On("PartyMerge", targetID, []uint{2, 3}).Return(merged, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergePartiesRequest(targetID, &handlers.PartyMerge{SourceIDs: []uint{2, 3, 2}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.PartyModelToResponse(merged))
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Merge parties of another household", func(t *testing.T) {
+			repoSpy.On("PartyGet", targetID).Return(target, nil).Once()
+			repoSpy.On("PartyGet", uint(2)).Return(&model.Party{Name: "AMAZON EU SARL", UserID: userID}, nil).Once()
+			repoSpy.On("PartyMerge", targetID, []uint{2}).Return(nil, repository.ErrorPartyHousehold).Once()
+
+			res := httptest.NewRecorder()
+			req := NewMergePartiesRequest(targetID, &handlers.PartyMerge{SourceIDs: []uint{2}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorMergeHousehold.Message)
+		})
+	})
+}
+
+func TestDeletePartyAlias(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	party := &model.Party{Model: model.Model{ID: 1}, UserID: userID}
+
+	t.Run("Delete an alias of another party", func(t *testing.T) {
+		repoSpy.On("PartyGet", party.ID).Return(party, nil).Once()
+		repoSpy.On("PartyAliasDelete", party.ID, uint(5)).Return(repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeletePartyAliasRequest(party.ID, 5, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	t.Run("Delete alias", func(t *testing.T) {
+		repoSpy.On("PartyGet", party.ID).Return(party, nil).Once()
+		repoSpy.On("PartyAliasDelete", party.ID, uint(2)).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeletePartyAliasRequest(party.ID, 2, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+}
@@ -0,0 +1,227 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestReconcileWallet(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	statementDate := time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC)
+	statementBalance := decimal.RequireFromString("120.5")
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+		statement := &handlers.ReconciliationRequest{StatementDate: statementDate, StatementBalance: &statementBalance}
+
+		missingTokenReq := NewReconcileWalletRequest(1, statement, token)
+		invalidTokenReq := NewReconcileWalletRequest(1, statement, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		walletID := uint(1)
+		wallet := &model.Wallet{Model: model.Model{ID: walletID}, Currency: "EUR", UserID: userID}
+		precise := decimal.RequireFromString("120.505")
+
+		badRequests := []struct {
+			desc      string
+			statement *handlers.ReconciliationRequest
+			message   string
+			getWallet bool
+		}{
+			{"Reconcile without a statement date", &handlers.ReconciliationRequest{StatementBalance: &statementBalance}, handlers.ErrorRequiredStatementDate.Message, false},
+			{"Reconcile without a statement balance", &handlers.ReconciliationRequest{StatementDate: statementDate}, handlers.ErrorRequiredStatementBalance.Message, false},
+			{"Reconcile with a balance too precise for the currency", &handlers.ReconciliationRequest{StatementDate: statementDate, StatementBalance: &precise}, handlers.ErrorAmountPrecision.Message, true},
+		}
+
+		for _, tc := range badRequests {
+			t.Run(tc.desc, func(t *testing.T) {
+				repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+				if tc.getWallet {
+					repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+				}
+
+				res := httptest.NewRecorder()
+				req := NewReconcileWalletRequest(walletID, tc.statement, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		statement := &handlers.ReconciliationRequest{StatementDate: statementDate, StatementBalance: &statementBalance}
+
+		t.Run("Reconcile with a difference", func(t *testing.T) {
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Twice()
+			repoSpy.On("WalletClearedBalance", walletID, statementDate).Return(decimal.RequireFromString("100"), nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewReconcileWalletRequest(walletID, statement, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.ReconciliationResult{
+				StatementDate:    statementDate,
+				StatementBalance: statementBalance,
+				ClearedBalance:   decimal.RequireFromString("100"),
+				Difference:       decimal.RequireFromString("20.5"),
+			})
+		})
+
+		rModel := &model.Reconciliation{
+			WalletID:         walletID,
+			UserID:           userID,
+			StatementDate:    statementDate,
+			StatementBalance: statementBalance,
+		}
+
+		t.Run("Reconcile while the cleared transactions change", func(t *testing.T) {
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Twice()
+			repoSpy.On("WalletClearedBalance", walletID, statementDate).Return(statementBalance, nil).Once()
+			repoSpy.On("ReconciliationCreate", rModel).Return(repository.ErrorStatementOutOfBalance).Once()
+
+			res := httptest.NewRecorder()
+			req := NewReconcileWalletRequest(walletID, statement, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorStatementOutOfBalance.Message)
+		})
+
+		t.Run("Reconcile matching balances", func(t *testing.T) {
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Twice()
+			repoSpy.On("WalletClearedBalance", walletID, statementDate).Return(statementBalance, nil).Once()
+			repoSpy.On("ReconciliationCreate", rModel).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewReconcileWalletRequest(walletID, statement, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, &handlers.ReconciliationResult{
+				StatementDate:    statementDate,
+				StatementBalance: statementBalance,
+				ClearedBalance:   statementBalance,
+				Difference:       decimal.Zero,
+				Reconciled:       true,
+				Reconciliation:   handlers.ReconciliationModelToResponse(rModel),
+			})
+			repoSpy.AssertExpectations(t)
+		})
+	})
+}
+
+func TestListReconciliations(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	walletID := uint(1)
+	reconciliations := []*model.Reconciliation{
+		{Model: model.Model{ID: 2}, WalletID: walletID, StatementDate: time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC), StatementBalance: decimal.RequireFromString("120.5"), TransactionCount: 4},
+		{Model: model.Model{ID: 1}, WalletID: walletID, StatementDate: time.Date(2021, 4, 30, 0, 0, 0, 0, time.UTC), StatementBalance: decimal.RequireFromString("80"), TransactionCount: 9},
+	}
+
+	repoSpy.On("WalletGet", walletID).Return(&model.Wallet{UserID: userID}, nil).Once()
+	repoSpy.On("ReconciliationList", walletID).Return(reconciliations, nil).Once()
+
+	res := httptest.NewRecorder()
+	req := NewListReconciliationsRequest(walletID, token)
+
+	r.ServeHTTP(res, req)
+
+	AssertStatusCode(t, res, http.StatusOK)
+	AssertResponseBody(t, res, &ReconciliationListResponse{
+		Count: 2,
+		Entries: []*handlers.Reconciliation{
+			handlers.ReconciliationModelToResponse(reconciliations[0]),
+			handlers.ReconciliationModelToResponse(reconciliations[1]),
+		},
+	})
+}
+
+func TestChangeReconciledTransaction(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	id := uint(1)
+	transaction := &model.Transaction{Model: model.Model{ID: id}, UserID: userID, Status: model.TransactionReconciled}
+
+	testCases := []struct {
+		desc string
+		req  *http.Request
+	}{
+		{"Update a reconciled transaction", NewUpdateTransactionRequest(id, Patch{"description": "changed"}, token)},
+		{"Delete a reconciled transaction", NewDeleteTransactionRequest(id, token)},
+		{"Merge a reconciled transaction", NewMergeTransactionsRequest(id, &handlers.TransactionMerge{DuplicateID: 2}, token)},
+		{"Split a reconciled transaction", NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{}, token)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			r.ServeHTTP(res, tc.req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorTransactionReconciled.Message)
+		})
+	}
+
+	t.Run("Merge a reconciled duplicate", func(t *testing.T) {
+		repoSpy.On("TransactionGet", id).Return(&model.Transaction{Model: model.Model{ID: id}, UserID: userID}, nil).Once()
+		repoSpy.On("TransactionGet", uint(2)).Return(&model.Transaction{UserID: userID, Status: model.TransactionReconciled}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewMergeTransactionsRequest(id, &handlers.TransactionMerge{DuplicateID: 2}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, handlers.ErrorTransactionReconciled.Message)
+	})
+}
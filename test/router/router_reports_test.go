@@ -0,0 +1,327 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetBalanceReport(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewGetBalanceReportRequest("", token)
+		invalidTokenReq := NewGetBalanceReportRequest("", token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		user := &model.User{BaseCurrency: "EUR"}
+
+		euroWallet := &model.Wallet{Name: "cash", Currency: "EUR", UserID: userID}
+		euroWallet.ID = 1
+		dollarWallet := &model.Wallet{Name: "travel", Currency: "USD", UserID: userID}
+		dollarWallet.ID = 2
+		wallets := []*model.Wallet{dollarWallet, euroWallet}
+
+		day1 := time.Date(2021, 11, 18, 0, 0, 0, 0, time.UTC)
+		day2 := time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC)
+
+		transactions := []*model.Transaction{
+			{WalletID: 1, Amount: decimal.RequireFromString("-20.50"), Timestamp: day1},
+			{WalletID: 2, Amount: decimal.RequireFromString("-11"), Timestamp: day1.Add(time.Hour)},
+			{WalletID: 2, Amount: decimal.RequireFromString("100"), Timestamp: day2.Add(time.Hour)},
+		}
+
+		rates := []*model.ExchangeRate{
+			{Date: day1, Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.1")},
+			{Date: day2, Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.25")},
+		}
+
+		t.Run("Get report with invalid period", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewGetBalanceReportRequest("?from=yesterday", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidDate.Message)
+		})
+
+		t.Run("Get report when an exchange rate is missing", func(t *testing.T) {
+			repoSpy.On("UserGet", userID).Return(user, nil).Once()
+			repoSpy.On("WalletList", userID).Return(wallets, nil).Once()
+			repoSpy.On("TransactionList", userID).Return(transactions, nil).Once()
+			repoSpy.On("ExchangeRateListByCurrencies", mock.Anything).Return([]*model.ExchangeRate{}, nil).Once()
+			repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetBalanceReportRequest("", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusUnprocessableEntity)
+		})
+
+		t.Run("Get report converted to the base currency", func(t *testing.T) {
+			repoSpy.On("UserGet", userID).Return(user, nil).Once()
+			repoSpy.On("WalletList", userID).Return(wallets, nil).Once()
+			repoSpy.On("TransactionList", userID).Return(transactions, nil).Once()
+			repoSpy.On("ExchangeRateListByCurrencies", []string{"EUR", "USD"}).Return(rates, nil).Once()
+			repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetBalanceReportRequest("", token)
+
+			r.ServeHTTP(res, req)
+
+			expected := &handlers.BalanceReport{
+				BaseCurrency: "EUR",
+				Total:        decimal.RequireFromString("49.50"),
+				Wallets: []*handlers.WalletBalance{
+					{
+						WalletID:         1,
+						Name:             "cash",
+						Currency:         "EUR",
+						Balance:          decimal.RequireFromString("-20.50"),
+						ConvertedBalance: decimal.RequireFromString("-20.50"),
+					},
+					{
+						WalletID:         2,
+						Name:             "travel",
+						Currency:         "USD",
+						Balance:          decimal.RequireFromString("89"),
+						ConvertedBalance: decimal.RequireFromString("70"),
+					},
+				},
+			}
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("Get report for a period", func(t *testing.T) {
+			repoSpy.On("UserGet", userID).Return(user, nil).Once()
+			repoSpy.On("WalletList", userID).Return(wallets, nil).Once()
+			repoSpy.On("TransactionList", userID).Return(transactions, nil).Once()
+			repoSpy.On("ExchangeRateListByCurrencies", []string{"EUR", "USD"}).Return(rates, nil).Once()
+			repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetBalanceReportRequest("?from=2021-11-18&to=2021-11-18", token)
+
+			r.ServeHTTP(res, req)
+
+			var got handlers.BalanceReport
+			ParseJSONtoResponse(t, res, &got)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertEqual(t, got.Total.String(), "-30.5")
+		})
+
+		t.Run("Get report with opening balances", func(t *testing.T) {
+			savings := &model.Wallet{Name: "savings", Currency: "USD", OpeningBalance: decimal.RequireFromString("55"), OpeningDate: &day1, UserID: userID}
+			savings.ID = 3
+			archived := &model.Wallet{Name: "old", Currency: "EUR", OpeningBalance: decimal.RequireFromString("10"), OpeningDate: &day1, Archived: true, UserID: userID}
+			archived.ID = 4
+
+			repoSpy.On("UserGet", userID).Return(user, nil).Once()
+			repoSpy.On("WalletList", userID).Return([]*model.Wallet{savings, archived}, nil).Once()
+			repoSpy.On("TransactionList", userID).Return([]*model.Transaction{}, nil).Once()
+			repoSpy.On("ExchangeRateListByCurrencies", []string{"EUR", "USD"}).Return(rates, nil).Once()
+			repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetBalanceReportRequest("", token)
+
+			r.ServeHTTP(res, req)
+
+			expected := &handlers.BalanceReport{
+				BaseCurrency: "EUR",
+				Total:        decimal.RequireFromString("60"),
+				Wallets: []*handlers.WalletBalance{
+					{
+						WalletID:         3,
+						Name:             "savings",
+						Currency:         "USD",
+						Balance:          decimal.RequireFromString("55"),
+						ConvertedBalance: decimal.RequireFromString("50"),
+					},
+					{
+						WalletID:         4,
+						Name:             "old",
+						Currency:         "EUR",
+						Balance:          decimal.RequireFromString("10"),
+						ConvertedBalance: decimal.RequireFromString("10"),
+					},
+				},
+			}
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, expected)
+		})
+	})
+}
+
+func TestGetPartyReport(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	day := time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Get totals per party in the base currency", func(t *testing.T) {
+
+		wallet := &model.Wallet{Currency: "USD", UserID: userID}
+		wallet.ID = 1
+		party := &model.Party{Name: "Rewe", UserID: userID}
+		party.ID = 3
+
+		repoSpy.On("UserGet", userID).Return(&model.User{BaseCurrency: "EUR"}, nil).Once()
+		repoSpy.On("WalletList", userID).Return([]*model.Wallet{wallet}, nil).Once()
+		repoSpy.On("PartyList", userID).Return([]*model.Party{party}, nil).Once()
+		repoSpy.On("TransactionList", userID).Return([]*model.Transaction{
+			{WalletID: 1, PartyID: 3, Amount: decimal.RequireFromString("-25"), Timestamp: day},
+			{WalletID: 1, PartyID: 3, Amount: decimal.RequireFromString("-12.50"), Timestamp: day},
+		}, nil).Once()
+		repoSpy.On("ExchangeRateListByCurrencies", []string{"EUR", "USD"}).Return([]*model.ExchangeRate{
+			{Date: day, Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.25")},
+		}, nil).Once()
+		repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetPartyReportRequest("", token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &handlers.PartyReport{
+			BaseCurrency: "EUR",
+			Parties: []*handlers.PartyTotal{
+				{PartyID: 3, Name: "Rewe", Count: 2, Total: decimal.RequireFromString("-30")},
+			},
+		}
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, expected)
+	})
+	t.Run("Split transactions are counted per split", func(t *testing.T) {
+		rewe := &model.Party{Name: "Rewe", UserID: userID}
+		rewe.ID = 3
+		dm := &model.Party{Name: "dm", UserID: userID}
+		dm.ID = 4
+
+		transaction := &model.Transaction{WalletID: 1, PartyID: 3, Amount: decimal.RequireFromString("-50"), Timestamp: day}
+		transaction.ID = 7
+
+		repoSpy.On("UserGet", userID).Return(&model.User{}, nil).Once()
+		repoSpy.On("WalletList", userID).Return([]*model.Wallet{}, nil).Once()
+		repoSpy.On("PartyList", userID).Return([]*model.Party{rewe, dm}, nil).Once()
+		repoSpy.On("TransactionList", userID).Return([]*model.Transaction{transaction}, nil).Once()
+		repoSpy.On("ExchangeRateListByCurrencies", []string{"EUR"}).Return([]*model.ExchangeRate{}, nil).Once()
+		repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{
+			{TransactionID: 7, Amount: decimal.RequireFromString("-30")},
+			{TransactionID: 7, Amount: decimal.RequireFromString("-20"), PartyID: &dm.ID},
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetPartyReportRequest("", token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &handlers.PartyReport{
+			BaseCurrency: "EUR",
+			Parties: []*handlers.PartyTotal{
+				{PartyID: 3, Name: "Rewe", Count: 1, Total: decimal.RequireFromString("-30")},
+				{PartyID: 4, Name: "dm", Count: 1, Total: decimal.RequireFromString("-20")},
+			},
+		}
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, expected)
+	})
+}
+
+func TestGetCategoryReport(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Get totals per category with split transactions", func(t *testing.T) {
+		day := time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC)
+
+		split := &model.Transaction{Amount: decimal.RequireFromString("-50"), Category: "groceries", Timestamp: day}
+		split.ID = 1
+		single := &model.Transaction{Amount: decimal.RequireFromString("-10"), Category: "groceries", Timestamp: day}
+		single.ID = 2
+
+		repoSpy.On("UserGet", userID).Return(&model.User{}, nil).Once()
+		repoSpy.On("WalletList", userID).Return([]*model.Wallet{}, nil).Once()
+		repoSpy.On("TransactionList", userID).Return([]*model.Transaction{split, single}, nil).Once()
+		repoSpy.On("ExchangeRateListByCurrencies", []string{"EUR"}).Return([]*model.ExchangeRate{}, nil).Once()
+		repoSpy.On("TransactionSplitListByUser", userID).Return([]*model.TransactionSplit{
+			{TransactionID: 1, Amount: decimal.RequireFromString("-35"), Category: "groceries"},
+			{TransactionID: 1, Amount: decimal.RequireFromString("-15"), Category: "household"},
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetCategoryReportRequest("", token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &handlers.CategoryReport{
+			BaseCurrency: "EUR",
+			Categories: []*handlers.CategoryTotal{
+				{Category: "groceries", Count: 2, Total: decimal.RequireFromString("-45")},
+				{Category: "household", Count: 1, Total: decimal.RequireFromString("-15")},
+			},
+		}
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, expected)
+	})
+}
@@ -0,0 +1,391 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateRule(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateRuleRequest(&handlers.RuleRequest{}, token)
+		invalidTokenReq := NewCreateRuleRequest(&handlers.RuleRequest{}, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		partyID := uint(4)
+		min, max := decimal.NewFromInt(10), decimal.NewFromInt(1)
+
+		badRequests := []struct {
+			desc    string
+			rule    *handlers.RuleRequest
+			message string
+		}{
+			{"Missing name", &handlers.RuleRequest{
+				Conditions: &handlers.RuleConditions{DescriptionContains: "lidl"},
+				Actions:    &handlers.RuleActions{Category: "groceries"},
+			}, handlers.ErrorRuleName.Message},
+			{"No condition", &handlers.RuleRequest{
+				Name:    "groceries",
+				Actions: &handlers.RuleActions{Category: "groceries"},
+			}, handlers.ErrorRuleNoCondition.Message},
+			{"No action", &handlers.RuleRequest{
+				Name:       "groceries",
+				Conditions: &handlers.RuleConditions{DescriptionContains: "lidl"},
+			}, handlers.ErrorRuleNoAction.Message},
+			{"Invalid regex", &handlers.RuleRequest{
+				Name:       "groceries",
+				Conditions: &handlers.RuleConditions{DescriptionRegex: "LIDL ("},
+				Actions:    &handlers.RuleActions{Category: "groceries"},
+			}, handlers.ErrorRuleRegex.Message},
+			{"Inverted amount range", &handlers.RuleRequest{
+				Name:       "groceries",
+				Conditions: &handlers.RuleConditions{AmountMin: &min, AmountMax: &max},
+				Actions:    &handlers.RuleActions{Category: "groceries"},
+			}, handlers.ErrorRuleAmountRange.Message},
+			{"Empty tag", &handlers.RuleRequest{
+				Name:       "groceries",
+				Conditions: &handlers.RuleConditions{DescriptionContains: "lidl"},
+				Actions:    &handlers.RuleActions{Tags: []string{" "}},
+			}, handlers.ErrorInvalidTag.Message},
+		}
+
+		for _, tc := range badRequests {
+			t.Run(tc.desc, func(t *testing.T) {
+				res := httptest.NewRecorder()
+				req := NewCreateRuleRequest(tc.rule, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Create rule setting another user's party", func(t *testing.T) {
+			repoSpy.On("PartyGet", partyID).Return(&model.Party{UserID: userID + 1}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateRuleRequest(&handlers.RuleRequest{
+				Name:       "groceries",
+				Conditions: &handlers.RuleConditions{DescriptionContains: "lidl"},
+				Actions:    &handlers.RuleActions{PartyID: &partyID},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+			AssertErrorMessage(t, res, handlers.ErrorBadPartyID.Message)
+		})
+
+		t.Run("Create valid rule", func(t *testing.T) {
+			rule := &model.Rule{
+				UserID:           userID,
+				Name:             "Lidl",
+				Enabled:          true,
+				DescriptionRegex: `^POS \d+ (LIDL)`,
+				SetPartyID:       &partyID,
+				SetCategory:      "groceries",
+				AddTags:          model.Tags{"food"},
+			}
+
+			repoSpy.On("PartyGet", partyID).Return(&model.Party{UserID: userID}, nil).Once()
+			repoSpy.On("RuleCreate", rule).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateRuleRequest(&handlers.RuleRequest{
+				Name:       "Lidl",
+				Conditions: &handlers.RuleConditions{DescriptionRegex: rule.DescriptionRegex},
+				Actions:    &handlers.RuleActions{PartyID: &partyID, Category: "groceries", Tags: []string{"Food"}},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, handlers.RuleModelToResponse(rule))
+		})
+	})
+}
+
+func TestUpdateRule(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	t.Run("Update another user's rule", func(t *testing.T) {
+		repoSpy.On("RuleGet", uint(1)).Return(&model.Rule{UserID: userID + 1}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateRuleRequest(1, Patch{"name": "mine now"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Update non-existent rule", func(t *testing.T) {
+		repoSpy.On("RuleGet", uint(2)).Return(nil, repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateRuleRequest(2, Patch{"name": "missing"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	newRule := func() *model.Rule {
+		return &model.Rule{
+			Model:               model.Model{ID: 3},
+			UserID:              userID,
+			Name:                "Lidl",
+			Enabled:             true,
+			DescriptionContains: "lidl",
+			SetCategory:         "groceries",
+			HitCount:            12,
+		}
+	}
+
+	t.Run("Remove all conditions of a rule", func(t *testing.T) {
+		repoSpy.On("RuleGet", uint(3)).Return(newRule(), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateRuleRequest(3, Patch{"conditions": nil}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorRuleNoCondition.Message)
+	})
+
+	t.Run("Disable a rule keeping its definition", func(t *testing.T) {
+		patched := &model.Rule{
+			UserID:              userID,
+			Name:                "Lidl",
+			DescriptionContains: "lidl",
+			SetCategory:         "groceries",
+			AddTags:             model.Tags{},
+		}
+		updated := newRule()
+		updated.Enabled = false
+
+		repoSpy.On("RuleGet", uint(3)).Return(newRule(), nil).Once()
+		repoSpy.On("RuleUpdate", uint(3), patched).Return(updated, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateRuleRequest(3, Patch{"enabled": false}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, handlers.RuleModelToResponse(updated))
+	})
+
+	t.Run("Replace one condition and keep the others", func(t *testing.T) {
+		patched := &model.Rule{
+			UserID:           userID,
+			Name:             "Lidl",
+			Enabled:          true,
+			DescriptionRegex: "^lidl",
+			SetCategory:      "groceries",
+			AddTags:          model.Tags{},
+		}
+		updated := newRule()
+		updated.DescriptionContains = ""
+		updated.DescriptionRegex = "^lidl"
+
+		repoSpy.On("RuleGet", uint(3)).Return(newRule(), nil).Once()
+		repoSpy.On("RuleUpdate", uint(3), patched).Return(updated, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateRuleRequest(3, Patch{"conditions": Patch{"description_contains": nil, "description_regex": "^lidl"}}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, handlers.RuleModelToResponse(updated))
+	})
+}
+
+func TestDeleteRule(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	repoSpy.On("RuleGet", uint(1)).Return(&model.Rule{UserID: userID}, nil).Once()
+	repoSpy.On("RuleDelete", uint(1)).Return(nil).Once()
+
+	res := httptest.NewRecorder()
+	req := NewDeleteRuleRequest(1, token)
+
+	r.ServeHTTP(res, req)
+
+	AssertStatusCode(t, res, http.StatusNoContent)
+}
+
+func TestListRules(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	rules := []*model.Rule{
+		{Model: model.Model{ID: 2}, Name: "Uber", Priority: 10, DescriptionContains: "uber", SetCategory: "transport"},
+		{Model: model.Model{ID: 1}, Name: "Lidl", DescriptionContains: "lidl", SetCategory: "groceries"},
+	}
+
+	repoSpy.On("RuleList", userID).Return(rules, nil).Once()
+
+	res := httptest.NewRecorder()
+	req := NewListRulesRequest(token)
+
+	r.ServeHTTP(res, req)
+
+	AssertStatusCode(t, res, http.StatusOK)
+	AssertResponseBody(t, res, &RuleListResponse{
+		Count:   2,
+		Entries: []*handlers.Rule{handlers.RuleModelToResponse(rules[0]), handlers.RuleModelToResponse(rules[1])},
+	})
+}
+
+func TestApplyRules(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	partyID := uint(9)
+	rules := func() []*model.Rule {
+		return []*model.Rule{{
+			Model:            model.Model{ID: 5},
+			UserID:           userID,
+			Enabled:          true,
+			DescriptionRegex: `^POS \d+ (LIDL)`,
+			SetDescription:   "${1}",
+			SetPartyID:       &partyID,
+			SetCategory:      "groceries",
+		}}
+	}
+	transactions := func() []*model.Transaction {
+		return []*model.Transaction{
+			{Model: model.Model{ID: 1}, UserID: userID, PartyID: 1, Description: "POS 1234 LIDL SAGT 56"},
+			{Model: model.Model{ID: 2}, UserID: userID, PartyID: partyID, Category: "groceries", Description: "LIDL"},
+			{Model: model.Model{ID: 3}, UserID: userID, PartyID: 1, Description: "Rent"},
+			{Model: model.Model{ID: 4}, UserID: userID + 1, PartyID: 1, Description: "POS 99 LIDL household member"},
+		}
+	}
+	before := transactions()[0]
+	after := transactions()[0]
+	after.PartyID = partyID
+	after.Category = "groceries"
+	after.Description = "LIDL"
+
+	wantChanges := []*handlers.RuleChange{{
+		TransactionID: 1,
+		Rules:         []uint{5},
+		Before:        handlers.TransactionModelToResponse(before),
+		After:         handlers.TransactionModelToResponse(after),
+	}}
+
+	t.Run("Dry run", func(t *testing.T) {
+		repoSpy.On("RuleList", userID).Return(rules(), nil).Once()
+		repoSpy.On("TransactionList", userID).Return(transactions(), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewApplyRulesRequest("dry_run=true", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.ApplyRulesResult{DryRun: true, Matched: 1, Changed: 1, Changes: wantChanges})
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		repoSpy.On("RuleList", userID).Return(rules(), nil).Once()
+		repoSpy.On("TransactionList", userID).Return(transactions(), nil).Once()
+		repoSpy.On("TransactionApplyRules", mock.MatchedBy(func(ts []*model.Transaction) bool {
+			return len(ts) == 1 && ts[0].ID == 1 && ts[0].PartyID == partyID
+		}), map[uint]uint{5: 1}).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewApplyRulesRequest("", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.ApplyRulesResult{Matched: 1, Changed: 1, Changes: wantChanges})
+		repoSpy.AssertExpectations(t)
+	})
+
+	t.Run("Apply again without changes", func(t *testing.T) {
+		repoSpy.On("RuleList", userID).Return([]*model.Rule{{
+			Model:               model.Model{ID: 6},
+			UserID:              userID,
+			Enabled:             true,
+			DescriptionContains: "LIDL",
+			SetPartyID:          &partyID,
+			SetCategory:         "groceries",
+		}}, nil).Once()
+		repoSpy.On("TransactionList", userID).Return([]*model.Transaction{after}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewApplyRulesRequest("", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.ApplyRulesResult{Matched: 1, Changed: 0, Changes: []*handlers.RuleChange{}})
+		repoSpy.AssertNumberOfCalls(t, "TransactionApplyRules", 1)
+	})
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"errors"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/router"
+	"expense-api/internal/search"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewSearchRequest("q=hotel", token)
+		invalidTokenReq := NewSearchRequest("q=hotel", token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		badRequests := []struct {
+			desc    string
+			query   string
+			message string
+		}{
+			{"Missing query", "", handlers.ErrorSearchQuery.Message},
+			{"Query without words", "q=%26%7C%21", handlers.ErrorSearchQuery.Message},
+			{"Unknown type", "q=hotel&type=transaction,tag", handlers.ErrorSearchType.Message},
+			{"Limit too high", "q=hotel&limit=1000", handlers.ErrorSearchLimit.Message},
+			{"Limit not a number", "q=hotel&limit=ten", handlers.ErrorSearchLimit.Message},
+			{"Malformed date", "q=hotel&from=03.05.2021", handlers.ErrorInvalidDate.Message},
+		}
+
+		for _, tc := range badRequests {
+			t.Run(tc.desc, func(t *testing.T) {
+				res := httptest.NewRecorder()
+				req := NewSearchRequest(tc.query, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Repository failure", func(t *testing.T) {
+			query := &search.Query{TSQuery: "hotel:*", Types: search.Types, Limit: 20}
+			repoSpy.On("Search", userID, query).Return(nil, errors.New("db down")).Once()
+
+			res := httptest.NewRecorder()
+			req := NewSearchRequest("q=hotel", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusInternalServerError)
+		})
+
+		t.Run("Search everything", func(t *testing.T) {
+			date := time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC)
+			query := &search.Query{TSQuery: "hotel:* & lisb:*", Types: search.Types, Limit: 20}
+			results := []*search.Result{
+				{Type: search.TypeTransaction, ID: 4, Title: "Hotel in Lisbon", Headline: "<mark>Hotel</mark> in <mark>Lisbon</mark>", Rank: 0.6, Date: date},
+				{Type: search.TypeParty, ID: 2, Title: "Lisboa Hotels", Headline: "<mark>Lisboa</mark> <mark>Hotels</mark>", Rank: 0.3, Date: date},
+			}
+			repoSpy.On("Search", userID, query).Return(results, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewSearchRequest("q=Hotel+Lisb", token)
+
+			r.ServeHTTP(res, req)
+
+			want := &SearchResultListResponse{
+				Count: 2,
+				Entries: []*handlers.SearchResult{
+					handlers.SearchResultToResponse(results[0]),
+					handlers.SearchResultToResponse(results[1]),
+				},
+			}
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, want)
+		})
+
+		t.Run("Search transactions in a period", func(t *testing.T) {
+			query := &search.Query{
+				TSQuery: "hotel:*",
+				Types:   []string{search.TypeTransaction, search.TypeWallet},
+				From:    time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC),
+				Limit:   5,
+			}
+			repoSpy.On("Search", userID, query).Return([]*search.Result{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewSearchRequest("q=hotel&type=transaction,wallet&from=2021-05-01&to=2021-05-31&limit=5", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &SearchResultListResponse{Count: 0, Entries: []*handlers.SearchResult{}})
+		})
+	})
+}
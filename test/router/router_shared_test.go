@@ -0,0 +1,282 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func newShare(payerID, userID uint, currency, amount string) *model.TransactionShare {
+	return &model.TransactionShare{
+		UserID: userID,
+		Amount: decimal.RequireFromString(amount),
+		Transaction: model.Transaction{
+			UserID: payerID,
+			Wallet: model.Wallet{Currency: currency},
+		},
+	}
+}
+
+func newUser(id uint, email string) *model.User {
+	user := &model.User{Email: email}
+	user.ID = id
+	return user
+}
+
+func TestGetSharedBalances(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewGetSharedBalancesRequest(token)
+		invalidTokenReq := NewGetSharedBalancesRequest(token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Get balances with every counterpart", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		repoSpy.On("TransactionShareListByUsers", []uint{userID}).Return([]*model.TransactionShare{
+			newShare(userID, userID, "EUR", "30"),
+			newShare(userID, 2, "EUR", "30"),
+			newShare(3, userID, "USD", "12.5"),
+		}, nil).Once()
+		repoSpy.On("SettlementListByUsers", []uint{userID}).Return([]*model.Settlement{
+			{FromUserID: 2, ToUserID: userID, Currency: "EUR", Amount: decimal.NewFromInt(10)},
+		}, nil).Once()
+		repoSpy.On("UserListByIDs", []uint{2, 3}).Return([]*model.User{
+			newUser(2, "jane@doe.com"),
+			newUser(3, "alex@doe.com"),
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetSharedBalancesRequest(token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &SharedBalanceListResponse{
+			Count: 2,
+			Entries: []*handlers.SharedBalance{
+				{UserID: 2, Email: "jane@doe.com", Currency: "EUR", Amount: decimal.NewFromInt(20)},
+				{UserID: 3, Email: "alex@doe.com", Currency: "USD", Amount: decimal.RequireFromString("-12.5")},
+			},
+		}
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, expected)
+	})
+}
+
+func TestSimplifySharedDebts(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Simplify the debts of everybody the user shares expenses with", func(t *testing.T) {
+		repoSpy.On("TransactionShareListByUsers", []uint{userID}).Return([]*model.TransactionShare{
+			newShare(userID, 2, "EUR", "10"),
+			newShare(3, userID, "EUR", "10"),
+		}, nil).Once()
+		repoSpy.On("SettlementListByUsers", []uint{userID}).Return([]*model.Settlement{}, nil).Once()
+		repoSpy.On("TransactionShareListByUsers", []uint{1, 2, 3}).Return([]*model.TransactionShare{
+			newShare(userID, 2, "EUR", "10"),
+			newShare(3, userID, "EUR", "10"),
+			newShare(2, 3, "EUR", "4"),
+			newShare(2, 4, "EUR", "50"),
+		}, nil).Once()
+		repoSpy.On("SettlementListByUsers", []uint{1, 2, 3}).Return([]*model.Settlement{}, nil).Once()
+		repoSpy.On("UserListByIDs", []uint{1, 2, 3}).Return([]*model.User{
+			newUser(1, "john@doe.com"),
+			newUser(2, "jane@doe.com"),
+			newUser(3, "alex@doe.com"),
+		}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewSimplifySharedDebtsRequest(token)
+
+		r.ServeHTTP(res, req)
+
+		expected := &PaymentListResponse{
+			Count: 1,
+			Entries: []*handlers.Payment{
+				{FromUserID: 2, FromEmail: "jane@doe.com", ToUserID: 3, ToEmail: "alex@doe.com", Currency: "EUR", Amount: decimal.NewFromInt(6)},
+			},
+		}
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, expected)
+	})
+}
+
+func TestCreateSettlement(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		settlement := &handlers.Settlement{}
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateSettlementRequest(settlement, token)
+		invalidTokenReq := NewCreateSettlementRequest(settlement, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		testCases := []struct {
+			desc       string
+			settlement *handlers.Settlement
+			wantError  *handlers.ErrorMessage
+		}{
+			{
+				desc:       "Settle up with yourself",
+				settlement: &handlers.Settlement{FromUserID: userID, Amount: decimal.NewFromInt(10), Currency: "EUR"},
+				wantError:  handlers.ErrorSettleWithSelf,
+			},
+			{
+				desc:       "Settle up with a negative amount",
+				settlement: &handlers.Settlement{FromUserID: 2, Amount: decimal.NewFromInt(-10), Currency: "EUR"},
+				wantError:  handlers.ErrorSettlementAmount,
+			},
+			{
+				desc:       "Settle up in an unknown currency",
+				settlement: &handlers.Settlement{FromUserID: 2, Amount: decimal.NewFromInt(10), Currency: "XYZ"},
+				wantError:  handlers.ErrorInvalidCurrency,
+			},
+			{
+				desc:       "Settle up with an amount that is too precise for the currency",
+				settlement: &handlers.Settlement{FromUserID: 2, Amount: decimal.RequireFromString("10.5"), Currency: "JPY"},
+				wantError:  handlers.ErrorAmountPrecision,
+			},
+		}
+
+		for _, tC := range testCases {
+			t.Run(tC.desc, func(t *testing.T) {
+				res := httptest.NewRecorder()
+				req := NewCreateSettlementRequest(tC.settlement, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tC.wantError.Message)
+			})
+		}
+
+		t.Run("Settle up with a user outside the current user's households", func(t *testing.T) {
+			repoSpy.On("HouseholdSharedByUsers", userID, uint(2)).Return(false, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateSettlementRequest(&handlers.Settlement{FromUserID: 2, Amount: decimal.NewFromInt(10), Currency: "EUR"}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorUserNotFound.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Settle up with valid arguments", func(t *testing.T) {
+			settlement := &handlers.Settlement{FromUserID: 2, Amount: decimal.NewFromInt(10), Currency: "eur", Note: "rent"}
+
+			repoSpy.On("HouseholdSharedByUsers", userID, uint(2)).Return(true, nil).Once()
+			repoSpy.On("SettlementCreate", mock.Anything).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateSettlementRequest(settlement, token)
+
+			r.ServeHTTP(res, req)
+
+			expected := &handlers.Settlement{FromUserID: 2, ToUserID: userID, Amount: decimal.NewFromInt(10), Currency: "EUR", Note: "rent"}
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, expected)
+		})
+	})
+}
+
+func TestDeleteSettlement(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Delete a settlement paid by the user", func(t *testing.T) {
+		id := uint(4)
+
+		repoSpy.On("SettlementGet", id).Return(&model.Settlement{FromUserID: userID, ToUserID: 2}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteSettlementRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Delete a settlement received from another user", func(t *testing.T) {
+		id := uint(4)
+
+		repoSpy.On("SettlementGet", id).Return(&model.Settlement{FromUserID: 2, ToUserID: userID}, nil).Once()
+		repoSpy.On("SettlementDelete", id).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteSettlementRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+}
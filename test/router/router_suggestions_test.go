@@ -0,0 +1,196 @@
+package router
+
+import (
+	"expense-api/internal/duplicates"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSuggestCategories(t *testing.T) {
+	repoSpy := &spies.RepositorySpy{}
+	repoSpy.On("WithActor", mock.Anything).Return(repoSpy).Maybe()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewSuggestCategoriesRequest("q=lidl", token)
+		invalidTokenReq := NewSuggestCategoriesRequest("q=lidl", token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		badRequests := []struct {
+			desc    string
+			query   string
+			message string
+		}{
+			{"Missing text", "amount=-20", handlers.ErrorSuggestionText.Message},
+			{"Amount not a number", "q=lidl&amount=twenty", handlers.ErrorSuggestionAmount.Message},
+			{"Invalid wallet", "q=lidl&wallet_id=-1", handlers.ErrorBadWalletID.Message},
+			{"Too many suggestions", "q=lidl&k=21", handlers.ErrorSuggestionLimit.Message},
+		}
+
+		for _, tc := range badRequests {
+			t.Run(tc.desc, func(t *testing.T) {
+				res := httptest.NewRecorder()
+				req := NewSuggestCategoriesRequest(tc.query, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		lidl := &model.Transaction{
+			Model:       model.Model{ID: 1},
+			UserID:      userID,
+			WalletID:    1,
+			PartyID:     2,
+			Timestamp:   time.Date(2021, 11, 19, 0, 0, 0, 0, time.UTC),
+			Amount:      decimal.RequireFromString("-23.40"),
+			Description: "POS 1234 LIDL SAGT 56",
+			Category:    "groceries",
+			Status:      model.TransactionUncleared,
+		}
+		history := []*model.Transaction{
+			lidl,
+			{Model: model.Model{ID: 5}, UserID: userID, WalletID: 1, Amount: decimal.RequireFromString("-41.10"), Description: "POS 9876 LIDL MITTE", Category: "groceries"},
+			{Model: model.Model{ID: 2}, UserID: userID, WalletID: 1, Amount: decimal.RequireFromString("-12.50"), Description: "UBER *TRIP", Category: "transport"},
+			{Model: model.Model{ID: 3}, UserID: userID, WalletID: 1, Amount: decimal.RequireFromString("-80"), Description: "DB Fernverkehr", Category: "transport"},
+			// Other household members' transactions don't teach the user's classifier
+			{Model: model.Model{ID: 4}, UserID: userID + 1, WalletID: 1, Amount: decimal.RequireFromString("-9"), Description: "LIDL", Category: "snacks"},
+		}
+
+		suggest := func(t *testing.T, query string) *CategorySuggestionListResponse {
+			t.Helper()
+
+			res := httptest.NewRecorder()
+			req := NewSuggestCategoriesRequest(query, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			var got CategorySuggestionListResponse
+			ParseJSONtoResponse(t, res, &got)
+			return &got
+		}
+
+		t.Run("Suggest from history", func(t *testing.T) {
+			repoSpy.On("TransactionStamp", userID).Return("stamp-1", nil).Once()
+			repoSpy.On("TransactionList", userID).Return(history, nil).Once()
+
+			got := suggest(t, "q=LIDL+Berlin&amount=-30&wallet_id=1&k=5")
+
+			if got.Count != 2 || got.Entries[0].Category != "groceries" {
+				t.Errorf("expected groceries first out of 2 categories, got %+v and %+v", got.Entries[0], got.Entries[1])
+			}
+		})
+
+		t.Run("Correcting a category is learnt without training again", func(t *testing.T) {
+			corrected := *lidl
+			corrected.Category = "supermarket"
+
+			repoSpy.On("TransactionGet", lidl.ID).Return(lidl, nil).Once()
+			repoSpy.On("TransactionStamp", userID).Return("stamp-1", nil).Once()
+			repoSpy.On("TransactionUpdate", lidl.ID, mock.Anything).Return(&corrected, nil).Once()
+			repoSpy.On("TransactionStamp", userID).Return("stamp-2", nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionRequest(lidl.ID, Patch{"category": corrected.Category}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+
+			repoSpy.On("TransactionStamp", userID).Return("stamp-2", nil).Once()
+
+			got := suggest(t, "q=LIDL+Berlin&k=5")
+
+			if got.Count != 3 || got.Entries[1].Category != "supermarket" {
+				t.Errorf("expected supermarket second out of 3 categories, got %+v", got.Entries[1])
+			}
+			repoSpy.AssertNumberOfCalls(t, "TransactionList", 1)
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("A change through another instance trains the classifier again", func(t *testing.T) {
+			corrected := *lidl
+			corrected.Category = "supermarket"
+			moved := *history[3]
+			moved.Category = "travel"
+
+			repoSpy.On("TransactionStamp", userID).Return("stamp-3", nil).Once()
+			repoSpy.On("TransactionList", userID).Return([]*model.Transaction{&corrected, history[1], history[2], &moved, history[4]}, nil).Once()
+
+			got := suggest(t, "q=LIDL+Berlin&k=5")
+
+			if got.Count != 4 || got.Entries[1].Category != "supermarket" {
+				t.Errorf("expected supermarket second out of 4 categories, got %+v", got.Entries)
+			}
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("The classifier is kept while the history doesn't change", func(t *testing.T) {
+			repoSpy.On("TransactionStamp", userID).Return("stamp-3", nil).Once()
+
+			got := suggest(t, "q=LIDL+Berlin&k=5")
+
+			if got.Count != 4 || got.Entries[1].Category != "supermarket" {
+				t.Errorf("expected supermarket second out of 4 categories, got %+v", got.Entries)
+			}
+			repoSpy.AssertNumberOfCalls(t, "TransactionList", 2)
+		})
+
+		t.Run("New transactions without a category come with suggestions", func(t *testing.T) {
+			repoSpy.On("TransactionStamp", userID).Return("stamp-3", nil).Once()
+			repoSpy.On("TransactionStamp", userID).Return("stamp-4", nil).Twice()
+			wallet := &model.Wallet{UserID: userID}
+			party := &model.Party{UserID: userID}
+
+			repoSpy.On("WalletGet", uint(1)).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+			repoSpy.On("PartyGet", uint(2)).Return(party, nil).Once()
+			repoSpy.On("TransactionListDuplicateCandidates", mock.Anything, duplicates.Window).Return([]*model.Transaction{}, nil).Once()
+			repoSpy.On("TransactionCreate", mock.Anything).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionRequest(&handlers.Transaction{
+				Amount:      decimal.RequireFromString("-15"),
+				Description: "UBER *TRIP Lisbon",
+				WalletID:    1,
+				PartyID:     2,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			var got handlers.Transaction
+			ParseJSONtoResponse(t, res, &got)
+			if len(got.Suggestions) != 3 || got.Suggestions[0].Category != "transport" || got.Suggestions[0].Confidence <= 0.5 {
+				t.Errorf("expected transport to be suggested confidently, got %+v", got.Suggestions[0])
+			}
+		})
+	})
+}
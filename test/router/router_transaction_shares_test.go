@@ -0,0 +1,293 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateTransactionShares(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		id := uint(1)
+		shares := &handlers.TransactionShares{}
+		token := "invalid-token"
+
+		missingTokenReq := NewUpdateTransactionSharesRequest(id, shares, token)
+		invalidTokenReq := NewUpdateTransactionSharesRequest(id, shares, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		id := uint(5)
+		walletID := uint(2)
+		wallet := &model.Wallet{UserID: userID}
+		transaction := &model.Transaction{
+			Amount:   decimal.RequireFromString("-100"),
+			UserID:   userID,
+			WalletID: walletID,
+		}
+		transaction.Version = 7
+
+		payer := &model.User{Email: "john@doe.com"}
+		payer.ID = userID
+		flatmate := &model.User{Email: "jane@doe.com"}
+		flatmate.ID = 2
+		partner := &model.User{Email: "alex@doe.com"}
+		partner.ID = 3
+
+		t.Run("Share a transaction with an unknown method", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{Method: "random"}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorInvalidShareMethod.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share an income", func(t *testing.T) {
+			income := &model.Transaction{Amount: decimal.NewFromInt(100), UserID: userID}
+
+			repoSpy.On("TransactionGet", id).Return(income, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{Method: "equal"}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorShareIncome.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share a transaction with somebody who isn't registered", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("UserGetWithEmail", "nobody@doe.com").Return(nil, repository.ErrorRecordNotFound).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{
+				Method:       "equal",
+				Participants: []*handlers.ShareParticipant{{Email: "nobody@doe.com"}},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorParticipantNotFound.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share a transaction with somebody outside the payer's households", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("UserGetWithEmail", partner.Email).Return(partner, nil).Once()
+			repoSpy.On("HouseholdSharedByUsers", userID, partner.ID).Return(false, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{
+				Method:       "equal",
+				Participants: []*handlers.ShareParticipant{{Email: partner.Email}},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorParticipantNotFound.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share a transaction only with yourself", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("UserGet", userID).Return(payer, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{
+				Method:       "equal",
+				Participants: []*handlers.ShareParticipant{{UserID: userID}},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorNoOtherParticipant.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share a transaction by percentages that don't add up to 100", func(t *testing.T) {
+			sixty := decimal.NewFromInt(60)
+			thirty := decimal.NewFromInt(30)
+
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("UserGet", userID).Return(payer, nil).Once()
+			repoSpy.On("UserGet", flatmate.ID).Return(flatmate, nil).Once()
+			repoSpy.On("HouseholdSharedByUsers", userID, flatmate.ID).Return(true, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{
+				Method: "percentage",
+				Participants: []*handlers.ShareParticipant{
+					{UserID: userID, Percentage: &sixty},
+					{UserID: flatmate.ID, Percentage: &thirty},
+				},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorPercentagesNot100.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share a transaction with exact amounts that don't add up", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("UserGetWithEmail", flatmate.Email).Return(flatmate, nil).Once()
+			repoSpy.On("UserGetWithEmail", partner.Email).Return(partner, nil).Once()
+			repoSpy.On("HouseholdSharedByUsers", userID, flatmate.ID).Return(true, nil).Once()
+			repoSpy.On("HouseholdSharedByUsers", userID, partner.ID).Return(true, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{
+				Method: "exact",
+				Participants: []*handlers.ShareParticipant{
+					{Email: flatmate.Email, Amount: decimal.NewFromInt(60)},
+					{Email: partner.Email, Amount: decimal.NewFromInt(30)},
+				},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorSharesMismatch.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Share a transaction equally", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("UserGet", userID).Return(payer, nil).Once()
+			repoSpy.On("UserGetWithEmail", flatmate.Email).Return(flatmate, nil).Once()
+			repoSpy.On("UserGetWithEmail", partner.Email).Return(partner, nil).Once()
+			repoSpy.On("HouseholdSharedByUsers", userID, flatmate.ID).Return(true, nil).Once()
+			repoSpy.On("HouseholdSharedByUsers", userID, partner.ID).Return(true, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("TransactionShareReplace", id, uint(7), mock.Anything).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{
+				Method: "equal",
+				Participants: []*handlers.ShareParticipant{
+					{UserID: userID},
+					{Email: flatmate.Email},
+					{Email: partner.Email},
+				},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			expected := &handlers.TransactionShares{
+				Method: "equal",
+				Participants: []*handlers.ShareParticipant{
+					{UserID: userID, Email: payer.Email, Amount: decimal.RequireFromString("33.34")},
+					{UserID: flatmate.ID, Email: flatmate.Email, Amount: decimal.RequireFromString("33.33")},
+					{UserID: partner.ID, Email: partner.Email, Amount: decimal.RequireFromString("33.33")},
+				},
+			}
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("Share a transaction with an outdated If-Match", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionSharesRequest(id, &handlers.TransactionShares{Method: "equal"}, token)
+			req.Header.Set("If-Match", `"6"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
+		})
+	})
+}
+
+func TestDeleteTransactionShares(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Stop sharing a transaction", func(t *testing.T) {
+		id := uint(5)
+
+		repoSpy.On("TransactionGet", id).Return(&model.Transaction{Model: model.Model{Version: 2}, UserID: userID}, nil).Once()
+		repoSpy.On("TransactionShareReplace", id, uint(2), []*model.TransactionShare(nil)).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteTransactionSharesRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+
+	t.Run("Stop sharing a transaction that was changed in the meantime", func(t *testing.T) {
+		id := uint(5)
+
+		repoSpy.On("TransactionGet", id).Return(&model.Transaction{Model: model.Model{Version: 2}, UserID: userID}, nil).Once()
+		repoSpy.On("TransactionShareReplace", id, uint(2), []*model.TransactionShare(nil)).Return(repository.ErrorVersionConflict).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteTransactionSharesRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusConflict)
+		AssertErrorMessage(t, res, handlers.ErrorConcurrentUpdate.Message)
+	})
+}
@@ -0,0 +1,264 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateTransactionSplits(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		id := uint(1)
+		splits := &handlers.TransactionSplits{}
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateTransactionSplitsRequest(id, splits, token)
+		invalidTokenReq := NewCreateTransactionSplitsRequest(id, splits, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		id := uint(5)
+		walletID := uint(2)
+		wallet := &model.Wallet{UserID: userID}
+		transaction := &model.Transaction{
+			Amount:   decimal.RequireFromString("-50"),
+			UserID:   userID,
+			WalletID: walletID,
+		}
+		transaction.Version = 4
+
+		t.Run("Split a transaction with a single line", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{
+				Splits: []*handlers.TransactionSplit{
+					{Amount: decimal.RequireFromString("-50")},
+				},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorTooFewSplits.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Split a transaction with lines that don't add up", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{}, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{
+				Splits: []*handlers.TransactionSplit{
+					{Amount: decimal.RequireFromString("-30")},
+					{Amount: decimal.RequireFromString("-15.5")},
+				},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			difference := decimal.RequireFromString("4.5")
+			expected := &handlers.SplitValidationError{
+				Message:    "splits add up to -45.5, but the transaction amount is -50 (off by 4.5)",
+				Difference: &difference,
+			}
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("Split a transaction with invalid lines", func(t *testing.T) {
+			partyID := uint(9)
+
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{}, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("PartyGet", partyID).Return(&model.Party{UserID: userID + 1}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{
+				Splits: []*handlers.TransactionSplit{
+					{Amount: decimal.RequireFromString("-60")},
+					{Amount: decimal.RequireFromString("10"), PartyID: partyID},
+				},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			expected := &handlers.SplitValidationError{
+				Message: handlers.ErrorInvalidSplits.Message,
+				Lines: []handlers.SplitLineError{
+					{Line: 2, Message: "amount 10 must have the same sign as the transaction amount -50"},
+					{Line: 2, Message: "party 9 belongs to another user"},
+				},
+			}
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("Split a transaction that is already split", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{{}, {}}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorTransactionAlreadySplit.Message
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Split a transaction with valid lines", func(t *testing.T) {
+			splits := []*handlers.TransactionSplit{
+				{Amount: decimal.RequireFromString("-35"), Category: "groceries"},
+				{Amount: decimal.RequireFromString("-15"), Category: "household"},
+			}
+
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{}, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("TransactionSplitReplace", id, uint(4), mock.Anything).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{Splits: splits}, token)
+
+			r.ServeHTTP(res, req)
+
+			expected := &TransactionSplitListResponse{
+				Count:   len(splits),
+				Entries: splits,
+			}
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("Split a transaction that was changed in the meantime", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{}, nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("TransactionSplitReplace", id, uint(4), mock.Anything).Return(repository.ErrorVersionConflict).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{Splits: []*handlers.TransactionSplit{
+				{Amount: decimal.RequireFromString("-35")},
+				{Amount: decimal.RequireFromString("-15")},
+			}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorConcurrentUpdate.Message)
+		})
+
+		t.Run("Split a transaction with an outdated If-Match", func(t *testing.T) {
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionSplitsRequest(id, &handlers.TransactionSplits{}, token)
+			req.Header.Set("If-Match", `"3"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
+		})
+	})
+}
+
+func TestUpdateTransactionSplits(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Replace splits of a transaction that isn't split", func(t *testing.T) {
+		id := uint(5)
+
+		repoSpy.On("TransactionGet", id).Return(&model.Transaction{UserID: userID}, nil).Once()
+		repoSpy.On("TransactionSplitList", id).Return([]*model.TransactionSplit{}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateTransactionSplitsRequest(id, &handlers.TransactionSplits{}, token)
+
+		r.ServeHTTP(res, req)
+
+		wantErrorMessage := handlers.ErrorTransactionNotSplit.Message
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+		AssertErrorMessage(t, res, wantErrorMessage)
+	})
+}
+
+func TestDeleteTransactionSplits(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	claims := auth.CustomClaims{
+		ID: userID,
+	}
+	jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+	t.Run("Delete splits of a transaction", func(t *testing.T) {
+		id := uint(5)
+
+		repoSpy.On("TransactionGet", id).Return(&model.Transaction{Model: model.Model{Version: 2}, UserID: userID}, nil).Once()
+		repoSpy.On("TransactionSplitReplace", id, uint(2), []*model.TransactionSplit(nil)).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewDeleteTransactionSplitsRequest(id, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNoContent)
+	})
+}
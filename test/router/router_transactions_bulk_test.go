@@ -0,0 +1,238 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBulkTransactions(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewBulkTransactionsRequest(map[string]interface{}{}, token)
+		invalidTokenReq := NewBulkTransactionsRequest(map[string]interface{}{}, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		timestamp := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+		wallet := &model.Wallet{Currency: "EUR", UserID: userID}
+		wallet.ID = 1
+		otherWallet := &model.Wallet{Currency: "EUR", UserID: userID}
+		otherWallet.ID = 4
+		foreignWallet := &model.Wallet{Currency: "EUR", UserID: userID + 1}
+		foreignWallet.ID = 6
+		party := &model.Party{UserID: userID}
+		party.ID = 5
+
+		newCurrent := func(id uint) *model.Transaction {
+			current := &model.Transaction{
+				Timestamp: timestamp,
+				Amount:    decimal.NewFromInt(20),
+				Status:    model.TransactionCleared,
+				WalletID:  wallet.ID,
+				PartyID:   party.ID,
+				UserID:    userID,
+			}
+			current.ID = id
+			current.Version = 3
+			return current
+		}
+
+		created := &model.Transaction{
+			Timestamp: timestamp,
+			Amount:    decimal.RequireFromString("10"),
+			Status:    model.TransactionUncleared,
+			WalletID:  wallet.ID,
+			PartyID:   party.ID,
+			UserID:    userID,
+		}
+		createdResponse := handlers.TransactionModelToResponse(created)
+		createdResponse.Suggestions = []*handlers.CategorySuggestion{{Category: "refunds", Confidence: 1}}
+		moved := &model.Transaction{
+			Timestamp: timestamp,
+			Amount:    decimal.RequireFromString("20"),
+			Tags:      model.Tags{},
+			Status:    model.TransactionCleared,
+			WalletID:  otherWallet.ID,
+			PartyID:   party.ID,
+			UserID:    userID,
+		}
+		moved.Version = 3
+
+		operations := []map[string]interface{}{
+			{"op": "create", "transaction": map[string]interface{}{"wallet_id": 1, "party_id": 5, "amount": "10", "timestamp": timestamp}},
+			{"op": "update", "id": 2, "version": 3, "transaction": Patch{"wallet_id": 4}},
+			{"op": "delete", "id": 3},
+		}
+
+		t.Run("Bulk request with an unknown mode", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewBulkTransactionsRequest(map[string]interface{}{"mode": "some", "operations": operations}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidBulkMode.Message)
+		})
+
+		t.Run("Bulk request without operations", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewBulkTransactionsRequest(map[string]interface{}{"operations": []interface{}{}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorBulkOperationCount.Message)
+		})
+
+		t.Run("Apply all operations at once", func(t *testing.T) {
+			repoSpy.On("HouseholdListByUser", userID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("TransactionListByIDs", []uint{2, 3}).Return([]*model.Transaction{newCurrent(2), newCurrent(3)}, nil).Once()
+			repoSpy.On("WalletListByIDs", []uint{1, 4}).Return([]*model.Wallet{wallet, otherWallet}, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+			repoSpy.On("PartyListByIDs", []uint{5}).Return([]*model.Party{party}, nil).Once()
+			repoSpy.On("TransactionBulk", []*repository.TransactionOperation{
+				{Kind: repository.OperationCreate, Transaction: created},
+				{Kind: repository.OperationUpdate, ID: 2, Transaction: moved},
+				{Kind: repository.OperationDelete, ID: 3},
+			}).Return(-1, nil).Once()
+			// The created transaction has no category, the user's classifier is trained to suggest one
+			repoSpy.On("TransactionList", userID).Return([]*model.Transaction{
+				{UserID: userID, WalletID: wallet.ID, Amount: decimal.RequireFromString("12"), Category: "refunds"},
+			}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewBulkTransactionsRequest(map[string]interface{}{"operations": operations}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.BulkTransactionsResponse{
+				Mode:      handlers.BulkAllOrNothing,
+				Succeeded: 3,
+				Results: []*handlers.BulkResult{
+					{Index: 0, Op: "create", Status: http.StatusCreated, Transaction: createdResponse},
+					{Index: 1, Op: "update", Status: http.StatusOK, Transaction: handlers.TransactionModelToResponse(moved)},
+					{Index: 2, Op: "delete", Status: http.StatusNoContent},
+				},
+			})
+			repoSpy.AssertExpectations(t)
+		})
+
+		t.Run("Apply nothing if an operation is invalid", func(t *testing.T) {
+			repoSpy.On("HouseholdListByUser", userID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("TransactionListByIDs", []uint{2}).Return([]*model.Transaction{newCurrent(2)}, nil).Once()
+			repoSpy.On("WalletListByIDs", []uint{1, 6}).Return([]*model.Wallet{wallet, foreignWallet}, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+			repoSpy.On("PartyListByIDs", []uint{5}).Return([]*model.Party{party}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewBulkTransactionsRequest(map[string]interface{}{"operations": []map[string]interface{}{
+				operations[0],
+				{"op": "update", "id": 2, "transaction": Patch{"wallet_id": 6}},
+				{"op": "move", "id": 3},
+			}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusUnprocessableEntity)
+			AssertResponseBody(t, res, &handlers.BulkTransactionsResponse{
+				Mode:   handlers.BulkAllOrNothing,
+				Failed: 3,
+				Results: []*handlers.BulkResult{
+					{Index: 0, Op: "create", Status: http.StatusFailedDependency, Error: handlers.ErrorBulkNotApplied},
+					{Index: 1, Op: "update", Status: http.StatusForbidden, Error: handlers.ErrorBadWalletID},
+					{Index: 2, Op: "move", Status: http.StatusBadRequest, Error: handlers.ErrorInvalidBulkOperation},
+				},
+			})
+			// Only the first request was applied
+			repoSpy.AssertNumberOfCalls(t, "TransactionBulk", 1)
+		})
+
+		t.Run("Apply nothing if an operation fails in the database", func(t *testing.T) {
+			current := newCurrent(2)
+
+			repoSpy.On("HouseholdListByUser", userID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("TransactionListByIDs", []uint{2}).Return([]*model.Transaction{current}, nil).Once()
+			repoSpy.On("WalletListByIDs", []uint{1}).Return([]*model.Wallet{wallet}, nil).Once()
+			repoSpy.On("TransactionBulk", mock.Anything).Return(0, repository.ErrorSplitsOutOfBalance).Once()
+
+			res := httptest.NewRecorder()
+			req := NewBulkTransactionsRequest(map[string]interface{}{"operations": []map[string]interface{}{
+				{"op": "update", "id": 2, "transaction": Patch{"amount": "25"}},
+			}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusUnprocessableEntity)
+			AssertResponseBody(t, res, &handlers.BulkTransactionsResponse{
+				Mode:   handlers.BulkAllOrNothing,
+				Failed: 1,
+				Results: []*handlers.BulkResult{
+					{Index: 0, Op: "update", Status: http.StatusConflict, Error: handlers.ErrorSplitsOutOfBalance},
+				},
+			})
+		})
+
+		t.Run("Apply the valid operations on a best effort basis", func(t *testing.T) {
+			reconciled := newCurrent(3)
+			reconciled.Status = model.TransactionReconciled
+
+			repoSpy.On("HouseholdListByUser", userID).Return([]*model.HouseholdMember{}, nil).Once()
+			repoSpy.On("TransactionListByIDs", []uint{3, 9}).Return([]*model.Transaction{reconciled}, nil).Once()
+			repoSpy.On("WalletListByIDs", []uint{1}).Return([]*model.Wallet{wallet}, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+			repoSpy.On("PartyListByIDs", []uint{5}).Return([]*model.Party{party}, nil).Once()
+			repoSpy.On("TransactionCreate", created).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewBulkTransactionsRequest(map[string]interface{}{"mode": handlers.BulkBestEffort, "operations": []map[string]interface{}{
+				operations[0],
+				{"op": "delete", "id": 3},
+				{"op": "delete", "id": 9},
+			}}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.BulkTransactionsResponse{
+				Mode:      handlers.BulkBestEffort,
+				Succeeded: 1,
+				Failed:    2,
+				Results: []*handlers.BulkResult{
+					{Index: 0, Op: "create", Status: http.StatusCreated, Transaction: createdResponse},
+					{Index: 1, Op: "delete", Status: http.StatusConflict, Error: handlers.ErrorTransactionReconciled},
+					{Index: 2, Op: "delete", Status: http.StatusNotFound, Error: handlers.ErrorTransactionNotFound},
+				},
+			})
+		})
+	})
+}
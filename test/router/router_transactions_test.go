@@ -1,6 +1,7 @@
 package router
 
 import (
+	"expense-api/internal/duplicates"
 	"expense-api/internal/handlers"
 	"expense-api/internal/middleware/auth"
 	"expense-api/internal/model"
@@ -13,14 +14,16 @@ import (
 	"time"
 
 	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateTransaction(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		transaction := &handlers.Transaction{}
@@ -54,6 +57,36 @@ func TestCreateTransaction(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusBadRequest)
 		})
 
+		t.Run("Create transaction with an empty tag", func(t *testing.T) {
+			transaction := &handlers.Transaction{
+				Amount: decimal.NewFromInt32(100),
+				Tags:   []string{"holiday", " "},
+			}
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionRequest(transaction, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidTag.Message)
+		})
+
+		t.Run("Create transaction that is already reconciled", func(t *testing.T) {
+			transaction := &handlers.Transaction{
+				Amount: decimal.NewFromInt32(100),
+				Status: model.TransactionReconciled,
+			}
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionRequest(transaction, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorInvalidStatus.Message)
+		})
+
 		t.Run("Create transaction with valid data but missing wallet id", func(t *testing.T) {
 			transaction := &handlers.Transaction{
 				Timestamp: time.Date(2020, 12, 3, 19, 20, 0, 0, time.UTC),
@@ -116,6 +149,30 @@ func TestCreateTransaction(t *testing.T) {
 			AssertErrorMessage(t, res, wantErrorMessage)
 		})
 
+		t.Run("Create transaction with an amount that is too precise for the wallet's currency", func(t *testing.T) {
+			walletID := uint(1)
+			wallet := &model.Wallet{
+				UserID:   userID,
+				Currency: "EUR",
+			}
+			transaction := &handlers.Transaction{
+				Amount:   decimal.RequireFromString("9.999"),
+				WalletID: walletID,
+			}
+
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionRequest(transaction, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorAmountPrecision.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
 		t.Run("Create transaction with valid data but missing party id", func(t *testing.T) {
 			walletID := uint(1)
 			wallet := &model.Wallet{
@@ -128,13 +185,14 @@ func TestCreateTransaction(t *testing.T) {
 			}
 
 			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
 
 			res := httptest.NewRecorder()
 			req := NewCreateTransactionRequest(transaction, token)
 
 			r.ServeHTTP(res, req)
 
-			wantErrorMessage := handlers.ErrorRequiredWalletID.Message
+			wantErrorMessage := handlers.ErrorRequiredPartyID.Message
 
 			AssertStatusCode(t, res, http.StatusBadRequest)
 			AssertErrorMessage(t, res, wantErrorMessage)
@@ -154,6 +212,7 @@ func TestCreateTransaction(t *testing.T) {
 			}
 
 			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
 			repoSpy.On("PartyGet", partyID).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
@@ -184,6 +243,7 @@ func TestCreateTransaction(t *testing.T) {
 			}
 
 			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
 			repoSpy.On("PartyGet", partyID).Return(party, nil).Once()
 
 			res := httptest.NewRecorder()
@@ -209,14 +269,19 @@ func TestCreateTransaction(t *testing.T) {
 			transaction := &model.Transaction{
 				Timestamp: time.Date(2020, 12, 3, 19, 20, 0, 0, time.UTC),
 				Amount:    decimal.NewFromInt32(100),
+				Status:    model.TransactionUncleared,
 				UserID:    userID,
 				WalletID:  walletID,
 				PartyID:   partyID,
 			}
 
 			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
 			repoSpy.On("PartyGet", partyID).Return(party, nil).Once()
+			repoSpy.On("TransactionListDuplicateCandidates", mock.Anything, duplicates.Window).Return([]*model.Transaction{}, nil).Once()
 			repoSpy.On("TransactionCreate", transaction).Return(nil).Once()
+			// The user's category classifier is trained on their first transaction without a category
+			repoSpy.On("TransactionList", userID).Return([]*model.Transaction{}, nil).Once()
 
 			res := httptest.NewRecorder()
 			req := NewCreateTransactionRequest(&handlers.Transaction{
@@ -233,15 +298,93 @@ func TestCreateTransaction(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusCreated)
 			AssertResponseBody(t, res, resBody)
 		})
+
+		t.Run("Create transaction with tags", func(t *testing.T) {
+			walletID := uint(1)
+			wallet := &model.Wallet{UserID: userID}
+			partyID := uint(1)
+			party := &model.Party{UserID: userID}
+			transaction := &model.Transaction{
+				Timestamp: time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC),
+				Amount:    decimal.NewFromInt32(-120),
+				Tags:      model.Tags{"holiday", "lisbon"},
+				Status:    model.TransactionUncleared,
+				UserID:    userID,
+				WalletID:  walletID,
+				PartyID:   partyID,
+			}
+
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return([]*model.Rule{}, nil).Once()
+			repoSpy.On("PartyGet", partyID).Return(party, nil).Once()
+			repoSpy.On("TransactionListDuplicateCandidates", mock.Anything, duplicates.Window).Return([]*model.Transaction{}, nil).Once()
+			repoSpy.On("TransactionCreate", transaction).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionRequest(&handlers.Transaction{
+				Timestamp: transaction.Timestamp,
+				Amount:    transaction.Amount,
+				Tags:      []string{"Holiday", " lisbon", "holiday"},
+				WalletID:  transaction.WalletID,
+				PartyID:   transaction.PartyID,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, handlers.TransactionModelToResponse(transaction))
+		})
+
+		t.Run("Create transaction whose party is set by a rule", func(t *testing.T) {
+			walletID := uint(1)
+			wallet := &model.Wallet{UserID: userID}
+			partyID := uint(3)
+			party := &model.Party{UserID: userID}
+			rules := []*model.Rule{
+				{Model: model.Model{ID: 7}, Enabled: true, DescriptionContains: "lidl", SetPartyID: &partyID, SetCategory: "groceries"},
+			}
+			transaction := &model.Transaction{
+				Timestamp:   time.Date(2021, 5, 3, 10, 0, 0, 0, time.UTC),
+				Amount:      decimal.NewFromInt32(-20),
+				Description: "POS 1234 LIDL SAGT 56",
+				Category:    "groceries",
+				Status:      model.TransactionUncleared,
+				UserID:      userID,
+				WalletID:    walletID,
+				PartyID:     partyID,
+			}
+
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("RuleList", userID).Return(rules, nil).Once()
+			repoSpy.On("PartyGet", partyID).Return(party, nil).Once()
+			repoSpy.On("TransactionListDuplicateCandidates", mock.Anything, duplicates.Window).Return([]*model.Transaction{}, nil).Once()
+			repoSpy.On("TransactionCreate", transaction).Return(nil).Once()
+			repoSpy.On("RuleRecordHits", map[uint]uint{7: 1}).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateTransactionRequest(&handlers.Transaction{
+				Timestamp:   transaction.Timestamp,
+				Amount:      transaction.Amount,
+				Description: transaction.Description,
+				WalletID:    walletID,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, handlers.TransactionModelToResponse(transaction))
+			repoSpy.AssertExpectations(t)
+		})
 	})
 }
 
 func TestGetTransaction(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
@@ -328,19 +471,20 @@ func TestGetTransaction(t *testing.T) {
 }
 
 func TestUpdateTransaction(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
-		transaction := &handlers.Transaction{}
+		patch := Patch{}
 		token := "invalid-token"
 
-		missingTokenReq := NewUpdateTransactionRequest(id, transaction, token)
-		invalidTokenReq := NewUpdateTransactionRequest(id, transaction, token)
+		missingTokenReq := NewUpdateTransactionRequest(id, patch, token)
+		invalidTokenReq := NewUpdateTransactionRequest(id, patch, token)
 
 		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
 		t.Run("Unauthorized test cases", unauthorizedTestCases)
@@ -354,16 +498,47 @@ func TestUpdateTransaction(t *testing.T) {
 		}
 		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
 
-		t.Run("Update non-existent transaction", func(t *testing.T) {
-			id := uint(1)
-			transaction := &handlers.Transaction{
-				Amount: decimal.NewFromInt32(100),
+		id := uint(3)
+		walletID := uint(2)
+		partyID := uint(4)
+		timestamp := time.Date(2020, 12, 3, 19, 20, 0, 0, time.UTC)
+
+		newTransaction := func() *model.Transaction {
+			transaction := &model.Transaction{
+				Timestamp:   timestamp,
+				Amount:      decimal.NewFromInt32(100),
+				Description: "LIDL SAGT DANKE",
+				Category:    "groceries",
+				Tags:        model.Tags{"food"},
+				Status:      model.TransactionUncleared,
+				UserID:      userID,
+				WalletID:    walletID,
+				PartyID:     partyID,
+			}
+			transaction.ID = id
+			return transaction
+		}
+
+		// patched is the transaction the handler passes on to the repository
+		patched := func() *model.Transaction {
+			return &model.Transaction{
+				Timestamp:   timestamp,
+				Amount:      decimal.RequireFromString("100"),
+				Description: "LIDL SAGT DANKE",
+				Category:    "groceries",
+				Tags:        model.Tags{"food"},
+				Status:      model.TransactionUncleared,
+				UserID:      userID,
+				WalletID:    walletID,
+				PartyID:     partyID,
 			}
+		}
 
-			repoSpy.On("TransactionGet", id).Return(nil, repository.ErrorRecordNotFound).Once()
+		t.Run("Update non-existent transaction", func(t *testing.T) {
+			repoSpy.On("TransactionGet", uint(1)).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, transaction, token)
+			req := NewUpdateTransactionRequest(1, Patch{"amount": "100"}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -371,42 +546,55 @@ func TestUpdateTransaction(t *testing.T) {
 		})
 
 		t.Run("Try to update transaction with valid id that belongs to another user", func(t *testing.T) {
-			id := uint(1)
-			transaction := &model.Transaction{
-				Timestamp: time.Date(2020, 12, 3, 19, 20, 0, 0, time.UTC),
-				Amount:    decimal.NewFromInt32(100),
-				UserID:    userID + 1,
-			}
+			transaction := newTransaction()
+			transaction.UserID = userID + 1
 
 			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, &handlers.Transaction{
-				Amount: transaction.Amount.Add(decimal.NewFromInt(100)),
-			}, token)
+			req := NewUpdateTransactionRequest(id, Patch{"amount": "200"}, token)
 
 			r.ServeHTTP(res, req)
 
 			AssertStatusCode(t, res, http.StatusForbidden)
 		})
 
-		t.Run("Change a transaction's wallet ID to one that doesn't exist", func(t *testing.T) {
-			id := uint(1)
-			walletID := uint(3)
+		badPatches := []struct {
+			desc    string
+			patch   Patch
+			message string
+		}{
+			{desc: "Remove the timestamp", patch: Patch{"timestamp": nil}, message: handlers.ErrorRequiredTimestamp.Message},
+			{desc: "Remove the wallet", patch: Patch{"wallet_id": nil}, message: handlers.ErrorRequiredWalletID.Message},
+			{desc: "Remove the party", patch: Patch{"party_id": nil}, message: handlers.ErrorRequiredPartyID.Message},
+			{desc: "Remove the status", patch: Patch{"status": nil}, message: handlers.ErrorInvalidStatus.Message},
+			{desc: "Reconcile the transaction", patch: Patch{"status": model.TransactionReconciled}, message: handlers.ErrorInvalidStatus.Message},
+			{desc: "Add an empty tag", patch: Patch{"tags": []string{"food", " "}}, message: handlers.ErrorInvalidTag.Message},
+			{desc: "Set the amount to something other than a number", patch: Patch{"amount": "a lot"}, message: handlers.ErrorInvalidPatch.Message},
+		}
 
-			transaction := &model.Transaction{
-				UserID: userID,
-			}
+		for _, tc := range badPatches {
+			t.Run(tc.desc, func(t *testing.T) {
+				repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
 
-			updateTransaction := &handlers.Transaction{
-				WalletID: walletID,
-			}
+				res := httptest.NewRecorder()
+				req := NewUpdateTransactionRequest(id, tc.patch, token)
 
-			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
-			repoSpy.On("WalletGet", walletID).Return(nil, repository.ErrorRecordNotFound).Once()
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Change a transaction's wallet ID to one that doesn't exist", func(t *testing.T) {
+			nonExistentWalletID := uint(5)
+
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
+			repoSpy.On("WalletGet", nonExistentWalletID).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, updateTransaction, token)
+			req := NewUpdateTransactionRequest(id, Patch{"wallet_id": nonExistentWalletID}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -417,29 +605,19 @@ func TestUpdateTransaction(t *testing.T) {
 		})
 
 		t.Run("Change a transaction's wallet ID to one that belongs to another user", func(t *testing.T) {
-			id := uint(1)
 			anotherUsersID := uint(199999)
-			anotherUsersWalletID := uint(3)
-
-			transaction := &model.Transaction{
-				UserID: userID,
-			}
-
-			updateTransaction := &handlers.Transaction{
-				WalletID: anotherUsersWalletID,
-			}
+			anotherUsersWalletID := uint(5)
 
 			anotherUsersWallet := &model.Wallet{
 				UserID: anotherUsersID,
 			}
 			anotherUsersWallet.ID = anotherUsersWalletID
 
-			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
 			repoSpy.On("WalletGet", anotherUsersWalletID).Return(anotherUsersWallet, nil).Once()
-			repoSpy.On("TransactionUpdate", id, updateTransaction).Return(nil, repository.ErrorUniqueConstaintViolation).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, updateTransaction, token)
+			req := NewUpdateTransactionRequest(id, Patch{"wallet_id": anotherUsersWalletID}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -450,28 +628,13 @@ func TestUpdateTransaction(t *testing.T) {
 		})
 
 		t.Run("Change a transaction's party ID to one that doesn't exist", func(t *testing.T) {
-			id := uint(1)
-			nonExistentPartyID := uint(3)
-
-			walletID := uint(1)
-			wallet := &model.Wallet{
-				UserID: userID,
-			}
-
-			transaction := &model.Transaction{
-				UserID: userID,
-			}
+			nonExistentPartyID := uint(6)
 
-			updateTransaction := &handlers.Transaction{
-				PartyID: nonExistentPartyID,
-			}
-
-			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
-			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
 			repoSpy.On("PartyGet", nonExistentPartyID).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, updateTransaction, token)
+			req := NewUpdateTransactionRequest(id, Patch{"party_id": nonExistentPartyID}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -482,60 +645,118 @@ func TestUpdateTransaction(t *testing.T) {
 		})
 
 		t.Run("Change a transaction's party ID to one that belongs to another user", func(t *testing.T) {
-			id := uint(1)
-
-			walletID := uint(1)
-			wallet := &model.Wallet{
-				UserID: userID,
-			}
-
 			anotherUsersID := uint(6)
 			anotherUsersPartyID := uint(5)
 			anotherUsersParty := &model.Party{
 				UserID: anotherUsersID,
 			}
 
-			transaction := &model.Transaction{
-				UserID: userID,
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
+			repoSpy.On("PartyGet", anotherUsersPartyID).Return(anotherUsersParty, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionRequest(id, Patch{"party_id": anotherUsersPartyID}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorBadPartyID.Message
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Update transaction with an amount that is too precise for the wallet's currency", func(t *testing.T) {
+			wallet := &model.Wallet{
+				UserID:   userID,
+				Currency: "JPY",
 			}
 
-			updateTransaction := &handlers.Transaction{
-				PartyID: anotherUsersPartyID,
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionRequest(id, Patch{"amount": "100.5"}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorAmountPrecision.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Change the amount of a split transaction", func(t *testing.T) {
+			wallet := &model.Wallet{
+				UserID: userID,
 			}
 
-			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			updateTransaction := patched()
+			updateTransaction.Amount = decimal.RequireFromString("-120")
+
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
 			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
-			repoSpy.On("PartyGet", anotherUsersPartyID).Return(anotherUsersParty, nil).Once()
+			repoSpy.On("TransactionUpdate", id, updateTransaction).Return(nil, repository.ErrorSplitsOutOfBalance).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, updateTransaction, token)
+			req := NewUpdateTransactionRequest(id, Patch{"amount": "-120"}, token)
 
 			r.ServeHTTP(res, req)
 
-			wantErrorMessage := handlers.ErrorBadPartyID.Message
+			wantErrorMessage := handlers.ErrorSplitsOutOfBalance.Message
 
-			AssertStatusCode(t, res, http.StatusForbidden)
+			AssertStatusCode(t, res, http.StatusConflict)
 			AssertErrorMessage(t, res, wantErrorMessage)
 		})
 
-		t.Run("Update existing transaction with valid arguments", func(t *testing.T) {
-			id := uint(3)
-			transaction := &model.Transaction{
-				Amount: decimal.NewFromInt32(100),
+		t.Run("Set the amount of a transaction to 0", func(t *testing.T) {
+			wallet := &model.Wallet{
 				UserID: userID,
 			}
 
-			updateTransaction := &model.Transaction{
-				Amount: transaction.Amount.Add(decimal.NewFromInt(100)),
+			updateTransaction := patched()
+			updateTransaction.Amount = decimal.RequireFromString("0")
+
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("TransactionUpdate", id, updateTransaction).Return(updateTransaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateTransactionRequest(id, Patch{"amount": 0}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.TransactionModelToResponse(updateTransaction))
+		})
+
+		t.Run("Update existing transaction with valid arguments", func(t *testing.T) {
+			newPartyID := uint(7)
+			wallet := &model.Wallet{
+				UserID: userID,
+			}
+			party := &model.Party{
 				UserID: userID,
 			}
 
-			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+			updateTransaction := patched()
+			updateTransaction.Amount = decimal.RequireFromString("200")
+			updateTransaction.Description = ""
+			updateTransaction.Tags = model.Tags{"food", "weekly"}
+			updateTransaction.Status = model.TransactionCleared
+			updateTransaction.PartyID = newPartyID
+
+			repoSpy.On("TransactionGet", id).Return(newTransaction(), nil).Once()
+			repoSpy.On("WalletGet", walletID).Return(wallet, nil).Once()
+			repoSpy.On("PartyGet", newPartyID).Return(party, nil).Once()
 			repoSpy.On("TransactionUpdate", id, updateTransaction).Return(updateTransaction, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateTransactionRequest(id, &handlers.Transaction{
-				Amount: updateTransaction.Amount,
+			req := NewUpdateTransactionRequest(id, Patch{
+				"amount":      "200",
+				"description": nil,
+				"tags":        []string{"Food", "weekly"},
+				"status":      model.TransactionCleared,
+				"party_id":    newPartyID,
 			}, token)
 
 			r.ServeHTTP(res, req)
@@ -549,11 +770,12 @@ func TestUpdateTransaction(t *testing.T) {
 }
 
 func TestDeleteTransaction(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
@@ -621,16 +843,39 @@ func TestDeleteTransaction(t *testing.T) {
 			r.ServeHTTP(res, req)
 
 			AssertStatusCode(t, res, http.StatusNoContent)
+			// The attachments stay until the transaction is purged from the trash
+			blobStoreSpy.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		})
+
+		t.Run("Delete transaction with an outdated If-Match", func(t *testing.T) {
+			id := uint(2)
+			transaction := &model.Transaction{
+				Amount: decimal.NewFromInt32(100),
+				UserID: userID,
+			}
+			transaction.Version = 4
+
+			repoSpy.On("TransactionGet", id).Return(transaction, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteTransactionRequest(id, token)
+			req.Header.Set("If-Match", `"3"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
 		})
 	})
 }
 
 func TestListTransactions(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	newTransactionListResponse := func(slice []*handlers.Transaction) *TransactionListResponse {
 		return &TransactionListResponse{
@@ -682,7 +927,7 @@ func TestListTransactions(t *testing.T) {
 
 			r.ServeHTTP(res, req)
 
-			expected := newTransactionListResponse([]*handlers.Transaction{{}})
+			expected := newTransactionListResponse([]*handlers.Transaction{{Tags: []string{}}})
 
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, expected)
@@ -0,0 +1,234 @@
+package router
+
+import (
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func trashedAt(t time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: t, Valid: true}
+}
+
+func TestListTrash(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewListTrashRequest(token)
+		invalidTokenReq := NewListTrashRequest(token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		t.Run("List empty trash", func(t *testing.T) {
+			repoSpy.On("WalletListTrashed", userID).Return([]*model.Wallet{}, nil).Once()
+			repoSpy.On("PartyListTrashed", userID).Return([]*model.Party{}, nil).Once()
+			repoSpy.On("TransactionListTrashed", userID).Return([]*model.Transaction{}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListTrashRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.Trash{
+				Wallets:      []*handlers.TrashedWallet{},
+				Parties:      []*handlers.TrashedParty{},
+				Transactions: []*handlers.TrashedTransaction{},
+			})
+		})
+
+		t.Run("List trashed wallets, parties and transactions", func(t *testing.T) {
+			deletedAt := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+
+			wallet := &model.Wallet{Name: "old wallet", OpeningBalance: decimal.RequireFromString("0"), UserID: userID, DeletedAt: trashedAt(deletedAt)}
+			wallet.ID = 2
+			party := &model.Party{Name: "old party", UserID: userID, DeletedAt: trashedAt(deletedAt)}
+			party.ID = 3
+			transaction := &model.Transaction{Amount: decimal.RequireFromString("10"), WalletID: 2, PartyID: 3, UserID: userID, DeletedAt: trashedAt(deletedAt)}
+			transaction.ID = 4
+
+			repoSpy.On("WalletListTrashed", userID).Return([]*model.Wallet{wallet}, nil).Once()
+			repoSpy.On("PartyListTrashed", userID).Return([]*model.Party{party}, nil).Once()
+			repoSpy.On("TransactionListTrashed", userID).Return([]*model.Transaction{transaction}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListTrashRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, &handlers.Trash{
+				Wallets:      []*handlers.TrashedWallet{{Wallet: handlers.WalletModelToResponse(wallet), DeletedAt: deletedAt}},
+				Parties:      []*handlers.TrashedParty{{Party: handlers.PartyModelToResponse(party), DeletedAt: deletedAt}},
+				Transactions: []*handlers.TrashedTransaction{{Transaction: handlers.TransactionModelToResponse(transaction), DeletedAt: deletedAt}},
+			})
+		})
+	})
+}
+
+func TestRestoreFromTrash(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewRestoreWalletRequest(1, token)
+		invalidTokenReq := NewRestoreWalletRequest(1, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		claims := auth.CustomClaims{
+			ID: userID,
+		}
+		jwtServiceSpy.On("ValidateJWT", token).Return(&claims, nil)
+
+		deletedAt := time.Date(2021, 3, 4, 10, 0, 0, 0, time.UTC)
+
+		t.Run("Restore wallet that isn't in the trash", func(t *testing.T) {
+			repoSpy.On("WalletGetTrashed", uint(1)).Return(nil, repository.ErrorRecordNotFound).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestoreWalletRequest(1, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNotFound)
+		})
+
+		t.Run("Restore wallet of another user", func(t *testing.T) {
+			wallet := &model.Wallet{Name: "old wallet", UserID: userID + 1, DeletedAt: trashedAt(deletedAt)}
+
+			repoSpy.On("WalletGetTrashed", uint(2)).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestoreWalletRequest(2, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusForbidden)
+		})
+
+		t.Run("Restore wallet", func(t *testing.T) {
+			wallet := &model.Wallet{Name: "old wallet", OpeningBalance: decimal.RequireFromString("0"), UserID: userID, DeletedAt: trashedAt(deletedAt)}
+			wallet.ID = 3
+			restored := &model.Wallet{Name: "old wallet", OpeningBalance: decimal.RequireFromString("0"), UserID: userID}
+			restored.ID = 3
+			restored.Version = 2
+
+			repoSpy.On("WalletGetTrashed", uint(3)).Return(wallet, nil).Once()
+			repoSpy.On("WalletRestore", uint(3)).Return(restored, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestoreWalletRequest(3, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertHeader(t, res, "ETag", `"2"`)
+			AssertResponseBody(t, res, handlers.WalletModelToResponse(restored))
+		})
+
+		t.Run("Restore wallet whose name was taken in the meantime", func(t *testing.T) {
+			wallet := &model.Wallet{Name: "old wallet", UserID: userID, DeletedAt: trashedAt(deletedAt)}
+
+			repoSpy.On("WalletGetTrashed", uint(4)).Return(wallet, nil).Once()
+			repoSpy.On("WalletRestore", uint(4)).Return(nil, repository.ErrorUniqueConstaintViolation).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestoreWalletRequest(4, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorWalletNameTaken.Message)
+		})
+
+		t.Run("Restore party", func(t *testing.T) {
+			party := &model.Party{Name: "old party", UserID: userID, DeletedAt: trashedAt(deletedAt)}
+			party.ID = 5
+			restored := &model.Party{Name: "old party", UserID: userID}
+			restored.ID = 5
+
+			repoSpy.On("PartyGetTrashed", uint(5)).Return(party, nil).Once()
+			repoSpy.On("PartyRestore", uint(5)).Return(restored, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestorePartyRequest(5, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.PartyModelToResponse(restored))
+		})
+
+		t.Run("Restore transaction", func(t *testing.T) {
+			transaction := &model.Transaction{Amount: decimal.RequireFromString("10"), WalletID: 2, PartyID: 3, UserID: userID, DeletedAt: trashedAt(deletedAt)}
+			transaction.ID = 6
+			restored := &model.Transaction{Amount: decimal.RequireFromString("10"), WalletID: 2, PartyID: 3, UserID: userID}
+			restored.ID = 6
+
+			repoSpy.On("TransactionGetTrashed", uint(6)).Return(transaction, nil).Once()
+			repoSpy.On("TransactionRestore", uint(6)).Return(restored, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestoreTransactionRequest(6, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.TransactionModelToResponse(restored))
+		})
+
+		t.Run("Restore transaction whose wallet is in the trash", func(t *testing.T) {
+			transaction := &model.Transaction{Amount: decimal.RequireFromString("10"), WalletID: 2, PartyID: 3, UserID: userID, DeletedAt: trashedAt(deletedAt)}
+
+			repoSpy.On("TransactionGetTrashed", uint(7)).Return(transaction, nil).Once()
+			repoSpy.On("TransactionRestore", uint(7)).Return(nil, repository.ErrorParentTrashed).Once()
+
+			res := httptest.NewRecorder()
+			req := NewRestoreTransactionRequest(7, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorParentTrashed.Message)
+		})
+	})
+}
@@ -10,14 +10,18 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 func TestCreateWallet(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		wallet := &handlers.Wallet{}
@@ -40,8 +44,10 @@ func TestCreateWallet(t *testing.T) {
 
 		t.Run("Try to create a wallet with already existing name, belonging to the same user", func(t *testing.T) {
 			wallet := &model.Wallet{
-				Name:   "cash",
-				UserID: userID,
+				Name:           "cash",
+				Type:           model.WalletChecking,
+				OpeningBalance: decimal.RequireFromString("0"),
+				UserID:         userID,
 			}
 
 			repoSpy.On("WalletCreate", wallet).Return(repository.ErrorUniqueConstaintViolation).Once()
@@ -59,10 +65,52 @@ func TestCreateWallet(t *testing.T) {
 			AssertErrorMessage(t, res, wantErrorMessage)
 		})
 
+		t.Run("Create wallet with an unknown currency", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewCreateWalletRequest(&handlers.Wallet{
+				Name:     "cash",
+				Currency: "ABC",
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			wantErrorMessage := handlers.ErrorInvalidCurrency.Message
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, wantErrorMessage)
+		})
+
+		t.Run("Create wallet with a currency", func(t *testing.T) {
+			wallet := &model.Wallet{
+				Name:           "travel",
+				Currency:       "USD",
+				Type:           model.WalletChecking,
+				OpeningBalance: decimal.RequireFromString("0"),
+				UserID:         userID,
+			}
+
+			repoSpy.On("WalletCreate", wallet).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateWalletRequest(&handlers.Wallet{
+				Name:     wallet.Name,
+				Currency: "usd",
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			resBody := handlers.WalletModelToResponse(wallet)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, resBody)
+		})
+
 		t.Run("Create wallet with valid data", func(t *testing.T) {
 			wallet := &model.Wallet{
-				Name:   "cash",
-				UserID: userID,
+				Name:           "cash",
+				Type:           model.WalletChecking,
+				OpeningBalance: decimal.RequireFromString("0"),
+				UserID:         userID,
 			}
 
 			repoSpy.On("WalletCreate", wallet).Return(nil).Once()
@@ -79,15 +127,93 @@ func TestCreateWallet(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusCreated)
 			AssertResponseBody(t, res, resBody)
 		})
+
+		t.Run("Create wallet without a name", func(t *testing.T) {
+			res := httptest.NewRecorder()
+			req := NewCreateWalletRequest(&handlers.Wallet{Type: model.WalletCash}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorWalletName.Message)
+		})
+
+		t.Run("Create a savings wallet with a credit limit", func(t *testing.T) {
+			creditLimit := decimal.RequireFromString("1000")
+
+			res := httptest.NewRecorder()
+			req := NewCreateWalletRequest(&handlers.Wallet{
+				Name:        "savings",
+				Type:        model.WalletSavings,
+				CreditLimit: &creditLimit,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorCreditLimitType.Message)
+		})
+
+		t.Run("Create a wallet with a monthly budget of 0", func(t *testing.T) {
+			budget := decimal.Zero
+
+			res := httptest.NewRecorder()
+			req := NewCreateWalletRequest(&handlers.Wallet{
+				Name:          "groceries",
+				Type:          model.WalletChecking,
+				MonthlyBudget: &budget,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusBadRequest)
+			AssertErrorMessage(t, res, handlers.ErrorMonthlyBudget.Message)
+		})
+
+		t.Run("Create a credit card wallet", func(t *testing.T) {
+			creditLimit := decimal.RequireFromString("1000")
+			openingDate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			wallet := &model.Wallet{
+				Name:           "visa",
+				Type:           model.WalletCreditCard,
+				OpeningBalance: decimal.RequireFromString("-150"),
+				OpeningDate:    &openingDate,
+				CreditLimit:    &creditLimit,
+				DisplayOrder:   1,
+				UserID:         userID,
+			}
+
+			repoSpy.On("WalletCreate", wallet).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateWalletRequest(&handlers.Wallet{
+				Name:           wallet.Name,
+				Type:           wallet.Type,
+				OpeningBalance: wallet.OpeningBalance,
+				OpeningDate:    wallet.OpeningDate,
+				CreditLimit:    wallet.CreditLimit,
+				DisplayOrder:   wallet.DisplayOrder,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			available := decimal.RequireFromString("850")
+			resBody := handlers.WalletModelToResponse(wallet)
+			resBody.AvailableCredit = &available
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, resBody)
+		})
 	})
 }
 
 func TestGetWallet(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
@@ -168,23 +294,97 @@ func TestGetWallet(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, resBody)
 		})
+
+		t.Run("Get wallet the client already has", func(t *testing.T) {
+			id := uint(1)
+			wallet := &model.Wallet{
+				Name:   "new wallet",
+				UserID: userID,
+			}
+			wallet.Version = 5
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Twice()
+
+			res := httptest.NewRecorder()
+			req := NewGetWalletRequest(id, token)
+			req.Header.Set("If-None-Match", `"5"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNotModified)
+			AssertHeader(t, res, "ETag", `"5"`)
+		})
+
+		t.Run("Get a credit card wallet", func(t *testing.T) {
+			id := uint(2)
+			creditLimit := decimal.RequireFromString("1000")
+			wallet := &model.Wallet{
+				Model:          model.Model{ID: id},
+				Name:           "visa",
+				Type:           model.WalletCreditCard,
+				OpeningBalance: decimal.RequireFromString("-150"),
+				CreditLimit:    &creditLimit,
+				UserID:         userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Twice()
+			repoSpy.On("TransactionSumByWallet", []uint{id}).Return(map[uint]decimal.Decimal{id: decimal.RequireFromString("-320.5")}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetWalletRequest(id, token)
+
+			r.ServeHTTP(res, req)
+
+			available := decimal.RequireFromString("529.5")
+			resBody := handlers.WalletModelToResponse(wallet)
+			resBody.AvailableCredit = &available
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, resBody)
+			AssertHeader(t, res, "ETag", handlers.DerivedETag(0, "529.5"))
+		})
+
+		t.Run("Get a credit card wallet whose available credit changed", func(t *testing.T) {
+			id := uint(2)
+			creditLimit := decimal.RequireFromString("1000")
+			wallet := &model.Wallet{
+				Model:       model.Model{ID: id, Version: 5},
+				Name:        "visa",
+				Type:        model.WalletCreditCard,
+				CreditLimit: &creditLimit,
+				UserID:      userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Twice()
+			repoSpy.On("TransactionSumByWallet", []uint{id}).Return(map[uint]decimal.Decimal{id: decimal.RequireFromString("-100")}, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewGetWalletRequest(id, token)
+			req.Header.Set("If-None-Match", handlers.DerivedETag(5, "1000"))
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertHeader(t, res, "ETag", handlers.DerivedETag(5, "900"))
+		})
 	})
 }
 
 func TestUpdateWallet(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
-		wallet := &handlers.Wallet{}
+		patch := Patch{"name": "new wallet"}
 		token := "invalid-token"
 
-		missingTokenReq := NewUpdateWalletRequest(id, wallet, token)
-		invalidTokenReq := NewUpdateWalletRequest(id, wallet, token)
+		missingTokenReq := NewUpdateWalletRequest(id, patch, token)
+		invalidTokenReq := NewUpdateWalletRequest(id, patch, token)
 
 		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
 		t.Run("Unauthorized test cases", unauthorizedTestCases)
@@ -200,14 +400,11 @@ func TestUpdateWallet(t *testing.T) {
 
 		t.Run("Update non-existent wallet", func(t *testing.T) {
 			id := uint(1)
-			wallet := &handlers.Wallet{
-				Name: "new wallet",
-			}
 
 			repoSpy.On("WalletGet", id).Return(nil, repository.ErrorRecordNotFound).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateWalletRequest(id, wallet, token)
+			req := NewUpdateWalletRequest(id, Patch{"name": "new wallet"}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -224,7 +421,7 @@ func TestUpdateWallet(t *testing.T) {
 			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateWalletRequest(id, &handlers.Wallet{Name: wallet.Name}, token)
+			req := NewUpdateWalletRequest(id, Patch{"name": wallet.Name}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -234,15 +431,22 @@ func TestUpdateWallet(t *testing.T) {
 		t.Run("Try to update a wallet with already existing name, belonging to the same user", func(t *testing.T) {
 			id := uint(1)
 			wallet := &model.Wallet{
-				Name:   "cash",
+				Name:   "food",
+				Type:   model.WalletCash,
 				UserID: userID,
 			}
+			updated := &model.Wallet{
+				Name:           "cash",
+				Type:           model.WalletCash,
+				OpeningBalance: decimal.RequireFromString("0"),
+				UserID:         userID,
+			}
 
 			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
-			repoSpy.On("WalletUpdate", id, wallet).Return(nil, repository.ErrorUniqueConstaintViolation).Once()
+			repoSpy.On("WalletUpdate", id, updated).Return(nil, repository.ErrorUniqueConstaintViolation).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateWalletRequest(id, &handlers.Wallet{Name: wallet.Name}, token)
+			req := NewUpdateWalletRequest(id, Patch{"name": "cash"}, token)
 
 			r.ServeHTTP(res, req)
 
@@ -252,35 +456,174 @@ func TestUpdateWallet(t *testing.T) {
 			AssertErrorMessage(t, res, wantErrorMessage)
 		})
 
+		badPatches := []struct {
+			desc    string
+			patch   Patch
+			message string
+		}{
+			{"Clear the name of a wallet", Patch{"name": nil}, handlers.ErrorWalletName.Message},
+			{"Update wallet with an unknown type", Patch{"type": "piggy_bank"}, handlers.ErrorInvalidWalletType.Message},
+			{"Give a checking wallet a credit limit", Patch{"credit_limit": "500"}, handlers.ErrorCreditLimitType.Message},
+			{"Give a card wallet a negative credit limit", Patch{"type": model.WalletCreditCard, "credit_limit": "-500"}, handlers.ErrorNegativeCreditLimit.Message},
+			{"Give a wallet an opening balance too precise for its currency", Patch{"opening_balance": "10.001"}, handlers.ErrorAmountPrecision.Message},
+			{"Change the currency of a wallet", Patch{"currency": "USD"}, handlers.ErrorWalletCurrencyChange.Message},
+			{"Update wallet with a patch that isn't an object", nil, handlers.ErrorInvalidPatch.Message},
+			{"Update wallet with a patch of the wrong type", Patch{"display_order": "first"}, handlers.ErrorInvalidPatch.Message},
+		}
+
+		for _, tc := range badPatches {
+			t.Run(tc.desc, func(t *testing.T) {
+				id := uint(2)
+				wallet := &model.Wallet{Name: "current", Currency: "EUR", Type: model.WalletChecking, UserID: userID}
+
+				repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+
+				res := httptest.NewRecorder()
+				req := NewUpdateWalletRequest(id, tc.patch, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
 		t.Run("Update existing wallet with valid arguments", func(t *testing.T) {
 			id := uint(3)
+			openingDate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
 			wallet := &model.Wallet{
-				Name:   "new wallet",
-				UserID: userID,
+				Name:        "old wallet",
+				Description: "kept",
+				Type:        model.WalletChecking,
+				UserID:      userID,
+			}
+			updated := &model.Wallet{
+				Name:           "new wallet",
+				Description:    "kept",
+				Type:           model.WalletSavings,
+				OpeningBalance: decimal.RequireFromString("250.5"),
+				OpeningDate:    &openingDate,
+				DisplayOrder:   2,
+				UserID:         userID,
 			}
 
 			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
-			repoSpy.On("WalletUpdate", id, wallet).Return(wallet, nil).Once()
+			repoSpy.On("WalletUpdate", id, updated).Return(updated, nil).Once()
 
 			res := httptest.NewRecorder()
-			req := NewUpdateWalletRequest(id, &handlers.Wallet{Name: wallet.Name}, token)
+			req := NewUpdateWalletRequest(id, Patch{
+				"name":            "new wallet",
+				"type":            model.WalletSavings,
+				"opening_balance": "250.5",
+				"opening_date":    openingDate,
+				"display_order":   2,
+			}, token)
 
 			r.ServeHTTP(res, req)
 
-			resBody := handlers.WalletModelToResponse(wallet)
+			resBody := handlers.WalletModelToResponse(updated)
 
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, resBody)
 		})
+
+		t.Run("Update wallet with an outdated If-Match", func(t *testing.T) {
+			id := uint(3)
+			wallet := &model.Wallet{
+				Name:   "old wallet",
+				Type:   model.WalletChecking,
+				UserID: userID,
+			}
+			wallet.Version = 2
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateWalletRequest(id, Patch{"name": "new wallet"}, token)
+			req.Header.Set("If-Match", `"1"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+			AssertErrorMessage(t, res, handlers.ErrorPreconditionFailed.Message)
+		})
+
+		t.Run("Update wallet that is changed at the same time", func(t *testing.T) {
+			id := uint(3)
+			wallet := &model.Wallet{
+				Name:   "old wallet",
+				Type:   model.WalletChecking,
+				UserID: userID,
+			}
+			wallet.Version = 2
+			updated := &model.Wallet{
+				Name:           "new wallet",
+				Type:           model.WalletChecking,
+				OpeningBalance: decimal.RequireFromString("0"),
+				UserID:         userID,
+			}
+			updated.Version = 2
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletUpdate", id, updated).Return(nil, repository.ErrorVersionConflict).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateWalletRequest(id, Patch{"name": "new wallet"}, token)
+			req.Header.Set("If-Match", `"2"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorConcurrentUpdate.Message)
+		})
+
+		t.Run("Clear fields of a card wallet", func(t *testing.T) {
+			id := uint(4)
+			openingDate := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			creditLimit := decimal.RequireFromString("500")
+			wallet := &model.Wallet{
+				Name:        "visa",
+				Description: "gold card",
+				Type:        model.WalletCreditCard,
+				OpeningDate: &openingDate,
+				CreditLimit: &creditLimit,
+				Archived:    true,
+				UserID:      userID,
+			}
+			updated := &model.Wallet{
+				Name:           "visa",
+				Type:           model.WalletCreditCard,
+				OpeningBalance: decimal.RequireFromString("0"),
+				UserID:         userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletUpdate", id, updated).Return(updated, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewUpdateWalletRequest(id, Patch{
+				"description":  nil,
+				"opening_date": nil,
+				"credit_limit": nil,
+				"archived":     false,
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, handlers.WalletModelToResponse(updated))
+			repoSpy.AssertExpectations(t)
+		})
 	})
 }
 
 func TestDeleteWallet(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
 		id := uint(1)
@@ -339,7 +682,7 @@ func TestDeleteWallet(t *testing.T) {
 			}
 
 			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
-			repoSpy.On("WalletDelete", id).Return(nil).Once()
+			repoSpy.On("WalletDelete", id, false).Return(nil).Once()
 
 			res := httptest.NewRecorder()
 			req := NewDeleteWalletRequest(id, token)
@@ -348,15 +691,212 @@ func TestDeleteWallet(t *testing.T) {
 
 			AssertStatusCode(t, res, http.StatusNoContent)
 		})
+
+		t.Run("Try to delete wallet that still has transactions", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:   "new wallet",
+				UserID: userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletDelete", id, false).Return(repository.ErrorWalletNotEmpty).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletRequest(id, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorWalletHasTransactions.Message)
+		})
+
+		t.Run("Delete wallet together with its transactions", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:   "new wallet",
+				UserID: userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletDelete", id, true).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletWithQueryRequest(id, "cascade=true", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNoContent)
+		})
+
+		t.Run("Delete wallet with reconciled transactions", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:   "new wallet",
+				UserID: userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletDelete", id, true).Return(repository.ErrorHasReconciled).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletWithQueryRequest(id, "cascade=true", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorWalletReconciled.Message)
+		})
+
+		t.Run("Delete wallet and move its transactions to another wallet", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:     "new wallet",
+				Currency: "EUR",
+				UserID:   userID,
+			}
+			target := &model.Wallet{
+				Name:     "target wallet",
+				Currency: "EUR",
+				UserID:   userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletGet", uint(3)).Return(target, nil).Once()
+			repoSpy.On("WalletReassign", id, uint(3)).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletWithQueryRequest(id, "reassign_to=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNoContent)
+		})
+
+		t.Run("Try to move reconciled transactions of a wallet", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:     "new wallet",
+				Currency: "EUR",
+				UserID:   userID,
+			}
+			target := &model.Wallet{
+				Name:     "target wallet",
+				Currency: "EUR",
+				UserID:   userID,
+			}
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletGet", uint(3)).Return(target, nil).Once()
+			repoSpy.On("WalletReassign", id, uint(3)).Return(repository.ErrorHasReconciled).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletWithQueryRequest(id, "reassign_to=3", token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusConflict)
+			AssertErrorMessage(t, res, handlers.ErrorWalletReconciled.Message)
+		})
+
+		t.Run("Try to move the transactions of a wallet to an invalid wallet", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:     "new wallet",
+				Currency: "EUR",
+				UserID:   userID,
+			}
+			foreign := &model.Wallet{
+				Name:     "foreign wallet",
+				Currency: "EUR",
+				UserID:   userID + 1,
+			}
+			dollars := &model.Wallet{
+				Name:     "dollar wallet",
+				Currency: "USD",
+				UserID:   userID,
+			}
+
+			testCases := []struct {
+				name     string
+				query    string
+				target   *model.Wallet
+				status   int
+				expected *handlers.ErrorMessage
+			}{
+				{"Together with cascade", "cascade=true&reassign_to=3", nil, http.StatusBadRequest, handlers.ErrorCascadeAndReassign},
+				{"Into itself", "reassign_to=2", nil, http.StatusBadRequest, handlers.ErrorBadReassignID},
+				{"Not a number", "reassign_to=three", nil, http.StatusBadRequest, handlers.ErrorBadReassignID},
+				{"Of another user", "reassign_to=3", foreign, http.StatusForbidden, handlers.ErrorBadWalletID},
+				{"In another currency", "reassign_to=3", dollars, http.StatusBadRequest, handlers.ErrorReassignCurrency},
+			}
+
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+					if tc.target != nil {
+						repoSpy.On("WalletGet", uint(3)).Return(tc.target, nil).Once()
+					}
+
+					res := httptest.NewRecorder()
+					req := NewDeleteWalletWithQueryRequest(id, tc.query, token)
+
+					r.ServeHTTP(res, req)
+
+					AssertStatusCode(t, res, tc.status)
+					AssertErrorMessage(t, res, tc.expected.Message)
+				})
+			}
+		})
+
+		t.Run("Delete wallet with an outdated If-Match", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:   "new wallet",
+				UserID: userID,
+			}
+			wallet.Version = 7
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletRequest(id, token)
+			req.Header.Set("If-Match", `"6"`)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusPreconditionFailed)
+		})
+
+		t.Run("Delete card wallet with the ETag that has its available credit", func(t *testing.T) {
+			id := uint(2)
+			wallet := &model.Wallet{
+				Name:   "visa",
+				UserID: userID,
+			}
+			wallet.Version = 7
+
+			repoSpy.On("WalletGet", id).Return(wallet, nil).Once()
+			repoSpy.On("WalletDelete", id, false).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewDeleteWalletRequest(id, token)
+			req.Header.Set("If-Match", handlers.DerivedETag(7, "529.5"))
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusNoContent)
+		})
 	})
 }
 
 func TestListWallets(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	newWalletListResponse := func(slice []*handlers.Wallet) *WalletListResponse {
 		return &WalletListResponse{
@@ -413,15 +953,52 @@ func TestListWallets(t *testing.T) {
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, expected)
 		})
+
+		wallets := []*model.Wallet{
+			{Model: model.Model{ID: 1}, Name: "checking", Type: model.WalletChecking},
+			{Model: model.Model{ID: 2}, Name: "old savings", Type: model.WalletSavings, Archived: true},
+		}
+
+		t.Run("List wallets without the archived ones", func(t *testing.T) {
+			repoSpy.On("WalletList", userID).Return(wallets, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListWalletsRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			expected := newWalletListResponse([]*handlers.Wallet{handlers.WalletModelToResponse(wallets[0])})
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, expected)
+		})
+
+		t.Run("List wallets including the archived ones", func(t *testing.T) {
+			repoSpy.On("WalletList", userID).Return(wallets, nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewListAllWalletsRequest(token)
+
+			r.ServeHTTP(res, req)
+
+			expected := newWalletListResponse([]*handlers.Wallet{
+				handlers.WalletModelToResponse(wallets[0]),
+				handlers.WalletModelToResponse(wallets[1]),
+			})
+
+			AssertStatusCode(t, res, http.StatusOK)
+			AssertResponseBody(t, res, expected)
+		})
 	})
 }
 
 func TestListTransactionsByWallet(t *testing.T) {
-	repoSpy := &spies.RepositorySpy{}
+	repoSpy := NewRepositorySpy()
 	jwtServiceSpy := &spies.JWTServiceSpy{}
 	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
 
-	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, router.TestConfig)
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
 
 	newTransactionListResponse := func(slice []*handlers.Transaction) *TransactionListResponse {
 		return &TransactionListResponse{
@@ -509,7 +1086,7 @@ func TestListTransactionsByWallet(t *testing.T) {
 
 			r.ServeHTTP(res, req)
 
-			expected := newTransactionListResponse([]*handlers.Transaction{{}})
+			expected := newTransactionListResponse([]*handlers.Transaction{{Tags: []string{}}})
 
 			AssertStatusCode(t, res, http.StatusOK)
 			AssertResponseBody(t, res, expected)
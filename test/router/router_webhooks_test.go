@@ -0,0 +1,446 @@
+package router
+
+import (
+	"encoding/json"
+	"expense-api/internal/handlers"
+	"expense-api/internal/middleware/auth"
+	"expense-api/internal/model"
+	"expense-api/internal/repository"
+	"expense-api/internal/router"
+	"expense-api/test/spies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateWebhook(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	t.Run("Missing/Invalid authorization token cases", func(t *testing.T) {
+		token := "invalid-token"
+
+		missingTokenReq := NewCreateWebhookRequest(&handlers.WebhookRequest{}, token)
+		invalidTokenReq := NewCreateWebhookRequest(&handlers.WebhookRequest{}, token)
+
+		unauthorizedTestCases := UnauthorizedTestCases(missingTokenReq, invalidTokenReq, r, jwtServiceSpy)
+		t.Run("Unauthorized test cases", unauthorizedTestCases)
+	})
+
+	t.Run("Valid authorization token cases", func(t *testing.T) {
+		token := "valid-token"
+		userID := uint(1)
+		jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+		badRequests := []struct {
+			desc    string
+			webhook *handlers.WebhookRequest
+			message string
+		}{
+			{"Missing URL", &handlers.WebhookRequest{
+				Events: []string{model.EventTransactionCreated},
+			}, handlers.ErrorWebhookURL.Message},
+			{"Relative URL", &handlers.WebhookRequest{
+				URL:    "/hooks/expenses",
+				Events: []string{model.EventTransactionCreated},
+			}, handlers.ErrorWebhookURL.Message},
+			{"Unsupported scheme", &handlers.WebhookRequest{
+				URL:    "ftp://192.0.2.1/hooks",
+				Events: []string{model.EventTransactionCreated},
+			}, handlers.ErrorWebhookURL.Message},
+			{"Private address", &handlers.WebhookRequest{
+				URL:    "http://10.0.0.4:8080/hooks",
+				Events: []string{model.EventTransactionCreated},
+			}, handlers.ErrorWebhookURL.Message},
+			{"Loopback address", &handlers.WebhookRequest{
+				URL:    "http://[::1]/hooks",
+				Events: []string{model.EventTransactionCreated},
+			}, handlers.ErrorWebhookURL.Message},
+			{"Localhost", &handlers.WebhookRequest{
+				URL:    "http://localhost:8080/hooks",
+				Events: []string{model.EventTransactionCreated},
+			}, handlers.ErrorWebhookURL.Message},
+			{"No events", &handlers.WebhookRequest{
+				URL: "https://hooks.example.com/expenses",
+			}, handlers.ErrorWebhookEvents.Message},
+			{"Unknown event", &handlers.WebhookRequest{
+				URL:    "https://hooks.example.com/expenses",
+				Events: []string{model.EventTransactionCreated, "party.created"},
+			}, handlers.ErrorWebhookEvents.Message},
+		}
+
+		for _, tc := range badRequests {
+			t.Run(tc.desc, func(t *testing.T) {
+				res := httptest.NewRecorder()
+				req := NewCreateWebhookRequest(tc.webhook, token)
+
+				r.ServeHTTP(res, req)
+
+				AssertStatusCode(t, res, http.StatusBadRequest)
+				AssertErrorMessage(t, res, tc.message)
+			})
+		}
+
+		t.Run("Create webhook with a generated secret", func(t *testing.T) {
+			var secret string
+			repoSpy.On("WebhookCreate", mock.MatchedBy(func(w *model.Webhook) bool {
+				secret = w.Secret
+				return w.UserID == userID && w.URL == "https://hooks.example.com/expenses" && w.Enabled &&
+					len(w.Events) == 2 && len(w.Secret) == 64
+			})).Return(nil).Once()
+
+			res := httptest.NewRecorder()
+			req := NewCreateWebhookRequest(&handlers.WebhookRequest{
+				URL:    "https://hooks.example.com/expenses",
+				Events: []string{model.EventTransactionCreated, "wallet.*"},
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+			AssertResponseBody(t, res, &handlers.Webhook{
+				URL:     "https://hooks.example.com/expenses",
+				Events:  []string{model.EventTransactionCreated, "wallet.*"},
+				Enabled: true,
+				Secret:  secret,
+			})
+		})
+
+		t.Run("Create disabled webhook with its own secret", func(t *testing.T) {
+			webhook := &model.Webhook{
+				UserID: userID,
+				URL:    "http://hooks.example.org:8123/api/webhook/expenses",
+				Events: model.Tags{"*"},
+				Secret: "s3cr3t",
+			}
+			repoSpy.On("WebhookCreate", webhook).Return(nil).Once()
+
+			enabled := false
+			res := httptest.NewRecorder()
+			req := NewCreateWebhookRequest(&handlers.WebhookRequest{
+				URL:     webhook.URL,
+				Events:  webhook.Events,
+				Enabled: &enabled,
+				Secret:  "s3cr3t",
+			}, token)
+
+			r.ServeHTTP(res, req)
+
+			AssertStatusCode(t, res, http.StatusCreated)
+		})
+
+		repoSpy.AssertExpectations(t)
+	})
+}
+
+func TestGetWebhook(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	t.Run("Get own webhook without its secret", func(t *testing.T) {
+		webhook := &model.Webhook{
+			Model:   model.Model{ID: 2},
+			UserID:  userID,
+			URL:     "https://hooks.example.com/expenses",
+			Events:  model.Tags{"transaction.*"},
+			Secret:  "s3cr3t",
+			Enabled: true,
+		}
+		repoSpy.On("WebhookGet", uint(2)).Return(webhook, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetWebhookRequest(2, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &handlers.Webhook{
+			ID:      2,
+			URL:     "https://hooks.example.com/expenses",
+			Events:  []string{"transaction.*"},
+			Enabled: true,
+		})
+	})
+
+	t.Run("Get another user's webhook", func(t *testing.T) {
+		repoSpy.On("WebhookGet", uint(3)).Return(&model.Webhook{UserID: userID + 1}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetWebhookRequest(3, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	t.Run("Get non-existent webhook", func(t *testing.T) {
+		repoSpy.On("WebhookGet", uint(4)).Return(nil, repository.ErrorRecordNotFound).Once()
+
+		res := httptest.NewRecorder()
+		req := NewGetWebhookRequest(4, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	newWebhook := func() *model.Webhook {
+		return &model.Webhook{
+			Model:   model.Model{ID: 2, Version: 1},
+			UserID:  userID,
+			URL:     "https://hooks.example.com/expenses",
+			Events:  model.Tags{model.EventTransactionCreated},
+			Secret:  "s3cr3t",
+			Enabled: true,
+		}
+	}
+
+	t.Run("Disable a webhook keeping its secret", func(t *testing.T) {
+		patched := newWebhook()
+		patched.ID = 0
+		patched.Enabled = false
+		updated := newWebhook()
+		updated.Enabled = false
+
+		repoSpy.On("WebhookGet", uint(2)).Return(newWebhook(), nil).Once()
+		repoSpy.On("WebhookUpdate", uint(2), patched).Return(updated, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateWebhookRequest(2, Patch{"enabled": false}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, handlers.WebhookModelToResponse(updated))
+	})
+
+	t.Run("Rotate the secret", func(t *testing.T) {
+		patched := newWebhook()
+		patched.ID = 0
+		patched.Secret = "n3w s3cr3t"
+
+		repoSpy.On("WebhookGet", uint(2)).Return(newWebhook(), nil).Once()
+		repoSpy.On("WebhookUpdate", uint(2), patched).Return(newWebhook(), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateWebhookRequest(2, Patch{"secret": "n3w s3cr3t"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+	})
+
+	t.Run("Remove all events", func(t *testing.T) {
+		repoSpy.On("WebhookGet", uint(2)).Return(newWebhook(), nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateWebhookRequest(2, Patch{"events": nil}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorWebhookEvents.Message)
+	})
+
+	t.Run("Update another user's webhook", func(t *testing.T) {
+		repoSpy.On("WebhookGet", uint(3)).Return(&model.Webhook{UserID: userID + 1}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewUpdateWebhookRequest(3, Patch{"url": "https://attacker.example.com"}, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	repoSpy.AssertExpectations(t)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	repoSpy.On("WebhookGet", uint(1)).Return(&model.Webhook{UserID: userID}, nil).Once()
+	repoSpy.On("WebhookDelete", uint(1)).Return(nil).Once()
+
+	res := httptest.NewRecorder()
+	req := NewDeleteWebhookRequest(1, token)
+
+	r.ServeHTTP(res, req)
+
+	AssertStatusCode(t, res, http.StatusNoContent)
+}
+
+func TestListWebhooks(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	webhooks := []*model.Webhook{
+		{Model: model.Model{ID: 1}, URL: "https://hooks.slack.com/services/T0/B0/X", Events: model.Tags{model.EventTransactionCreated}, Secret: "a"},
+		{Model: model.Model{ID: 2}, URL: "http://homeassistant.local:8123/api/webhook/x", Events: model.Tags{"*"}, Secret: "b"},
+	}
+	repoSpy.On("WebhookList", userID).Return(webhooks, nil).Once()
+
+	res := httptest.NewRecorder()
+	req := NewListWebhooksRequest(token)
+
+	r.ServeHTTP(res, req)
+
+	AssertStatusCode(t, res, http.StatusOK)
+	AssertResponseBody(t, res, &WebhookListResponse{
+		Count:   2,
+		Entries: []*handlers.Webhook{handlers.WebhookModelToResponse(webhooks[0]), handlers.WebhookModelToResponse(webhooks[1])},
+	})
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	repoSpy := NewRepositorySpy()
+	jwtServiceSpy := &spies.JWTServiceSpy{}
+	hasherSpy := &spies.PasswordHasherSpy{}
+	blobStoreSpy := &spies.BlobStoreSpy{}
+
+	r := router.Setup(repoSpy, jwtServiceSpy, hasherSpy, blobStoreSpy, router.TestConfig)
+
+	token := "valid-token"
+	userID := uint(1)
+	jwtServiceSpy.On("ValidateJWT", token).Return(&auth.CustomClaims{ID: userID}, nil)
+
+	webhook := &model.Webhook{Model: model.Model{ID: 2}, UserID: userID}
+	attemptedAt := time.Date(2021, 3, 31, 12, 0, 0, 0, time.UTC)
+	failed := &model.WebhookDelivery{
+		Model:          model.Model{ID: 5},
+		WebhookID:      webhook.ID,
+		EventID:        "0123456789abcdef",
+		Event:          model.EventTransactionCreated,
+		Payload:        `{"id":"0123456789abcdef","event":"transaction.created"}`,
+		Status:         model.WebhookDeliveryFailed,
+		Attempts:       8,
+		NextAttemptAt:  attemptedAt,
+		LastAttemptAt:  &attemptedAt,
+		ResponseStatus: http.StatusGone,
+		Error:          "410 Gone: ",
+	}
+
+	t.Run("List the deliveries of a webhook", func(t *testing.T) {
+		repoSpy.On("WebhookGet", webhook.ID).Return(webhook, nil).Once()
+		repoSpy.On("WebhookDeliveryList", webhook.ID).Return([]*model.WebhookDelivery{failed}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewListWebhookDeliveriesRequest(webhook.ID, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusOK)
+		AssertResponseBody(t, res, &WebhookDeliveryListResponse{
+			Count: 1,
+			Entries: []*handlers.WebhookDelivery{{
+				ID:             5,
+				EventID:        "0123456789abcdef",
+				Event:          model.EventTransactionCreated,
+				Payload:        json.RawMessage(failed.Payload),
+				Status:         model.WebhookDeliveryFailed,
+				Attempts:       8,
+				LastAttemptAt:  &attemptedAt,
+				ResponseStatus: http.StatusGone,
+				Error:          "410 Gone: ",
+			}},
+		})
+	})
+
+	t.Run("Replay a delivery", func(t *testing.T) {
+		repoSpy.On("WebhookGet", webhook.ID).Return(webhook, nil).Once()
+		repoSpy.On("WebhookDeliveryGet", failed.ID).Return(failed, nil).Once()
+		repoSpy.On("WebhookDeliveryCreate", mock.MatchedBy(func(d *model.WebhookDelivery) bool {
+			return d.WebhookID == webhook.ID && d.EventID == failed.EventID && d.Payload == failed.Payload &&
+				d.Status == model.WebhookDeliveryPending && d.Attempts == 0 && *d.ReplayOfID == failed.ID
+		})).Return(nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewReplayWebhookDeliveryRequest(webhook.ID, "5", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusAccepted)
+	})
+
+	t.Run("Replay a delivery of another webhook", func(t *testing.T) {
+		repoSpy.On("WebhookGet", webhook.ID).Return(webhook, nil).Once()
+		repoSpy.On("WebhookDeliveryGet", uint(6)).Return(&model.WebhookDelivery{WebhookID: webhook.ID + 1}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewReplayWebhookDeliveryRequest(webhook.ID, "6", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusNotFound)
+	})
+
+	t.Run("Replay with an invalid delivery ID", func(t *testing.T) {
+		repoSpy.On("WebhookGet", webhook.ID).Return(webhook, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewReplayWebhookDeliveryRequest(webhook.ID, "last", token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusBadRequest)
+		AssertErrorMessage(t, res, handlers.ErrorBadDeliveryID.Message)
+	})
+
+	t.Run("List the deliveries of another user's webhook", func(t *testing.T) {
+		repoSpy.On("WebhookGet", uint(3)).Return(&model.Webhook{UserID: userID + 1}, nil).Once()
+
+		res := httptest.NewRecorder()
+		req := NewListWebhookDeliveriesRequest(3, token)
+
+		r.ServeHTTP(res, req)
+
+		AssertStatusCode(t, res, http.StatusForbidden)
+	})
+
+	repoSpy.AssertExpectations(t)
+}
@@ -3,12 +3,14 @@ package router
 import (
 	"encoding/json"
 	"expense-api/internal/handlers"
+	"expense-api/test/spies"
 	"fmt"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type (
@@ -26,6 +28,71 @@ type (
 		Count   int                `json:"count"`
 		Entries []*handlers.Wallet `json:"entries"`
 	}
+
+	TransactionSplitListResponse struct {
+		Count   int                          `json:"count"`
+		Entries []*handlers.TransactionSplit `json:"entries"`
+	}
+
+	AuditEntryListResponse struct {
+		Count   int                    `json:"count"`
+		Entries []*handlers.AuditEntry `json:"entries"`
+	}
+
+	WebhookListResponse struct {
+		Count   int                 `json:"count"`
+		Entries []*handlers.Webhook `json:"entries"`
+	}
+
+	WebhookDeliveryListResponse struct {
+		Count   int                         `json:"count"`
+		Entries []*handlers.WebhookDelivery `json:"entries"`
+	}
+
+	AttachmentListResponse struct {
+		Count   int                    `json:"count"`
+		Entries []*handlers.Attachment `json:"entries"`
+	}
+
+	SearchResultListResponse struct {
+		Count   int                      `json:"count"`
+		Entries []*handlers.SearchResult `json:"entries"`
+	}
+
+	CategorySuggestionListResponse struct {
+		Count   int                            `json:"count"`
+		Entries []*handlers.CategorySuggestion `json:"entries"`
+	}
+
+	DuplicatePairListResponse struct {
+		Count   int                       `json:"count"`
+		Entries []*handlers.DuplicatePair `json:"entries"`
+	}
+
+	RuleListResponse struct {
+		Count   int              `json:"count"`
+		Entries []*handlers.Rule `json:"entries"`
+	}
+
+	SharedBalanceListResponse struct {
+		Count   int                       `json:"count"`
+		Entries []*handlers.SharedBalance `json:"entries"`
+	}
+
+	PaymentListResponse struct {
+		Count   int                 `json:"count"`
+		Entries []*handlers.Payment `json:"entries"`
+	}
+
+	ReconciliationListResponse struct {
+		Count   int                        `json:"count"`
+		Entries []*handlers.Reconciliation `json:"entries"`
+	}
+
+	ExchangeRateListResponse struct {
+		Count   int                      `json:"count"`
+		Entries []*handlers.ExchangeRate `json:"entries"`
+	}
 )
 
 type Response interface {
@@ -34,9 +101,51 @@ type Response interface {
 		handlers.Party |
 		handlers.Wallet |
 		handlers.Transaction |
+		handlers.ExchangeRate |
+		handlers.ExchangeRateImport |
+		handlers.BalanceReport |
+		handlers.PartyReport |
+		handlers.CategoryReport |
+		handlers.SplitValidationError |
+		handlers.TransactionShares |
+		handlers.Attachment |
+		handlers.Settlement |
+		handlers.Household |
+		handlers.HouseholdInvitation |
+		handlers.Rule |
+		handlers.DuplicateWarning |
+		handlers.ApplyRulesResult |
+		handlers.ReconciliationResult |
+		handlers.BulkTransactionsResponse |
+		handlers.Trash |
+		handlers.AuditVerification |
+		handlers.Webhook |
+		handlers.WebhookDelivery |
 		PartyListResponse |
 		WalletListResponse |
-		TransactionListResponse
+		TransactionListResponse |
+		TransactionSplitListResponse |
+		AttachmentListResponse |
+		AuditEntryListResponse |
+		WebhookListResponse |
+		WebhookDeliveryListResponse |
+		SearchResultListResponse |
+		RuleListResponse |
+		DuplicatePairListResponse |
+		CategorySuggestionListResponse |
+		SharedBalanceListResponse |
+		PaymentListResponse |
+		ReconciliationListResponse |
+		ExchangeRateListResponse
+}
+
+// NewRepositorySpy creates a repository spy that also serves the requests of every actor
+func NewRepositorySpy() *spies.RepositorySpy {
+	repoSpy := &spies.RepositorySpy{}
+	repoSpy.On("WithActor", mock.Anything).Return(repoSpy).Maybe()
+	// The transactions never change, classifiers are trained once per router
+	repoSpy.On("TransactionStamp", mock.Anything).Return("", nil).Maybe()
+	return repoSpy
 }
 
 // Assertions
@@ -62,6 +171,13 @@ func AssertErrorMessage(t *testing.T, res *httptest.ResponseRecorder, expected s
 	assert.Equal(t, expected, got, notEqualMsg)
 }
 
+func AssertHeader(t *testing.T, res *httptest.ResponseRecorder, key, expected string) {
+	t.Helper()
+	if got := res.Header().Get(key); got != expected {
+		t.Errorf("expected header %s: %v, got %v", key, expected, got)
+	}
+}
+
 func AssertResponseBody[R Response](t *testing.T, res *httptest.ResponseRecorder, expected *R) {
 	t.Helper()
 	var got R
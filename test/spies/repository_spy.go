@@ -3,11 +3,21 @@
 package spies
 
 import (
+	context "context"
+
 	model "expense-api/internal/model"
 
 	mock "github.com/stretchr/testify/mock"
 
 	testing "testing"
+
+	decimal "github.com/shopspring/decimal"
+
+	time "time"
+
+	search "expense-api/internal/search"
+
+	repository "expense-api/internal/repository"
 )
 
 // RepositorySpy is an autogenerated mock type for the Repository type
@@ -15,13 +25,13 @@ type RepositorySpy struct {
 	mock.Mock
 }
 
-// PartyCreate provides a mock function with given fields: w
-func (_m *RepositorySpy) PartyCreate(w *model.Party) error {
-	ret := _m.Called(w)
+// AttachmentCreate provides a mock function with given fields: a
+func (_m *RepositorySpy) AttachmentCreate(a *model.Attachment) error {
+	ret := _m.Called(a)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Party) error); ok {
-		r0 = rf(w)
+	if rf, ok := ret.Get(0).(func(*model.Attachment) error); ok {
+		r0 = rf(a)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,8 +39,8 @@ func (_m *RepositorySpy) PartyCreate(w *model.Party) error {
 	return r0
 }
 
-// PartyDelete provides a mock function with given fields: id
-func (_m *RepositorySpy) PartyDelete(id uint) error {
+// AttachmentDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) AttachmentDelete(id uint) error {
 	ret := _m.Called(id)
 
 	var r0 error
@@ -43,16 +53,16 @@ func (_m *RepositorySpy) PartyDelete(id uint) error {
 	return r0
 }
 
-// PartyGet provides a mock function with given fields: id
-func (_m *RepositorySpy) PartyGet(id uint) (*model.Party, error) {
+// AttachmentGet provides a mock function with given fields: id
+func (_m *RepositorySpy) AttachmentGet(id uint) (*model.Attachment, error) {
 	ret := _m.Called(id)
 
-	var r0 *model.Party
-	if rf, ok := ret.Get(0).(func(uint) *model.Party); ok {
+	var r0 *model.Attachment
+	if rf, ok := ret.Get(0).(func(uint) *model.Attachment); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Party)
+			r0 = ret.Get(0).(*model.Attachment)
 		}
 	}
 
@@ -66,22 +76,22 @@ func (_m *RepositorySpy) PartyGet(id uint) (*model.Party, error) {
 	return r0, r1
 }
 
-// PartyList provides a mock function with given fields: userID
-func (_m *RepositorySpy) PartyList(userID uint) ([]*model.Party, error) {
-	ret := _m.Called(userID)
+// AttachmentList provides a mock function with given fields: transactionID
+func (_m *RepositorySpy) AttachmentList(transactionID uint) ([]*model.Attachment, error) {
+	ret := _m.Called(transactionID)
 
-	var r0 []*model.Party
-	if rf, ok := ret.Get(0).(func(uint) []*model.Party); ok {
-		r0 = rf(userID)
+	var r0 []*model.Attachment
+	if rf, ok := ret.Get(0).(func(uint) []*model.Attachment); ok {
+		r0 = rf(transactionID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Party)
+			r0 = ret.Get(0).([]*model.Attachment)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(userID)
+		r1 = rf(transactionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -89,22 +99,22 @@ func (_m *RepositorySpy) PartyList(userID uint) ([]*model.Party, error) {
 	return r0, r1
 }
 
-// PartyUpdate provides a mock function with given fields: id, w
-func (_m *RepositorySpy) PartyUpdate(id uint, w *model.Party) (*model.Party, error) {
-	ret := _m.Called(id, w)
+// AttachmentListByUser provides a mock function with given fields: userID
+func (_m *RepositorySpy) AttachmentListByUser(userID uint) ([]*model.Attachment, error) {
+	ret := _m.Called(userID)
 
-	var r0 *model.Party
-	if rf, ok := ret.Get(0).(func(uint, *model.Party) *model.Party); ok {
-		r0 = rf(id, w)
+	var r0 []*model.Attachment
+	if rf, ok := ret.Get(0).(func(uint) []*model.Attachment); ok {
+		r0 = rf(userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Party)
+			r0 = ret.Get(0).([]*model.Attachment)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, *model.Party) error); ok {
-		r1 = rf(id, w)
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,50 +122,45 @@ func (_m *RepositorySpy) PartyUpdate(id uint, w *model.Party) (*model.Party, err
 	return r0, r1
 }
 
-// TransactionCreate provides a mock function with given fields: t
-func (_m *RepositorySpy) TransactionCreate(t *model.Transaction) error {
-	ret := _m.Called(t)
+// AuditChainUsers provides a mock function with given fields:
+func (_m *RepositorySpy) AuditChainUsers() ([]uint, error) {
+	ret := _m.Called()
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Transaction) error); ok {
-		r0 = rf(t)
+	var r0 []uint
+	if rf, ok := ret.Get(0).(func() []uint); ok {
+		r0 = rf()
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint)
+		}
 	}
 
-	return r0
-}
-
-// TransactionDelete provides a mock function with given fields: id
-func (_m *RepositorySpy) TransactionDelete(id uint) error {
-	ret := _m.Called(id)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(id)
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// TransactionGet provides a mock function with given fields: id
-func (_m *RepositorySpy) TransactionGet(id uint) (*model.Transaction, error) {
-	ret := _m.Called(id)
+// AuditList provides a mock function with given fields: resource, resourceID
+func (_m *RepositorySpy) AuditList(resource string, resourceID uint) ([]*model.AuditEntry, error) {
+	ret := _m.Called(resource, resourceID)
 
-	var r0 *model.Transaction
-	if rf, ok := ret.Get(0).(func(uint) *model.Transaction); ok {
-		r0 = rf(id)
+	var r0 []*model.AuditEntry
+	if rf, ok := ret.Get(0).(func(string, uint) []*model.AuditEntry); ok {
+		r0 = rf(resource, resourceID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Transaction)
+			r0 = ret.Get(0).([]*model.AuditEntry)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(string, uint) error); ok {
+		r1 = rf(resource, resourceID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -163,22 +168,22 @@ func (_m *RepositorySpy) TransactionGet(id uint) (*model.Transaction, error) {
 	return r0, r1
 }
 
-// TransactionList provides a mock function with given fields: userID
-func (_m *RepositorySpy) TransactionList(userID uint) ([]*model.Transaction, error) {
-	ret := _m.Called(userID)
+// AuditListChain provides a mock function with given fields: userID, afterID, limit
+func (_m *RepositorySpy) AuditListChain(userID uint, afterID uint, limit int) ([]*model.AuditEntry, error) {
+	ret := _m.Called(userID, afterID, limit)
 
-	var r0 []*model.Transaction
-	if rf, ok := ret.Get(0).(func(uint) []*model.Transaction); ok {
-		r0 = rf(userID)
+	var r0 []*model.AuditEntry
+	if rf, ok := ret.Get(0).(func(uint, uint, int) []*model.AuditEntry); ok {
+		r0 = rf(userID, afterID, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Transaction)
+			r0 = ret.Get(0).([]*model.AuditEntry)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(uint, uint, int) error); ok {
+		r1 = rf(userID, afterID, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -186,22 +191,22 @@ func (_m *RepositorySpy) TransactionList(userID uint) ([]*model.Transaction, err
 	return r0, r1
 }
 
-// TransactionListByParty provides a mock function with given fields: userID, partyID
-func (_m *RepositorySpy) TransactionListByParty(userID uint, partyID uint) ([]*model.Transaction, error) {
-	ret := _m.Called(userID, partyID)
+// ExchangeRateList provides a mock function with given fields: base, quote
+func (_m *RepositorySpy) ExchangeRateList(base string, quote string) ([]*model.ExchangeRate, error) {
+	ret := _m.Called(base, quote)
 
-	var r0 []*model.Transaction
-	if rf, ok := ret.Get(0).(func(uint, uint) []*model.Transaction); ok {
-		r0 = rf(userID, partyID)
+	var r0 []*model.ExchangeRate
+	if rf, ok := ret.Get(0).(func(string, string) []*model.ExchangeRate); ok {
+		r0 = rf(base, quote)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Transaction)
+			r0 = ret.Get(0).([]*model.ExchangeRate)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
-		r1 = rf(userID, partyID)
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(base, quote)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -209,22 +214,22 @@ func (_m *RepositorySpy) TransactionListByParty(userID uint, partyID uint) ([]*m
 	return r0, r1
 }
 
-// TransactionListByWallet provides a mock function with given fields: userID, walletID
-func (_m *RepositorySpy) TransactionListByWallet(userID uint, walletID uint) ([]*model.Transaction, error) {
-	ret := _m.Called(userID, walletID)
+// ExchangeRateListByCurrencies provides a mock function with given fields: currencies
+func (_m *RepositorySpy) ExchangeRateListByCurrencies(currencies []string) ([]*model.ExchangeRate, error) {
+	ret := _m.Called(currencies)
 
-	var r0 []*model.Transaction
-	if rf, ok := ret.Get(0).(func(uint, uint) []*model.Transaction); ok {
-		r0 = rf(userID, walletID)
+	var r0 []*model.ExchangeRate
+	if rf, ok := ret.Get(0).(func([]string) []*model.ExchangeRate); ok {
+		r0 = rf(currencies)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Transaction)
+			r0 = ret.Get(0).([]*model.ExchangeRate)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
-		r1 = rf(userID, walletID)
+	if rf, ok := ret.Get(1).(func([]string) error); ok {
+		r1 = rf(currencies)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -232,22 +237,64 @@ func (_m *RepositorySpy) TransactionListByWallet(userID uint, walletID uint) ([]
 	return r0, r1
 }
 
-// TransactionUpdate provides a mock function with given fields: id, t
-func (_m *RepositorySpy) TransactionUpdate(id uint, t *model.Transaction) (*model.Transaction, error) {
-	ret := _m.Called(id, t)
+// ExchangeRateUpsert provides a mock function with given fields: rates
+func (_m *RepositorySpy) ExchangeRateUpsert(rates []*model.ExchangeRate) error {
+	ret := _m.Called(rates)
 
-	var r0 *model.Transaction
-	if rf, ok := ret.Get(0).(func(uint, *model.Transaction) *model.Transaction); ok {
-		r0 = rf(id, t)
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.ExchangeRate) error); ok {
+		r0 = rf(rates)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HouseholdCreate provides a mock function with given fields: h, ownerID, ownerRole
+func (_m *RepositorySpy) HouseholdCreate(h *model.Household, ownerID uint, ownerRole string) error {
+	ret := _m.Called(h, ownerID, ownerRole)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Household, uint, string) error); ok {
+		r0 = rf(h, ownerID, ownerRole)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HouseholdDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) HouseholdDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HouseholdGet provides a mock function with given fields: id
+func (_m *RepositorySpy) HouseholdGet(id uint) (*model.Household, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Household
+	if rf, ok := ret.Get(0).(func(uint) *model.Household); ok {
+		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Transaction)
+			r0 = ret.Get(0).(*model.Household)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, *model.Transaction) error); ok {
-		r1 = rf(id, t)
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -255,22 +302,36 @@ func (_m *RepositorySpy) TransactionUpdate(id uint, t *model.Transaction) (*mode
 	return r0, r1
 }
 
-// UserCreate provides a mock function with given fields: firstName, LastName, Email, Password, Salt
-func (_m *RepositorySpy) UserCreate(firstName string, LastName string, Email string, Password string, Salt string) (*model.User, error) {
-	ret := _m.Called(firstName, LastName, Email, Password, Salt)
+// HouseholdHandOver provides a mock function with given fields: householdID, fromUserID, toUserID
+func (_m *RepositorySpy) HouseholdHandOver(householdID uint, fromUserID uint, toUserID uint) error {
+	ret := _m.Called(householdID, fromUserID, toUserID)
 
-	var r0 *model.User
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string) *model.User); ok {
-		r0 = rf(firstName, LastName, Email, Password, Salt)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint, uint) error); ok {
+		r0 = rf(householdID, fromUserID, toUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HouseholdInvitationAccept provides a mock function with given fields: id, userID
+func (_m *RepositorySpy) HouseholdInvitationAccept(id uint, userID uint) (*model.HouseholdMember, error) {
+	ret := _m.Called(id, userID)
+
+	var r0 *model.HouseholdMember
+	if rf, ok := ret.Get(0).(func(uint, uint) *model.HouseholdMember); ok {
+		r0 = rf(id, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).(*model.HouseholdMember)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string, string, string) error); ok {
-		r1 = rf(firstName, LastName, Email, Password, Salt)
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(id, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -278,8 +339,22 @@ func (_m *RepositorySpy) UserCreate(firstName string, LastName string, Email str
 	return r0, r1
 }
 
-// UserDelete provides a mock function with given fields: id
-func (_m *RepositorySpy) UserDelete(id uint) error {
+// HouseholdInvitationCreate provides a mock function with given fields: i
+func (_m *RepositorySpy) HouseholdInvitationCreate(i *model.HouseholdInvitation) error {
+	ret := _m.Called(i)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.HouseholdInvitation) error); ok {
+		r0 = rf(i)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HouseholdInvitationDecline provides a mock function with given fields: id
+func (_m *RepositorySpy) HouseholdInvitationDecline(id uint) error {
 	ret := _m.Called(id)
 
 	var r0 error
@@ -292,16 +367,16 @@ func (_m *RepositorySpy) UserDelete(id uint) error {
 	return r0
 }
 
-// UserGet provides a mock function with given fields: id
-func (_m *RepositorySpy) UserGet(id uint) (*model.User, error) {
+// HouseholdInvitationGet provides a mock function with given fields: id
+func (_m *RepositorySpy) HouseholdInvitationGet(id uint) (*model.HouseholdInvitation, error) {
 	ret := _m.Called(id)
 
-	var r0 *model.User
-	if rf, ok := ret.Get(0).(func(uint) *model.User); ok {
+	var r0 *model.HouseholdInvitation
+	if rf, ok := ret.Get(0).(func(uint) *model.HouseholdInvitation); ok {
 		r0 = rf(id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).(*model.HouseholdInvitation)
 		}
 	}
 
@@ -315,16 +390,39 @@ func (_m *RepositorySpy) UserGet(id uint) (*model.User, error) {
 	return r0, r1
 }
 
-// UserGetWithEmail provides a mock function with given fields: email
-func (_m *RepositorySpy) UserGetWithEmail(email string) (*model.User, error) {
+// HouseholdInvitationList provides a mock function with given fields: householdID
+func (_m *RepositorySpy) HouseholdInvitationList(householdID uint) ([]*model.HouseholdInvitation, error) {
+	ret := _m.Called(householdID)
+
+	var r0 []*model.HouseholdInvitation
+	if rf, ok := ret.Get(0).(func(uint) []*model.HouseholdInvitation); ok {
+		r0 = rf(householdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.HouseholdInvitation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(householdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HouseholdInvitationListByEmail provides a mock function with given fields: email
+func (_m *RepositorySpy) HouseholdInvitationListByEmail(email string) ([]*model.HouseholdInvitation, error) {
 	ret := _m.Called(email)
 
-	var r0 *model.User
-	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+	var r0 []*model.HouseholdInvitation
+	if rf, ok := ret.Get(0).(func(string) []*model.HouseholdInvitation); ok {
 		r0 = rf(email)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).([]*model.HouseholdInvitation)
 		}
 	}
 
@@ -338,22 +436,22 @@ func (_m *RepositorySpy) UserGetWithEmail(email string) (*model.User, error) {
 	return r0, r1
 }
 
-// UserUpdate provides a mock function with given fields: id, firstName, LastName, Email
-func (_m *RepositorySpy) UserUpdate(id uint, firstName string, LastName string, Email string) (*model.User, error) {
-	ret := _m.Called(id, firstName, LastName, Email)
+// HouseholdListByUser provides a mock function with given fields: userID
+func (_m *RepositorySpy) HouseholdListByUser(userID uint) ([]*model.HouseholdMember, error) {
+	ret := _m.Called(userID)
 
-	var r0 *model.User
-	if rf, ok := ret.Get(0).(func(uint, string, string, string) *model.User); ok {
-		r0 = rf(id, firstName, LastName, Email)
+	var r0 []*model.HouseholdMember
+	if rf, ok := ret.Get(0).(func(uint) []*model.HouseholdMember); ok {
+		r0 = rf(userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.User)
+			r0 = ret.Get(0).([]*model.HouseholdMember)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, string, string, string) error); ok {
-		r1 = rf(id, firstName, LastName, Email)
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -361,13 +459,13 @@ func (_m *RepositorySpy) UserUpdate(id uint, firstName string, LastName string,
 	return r0, r1
 }
 
-// WalletCreate provides a mock function with given fields: w
-func (_m *RepositorySpy) WalletCreate(w *model.Wallet) error {
-	ret := _m.Called(w)
+// HouseholdMemberDelete provides a mock function with given fields: householdID, userID
+func (_m *RepositorySpy) HouseholdMemberDelete(householdID uint, userID uint) error {
+	ret := _m.Called(householdID, userID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*model.Wallet) error); ok {
-		r0 = rf(w)
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(householdID, userID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -375,36 +473,45 @@ func (_m *RepositorySpy) WalletCreate(w *model.Wallet) error {
 	return r0
 }
 
-// WalletDelete provides a mock function with given fields: id
-func (_m *RepositorySpy) WalletDelete(id uint) error {
-	ret := _m.Called(id)
+// HouseholdMemberGet provides a mock function with given fields: householdID, userID
+func (_m *RepositorySpy) HouseholdMemberGet(householdID uint, userID uint) (*model.HouseholdMember, error) {
+	ret := _m.Called(householdID, userID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(id)
+	var r0 *model.HouseholdMember
+	if rf, ok := ret.Get(0).(func(uint, uint) *model.HouseholdMember); ok {
+		r0 = rf(householdID, userID)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.HouseholdMember)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(householdID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// WalletGet provides a mock function with given fields: id
-func (_m *RepositorySpy) WalletGet(id uint) (*model.Wallet, error) {
-	ret := _m.Called(id)
+// HouseholdMemberList provides a mock function with given fields: householdID
+func (_m *RepositorySpy) HouseholdMemberList(householdID uint) ([]*model.HouseholdMember, error) {
+	ret := _m.Called(householdID)
 
-	var r0 *model.Wallet
-	if rf, ok := ret.Get(0).(func(uint) *model.Wallet); ok {
-		r0 = rf(id)
+	var r0 []*model.HouseholdMember
+	if rf, ok := ret.Get(0).(func(uint) []*model.HouseholdMember); ok {
+		r0 = rf(householdID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Wallet)
+			r0 = ret.Get(0).([]*model.HouseholdMember)
 		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(id)
+		r1 = rf(householdID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -412,22 +519,22 @@ func (_m *RepositorySpy) WalletGet(id uint) (*model.Wallet, error) {
 	return r0, r1
 }
 
-// WalletList provides a mock function with given fields: userID
-func (_m *RepositorySpy) WalletList(userID uint) ([]*model.Wallet, error) {
-	ret := _m.Called(userID)
+// HouseholdMemberUpdate provides a mock function with given fields: householdID, userID, role
+func (_m *RepositorySpy) HouseholdMemberUpdate(householdID uint, userID uint, role string) (*model.HouseholdMember, error) {
+	ret := _m.Called(householdID, userID, role)
 
-	var r0 []*model.Wallet
-	if rf, ok := ret.Get(0).(func(uint) []*model.Wallet); ok {
-		r0 = rf(userID)
+	var r0 *model.HouseholdMember
+	if rf, ok := ret.Get(0).(func(uint, uint, string) *model.HouseholdMember); ok {
+		r0 = rf(householdID, userID, role)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*model.Wallet)
+			r0 = ret.Get(0).(*model.HouseholdMember)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(uint, uint, string) error); ok {
+		r1 = rf(householdID, userID, role)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -435,22 +542,20 @@ func (_m *RepositorySpy) WalletList(userID uint) ([]*model.Wallet, error) {
 	return r0, r1
 }
 
-// WalletUpdate provides a mock function with given fields: id, w
-func (_m *RepositorySpy) WalletUpdate(id uint, w *model.Wallet) (*model.Wallet, error) {
-	ret := _m.Called(id, w)
+// HouseholdSharedByUsers provides a mock function with given fields: userID, otherID
+func (_m *RepositorySpy) HouseholdSharedByUsers(userID uint, otherID uint) (bool, error) {
+	ret := _m.Called(userID, otherID)
 
-	var r0 *model.Wallet
-	if rf, ok := ret.Get(0).(func(uint, *model.Wallet) *model.Wallet); ok {
-		r0 = rf(id, w)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uint, uint) bool); ok {
+		r0 = rf(userID, otherID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.Wallet)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(uint, *model.Wallet) error); ok {
-		r1 = rf(id, w)
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, otherID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -458,6 +563,1854 @@ func (_m *RepositorySpy) WalletUpdate(id uint, w *model.Wallet) (*model.Wallet,
 	return r0, r1
 }
 
+// HouseholdUpdate provides a mock function with given fields: id, h
+func (_m *RepositorySpy) HouseholdUpdate(id uint, h *model.Household) (*model.Household, error) {
+	ret := _m.Called(id, h)
+
+	var r0 *model.Household
+	if rf, ok := ret.Get(0).(func(uint, *model.Household) *model.Household); ok {
+		r0 = rf(id, h)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Household)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.Household) error); ok {
+		r1 = rf(id, h)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IdempotencyKeyComplete provides a mock function with given fields: id, statusCode, body
+func (_m *RepositorySpy) IdempotencyKeyComplete(id uint, statusCode int, body []byte) error {
+	ret := _m.Called(id, statusCode, body)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, int, []byte) error); ok {
+		r0 = rf(id, statusCode, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyKeyCreate provides a mock function with given fields: k
+func (_m *RepositorySpy) IdempotencyKeyCreate(k *model.IdempotencyKey) error {
+	ret := _m.Called(k)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.IdempotencyKey) error); ok {
+		r0 = rf(k)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyKeyDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) IdempotencyKeyDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdempotencyKeyGet provides a mock function with given fields: userID, key
+func (_m *RepositorySpy) IdempotencyKeyGet(userID uint, key string) (*model.IdempotencyKey, error) {
+	ret := _m.Called(userID, key)
+
+	var r0 *model.IdempotencyKey
+	if rf, ok := ret.Get(0).(func(uint, string) *model.IdempotencyKey); ok {
+		r0 = rf(userID, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.IdempotencyKey)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(userID, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IdempotencyKeyPurge provides a mock function with given fields: before
+func (_m *RepositorySpy) IdempotencyKeyPurge(before time.Time) error {
+	ret := _m.Called(before)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OutboxDispatch provides a mock function with given fields: limit, dispatch
+func (_m *RepositorySpy) OutboxDispatch(limit int, dispatch func([]*model.OutboxEvent)) (int, error) {
+	ret := _m.Called(limit, dispatch)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, func([]*model.OutboxEvent)) int); ok {
+		r0 = rf(limit, dispatch)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, func([]*model.OutboxEvent)) error); ok {
+		r1 = rf(limit, dispatch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxGet provides a mock function with given fields: id
+func (_m *RepositorySpy) OutboxGet(id uint) (*model.OutboxEvent, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.OutboxEvent
+	if rf, ok := ret.Get(0).(func(uint) *model.OutboxEvent); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OutboxEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxLatestID provides a mock function with given fields:
+func (_m *RepositorySpy) OutboxLatestID() (uint, error) {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxList provides a mock function with given fields: userID, householdIDs, afterID, limit
+func (_m *RepositorySpy) OutboxList(userID uint, householdIDs []uint, afterID uint, limit int) ([]*model.OutboxEvent, error) {
+	ret := _m.Called(userID, householdIDs, afterID, limit)
+
+	var r0 []*model.OutboxEvent
+	if rf, ok := ret.Get(0).(func(uint, []uint, uint, int) []*model.OutboxEvent); ok {
+		r0 = rf(userID, householdIDs, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OutboxEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, []uint, uint, int) error); ok {
+		r1 = rf(userID, householdIDs, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OutboxListen provides a mock function with given fields: ctx, notify
+func (_m *RepositorySpy) OutboxListen(ctx context.Context, notify func(uint)) error {
+	ret := _m.Called(ctx, notify)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(uint)) error); ok {
+		r0 = rf(ctx, notify)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OutboxNotify provides a mock function with given fields: id
+func (_m *RepositorySpy) OutboxNotify(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PartyAliasDelete provides a mock function with given fields: partyID, aliasID
+func (_m *RepositorySpy) PartyAliasDelete(partyID uint, aliasID uint) error {
+	ret := _m.Called(partyID, aliasID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(partyID, aliasID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PartyCreate provides a mock function with given fields: w
+func (_m *RepositorySpy) PartyCreate(w *model.Party) error {
+	ret := _m.Called(w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Party) error); ok {
+		r0 = rf(w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PartyDelete provides a mock function with given fields: id, cascade
+func (_m *RepositorySpy) PartyDelete(id uint, cascade bool) error {
+	ret := _m.Called(id, cascade)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, bool) error); ok {
+		r0 = rf(id, cascade)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PartyGet provides a mock function with given fields: id
+func (_m *RepositorySpy) PartyGet(id uint) (*model.Party, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Party
+	if rf, ok := ret.Get(0).(func(uint) *model.Party); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyGetByAlias provides a mock function with given fields: userID, name
+func (_m *RepositorySpy) PartyGetByAlias(userID uint, name string) (*model.Party, error) {
+	ret := _m.Called(userID, name)
+
+	var r0 *model.Party
+	if rf, ok := ret.Get(0).(func(uint, string) *model.Party); ok {
+		r0 = rf(userID, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(userID, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyGetTrashed provides a mock function with given fields: id
+func (_m *RepositorySpy) PartyGetTrashed(id uint) (*model.Party, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Party
+	if rf, ok := ret.Get(0).(func(uint) *model.Party); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyList provides a mock function with given fields: userID
+func (_m *RepositorySpy) PartyList(userID uint) ([]*model.Party, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Party
+	if rf, ok := ret.Get(0).(func(uint) []*model.Party); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyListByIDs provides a mock function with given fields: ids
+func (_m *RepositorySpy) PartyListByIDs(ids []uint) ([]*model.Party, error) {
+	ret := _m.Called(ids)
+
+	var r0 []*model.Party
+	if rf, ok := ret.Get(0).(func([]uint) []*model.Party); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyListTrashed provides a mock function with given fields: userID
+func (_m *RepositorySpy) PartyListTrashed(userID uint) ([]*model.Party, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Party
+	if rf, ok := ret.Get(0).(func(uint) []*model.Party); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyMerge provides a mock function with given fields: targetID, sourceIDs
+func (_m *RepositorySpy) PartyMerge(targetID uint, sourceIDs []uint) (*model.Party, error) {
+	ret := _m.Called(targetID, sourceIDs)
+
+	var r0 *model.Party
+	if rf, ok := ret.Get(0).(func(uint, []uint) *model.Party); ok {
+		r0 = rf(targetID, sourceIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, []uint) error); ok {
+		r1 = rf(targetID, sourceIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyReassign provides a mock function with given fields: id, targetID
+func (_m *RepositorySpy) PartyReassign(id uint, targetID uint) error {
+	ret := _m.Called(id, targetID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, targetID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PartyRestore provides a mock function with given fields: id
+func (_m *RepositorySpy) PartyRestore(id uint) (*model.Party, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Party
+	if rf, ok := ret.Get(0).(func(uint) *model.Party); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PartyUpdate provides a mock function with given fields: id, w
+func (_m *RepositorySpy) PartyUpdate(id uint, w *model.Party) (*model.Party, error) {
+	ret := _m.Called(id, w)
+
+	var r0 *model.Party
+	if rf, ok := ret.Get(0).(func(uint, *model.Party) *model.Party); ok {
+		r0 = rf(id, w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Party)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.Party) error); ok {
+		r1 = rf(id, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReconciliationCreate provides a mock function with given fields: rec
+func (_m *RepositorySpy) ReconciliationCreate(rec *model.Reconciliation) error {
+	ret := _m.Called(rec)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Reconciliation) error); ok {
+		r0 = rf(rec)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReconciliationList provides a mock function with given fields: walletID
+func (_m *RepositorySpy) ReconciliationList(walletID uint) ([]*model.Reconciliation, error) {
+	ret := _m.Called(walletID)
+
+	var r0 []*model.Reconciliation
+	if rf, ok := ret.Get(0).(func(uint) []*model.Reconciliation); ok {
+		r0 = rf(walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Reconciliation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RuleCreate provides a mock function with given fields: rule
+func (_m *RepositorySpy) RuleCreate(rule *model.Rule) error {
+	ret := _m.Called(rule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Rule) error); ok {
+		r0 = rf(rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RuleDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) RuleDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RuleGet provides a mock function with given fields: id
+func (_m *RepositorySpy) RuleGet(id uint) (*model.Rule, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Rule
+	if rf, ok := ret.Get(0).(func(uint) *model.Rule); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Rule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RuleList provides a mock function with given fields: userID
+func (_m *RepositorySpy) RuleList(userID uint) ([]*model.Rule, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Rule
+	if rf, ok := ret.Get(0).(func(uint) []*model.Rule); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Rule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RuleRecordHits provides a mock function with given fields: hits
+func (_m *RepositorySpy) RuleRecordHits(hits map[uint]uint) error {
+	ret := _m.Called(hits)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(map[uint]uint) error); ok {
+		r0 = rf(hits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RuleUpdate provides a mock function with given fields: id, updated
+func (_m *RepositorySpy) RuleUpdate(id uint, updated *model.Rule) (*model.Rule, error) {
+	ret := _m.Called(id, updated)
+
+	var r0 *model.Rule
+	if rf, ok := ret.Get(0).(func(uint, *model.Rule) *model.Rule); ok {
+		r0 = rf(id, updated)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Rule)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.Rule) error); ok {
+		r1 = rf(id, updated)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: userID, query
+func (_m *RepositorySpy) Search(userID uint, query *search.Query) ([]*search.Result, error) {
+	ret := _m.Called(userID, query)
+
+	var r0 []*search.Result
+	if rf, ok := ret.Get(0).(func(uint, *search.Query) []*search.Result); ok {
+		r0 = rf(userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*search.Result)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *search.Query) error); ok {
+		r1 = rf(userID, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementCreate provides a mock function with given fields: s
+func (_m *RepositorySpy) SettlementCreate(s *model.Settlement) error {
+	ret := _m.Called(s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Settlement) error); ok {
+		r0 = rf(s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SettlementDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) SettlementDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SettlementGet provides a mock function with given fields: id
+func (_m *RepositorySpy) SettlementGet(id uint) (*model.Settlement, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Settlement
+	if rf, ok := ret.Get(0).(func(uint) *model.Settlement); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Settlement)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SettlementListByUsers provides a mock function with given fields: userIDs
+func (_m *RepositorySpy) SettlementListByUsers(userIDs []uint) ([]*model.Settlement, error) {
+	ret := _m.Called(userIDs)
+
+	var r0 []*model.Settlement
+	if rf, ok := ret.Get(0).(func([]uint) []*model.Settlement); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Settlement)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionApplyRules provides a mock function with given fields: transactions, hits
+func (_m *RepositorySpy) TransactionApplyRules(transactions []*model.Transaction, hits map[uint]uint) error {
+	ret := _m.Called(transactions, hits)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.Transaction, map[uint]uint) error); ok {
+		r0 = rf(transactions, hits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionBulk provides a mock function with given fields: ops
+func (_m *RepositorySpy) TransactionBulk(ops []*repository.TransactionOperation) (int, error) {
+	ret := _m.Called(ops)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func([]*repository.TransactionOperation) int); ok {
+		r0 = rf(ops)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*repository.TransactionOperation) error); ok {
+		r1 = rf(ops)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionCreate provides a mock function with given fields: t
+func (_m *RepositorySpy) TransactionCreate(t *model.Transaction) error {
+	ret := _m.Called(t)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Transaction) error); ok {
+		r0 = rf(t)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) TransactionDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionGet provides a mock function with given fields: id
+func (_m *RepositorySpy) TransactionGet(id uint) (*model.Transaction, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Transaction
+	if rf, ok := ret.Get(0).(func(uint) *model.Transaction); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionGetTrashed provides a mock function with given fields: id
+func (_m *RepositorySpy) TransactionGetTrashed(id uint) (*model.Transaction, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Transaction
+	if rf, ok := ret.Get(0).(func(uint) *model.Transaction); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionList provides a mock function with given fields: userID
+func (_m *RepositorySpy) TransactionList(userID uint) ([]*model.Transaction, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(uint) []*model.Transaction); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionListByIDs provides a mock function with given fields: ids
+func (_m *RepositorySpy) TransactionListByIDs(ids []uint) ([]*model.Transaction, error) {
+	ret := _m.Called(ids)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func([]uint) []*model.Transaction); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionListByParty provides a mock function with given fields: userID, partyID
+func (_m *RepositorySpy) TransactionListByParty(userID uint, partyID uint) ([]*model.Transaction, error) {
+	ret := _m.Called(userID, partyID)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(uint, uint) []*model.Transaction); ok {
+		r0 = rf(userID, partyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, partyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionListByWallet provides a mock function with given fields: userID, walletID
+func (_m *RepositorySpy) TransactionListByWallet(userID uint, walletID uint) ([]*model.Transaction, error) {
+	ret := _m.Called(userID, walletID)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(uint, uint) []*model.Transaction); ok {
+		r0 = rf(userID, walletID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionListDuplicateCandidates provides a mock function with given fields: t, window
+func (_m *RepositorySpy) TransactionListDuplicateCandidates(t *model.Transaction, window time.Duration) ([]*model.Transaction, error) {
+	ret := _m.Called(t, window)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(*model.Transaction, time.Duration) []*model.Transaction); ok {
+		r0 = rf(t, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.Transaction, time.Duration) error); ok {
+		r1 = rf(t, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionListFiltered provides a mock function with given fields: userID, filter
+func (_m *RepositorySpy) TransactionListFiltered(userID uint, filter *repository.TransactionFilter) ([]*model.Transaction, error) {
+	ret := _m.Called(userID, filter)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(uint, *repository.TransactionFilter) []*model.Transaction); ok {
+		r0 = rf(userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *repository.TransactionFilter) error); ok {
+		r1 = rf(userID, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionListTrashed provides a mock function with given fields: userID
+func (_m *RepositorySpy) TransactionListTrashed(userID uint) ([]*model.Transaction, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Transaction
+	if rf, ok := ret.Get(0).(func(uint) []*model.Transaction); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionMerge provides a mock function with given fields: survivorID, duplicateID
+func (_m *RepositorySpy) TransactionMerge(survivorID uint, duplicateID uint) (*model.Transaction, error) {
+	ret := _m.Called(survivorID, duplicateID)
+
+	var r0 *model.Transaction
+	if rf, ok := ret.Get(0).(func(uint, uint) *model.Transaction); ok {
+		r0 = rf(survivorID, duplicateID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(survivorID, duplicateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRestore provides a mock function with given fields: id
+func (_m *RepositorySpy) TransactionRestore(id uint) (*model.Transaction, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Transaction
+	if rf, ok := ret.Get(0).(func(uint) *model.Transaction); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionShareList provides a mock function with given fields: transactionID
+func (_m *RepositorySpy) TransactionShareList(transactionID uint) ([]*model.TransactionShare, error) {
+	ret := _m.Called(transactionID)
+
+	var r0 []*model.TransactionShare
+	if rf, ok := ret.Get(0).(func(uint) []*model.TransactionShare); ok {
+		r0 = rf(transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionShareListByUsers provides a mock function with given fields: userIDs
+func (_m *RepositorySpy) TransactionShareListByUsers(userIDs []uint) ([]*model.TransactionShare, error) {
+	ret := _m.Called(userIDs)
+
+	var r0 []*model.TransactionShare
+	if rf, ok := ret.Get(0).(func([]uint) []*model.TransactionShare); ok {
+		r0 = rf(userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionShare)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionShareReplace provides a mock function with given fields: transactionID, version, shares
+func (_m *RepositorySpy) TransactionShareReplace(transactionID uint, version uint, shares []*model.TransactionShare) error {
+	ret := _m.Called(transactionID, version, shares)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint, []*model.TransactionShare) error); ok {
+		r0 = rf(transactionID, version, shares)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionSplitList provides a mock function with given fields: transactionID
+func (_m *RepositorySpy) TransactionSplitList(transactionID uint) ([]*model.TransactionSplit, error) {
+	ret := _m.Called(transactionID)
+
+	var r0 []*model.TransactionSplit
+	if rf, ok := ret.Get(0).(func(uint) []*model.TransactionSplit); ok {
+		r0 = rf(transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionSplit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionSplitListByUser provides a mock function with given fields: userID
+func (_m *RepositorySpy) TransactionSplitListByUser(userID uint) ([]*model.TransactionSplit, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.TransactionSplit
+	if rf, ok := ret.Get(0).(func(uint) []*model.TransactionSplit); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.TransactionSplit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionSplitReplace provides a mock function with given fields: transactionID, version, splits
+func (_m *RepositorySpy) TransactionSplitReplace(transactionID uint, version uint, splits []*model.TransactionSplit) error {
+	ret := _m.Called(transactionID, version, splits)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint, []*model.TransactionSplit) error); ok {
+		r0 = rf(transactionID, version, splits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TransactionStamp provides a mock function with given fields: userID
+func (_m *RepositorySpy) TransactionStamp(userID uint) (string, error) {
+	ret := _m.Called(userID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(uint) string); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionSumByWallet provides a mock function with given fields: walletIDs
+func (_m *RepositorySpy) TransactionSumByWallet(walletIDs []uint) (map[uint]decimal.Decimal, error) {
+	ret := _m.Called(walletIDs)
+
+	var r0 map[uint]decimal.Decimal
+	if rf, ok := ret.Get(0).(func([]uint) map[uint]decimal.Decimal); ok {
+		r0 = rf(walletIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint]decimal.Decimal)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(walletIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionSumExpenses provides a mock function with given fields: walletID, from, to
+func (_m *RepositorySpy) TransactionSumExpenses(walletID uint, from time.Time, to time.Time) (decimal.Decimal, error) {
+	ret := _m.Called(walletID, from, to)
+
+	var r0 decimal.Decimal
+	if rf, ok := ret.Get(0).(func(uint, time.Time, time.Time) decimal.Decimal); ok {
+		r0 = rf(walletID, from, to)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, time.Time, time.Time) error); ok {
+		r1 = rf(walletID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionUpdate provides a mock function with given fields: id, t
+func (_m *RepositorySpy) TransactionUpdate(id uint, t *model.Transaction) (*model.Transaction, error) {
+	ret := _m.Called(id, t)
+
+	var r0 *model.Transaction
+	if rf, ok := ret.Get(0).(func(uint, *model.Transaction) *model.Transaction); ok {
+		r0 = rf(id, t)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.Transaction) error); ok {
+		r1 = rf(id, t)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TrashPurge provides a mock function with given fields: before
+func (_m *RepositorySpy) TrashPurge(before time.Time) ([]*model.Attachment, error) {
+	ret := _m.Called(before)
+
+	var r0 []*model.Attachment
+	if rf, ok := ret.Get(0).(func(time.Time) []*model.Attachment); ok {
+		r0 = rf(before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserCreate provides a mock function with given fields: firstName, LastName, Email, Password, Salt
+func (_m *RepositorySpy) UserCreate(firstName string, LastName string, Email string, Password string, Salt string) (*model.User, error) {
+	ret := _m.Called(firstName, LastName, Email, Password, Salt)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string, string, string, string, string) *model.User); ok {
+		r0 = rf(firstName, LastName, Email, Password, Salt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, string, string, string) error); ok {
+		r1 = rf(firstName, LastName, Email, Password, Salt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) UserDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserGet provides a mock function with given fields: id
+func (_m *RepositorySpy) UserGet(id uint) (*model.User, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(uint) *model.User); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserGetWithEmail provides a mock function with given fields: email
+func (_m *RepositorySpy) UserGetWithEmail(email string) (*model.User, error) {
+	ret := _m.Called(email)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(string) *model.User); ok {
+		r0 = rf(email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserListByIDs provides a mock function with given fields: ids
+func (_m *RepositorySpy) UserListByIDs(ids []uint) ([]*model.User, error) {
+	ret := _m.Called(ids)
+
+	var r0 []*model.User
+	if rf, ok := ret.Get(0).(func([]uint) []*model.User); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserUpdate provides a mock function with given fields: id, u
+func (_m *RepositorySpy) UserUpdate(id uint, u *model.User) (*model.User, error) {
+	ret := _m.Called(id, u)
+
+	var r0 *model.User
+	if rf, ok := ret.Get(0).(func(uint, *model.User) *model.User); ok {
+		r0 = rf(id, u)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.User) error); ok {
+		r1 = rf(id, u)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletClearedBalance provides a mock function with given fields: walletID, until
+func (_m *RepositorySpy) WalletClearedBalance(walletID uint, until time.Time) (decimal.Decimal, error) {
+	ret := _m.Called(walletID, until)
+
+	var r0 decimal.Decimal
+	if rf, ok := ret.Get(0).(func(uint, time.Time) decimal.Decimal); ok {
+		r0 = rf(walletID, until)
+	} else {
+		r0 = ret.Get(0).(decimal.Decimal)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(walletID, until)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletCreate provides a mock function with given fields: w
+func (_m *RepositorySpy) WalletCreate(w *model.Wallet) error {
+	ret := _m.Called(w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Wallet) error); ok {
+		r0 = rf(w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WalletDelete provides a mock function with given fields: id, cascade
+func (_m *RepositorySpy) WalletDelete(id uint, cascade bool) error {
+	ret := _m.Called(id, cascade)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, bool) error); ok {
+		r0 = rf(id, cascade)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WalletGet provides a mock function with given fields: id
+func (_m *RepositorySpy) WalletGet(id uint) (*model.Wallet, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Wallet
+	if rf, ok := ret.Get(0).(func(uint) *model.Wallet); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletGetTrashed provides a mock function with given fields: id
+func (_m *RepositorySpy) WalletGetTrashed(id uint) (*model.Wallet, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Wallet
+	if rf, ok := ret.Get(0).(func(uint) *model.Wallet); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletList provides a mock function with given fields: userID
+func (_m *RepositorySpy) WalletList(userID uint) ([]*model.Wallet, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Wallet
+	if rf, ok := ret.Get(0).(func(uint) []*model.Wallet); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletListByIDs provides a mock function with given fields: ids
+func (_m *RepositorySpy) WalletListByIDs(ids []uint) ([]*model.Wallet, error) {
+	ret := _m.Called(ids)
+
+	var r0 []*model.Wallet
+	if rf, ok := ret.Get(0).(func([]uint) []*model.Wallet); ok {
+		r0 = rf(ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletListTrashed provides a mock function with given fields: userID
+func (_m *RepositorySpy) WalletListTrashed(userID uint) ([]*model.Wallet, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Wallet
+	if rf, ok := ret.Get(0).(func(uint) []*model.Wallet); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletReassign provides a mock function with given fields: id, targetID
+func (_m *RepositorySpy) WalletReassign(id uint, targetID uint) error {
+	ret := _m.Called(id, targetID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(id, targetID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WalletRestore provides a mock function with given fields: id
+func (_m *RepositorySpy) WalletRestore(id uint) (*model.Wallet, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Wallet
+	if rf, ok := ret.Get(0).(func(uint) *model.Wallet); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WalletUpdate provides a mock function with given fields: id, w
+func (_m *RepositorySpy) WalletUpdate(id uint, w *model.Wallet) (*model.Wallet, error) {
+	ret := _m.Called(id, w)
+
+	var r0 *model.Wallet
+	if rf, ok := ret.Get(0).(func(uint, *model.Wallet) *model.Wallet); ok {
+		r0 = rf(id, w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Wallet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.Wallet) error); ok {
+		r1 = rf(id, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookCreate provides a mock function with given fields: w
+func (_m *RepositorySpy) WebhookCreate(w *model.Webhook) error {
+	ret := _m.Called(w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Webhook) error); ok {
+		r0 = rf(w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookDelete provides a mock function with given fields: id
+func (_m *RepositorySpy) WebhookDelete(id uint) error {
+	ret := _m.Called(id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookDeliveryClaim provides a mock function with given fields: now, lease, limit
+func (_m *RepositorySpy) WebhookDeliveryClaim(now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	ret := _m.Called(now, lease, limit)
+
+	var r0 []*model.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(time.Time, time.Duration, int) []*model.WebhookDelivery); ok {
+		r0 = rf(now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, time.Duration, int) error); ok {
+		r1 = rf(now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookDeliveryCreate provides a mock function with given fields: d
+func (_m *RepositorySpy) WebhookDeliveryCreate(d *model.WebhookDelivery) error {
+	ret := _m.Called(d)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WebhookDelivery) error); ok {
+		r0 = rf(d)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookDeliveryEnqueue provides a mock function with given fields: deliveries
+func (_m *RepositorySpy) WebhookDeliveryEnqueue(deliveries []*model.WebhookDelivery) error {
+	ret := _m.Called(deliveries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*model.WebhookDelivery) error); ok {
+		r0 = rf(deliveries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookDeliveryGet provides a mock function with given fields: id
+func (_m *RepositorySpy) WebhookDeliveryGet(id uint) (*model.WebhookDelivery, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(uint) *model.WebhookDelivery); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookDeliveryList provides a mock function with given fields: webhookID
+func (_m *RepositorySpy) WebhookDeliveryList(webhookID uint) ([]*model.WebhookDelivery, error) {
+	ret := _m.Called(webhookID)
+
+	var r0 []*model.WebhookDelivery
+	if rf, ok := ret.Get(0).(func(uint) []*model.WebhookDelivery); ok {
+		r0 = rf(webhookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookDelivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(webhookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookDeliveryRecordAttempt provides a mock function with given fields: d
+func (_m *RepositorySpy) WebhookDeliveryRecordAttempt(d *model.WebhookDelivery) error {
+	ret := _m.Called(d)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.WebhookDelivery) error); ok {
+		r0 = rf(d)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WebhookGet provides a mock function with given fields: id
+func (_m *RepositorySpy) WebhookGet(id uint) (*model.Webhook, error) {
+	ret := _m.Called(id)
+
+	var r0 *model.Webhook
+	if rf, ok := ret.Get(0).(func(uint) *model.Webhook); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookList provides a mock function with given fields: userID
+func (_m *RepositorySpy) WebhookList(userID uint) ([]*model.Webhook, error) {
+	ret := _m.Called(userID)
+
+	var r0 []*model.Webhook
+	if rf, ok := ret.Get(0).(func(uint) []*model.Webhook); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WebhookUpdate provides a mock function with given fields: id, updated
+func (_m *RepositorySpy) WebhookUpdate(id uint, updated *model.Webhook) (*model.Webhook, error) {
+	ret := _m.Called(id, updated)
+
+	var r0 *model.Webhook
+	if rf, ok := ret.Get(0).(func(uint, *model.Webhook) *model.Webhook); ok {
+		r0 = rf(id, updated)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint, *model.Webhook) error); ok {
+		r1 = rf(id, updated)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithActor provides a mock function with given fields: actor
+func (_m *RepositorySpy) WithActor(actor *repository.Actor) repository.Repository {
+	ret := _m.Called(actor)
+
+	var r0 repository.Repository
+	if rf, ok := ret.Get(0).(func(*repository.Actor) repository.Repository); ok {
+		r0 = rf(actor)
+	} else {
+		r0 = ret.Get(0).(repository.Repository)
+	}
+
+	return r0
+}
+
 // NewRepositorySpy creates a new instance of RepositorySpy. It also registers the testing.TB interface on the mock and a cleanup function to assert the mocks expectations.
 func NewRepositorySpy(t testing.TB) *RepositorySpy {
 	mock := &RepositorySpy{}